@@ -0,0 +1,45 @@
+/**
+ * @file api/audit.ts
+ * @description 审计日志相关 API
+ */
+import api, { type ApiResponse } from './index'
+
+export interface AuditLog {
+  id: number
+  actor_id: number
+  actor_name: string
+  source: 'session' | 'token' | 'anonymous' | 'system'
+  access_token_id: number
+  ip: string
+  action: string
+  entity_type: string
+  entity_id: number
+  before: string // 变更前 JSON 快照 (创建时为空)
+  after: string // 变更后 JSON 快照 (删除时为空)
+  create_time: string
+}
+
+export interface AuditLogQuery {
+  actor_id?: number
+  action?: string
+  entity_type?: string
+  entity_id?: number
+  source?: string
+  start_date?: string
+  end_date?: string
+  page?: number
+  page_size?: number
+}
+
+export interface AuditLogListResult {
+  list: AuditLog[]
+  total: number
+  page: number
+  page_size: number
+}
+
+export const auditApi = {
+  // 分页查询审计日志
+  list: (params: AuditLogQuery) =>
+    api.get<ApiResponse<AuditLogListResult>>('/audit-logs', { params }),
+}
@@ -17,6 +17,20 @@ export interface User {
   department: string // 部门
   position: string   // 职位
   status: number     // 状态 (1:正常, 0:禁用)
+  locked_until?: string // 登录锁定截止时间 (连续登录失败被临时锁定时，仅管理员列表返回)
+}
+
+// 登录锁定记录
+export interface LoginLockout {
+  id: number
+  user_id: number
+  username: string     // 尝试登录的用户名
+  ip: string           // 触发锁定的请求 IP
+  failures: number     // 连续失败次数
+  locked_until: string // 锁定截止时间
+  unlocked_at: string | null // 管理员解锁时间
+  unlocked_by: number  // 解锁操作人ID
+  create_time: string  // 锁定时间
 }
 
 // 登录请求参数
@@ -26,9 +40,20 @@ export interface LoginRequest {
 }
 
 // 登录响应数据
+// 启用两步验证的用户仅返回 two_factor_required 与 challenge_token，需再提交验证码
 export interface LoginResponse {
-  token: string // JWT Token
-  user: User    // 用户信息
+  token: string         // 访问令牌 (JWT，短期有效)
+  refresh_token: string // 刷新令牌 (每次刷新后轮换)
+  expires_in: number    // 访问令牌有效期 (秒)
+  user: User            // 用户信息
+  two_factor_required?: boolean // 是否需要两步验证
+  challenge_token?: string      // 两步验证质询令牌
+}
+
+// 两步验证登录请求参数
+export interface TwoFactorVerifyRequest {
+  challenge_token: string
+  code: string // 验证器验证码或恢复码
 }
 
 // 注册请求参数
@@ -97,13 +122,17 @@ export const authApi = {
   login: (data: LoginRequest) =>
     api.post<ApiResponse<LoginResponse>>('/auth/login', data),
 
+  // 两步验证登录
+  verifyTwoFactor: (data: TwoFactorVerifyRequest) =>
+    api.post<ApiResponse<LoginResponse>>('/auth/2fa/verify', data),
+
   // 注册
   register: (data: RegisterRequest) =>
     api.post<ApiResponse<null>>('/auth/register', data),
 
   // 退出登录
-  logout: () =>
-    api.post<ApiResponse<null>>('/auth/logout'),
+  logout: (refreshToken?: string | null) =>
+    api.post<ApiResponse<null>>('/auth/logout', { refresh_token: refreshToken ?? '' }),
 
   // 获取当前用户
   getCurrentUser: () =>
@@ -132,4 +161,10 @@ export const authApi = {
 
   resetPassword: (id: number, password: string) =>
     api.put<ApiResponse<null>>(`/users/${id}/password`, { new_password: password }),
+
+  unlockUser: (id: number) =>
+    api.post<ApiResponse<null>>(`/users/${id}/unlock`),
+
+  getUserLockouts: (id: number) =>
+    api.get<ApiResponse<LoginLockout[]>>(`/users/${id}/lockouts`),
 }
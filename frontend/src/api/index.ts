@@ -2,7 +2,7 @@
  * @file api/index.ts
  * @description API 请求基础配置
  * 封装 Axios 实例，配置基础 URL、超时时间，并实现请求与响应拦截器。
- * 处理 Token 自动注入、统一错误处理以及登录过期跳转逻辑。
+ * 处理 Token 自动注入、访问令牌过期自动刷新、统一错误处理以及登录失效跳转逻辑。
  */
 import axios, { type AxiosInstance, type AxiosResponse, type InternalAxiosRequestConfig } from 'axios'
 
@@ -53,10 +53,37 @@ export const setAuthLogout = (fn: () => void) => {
   authLogout = fn
 }
 
+// 正在进行的刷新请求 (并发请求共享同一次刷新，避免刷新令牌被重复轮换)
+let refreshing: Promise<string | null> | null = null
+
+// 使用刷新令牌换取新的访问令牌，失败时返回 null
+const refreshAccessToken = (): Promise<string | null> => {
+  if (!refreshing) {
+    const refreshToken = localStorage.getItem('refresh_token')
+    const request = refreshToken
+      ? axios
+          .post<ApiResponse<{ token: string; refresh_token: string }>>('/api/v1/auth/refresh', {
+            refresh_token: refreshToken,
+          })
+          .then((res) => {
+            if (res.data.code !== 0) return null
+            localStorage.setItem('token', res.data.data.token)
+            localStorage.setItem('refresh_token', res.data.data.refresh_token)
+            return res.data.data.token
+          })
+          .catch(() => null)
+      : Promise.resolve(null)
+    refreshing = request.finally(() => {
+      refreshing = null
+    })
+  }
+  return refreshing
+}
+
 // 响应拦截器：处理业务错误和 Token 过期
 
 api.interceptors.response.use(
-  (response: AxiosResponse<ApiResponse>) => {
+  async (response: AxiosResponse<ApiResponse>) => {
     const { code, message } = response.data
 
     // 成功
@@ -64,17 +91,30 @@ api.interceptors.response.use(
       return response
     }
 
-    // Token 过期
-    if (code === 2002) {
+    // 访问令牌过期：尝试刷新后重试一次原请求
+    const config = response.config as InternalAxiosRequestConfig & { _retried?: boolean }
+    if (code === 2002 && !config._retried) {
+      const token = await refreshAccessToken()
+      if (token) {
+        config._retried = true
+        config.headers.Authorization = `Bearer ${token}`
+        return api(config)
+      }
+    }
+
+    // Token 过期且刷新失败，或会话已被吊销 (退出登录、修改密码等)
+    // 认证接口自身返回的 2001 (如密码错误) 不触发登出
+    if (code === 2002 || (code === 2001 && !config.url?.startsWith('/auth/'))) {
       if (authLogout) {
         authLogout()
       } else {
         // Fallback
         localStorage.removeItem('token')
+        localStorage.removeItem('refresh_token')
         localStorage.removeItem('user')
         window.location.href = '/login'
       }
-      return Promise.reject(new Error('登录已过期，请重新登录'))
+      return Promise.reject(new Error(message || '登录已过期，请重新登录'))
     }
 
     // 其他错误
@@ -11,8 +11,10 @@ export interface Project {
   id: number
   name: string            // 项目名称
   company: string         // 所属公司
-  total_amount: number    // 合同总金额
-  received_amount: number // 已收款金额
+  total_amount: number    // 合同总金额 (原合同)
+  change_amount: number   // 已批准变更单金额合计
+  effective_amount: number // 有效合同金额 (合同总金额 + 已批准变更金额)
+  received_amount: number // 已收款净额 (扣除退款)
   status: 'active' | 'completed' | 'pending' | 'notstarted' | 'archived' // 项目状态
   type: string            // 项目类型
   contract_number: string // 合同编号
@@ -34,13 +36,80 @@ export interface Payment {
   amount: number          // 金额
   percentage: number      // 占比 (%)
   plan_date: string       // 计划收款日期
-  status: 'paid' | 'pending' | 'overdue' // 状态
-  actual_date: string     // 实际收款日期
+  status: 'paid' | 'partially_paid' | 'pending' | 'overdue' // 状态
+  actual_date: string     // 实际收款日期 (最近一笔收款)
   method: string          // 收款方式
+  received_amount: number // 已收金额
+  received_percentage: number // 已收占比 (%)
+  refunded_amount: number // 已退金额 (实收净额 = 已收金额 - 已退金额)
   remark: string          // 备注
+  schedule_id?: number | null // 生成该款项的周期收款计划 ID
+  schedule_seq: number    // 周期收款计划中的期数
   project?: Project       // 关联项目 (可选)
 }
 
+// 周期收款计划 (按月/季/年自动生成款项)
+export interface PaymentSchedule {
+  id: number
+  project_id: number      // 关联项目 ID
+  stage: string           // 阶段名称 (生成款项名为 "阶段 第N期")
+  amount: number          // 每期金额
+  frequency: 'monthly' | 'quarterly' | 'yearly' // 周期
+  day_of_month: number    // 每期收款日 (超过当月天数取月末)
+  start_date: string      // 开始日期
+  end_date: string | null // 结束日期 (可选)
+  count: number           // 总期数 (0 表示不限)
+  method: string          // 收款方式
+  remark: string          // 备注
+  status: 'active' | 'stopped' | 'completed' // 状态
+  generated_count: number // 已生成期数
+  next_date: string | null // 下一期计划日期
+  create_time: string
+}
+
+// 创建/修改周期收款计划请求参数
+export interface PaymentScheduleRequest {
+  project_id: number
+  stage: string
+  amount: number
+  frequency: 'monthly' | 'quarterly' | 'yearly'
+  day_of_month: number
+  start_date: string
+  end_date?: string
+  count?: number
+  method?: string
+  remark?: string
+}
+
+// 收款记录 (一期款项可分多笔到账)
+export interface PaymentReceipt {
+  id: number
+  payment_id: number      // 关联款项 ID
+  project_id: number      // 关联项目 ID
+  amount: number          // 到账金额
+  received_date: string   // 到账日期
+  method: string          // 收款方式
+  reference: string       // 流水号/凭证号
+  remark: string          // 备注
+  user_id: number         // 登记人 ID
+  create_time: string
+}
+
+// 退款记录 (退款或贷项通知)
+export interface PaymentRefund {
+  id: number
+  payment_id: number      // 关联款项 ID
+  project_id: number      // 关联项目 ID
+  type: 'refund' | 'credit_note' // 退款 / 贷项通知
+  amount: number          // 退款金额
+  refund_date: string     // 退款日期
+  method: string          // 退款方式
+  reference: string       // 流水号/贷项通知单号
+  reason: string          // 退款原因
+  user_id: number         // 登记人 ID
+  create_time: string
+}
+
 // 项目列表查询参数
 export interface ProjectListParams {
   page?: number
@@ -62,6 +131,98 @@ export interface ProjectRequest {
   start_date: string
   end_date: string
   description?: string
+  rebalance_payments?: boolean // 更新时按新的合同总金额等比例调整待收款项
+}
+
+// 收款计划校验结果
+export interface PaymentPlan {
+  project_id: number
+  total_amount: number       // 合同总金额
+  planned_amount: number     // 各期款项合计
+  planned_percentage: number // 款项合计占合同总金额的百分比 (%)
+  difference: number         // 合同总金额 - 款项合计 (正数为未分配，负数为超额分配)
+  status: 'balanced' | 'under_allocated' | 'over_allocated'
+  fixed_amount: number       // 不参与调整的款项合计 (已收、部分收款及周期计划生成)
+  adjustable_amount: number  // 可调整的待收款项合计
+  adjustable_count: number   // 可调整的待收款项数量
+}
+
+// 合同变更单
+export interface ChangeOrder {
+  id: number
+  project_id: number
+  number: string          // 变更单编号 (项目内唯一)
+  date: string            // 变更日期
+  amount: number          // 变更金额 (正数为增加，负数为减少)
+  reason: string          // 变更原因
+  status: 'draft' | 'approved'
+  approved_by?: number    // 批准人 ID
+  approved_at?: string    // 批准时间
+  user_id: number
+  create_time: string
+}
+
+// 创建/修改合同变更单请求参数
+export interface ChangeOrderRequest {
+  project_id?: number     // 关联项目 (仅创建时有效)
+  number: string
+  date: string
+  amount: number
+  reason?: string
+}
+
+// 合同金额时间线
+export interface ContractTimeline {
+  project_id: number
+  original_amount: number  // 原合同金额
+  change_amount: number    // 已批准变更金额合计
+  effective_amount: number // 有效合同金额
+  entries: {
+    type: 'original' | 'change_order'
+    date: string           // 签订日期或变更日期
+    change_order_id?: number
+    number: string         // 合同编号或变更单编号
+    reason: string
+    amount: number         // 原合同金额或本次变更金额
+    total: number          // 本次变更后的有效合同金额
+    approved_by?: number
+    approved_at?: string
+  }[]
+}
+
+// 从模板创建项目请求参数 (描述与结束日期为空时取模板默认值)
+export interface ProjectFromTemplateRequest extends Omit<ProjectRequest, 'type' | 'end_date'> {
+  template_id?: number    // 模板 ID (为空时使用项目类型对应的模板)
+  type?: string
+  end_date?: string
+}
+
+// 项目模板的收款阶段
+export interface ProjectTemplateStage {
+  id?: number
+  stage: string           // 款项阶段 (payment_stage 字典值)
+  percentage: number      // 占合同总金额的百分比 (%)
+  offset_days: number     // 计划收款日期相对项目开始日期的偏移天数
+}
+
+// 项目模板 (按项目类型预置收款阶段)
+export interface ProjectTemplate {
+  id: number
+  name: string            // 模板名称
+  type: string            // 适用的项目类型
+  description: string     // 默认项目描述
+  duration_days: number   // 默认工期 (天)
+  stages: ProjectTemplateStage[]
+  create_time: string
+}
+
+// 创建/更新项目模板请求参数
+export interface ProjectTemplateRequest {
+  name: string
+  type: string
+  description?: string
+  duration_days?: number
+  stages: ProjectTemplateStage[]
 }
 
 // 创建收款请求参数
@@ -79,7 +240,41 @@ export interface PaymentRequest {
 // 确认收款请求参数
 export interface ConfirmPaymentRequest {
   actual_date: string
+  amount?: number    // 到账金额，不填则登记全部未收金额
+  method?: string
+  reference?: string // 流水号/凭证号
+}
+
+// 登记退款请求参数
+export interface RefundPaymentRequest {
+  type?: 'refund' | 'credit_note' // 默认为 refund
+  refund_date: string
+  amount: number     // 不超过款项的实收净额
   method?: string
+  reference?: string // 流水号/贷项通知单号
+  reason?: string
+}
+
+// 历史版本
+export interface Revision {
+  id: number
+  entity_type: 'project' | 'payment'
+  entity_id: number
+  version: number
+  action: string // baseline, create, update, archive, confirm, refund, restore
+  snapshot: string // 实体快照 (JSON)
+  actor_id: number
+  actor_name: string
+  create_time: string
+}
+
+// 版本差异
+export interface RevisionDiff {
+  entity_type: string
+  entity_id: number
+  from: number
+  to: number
+  changes: { field: string; from: unknown; to: unknown }[]
 }
 
 // 项目 API 集合
@@ -96,6 +291,22 @@ export const projectApi = {
   create: (data: ProjectRequest) =>
     api.post<ApiResponse<Project>>('/projects', data),
 
+  // 校验收款计划 (款项合计与合同总金额是否一致)
+  getPaymentPlan: (id: number) =>
+    api.get<ApiResponse<PaymentPlan>>(`/projects/${id}/payment-plan`, { params: { _t: Date.now() } }),
+
+  // 按合同总金额等比例调整待收款项
+  rebalancePaymentPlan: (id: number) =>
+    api.post<ApiResponse<PaymentPlan>>(`/projects/${id}/payment-plan/rebalance`),
+
+  // 获取合同金额时间线 (原合同及已批准的变更单)
+  getContractTimeline: (id: number) =>
+    api.get<ApiResponse<ContractTimeline>>(`/projects/${id}/contract-timeline`, { params: { _t: Date.now() } }),
+
+  // 从模板创建项目 (同时生成各期款项)
+  createFromTemplate: (data: ProjectFromTemplateRequest) =>
+    api.post<ApiResponse<Project>>('/projects/from-template', data),
+
   // 更新项目
   update: (id: number, data: ProjectRequest) =>
     api.put<ApiResponse<Project>>(`/projects/${id}`, data),
@@ -123,6 +334,18 @@ export const projectApi = {
     api.get<ApiResponse<{ contract_number: string }>>('/projects/generate-contract-number', { 
       params: { date, _t: Date.now() } 
     }),
+
+  // 获取项目历史版本
+  getRevisions: (id: number) =>
+    api.get<ApiResponse<Revision[]>>(`/projects/${id}/revisions`, { params: { _t: Date.now() } }),
+
+  // 对比项目的两个版本
+  diffRevisions: (id: number, from: number, to: number) =>
+    api.get<ApiResponse<RevisionDiff>>(`/projects/${id}/revisions/diff`, { params: { from, to } }),
+
+  // 恢复项目到指定版本
+  restoreRevision: (id: number, version: number) =>
+    api.post<ApiResponse<Project>>(`/projects/${id}/revisions/${version}/restore`),
 }
 
 // 收款 API 集合
@@ -145,5 +368,102 @@ export const paymentApi = {
 
   // 确认收款
   confirm: (id: number, data: ConfirmPaymentRequest) =>
-    api.post<ApiResponse<null>>(`/payments/${id}/confirm`, data),
+    api.post<ApiResponse<PaymentReceipt>>(`/payments/${id}/confirm`, data),
+
+  // 获取收款记录
+  getReceipts: (id: number) =>
+    api.get<ApiResponse<PaymentReceipt[]>>(`/payments/${id}/receipts`, { params: { _t: Date.now() } }),
+
+  // 删除收款记录
+  deleteReceipt: (id: number, receiptId: number) =>
+    api.delete<ApiResponse<null>>(`/payments/${id}/receipts/${receiptId}`),
+
+  // 登记退款 (退款或贷项通知)
+  refund: (id: number, data: RefundPaymentRequest) =>
+    api.post<ApiResponse<PaymentRefund>>(`/payments/${id}/refunds`, data),
+
+  // 获取退款记录
+  getRefunds: (id: number) =>
+    api.get<ApiResponse<PaymentRefund[]>>(`/payments/${id}/refunds`, { params: { _t: Date.now() } }),
+
+  // 删除退款记录
+  deleteRefund: (id: number, refundId: number) =>
+    api.delete<ApiResponse<null>>(`/payments/${id}/refunds/${refundId}`),
+
+  // 获取收款历史版本
+  getRevisions: (id: number) =>
+    api.get<ApiResponse<Revision[]>>(`/payments/${id}/revisions`, { params: { _t: Date.now() } }),
+
+  // 对比收款的两个版本
+  diffRevisions: (id: number, from: number, to: number) =>
+    api.get<ApiResponse<RevisionDiff>>(`/payments/${id}/revisions/diff`, { params: { from, to } }),
+
+  // 恢复收款到指定版本
+  restoreRevision: (id: number, version: number) =>
+    api.post<ApiResponse<Payment>>(`/payments/${id}/revisions/${version}/restore`),
+}
+
+// 周期收款计划 API 集合
+export const paymentScheduleApi = {
+  // 获取项目的周期收款计划
+  listByProject: (projectId: number) =>
+    api.get<ApiResponse<PaymentSchedule[]>>(`/projects/${projectId}/payment-schedules`, { params: { _t: Date.now() } }),
+
+  // 创建周期收款计划
+  create: (data: PaymentScheduleRequest) =>
+    api.post<ApiResponse<PaymentSchedule>>('/payment-schedules', data),
+
+  // 修改周期收款计划
+  update: (id: number, data: PaymentScheduleRequest) =>
+    api.put<ApiResponse<PaymentSchedule>>(`/payment-schedules/${id}`, data),
+
+  // 停止周期收款计划
+  stop: (id: number) =>
+    api.post<ApiResponse<PaymentSchedule>>(`/payment-schedules/${id}/stop`),
+}
+
+// 项目模板 API 集合
+export const projectTemplateApi = {
+  // 获取项目模板列表
+  list: () =>
+    api.get<ApiResponse<ProjectTemplate[]>>('/project-templates', { params: { _t: Date.now() } }),
+
+  // 获取项目模板详情
+  get: (id: number) =>
+    api.get<ApiResponse<ProjectTemplate>>(`/project-templates/${id}`),
+
+  // 创建项目模板
+  create: (data: ProjectTemplateRequest) =>
+    api.post<ApiResponse<ProjectTemplate>>('/project-templates', data),
+
+  // 更新项目模板
+  update: (id: number, data: ProjectTemplateRequest) =>
+    api.put<ApiResponse<ProjectTemplate>>(`/project-templates/${id}`, data),
+
+  // 删除项目模板
+  delete: (id: number) =>
+    api.delete<ApiResponse<null>>(`/project-templates/${id}`),
+}
+
+// 合同变更单 API 集合
+export const changeOrderApi = {
+  // 获取项目的变更单 (含草稿)
+  listByProject: (projectId: number) =>
+    api.get<ApiResponse<ChangeOrder[]>>(`/projects/${projectId}/change-orders`, { params: { _t: Date.now() } }),
+
+  // 创建变更单草稿
+  create: (data: ChangeOrderRequest) =>
+    api.post<ApiResponse<ChangeOrder>>('/change-orders', data),
+
+  // 修改变更单草稿
+  update: (id: number, data: ChangeOrderRequest) =>
+    api.put<ApiResponse<ChangeOrder>>(`/change-orders/${id}`, data),
+
+  // 删除变更单草稿
+  delete: (id: number) =>
+    api.delete<ApiResponse<null>>(`/change-orders/${id}`),
+
+  // 批准变更单 (仅项目所有者)
+  approve: (id: number) =>
+    api.post<ApiResponse<ChangeOrder>>(`/change-orders/${id}/approve`),
 }
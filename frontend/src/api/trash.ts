@@ -0,0 +1,34 @@
+/**
+ * @file api/trash.ts
+ * @description 回收站相关 API
+ */
+import api, { type ApiResponse } from './index'
+import type { Payment, Project } from './project'
+import type { User } from './auth'
+
+export interface TrashItem<T> {
+  id: number
+  type: 'project' | 'payment' | 'user'
+  name: string // 项目名称 / 款项阶段 / 用户名
+  detail: string // 客户 / 所属项目 / 姓名
+  deleted_at: string
+  purge_at: string | null // 预计自动彻底删除时间 (未启用自动清理时为 null)
+  data: T // 删除前的完整记录
+}
+
+export const trashApi = {
+  // 项目 (恢复时随项目删除的款项一并恢复)
+  listProjects: () => api.get<ApiResponse<TrashItem<Project>[]>>('/trash/projects'),
+  restoreProject: (id: number) => api.post<ApiResponse<Project>>(`/trash/projects/${id}/restore`),
+  purgeProject: (id: number) => api.delete<ApiResponse<null>>(`/trash/projects/${id}`),
+
+  // 款项
+  listPayments: () => api.get<ApiResponse<TrashItem<Payment>[]>>('/trash/payments'),
+  restorePayment: (id: number) => api.post<ApiResponse<Payment>>(`/trash/payments/${id}/restore`),
+  purgePayment: (id: number) => api.delete<ApiResponse<null>>(`/trash/payments/${id}`),
+
+  // 用户 (管理员)
+  listUsers: () => api.get<ApiResponse<TrashItem<User>[]>>('/trash/users'),
+  restoreUser: (id: number) => api.post<ApiResponse<User>>(`/trash/users/${id}/restore`),
+  purgeUser: (id: number) => api.delete<ApiResponse<null>>(`/trash/users/${id}`),
+}
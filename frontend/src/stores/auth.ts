@@ -5,7 +5,7 @@
  */
 import { ref, computed } from 'vue'
 import { defineStore } from 'pinia'
-import { authApi, type User, type LoginRequest } from '@/api/auth'
+import { authApi, type User, type LoginRequest, type LoginResponse } from '@/api/auth'
 
 export const useAuthStore = defineStore('auth', () => {
   // State: 从 localStorage 初始化状态，实现持久化
@@ -15,44 +15,92 @@ export const useAuthStore = defineStore('auth', () => {
   )
   const loading = ref(false) // 异步操作加载状态
   const error = ref<string | null>(null) // 错误信息
+  const challengeToken = ref<string | null>(null) // 两步验证质询令牌 (密码校验通过后暂存)
 
   // Getters (Computed)
   // 判断是否有 Token
   const isLoggedIn = computed(() => !!token.value)
   // 与 isLoggedIn 相同，可根据业务扩展
   const isAuthenticated = computed(() => !!token.value)
+  // 是否等待提交两步验证码
+  const twoFactorRequired = computed(() => !!challengeToken.value)
+
+  /**
+   * 保存登录结果
+   * @param data 登录或两步验证接口返回的令牌与用户信息
+   */
+  function saveSession(data: LoginResponse) {
+    // 保存到 state
+    token.value = data.token
+    user.value = data.user
+    challengeToken.value = null
+
+    // 保存到 localStorage
+    localStorage.setItem('token', data.token)
+    localStorage.setItem('refresh_token', data.refresh_token)
+    localStorage.setItem('user', JSON.stringify(data.user))
+    localStorage.setItem('isAuthenticated', 'true')
+  }
 
   /**
    * 用户登录
+   * 用户启用两步验证时，暂存质询令牌并返回 false，由页面提示输入验证码后调用 verifyTwoFactor。
    * @param credentials 登录凭证 (username, password)
-   * @returns 登录成功返回 true, 失败返回 false
+   * @returns 登录成功返回 true, 失败或需要两步验证返回 false
    */
   async function login(credentials: LoginRequest) {
     loading.value = true
     error.value = null
+    challengeToken.value = null
 
     try {
       const response = await authApi.login(credentials)
-      const { token: newToken, user: userData } = response.data.data
+      const data = response.data.data
+      if (data.two_factor_required) {
+        challengeToken.value = data.challenge_token ?? null
+        return false
+      }
 
-      // 保存到 state
-      token.value = newToken
-      user.value = userData
+      saveSession(data)
+      return true
+    } catch (err: unknown) {
+      error.value = err instanceof Error ? err.message : '登录失败'
+      return false
+    } finally {
+      loading.value = false
+    }
+  }
 
-      // 保存到 localStorage
-      localStorage.setItem('token', newToken)
-      localStorage.setItem('user', JSON.stringify(userData))
-      localStorage.setItem('isAuthenticated', 'true')
+  /**
+   * 两步验证登录
+   * @param code 验证器验证码或恢复码
+   * @returns 登录成功返回 true, 失败返回 false
+   */
+  async function verifyTwoFactor(code: string) {
+    if (!challengeToken.value) return false
+    loading.value = true
+    error.value = null
 
+    try {
+      const response = await authApi.verifyTwoFactor({ challenge_token: challengeToken.value, code })
+      saveSession(response.data.data)
       return true
     } catch (err: unknown) {
-      error.value = err instanceof Error ? err.message : '登录失败'
+      error.value = err instanceof Error ? err.message : '验证失败'
       return false
     } finally {
       loading.value = false
     }
   }
 
+  /**
+   * 取消两步验证，返回密码登录
+   */
+  function cancelTwoFactor() {
+    challengeToken.value = null
+    error.value = null
+  }
+
   /**
    * 用户注册
    * @param data 注册信息
@@ -78,7 +126,7 @@ export const useAuthStore = defineStore('auth', () => {
    */
   async function logout() {
     try {
-      await authApi.logout()
+      await authApi.logout(localStorage.getItem('refresh_token'))
     } catch {
       // 忽略错误，仍然清除本地状态
     }
@@ -89,6 +137,7 @@ export const useAuthStore = defineStore('auth', () => {
 
     // 清除 localStorage
     localStorage.removeItem('token')
+    localStorage.removeItem('refresh_token')
     localStorage.removeItem('user')
     localStorage.removeItem('isAuthenticated')
   }
@@ -160,8 +209,11 @@ export const useAuthStore = defineStore('auth', () => {
     // Computed
     isLoggedIn,
     isAuthenticated,
+    twoFactorRequired,
     // Actions
     login,
+    verifyTwoFactor,
+    cancelTwoFactor,
     register,
     logout,
     refreshUser,
@@ -27,16 +27,36 @@ type Config struct {
 	APIServerPort   int  // 对外 API 服务端口 (默认 3456)
 	EnableAPIServer bool // 是否启用对外 API 服务
 
-	JWTSecret     string // JWT 签名密钥
-	TokenExpiry   int64  // Token 有效期 (单位: 小时)
-	LogEnable     bool   // 是否启用请求日志
-	LogLevel      string // 日志级别: debug, info, warn, error
-	GitHubRepo    string // 用于检查更新的 GitHub 仓库地址 (格式: owner/repo)
-	LogPath       string // 日志文件输出路径
-	LogMaxSize    int    // 单个日志文件最大大小 (MB)
-	LogMaxBackups int    // 保留旧日志文件的最大个数
-	LogMaxAge     int    // 保留旧日志文件的最大天数
-	LogCompress   bool   // 是否压缩旧日志文件
+	JWTSecret         string // JWT 签名密钥
+	AccessTokenExpiry int64  // 访问令牌 (JWT) 有效期 (单位: 分钟)，过期后使用刷新令牌换取
+	TokenExpiry       int64  // 登录会话 (刷新令牌) 有效期 (单位: 小时)，每次刷新后顺延
+	LogEnable         bool   // 是否启用请求日志
+	LogLevel          string // 日志级别: debug, info, warn, error
+	GitHubRepo        string // 用于检查更新的 GitHub 仓库地址 (格式: owner/repo)
+	LogPath           string // 日志文件输出路径
+	LogMaxSize        int    // 单个日志文件最大大小 (MB)
+	LogMaxBackups     int    // 保留旧日志文件的最大个数
+	LogMaxAge         int    // 保留旧日志文件的最大天数
+	LogCompress       bool   // 是否压缩旧日志文件
+
+	// 登录保护配置
+	LoginMaxFailures    int // 连续登录失败多少次后锁定账户 (0 表示不锁定，仅退避)
+	LoginLockoutMinutes int // 账户锁定时长 (单位: 分钟)
+
+	// 备份配置
+	BackupDir  string // 备份文件目录 (默认位于数据库文件同级的 backups 子目录)
+	BackupKeep int    // 保留的备份个数 (超出时删除最旧的备份，0 表示不限制)
+
+	// 回收站配置
+	TrashRetentionDays int // 回收站保留天数 (超出后自动彻底删除，0 表示不自动清理)
+
+	// 周期收款配置
+	PaymentScheduleHorizonDays int // 周期收款计划提前生成款项的天数 (0 表示仅生成已到期的款项)
+
+	// 数据同步配置
+	OfflineSyncDir       string // 离线同步文件目录 (导出与导入均限定在此目录，默认位于数据库文件同级的 offline-sync 子目录)
+	SyncSecretKey        string // 同步配置中云端数据库密码的加密密钥 (默认复用 JWT 密钥)
+	SyncSchedulerEnabled bool   // 是否启用后台定时同步
 }
 
 // AppConfig 全局配置实例
@@ -98,7 +118,7 @@ func Load() {
 		EnableAPIServer: getEnvBool("ENABLE_API_SERVER", true),
 
 		JWTSecret:     getEnv("JWT_SECRET", "orange-secret-key-change-in-production"),
-		TokenExpiry:   getEnvInt("TOKEN_EXPIRY", 24),
+		TokenExpiry:   getEnvInt("TOKEN_EXPIRY", 168),
 		LogEnable:     getEnvBool("LOG_ENABLE", true),
 		LogLevel:      getEnv("LOG_LEVEL", "debug"),
 		GitHubRepo:    getEnv("GITHUB_REPO", "FruitsAI/Orange"),
@@ -107,7 +127,18 @@ func Load() {
 		LogMaxBackups: int(getEnvInt("LOG_MAX_BACKUPS", 5)), // 5 files
 		LogMaxAge:     int(getEnvInt("LOG_MAX_AGE", 30)),    // 30 days
 		LogCompress:   getEnvBool("LOG_COMPRESS", true),     // Compress by default
+
+		SyncSchedulerEnabled: getEnvBool("SYNC_SCHEDULER_ENABLED", true),
 	}
+	AppConfig.AccessTokenExpiry = getEnvInt("ACCESS_TOKEN_EXPIRY", 15)
+	AppConfig.SyncSecretKey = getEnv("SYNC_SECRET_KEY", AppConfig.JWTSecret)
+	AppConfig.OfflineSyncDir = getEnv("OFFLINE_SYNC_DIR", filepath.Join(filepath.Dir(AppConfig.DBPath), "offline-sync"))
+	AppConfig.BackupDir = getEnv("BACKUP_DIR", filepath.Join(filepath.Dir(AppConfig.DBPath), "backups"))
+	AppConfig.BackupKeep = int(getEnvInt("BACKUP_KEEP", 10))
+	AppConfig.LoginMaxFailures = int(getEnvInt("LOGIN_MAX_FAILURES", 5))
+	AppConfig.LoginLockoutMinutes = int(getEnvInt("LOGIN_LOCKOUT_MINUTES", 15))
+	AppConfig.TrashRetentionDays = int(getEnvInt("TRASH_RETENTION_DAYS", 30))
+	AppConfig.PaymentScheduleHorizonDays = int(getEnvInt("PAYMENT_SCHEDULE_HORIZON_DAYS", 30))
 }
 
 // getEnvBool 获取布尔类型的环境变量
@@ -86,6 +86,11 @@ func initDB() (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// 注册删除墓碑回调 (用于增量同步传播删除)
+	if err := registerTombstoneCallbacks(database); err != nil {
+		return nil, err
+	}
+
 	return database, nil
 }
 
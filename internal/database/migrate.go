@@ -0,0 +1,310 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationFS 内嵌的版本迁移脚本
+// 目录结构: migrations/<dialect>/<version>_<name>.up.sql / .down.sql，三种数据库各自维护一份。
+//
+//go:embed migrations
+var migrationFS embed.FS
+
+// MigrationTable 记录已执行迁移版本的表
+const MigrationTable = "schema_migrations"
+
+// MoneyMinorUnitsVersion 金额由元 (浮点) 改为以分为单位整数存储的迁移版本 (000013_money_minor_units)
+// 更早版本的备份与离线数据包中金额仍以元存储，不能直接写入当前表结构。
+const MoneyMinorUnitsVersion = 13
+
+// baselineTable 用于识别未引入版本迁移前 (由 AutoMigrate 创建) 的旧数据库
+const baselineTable = "users"
+
+// migrationTableDDL 各数据库的迁移版本表建表语句
+var migrationTableDDL = map[string]string{
+	"sqlite":   "CREATE TABLE IF NOT EXISTS `schema_migrations` (`version` integer PRIMARY KEY, `name` text NOT NULL, `applied_at` datetime NOT NULL)",
+	"mysql":    "CREATE TABLE IF NOT EXISTS `schema_migrations` (`version` bigint NOT NULL, `name` varchar(255) NOT NULL, `applied_at` datetime(3) NOT NULL, PRIMARY KEY (`version`))",
+	"postgres": `CREATE TABLE IF NOT EXISTS "schema_migrations" ("version" bigint NOT NULL, "name" varchar(255) NOT NULL, "applied_at" timestamptz NOT NULL, PRIMARY KEY ("version"))`,
+}
+
+// Migration 单个版本迁移
+type Migration struct {
+	Version int64  // 版本号 (文件名前缀，严格递增)
+	Name    string // 迁移名称
+	Up      string // 升级脚本
+	Down    string // 回滚脚本
+}
+
+// MigrationStatus 迁移执行状态
+type MigrationStatus struct {
+	Version   int64      `json:"version"`    // 版本号
+	Name      string     `json:"name"`       // 迁移名称
+	Applied   bool       `json:"applied"`    // 是否已执行
+	AppliedAt *time.Time `json:"applied_at"` // 执行时间
+}
+
+// appliedMigration schema_migrations 表中的记录
+type appliedMigration struct {
+	Version   int64
+	Name      string
+	AppliedAt time.Time
+}
+
+// migrationDialect 返回当前数据库对应的迁移脚本目录 (未知类型按 sqlite 处理，与 initDB 一致)
+func migrationDialect() string {
+	switch GetDBType() {
+	case "mysql", "postgres":
+		return GetDBType()
+	default:
+		return "sqlite"
+	}
+}
+
+// LoadMigrations 加载指定数据库类型的全部迁移 (按版本升序)
+// 每个版本必须同时提供 up 与 down 脚本，版本号不可重复。
+func LoadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFS, dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取迁移目录失败: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		versionPart, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("迁移文件名格式错误: %s", fileName)
+		}
+		version, err := strconv.ParseInt(versionPart, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("迁移文件版本号错误: %s", fileName)
+		}
+
+		content, err := fs.ReadFile(migrationFS, path.Join(dir, fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("迁移版本 %d 重复: %s / %s", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("迁移 %d_%s 缺少 up 或 down 脚本", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrate 执行全部未执行的迁移
+// 数据库中存在程序未知的 (更高) 版本时拒绝执行，避免旧版程序写坏新版数据库。
+func Migrate(db *gorm.DB) error {
+	migrations, applied, err := prepareMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	// 旧数据库 (引入版本迁移前由 AutoMigrate 建表) 视为已执行初始迁移
+	if len(applied) == 0 && len(migrations) > 0 && db.Migrator().HasTable(baselineTable) {
+		baseline := migrations[0]
+		slog.Info("Adopting existing database as baseline migration", "version", baseline.Version, "name", baseline.Name)
+		if err := recordMigration(db, baseline); err != nil {
+			return err
+		}
+		applied[baseline.Version] = appliedMigration{Version: baseline.Version, Name: baseline.Name}
+	}
+
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		slog.Info("Applying migration", "version", m.Version, "name", m.Name)
+		// MySQL 的 DDL 会隐式提交，无法整体回滚；SQLite 与 PostgreSQL 中单个迁移是原子的
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, m.Up); err != nil {
+				return err
+			}
+			return recordMigration(tx, m)
+		})
+		if err != nil {
+			return fmt.Errorf("执行迁移 %d_%s 失败: %w", m.Version, m.Name, err)
+		}
+	}
+	return nil
+}
+
+// SchemaVersion 获取数据库已执行的最高迁移版本 (未执行任何迁移时返回 0)
+func SchemaVersion(db *gorm.DB) (int64, error) {
+	var version int64
+	err := db.Table(MigrationTable).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	return version, err
+}
+
+// LatestVersion 获取程序内嵌的最高迁移版本 (当前数据库类型)
+func LatestVersion() (int64, error) {
+	migrations, err := LoadMigrations(migrationDialect())
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// MigrateDown 按版本倒序回滚最近执行的 steps 个迁移
+func MigrateDown(db *gorm.DB, steps int) error {
+	migrations, applied, err := prepareMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+		m := migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		slog.Info("Reverting migration", "version", m.Version, "name", m.Name)
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := execScript(tx, m.Down); err != nil {
+				return err
+			}
+			return tx.Exec("DELETE FROM "+MigrationTable+" WHERE version = ?", m.Version).Error
+		})
+		if err != nil {
+			return fmt.Errorf("回滚迁移 %d_%s 失败: %w", m.Version, m.Name, err)
+		}
+		steps--
+	}
+	return nil
+}
+
+// MigrationStatuses 获取全部迁移的执行状态
+func MigrationStatuses(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, applied, err := prepareMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if a, ok := applied[m.Version]; ok {
+			status.Applied = true
+			appliedAt := a.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// prepareMigrations 加载迁移脚本与已执行版本，并校验数据库版本不高于程序
+func prepareMigrations(db *gorm.DB) ([]Migration, map[int64]appliedMigration, error) {
+	dialect := migrationDialect()
+	migrations, err := LoadMigrations(dialect)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := db.Exec(migrationTableDDL[dialect]).Error; err != nil {
+		return nil, nil, fmt.Errorf("创建迁移版本表失败: %w", err)
+	}
+	var rows []appliedMigration
+	if err := db.Table(MigrationTable).Order("version ASC").Find(&rows).Error; err != nil {
+		return nil, nil, fmt.Errorf("读取迁移版本失败: %w", err)
+	}
+
+	known := make(map[int64]bool, len(migrations))
+	var latest int64
+	for _, m := range migrations {
+		known[m.Version] = true
+		latest = m.Version
+	}
+
+	applied := make(map[int64]appliedMigration, len(rows))
+	for _, row := range rows {
+		if row.Version > latest {
+			return nil, nil, fmt.Errorf("数据库版本 (%d) 高于程序支持的版本 (%d)，请升级应用", row.Version, latest)
+		}
+		if !known[row.Version] {
+			return nil, nil, fmt.Errorf("数据库包含未知的迁移版本: %d_%s", row.Version, row.Name)
+		}
+		applied[row.Version] = row
+	}
+	return migrations, applied, nil
+}
+
+// recordMigration 写入迁移执行记录
+func recordMigration(db *gorm.DB, m Migration) error {
+	return db.Exec("INSERT INTO "+MigrationTable+" (version, name, applied_at) VALUES (?, ?, ?)", m.Version, m.Name, time.Now()).Error
+}
+
+// execScript 逐条执行迁移脚本
+// 驱动不一定支持单次执行多条语句 (如 MySQL 默认关闭 multiStatements)，按行尾分号拆分后逐条执行。
+func execScript(db *gorm.DB, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements 按行尾分号拆分 SQL 脚本，忽略 "--" 注释行
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
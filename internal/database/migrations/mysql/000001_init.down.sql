@@ -0,0 +1,9 @@
+-- 初始表结构
+DROP TABLE IF EXISTS `personal_access_tokens`;
+DROP TABLE IF EXISTS `user_notifications`;
+DROP TABLE IF EXISTS `notifications`;
+DROP TABLE IF EXISTS `dictionary_item`;
+DROP TABLE IF EXISTS `dictionaries`;
+DROP TABLE IF EXISTS `payments`;
+DROP TABLE IF EXISTS `projects`;
+DROP TABLE IF EXISTS `users`;
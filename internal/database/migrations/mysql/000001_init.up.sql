@@ -0,0 +1,130 @@
+-- 初始表结构
+CREATE TABLE `users` (
+  `id` bigint AUTO_INCREMENT,
+  `username` varchar(50) NOT NULL,
+  `password` varchar(100) NOT NULL,
+  `name` varchar(50) NOT NULL,
+  `email` varchar(100),
+  `phone` varchar(20),
+  `avatar` varchar(255),
+  `role` varchar(20) NOT NULL DEFAULT 'user',
+  `department` varchar(50),
+  `position` varchar(50),
+  `status` bigint DEFAULT 1,
+  `last_login_time` datetime(3) NULL,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_users_username` (`username`)
+);
+
+CREATE TABLE `projects` (
+  `id` bigint AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL,
+  `company` varchar(100) NOT NULL,
+  `total_amount` real NOT NULL,
+  `received_amount` real DEFAULT 0,
+  `status` varchar(20) NOT NULL,
+  `type` varchar(50) NOT NULL,
+  `contract_number` varchar(50),
+  `contract_date` date,
+  `payment_method` varchar(30),
+  `start_date` date NOT NULL,
+  `end_date` date NOT NULL,
+  `description` longtext,
+  `user_id` bigint NOT NULL,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_projects_user_id` (`user_id`),
+  CONSTRAINT `fk_projects_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `payments` (
+  `id` bigint AUTO_INCREMENT,
+  `project_id` bigint NOT NULL,
+  `stage` varchar(50) NOT NULL,
+  `amount` real NOT NULL,
+  `percentage` real,
+  `plan_date` date NOT NULL,
+  `status` varchar(20) NOT NULL,
+  `actual_date` date,
+  `method` varchar(30),
+  `remark` varchar(255),
+  `user_id` bigint NOT NULL,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_payments_project_id` (`project_id`),
+  INDEX `idx_payments_plan_date` (`plan_date`),
+  INDEX `idx_payments_status` (`status`),
+  CONSTRAINT `fk_projects_payments` FOREIGN KEY (`project_id`) REFERENCES `projects`(`id`)
+);
+
+CREATE TABLE `dictionaries` (
+  `id` bigint AUTO_INCREMENT,
+  `code` varchar(50) NOT NULL,
+  `name` varchar(50) NOT NULL,
+  `status` bigint DEFAULT 1,
+  `remark` varchar(255),
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_dictionaries_code` (`code`)
+);
+
+CREATE TABLE `dictionary_item` (
+  `id` bigint AUTO_INCREMENT,
+  `dictionary_id` bigint NOT NULL,
+  `label` varchar(50) NOT NULL,
+  `value` varchar(50) NOT NULL,
+  `sort` bigint DEFAULT 0,
+  `status` bigint DEFAULT 1,
+  `remark` varchar(255),
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_dictionary_item_dictionary_id` (`dictionary_id`),
+  CONSTRAINT `fk_dictionaries_items` FOREIGN KEY (`dictionary_id`) REFERENCES `dictionaries`(`id`)
+);
+
+CREATE TABLE `notifications` (
+  `id` bigint AUTO_INCREMENT,
+  `title` varchar(100) NOT NULL,
+  `content` longtext NOT NULL,
+  `type` bigint DEFAULT 1,
+  `sender_id` bigint NOT NULL,
+  `is_global` bigint DEFAULT 0,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  `is_read` boolean,
+  PRIMARY KEY (`id`),
+  INDEX `idx_notifications_sender_id` (`sender_id`)
+);
+
+CREATE TABLE `user_notifications` (
+  `id` bigint AUTO_INCREMENT,
+  `user_id` bigint NOT NULL,
+  `notification_id` bigint NOT NULL,
+  `is_read` bigint DEFAULT 0,
+  `read_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_user_notification` (`user_id`,`notification_id`)
+);
+
+CREATE TABLE `personal_access_tokens` (
+  `id` bigint AUTO_INCREMENT,
+  `user_id` bigint NOT NULL,
+  `name` varchar(50) NOT NULL,
+  `token_hash` varchar(100) NOT NULL,
+  `scopes` varchar(255) DEFAULT '',
+  `status` bigint DEFAULT 1,
+  `last_used_at` datetime(3) NULL,
+  `expires_at` datetime(3) NULL,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_personal_access_tokens_user_id` (`user_id`),
+  INDEX `idx_personal_access_tokens_token_hash` (`token_hash`),
+  CONSTRAINT `fk_personal_access_tokens_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
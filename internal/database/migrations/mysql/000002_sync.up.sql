@@ -0,0 +1,86 @@
+-- 数据同步相关表
+ALTER TABLE `user_notifications` ADD `update_time` datetime(3) NULL;
+
+CREATE TABLE `sync_tombstones` (
+  `id` bigint AUTO_INCREMENT,
+  `table_name` varchar(50) NOT NULL,
+  `record_id` bigint NOT NULL,
+  `delete_time` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_sync_tombstone` (`table_name`,`delete_time`)
+);
+
+CREATE TABLE `sync_checkpoints` (
+  `id` bigint AUTO_INCREMENT,
+  `target` varchar(255) NOT NULL,
+  `table_name` varchar(50) NOT NULL,
+  `last_sync_time` datetime(3) NOT NULL,
+  `remote_sync_time` datetime(3) NULL,
+  `synced_count` bigint DEFAULT 0,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_sync_checkpoint` (`target`,`table_name`)
+);
+
+CREATE TABLE `sync_row_versions` (
+  `id` bigint AUTO_INCREMENT,
+  `target` varchar(255) NOT NULL,
+  `table_name` varchar(50) NOT NULL,
+  `record_id` bigint NOT NULL,
+  `hash` varchar(64) NOT NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_sync_row_version` (`target`,`table_name`,`record_id`)
+);
+
+CREATE TABLE `sync_profiles` (
+  `id` bigint AUTO_INCREMENT,
+  `name` varchar(50) NOT NULL,
+  `db_type` varchar(20) NOT NULL,
+  `host` varchar(255),
+  `port` bigint,
+  `db_user` varchar(100),
+  `password` varchar(500),
+  `db_name` varchar(100),
+  `ssl_mode` varchar(20),
+  `path` varchar(500),
+  `tables` varchar(500),
+  `direction` varchar(20) NOT NULL,
+  `atomic` bigint,
+  `interval_minutes` bigint,
+  `enabled` bigint,
+  `last_run_time` datetime(3) NULL,
+  `last_status` varchar(20),
+  `next_run_time` datetime(3) NULL,
+  `user_id` bigint NOT NULL,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_sync_profiles_name` (`name`),
+  INDEX `idx_sync_profiles_next_run_time` (`next_run_time`)
+);
+
+CREATE TABLE `sync_history` (
+  `id` bigint AUTO_INCREMENT,
+  `profile_id` bigint,
+  `profile_name` varchar(50),
+  `target` varchar(255),
+  `trigger` varchar(20) NOT NULL,
+  `direction` varchar(20),
+  `status` varchar(20) NOT NULL,
+  `start_time` datetime(3) NOT NULL,
+  `end_time` datetime(3) NULL,
+  `synced_count` bigint,
+  `pulled_count` bigint,
+  `deleted_count` bigint,
+  `conflict_count` bigint,
+  `results` longtext,
+  `error_message` longtext,
+  `user_id` bigint,
+  `create_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_sync_history_profile_id` (`profile_id`),
+  INDEX `idx_sync_history_status` (`status`),
+  INDEX `idx_sync_history_start_time` (`start_time`)
+);
@@ -0,0 +1,14 @@
+-- 项目成员 (项目共享)
+CREATE TABLE `project_members` (
+  `id` bigint AUTO_INCREMENT,
+  `project_id` bigint NOT NULL,
+  `user_id` bigint NOT NULL,
+  `role` varchar(20) NOT NULL,
+  `invited_by` bigint,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_project_member` (`project_id`,`user_id`),
+  INDEX `idx_project_members_user_id` (`user_id`),
+  CONSTRAINT `fk_project_members_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
@@ -0,0 +1,21 @@
+-- 角色与权限
+CREATE TABLE `roles` (
+  `id` bigint AUTO_INCREMENT,
+  `code` varchar(50) NOT NULL,
+  `name` varchar(50) NOT NULL,
+  `description` varchar(255),
+  `is_builtin` bigint DEFAULT 0,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_roles_code` (`code`)
+);
+
+CREATE TABLE `role_permissions` (
+  `id` bigint AUTO_INCREMENT,
+  `role_id` bigint NOT NULL,
+  `permission` varchar(50) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_role_permission` (`role_id`,`permission`),
+  CONSTRAINT `fk_roles_permissions` FOREIGN KEY (`role_id`) REFERENCES `roles`(`id`)
+);
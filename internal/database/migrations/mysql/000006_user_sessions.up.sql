@@ -0,0 +1,18 @@
+-- 登录会话
+CREATE TABLE `user_sessions` (
+  `id` bigint AUTO_INCREMENT,
+  `user_id` bigint NOT NULL,
+  `refresh_token_hash` varchar(100) NOT NULL,
+  `previous_token_hash` varchar(100),
+  `user_agent` varchar(255),
+  `ip` varchar(64),
+  `last_used_at` datetime(3) NULL,
+  `expires_at` datetime(3) NOT NULL,
+  `revoked_at` datetime(3) NULL,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_user_sessions_user_id` (`user_id`),
+  UNIQUE INDEX `idx_user_sessions_refresh_token_hash` (`refresh_token_hash`),
+  INDEX `idx_user_sessions_previous_token_hash` (`previous_token_hash`)
+);
@@ -0,0 +1,23 @@
+-- 两步验证
+CREATE TABLE `user_two_factors` (
+  `id` bigint AUTO_INCREMENT,
+  `user_id` bigint NOT NULL,
+  `secret` varchar(255) NOT NULL,
+  `enabled` bigint DEFAULT 0,
+  `last_used_step` bigint DEFAULT 0,
+  `enabled_at` datetime(3) NULL,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_user_two_factors_user_id` (`user_id`)
+);
+
+CREATE TABLE `user_recovery_codes` (
+  `id` bigint AUTO_INCREMENT,
+  `user_id` bigint NOT NULL,
+  `code_hash` varchar(100) NOT NULL,
+  `used_at` datetime(3) NULL,
+  `create_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_user_recovery_codes_user_id` (`user_id`)
+);
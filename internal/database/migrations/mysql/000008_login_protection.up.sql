@@ -0,0 +1,25 @@
+-- 登录保护
+CREATE TABLE `login_throttles` (
+  `id` bigint AUTO_INCREMENT,
+  `throttle_key` varchar(150) NOT NULL,
+  `failures` bigint DEFAULT 0,
+  `last_failure_at` datetime(3) NULL,
+  `locked_until` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_login_throttles_key` (`throttle_key`)
+);
+
+CREATE TABLE `login_lockouts` (
+  `id` bigint AUTO_INCREMENT,
+  `user_id` bigint,
+  `username` varchar(100),
+  `ip` varchar(64),
+  `failures` bigint,
+  `locked_until` datetime(3) NULL,
+  `unlocked_at` datetime(3) NULL,
+  `unlocked_by` bigint,
+  `create_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_login_lockouts_user_id` (`user_id`)
+);
@@ -0,0 +1,3 @@
+-- 审计日志
+DELETE FROM role_permissions WHERE permission = 'audit:read';
+DROP TABLE IF EXISTS `audit_logs`;
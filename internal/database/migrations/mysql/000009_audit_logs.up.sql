@@ -0,0 +1,23 @@
+-- 审计日志
+CREATE TABLE `audit_logs` (
+  `id` bigint AUTO_INCREMENT,
+  `actor_id` bigint,
+  `actor_name` varchar(50),
+  `source` varchar(20),
+  `access_token_id` bigint,
+  `ip` varchar(64),
+  `action` varchar(50) NOT NULL,
+  `entity_type` varchar(50) NOT NULL,
+  `entity_id` bigint,
+  `before_data` text,
+  `after_data` text,
+  `create_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_audit_logs_actor_id` (`actor_id`),
+  INDEX `idx_audit_logs_action` (`action`),
+  INDEX `idx_audit_entity` (`entity_type`,`entity_id`),
+  INDEX `idx_audit_logs_create_time` (`create_time`)
+);
+
+-- 内置审计员角色授予查看审计日志权限 (新数据库由初始化数据写入)
+INSERT INTO role_permissions (role_id, permission) SELECT id, 'audit:read' FROM roles WHERE code = 'auditor';
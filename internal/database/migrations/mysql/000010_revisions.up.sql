@@ -0,0 +1,14 @@
+-- 数据版本 (项目与款项历史快照)
+CREATE TABLE `revisions` (
+  `id` bigint AUTO_INCREMENT,
+  `entity_type` varchar(20) NOT NULL,
+  `entity_id` bigint NOT NULL,
+  `version` bigint NOT NULL,
+  `action` varchar(20) NOT NULL,
+  `snapshot` text NOT NULL,
+  `actor_id` bigint,
+  `actor_name` varchar(50),
+  `create_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_revision_version` (`entity_type`,`entity_id`,`version`)
+);
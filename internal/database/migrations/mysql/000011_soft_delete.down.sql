@@ -0,0 +1,7 @@
+-- 软删除 (回收站)
+DROP INDEX `idx_payments_deleted_at` ON `payments`;
+ALTER TABLE `payments` DROP COLUMN `deleted_at`;
+DROP INDEX `idx_projects_deleted_at` ON `projects`;
+ALTER TABLE `projects` DROP COLUMN `deleted_at`;
+DROP INDEX `idx_users_deleted_at` ON `users`;
+ALTER TABLE `users` DROP COLUMN `deleted_at`;
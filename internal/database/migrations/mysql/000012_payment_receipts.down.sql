@@ -0,0 +1,5 @@
+-- 收款记录
+UPDATE `payments` SET `status` = 'pending' WHERE `status` = 'partially_paid';
+DROP TABLE IF EXISTS `payment_receipts`;
+ALTER TABLE `payments` DROP COLUMN `received_percentage`;
+ALTER TABLE `payments` DROP COLUMN `received_amount`;
@@ -0,0 +1,12 @@
+-- 金额以分为单位的整数存储
+ALTER TABLE `projects` MODIFY `total_amount` real NOT NULL;
+UPDATE `projects` SET `total_amount` = `total_amount` / 100;
+ALTER TABLE `projects` MODIFY `received_amount` real DEFAULT 0;
+UPDATE `projects` SET `received_amount` = `received_amount` / 100;
+ALTER TABLE `payments` MODIFY `amount` real NOT NULL;
+UPDATE `payments` SET `amount` = `amount` / 100;
+ALTER TABLE `payments` MODIFY `received_amount` real DEFAULT 0;
+UPDATE `payments` SET `received_amount` = `received_amount` / 100;
+ALTER TABLE `payment_receipts` MODIFY `amount` real NOT NULL;
+UPDATE `payment_receipts` SET `amount` = `amount` / 100;
+DELETE FROM `sync_checkpoints` WHERE `table_name` IN ('projects', 'payments', 'payment_receipts');
@@ -0,0 +1,14 @@
+-- 金额以分为单位的整数存储
+UPDATE `projects` SET `total_amount` = ROUND(`total_amount` * 100);
+ALTER TABLE `projects` MODIFY `total_amount` bigint NOT NULL;
+UPDATE `projects` SET `received_amount` = ROUND(`received_amount` * 100);
+ALTER TABLE `projects` MODIFY `received_amount` bigint DEFAULT 0;
+UPDATE `payments` SET `amount` = ROUND(`amount` * 100);
+ALTER TABLE `payments` MODIFY `amount` bigint NOT NULL;
+UPDATE `payments` SET `received_amount` = ROUND(`received_amount` * 100);
+ALTER TABLE `payments` MODIFY `received_amount` bigint DEFAULT 0;
+UPDATE `payment_receipts` SET `amount` = ROUND(`amount` * 100);
+ALTER TABLE `payment_receipts` MODIFY `amount` bigint NOT NULL;
+UPDATE `projects` SET `received_amount` = (SELECT COALESCE(SUM(`received_amount`), 0) FROM `payments` WHERE `payments`.`project_id` = `projects`.`id` AND `payments`.`deleted_at` IS NULL);
+DELETE FROM `sync_checkpoints` WHERE `table_name` IN ('projects', 'payments', 'payment_receipts');
+DELETE FROM `sync_row_versions` WHERE `table_name` IN ('projects', 'payments', 'payment_receipts');
@@ -0,0 +1,5 @@
+-- 周期收款计划
+DROP TABLE IF EXISTS `payment_schedules`;
+DROP INDEX `idx_payments_schedule_id` ON `payments`;
+ALTER TABLE `payments` DROP COLUMN `schedule_seq`;
+ALTER TABLE `payments` DROP COLUMN `schedule_id`;
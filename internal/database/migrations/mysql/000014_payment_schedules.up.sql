@@ -0,0 +1,27 @@
+-- 周期收款计划
+CREATE TABLE `payment_schedules` (
+  `id` bigint AUTO_INCREMENT,
+  `project_id` bigint NOT NULL,
+  `stage` varchar(40) NOT NULL,
+  `amount` bigint NOT NULL,
+  `frequency` varchar(20) NOT NULL,
+  `day_of_month` bigint NOT NULL,
+  `start_date` date NOT NULL,
+  `end_date` date,
+  `count` bigint DEFAULT 0,
+  `method` varchar(30),
+  `remark` varchar(255),
+  `status` varchar(20) NOT NULL,
+  `generated_count` bigint DEFAULT 0,
+  `next_date` date,
+  `user_id` bigint NOT NULL,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_payment_schedules_project_id` (`project_id`),
+  INDEX `idx_payment_schedules_status` (`status`),
+  INDEX `idx_payment_schedules_next_date` (`next_date`)
+);
+ALTER TABLE `payments` ADD `schedule_id` bigint;
+CREATE INDEX `idx_payments_schedule_id` ON `payments`(`schedule_id`);
+ALTER TABLE `payments` ADD `schedule_seq` bigint DEFAULT 0;
@@ -0,0 +1,3 @@
+-- 项目模板
+DROP TABLE IF EXISTS `project_template_stages`;
+DROP TABLE IF EXISTS `project_templates`;
@@ -0,0 +1,27 @@
+-- 项目模板
+CREATE TABLE `project_templates` (
+  `id` bigint AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL,
+  `type` varchar(50) NOT NULL,
+  `description` longtext,
+  `duration_days` bigint DEFAULT 0,
+  `user_id` bigint NOT NULL,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_project_templates_type` (`type`)
+);
+
+CREATE TABLE `project_template_stages` (
+  `id` bigint AUTO_INCREMENT,
+  `template_id` bigint NOT NULL,
+  `stage` varchar(50) NOT NULL,
+  `percentage` double NOT NULL,
+  `offset_days` bigint DEFAULT 0,
+  `sort` bigint DEFAULT 0,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_project_template_stages_template_id` (`template_id`),
+  CONSTRAINT `fk_project_templates_stages` FOREIGN KEY (`template_id`) REFERENCES `project_templates`(`id`)
+);
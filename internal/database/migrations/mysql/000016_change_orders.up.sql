@@ -0,0 +1,20 @@
+-- 合同变更单
+CREATE TABLE `change_orders` (
+  `id` bigint AUTO_INCREMENT,
+  `project_id` bigint NOT NULL,
+  `number` varchar(50) NOT NULL,
+  `change_date` date NOT NULL,
+  `amount` bigint NOT NULL,
+  `reason` varchar(500),
+  `status` varchar(20) NOT NULL,
+  `approved_by` bigint,
+  `approved_at` datetime(3) NULL,
+  `user_id` bigint NOT NULL,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_change_orders_project_id` (`project_id`),
+  INDEX `idx_change_orders_status` (`status`)
+);
+
+ALTER TABLE `projects` ADD `change_amount` bigint DEFAULT 0;
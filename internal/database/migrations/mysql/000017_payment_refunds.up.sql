@@ -0,0 +1,21 @@
+-- 退款记录
+CREATE TABLE `payment_refunds` (
+  `id` bigint AUTO_INCREMENT,
+  `payment_id` bigint NOT NULL,
+  `project_id` bigint NOT NULL,
+  `type` varchar(20) NOT NULL,
+  `amount` bigint NOT NULL,
+  `refund_date` date NOT NULL,
+  `method` varchar(30),
+  `reference_no` varchar(100),
+  `reason` varchar(255),
+  `user_id` bigint NOT NULL,
+  `create_time` datetime(3) NULL,
+  `update_time` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_payment_refunds_payment_id` (`payment_id`),
+  INDEX `idx_payment_refunds_project_id` (`project_id`),
+  INDEX `idx_payment_refunds_refund_date` (`refund_date`)
+);
+
+ALTER TABLE `payments` ADD `refunded_amount` bigint DEFAULT 0;
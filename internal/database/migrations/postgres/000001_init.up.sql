@@ -0,0 +1,130 @@
+-- 初始表结构
+CREATE TABLE "users" (
+  "id" bigserial,
+  "username" varchar(50) NOT NULL,
+  "password" varchar(100) NOT NULL,
+  "name" varchar(50) NOT NULL,
+  "email" varchar(100),
+  "phone" varchar(20),
+  "avatar" varchar(255),
+  "role" varchar(20) NOT NULL DEFAULT 'user',
+  "department" varchar(50),
+  "position" varchar(50),
+  "status" bigint DEFAULT 1,
+  "last_login_time" timestamptz,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_username" ON "users" ("username");
+
+CREATE TABLE "projects" (
+  "id" bigserial,
+  "name" varchar(100) NOT NULL,
+  "company" varchar(100) NOT NULL,
+  "total_amount" real NOT NULL,
+  "received_amount" real DEFAULT 0,
+  "status" varchar(20) NOT NULL,
+  "type" varchar(50) NOT NULL,
+  "contract_number" varchar(50),
+  "contract_date" date,
+  "payment_method" varchar(30),
+  "start_date" date NOT NULL,
+  "end_date" date NOT NULL,
+  "description" text,
+  "user_id" bigint NOT NULL,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_projects_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_projects_user_id" ON "projects" ("user_id");
+
+CREATE TABLE "payments" (
+  "id" bigserial,
+  "project_id" bigint NOT NULL,
+  "stage" varchar(50) NOT NULL,
+  "amount" real NOT NULL,
+  "percentage" real,
+  "plan_date" date NOT NULL,
+  "status" varchar(20) NOT NULL,
+  "actual_date" date,
+  "method" varchar(30),
+  "remark" varchar(255),
+  "user_id" bigint NOT NULL,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_projects_payments" FOREIGN KEY ("project_id") REFERENCES "projects"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_payments_status" ON "payments" ("status");
+CREATE INDEX IF NOT EXISTS "idx_payments_plan_date" ON "payments" ("plan_date");
+CREATE INDEX IF NOT EXISTS "idx_payments_project_id" ON "payments" ("project_id");
+
+CREATE TABLE "dictionaries" (
+  "id" bigserial,
+  "code" varchar(50) NOT NULL,
+  "name" varchar(50) NOT NULL,
+  "status" bigint DEFAULT 1,
+  "remark" varchar(255),
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_dictionaries_code" ON "dictionaries" ("code");
+
+CREATE TABLE "dictionary_item" (
+  "id" bigserial,
+  "dictionary_id" bigint NOT NULL,
+  "label" varchar(50) NOT NULL,
+  "value" varchar(50) NOT NULL,
+  "sort" bigint DEFAULT 0,
+  "status" bigint DEFAULT 1,
+  "remark" varchar(255),
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_dictionaries_items" FOREIGN KEY ("dictionary_id") REFERENCES "dictionaries"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_dictionary_item_dictionary_id" ON "dictionary_item" ("dictionary_id");
+
+CREATE TABLE "notifications" (
+  "id" bigserial,
+  "title" varchar(100) NOT NULL,
+  "content" text NOT NULL,
+  "type" bigint DEFAULT 1,
+  "sender_id" bigint NOT NULL,
+  "is_global" bigint DEFAULT 0,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  "is_read" boolean,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_notifications_sender_id" ON "notifications" ("sender_id");
+
+CREATE TABLE "user_notifications" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "notification_id" bigint NOT NULL,
+  "is_read" bigint DEFAULT 0,
+  "read_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_notification" ON "user_notifications" ("user_id","notification_id");
+
+CREATE TABLE "personal_access_tokens" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "name" varchar(50) NOT NULL,
+  "token_hash" varchar(100) NOT NULL,
+  "scopes" varchar(255) DEFAULT '',
+  "status" bigint DEFAULT 1,
+  "last_used_at" timestamptz,
+  "expires_at" timestamptz,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_personal_access_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_personal_access_tokens_token_hash" ON "personal_access_tokens" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_personal_access_tokens_user_id" ON "personal_access_tokens" ("user_id");
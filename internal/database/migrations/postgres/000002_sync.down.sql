@@ -0,0 +1,7 @@
+-- 数据同步相关表
+DROP TABLE IF EXISTS "sync_history";
+DROP TABLE IF EXISTS "sync_profiles";
+DROP TABLE IF EXISTS "sync_row_versions";
+DROP TABLE IF EXISTS "sync_checkpoints";
+DROP TABLE IF EXISTS "sync_tombstones";
+ALTER TABLE "user_notifications" DROP COLUMN "update_time";
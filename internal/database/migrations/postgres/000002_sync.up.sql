@@ -0,0 +1,86 @@
+-- 数据同步相关表
+ALTER TABLE "user_notifications" ADD "update_time" timestamptz;
+
+CREATE TABLE "sync_tombstones" (
+  "id" bigserial,
+  "table_name" varchar(50) NOT NULL,
+  "record_id" bigint NOT NULL,
+  "delete_time" timestamptz NOT NULL,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sync_tombstone" ON "sync_tombstones" ("table_name","delete_time");
+
+CREATE TABLE "sync_checkpoints" (
+  "id" bigserial,
+  "target" varchar(255) NOT NULL,
+  "table_name" varchar(50) NOT NULL,
+  "last_sync_time" timestamptz NOT NULL,
+  "remote_sync_time" timestamptz,
+  "synced_count" bigint DEFAULT 0,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sync_checkpoint" ON "sync_checkpoints" ("target","table_name");
+
+CREATE TABLE "sync_row_versions" (
+  "id" bigserial,
+  "target" varchar(255) NOT NULL,
+  "table_name" varchar(50) NOT NULL,
+  "record_id" bigint NOT NULL,
+  "hash" varchar(64) NOT NULL,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sync_row_version" ON "sync_row_versions" ("target","table_name","record_id");
+
+CREATE TABLE "sync_profiles" (
+  "id" bigserial,
+  "name" varchar(50) NOT NULL,
+  "db_type" varchar(20) NOT NULL,
+  "host" varchar(255),
+  "port" bigint,
+  "db_user" varchar(100),
+  "password" varchar(500),
+  "db_name" varchar(100),
+  "ssl_mode" varchar(20),
+  "path" varchar(500),
+  "tables" varchar(500),
+  "direction" varchar(20) NOT NULL,
+  "atomic" bigint,
+  "interval_minutes" bigint,
+  "enabled" bigint,
+  "last_run_time" timestamptz,
+  "last_status" varchar(20),
+  "next_run_time" timestamptz,
+  "user_id" bigint NOT NULL,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sync_profiles_next_run_time" ON "sync_profiles" ("next_run_time");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sync_profiles_name" ON "sync_profiles" ("name");
+
+CREATE TABLE "sync_history" (
+  "id" bigserial,
+  "profile_id" bigint,
+  "profile_name" varchar(50),
+  "target" varchar(255),
+  "trigger" varchar(20) NOT NULL,
+  "direction" varchar(20),
+  "status" varchar(20) NOT NULL,
+  "start_time" timestamptz NOT NULL,
+  "end_time" timestamptz,
+  "synced_count" bigint,
+  "pulled_count" bigint,
+  "deleted_count" bigint,
+  "conflict_count" bigint,
+  "results" text,
+  "error_message" text,
+  "user_id" bigint,
+  "create_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_sync_history_start_time" ON "sync_history" ("start_time");
+CREATE INDEX IF NOT EXISTS "idx_sync_history_status" ON "sync_history" ("status");
+CREATE INDEX IF NOT EXISTS "idx_sync_history_profile_id" ON "sync_history" ("profile_id");
@@ -0,0 +1,2 @@
+-- 项目成员 (项目共享)
+DROP TABLE IF EXISTS "project_members";
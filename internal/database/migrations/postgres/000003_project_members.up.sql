@@ -0,0 +1,14 @@
+-- 项目成员 (项目共享)
+CREATE TABLE "project_members" (
+  "id" bigserial,
+  "project_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "role" varchar(20) NOT NULL,
+  "invited_by" bigint,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_project_members_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_project_members_user_id" ON "project_members" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_project_member" ON "project_members" ("project_id","user_id");
@@ -0,0 +1,3 @@
+-- 角色与权限
+DROP TABLE IF EXISTS "role_permissions";
+DROP TABLE IF EXISTS "roles";
@@ -0,0 +1,21 @@
+-- 角色与权限
+CREATE TABLE "roles" (
+  "id" bigserial,
+  "code" varchar(50) NOT NULL,
+  "name" varchar(50) NOT NULL,
+  "description" varchar(255),
+  "is_builtin" bigint DEFAULT 0,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_roles_code" ON "roles" ("code");
+
+CREATE TABLE "role_permissions" (
+  "id" bigserial,
+  "role_id" bigint NOT NULL,
+  "permission" varchar(50) NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_roles_permissions" FOREIGN KEY ("role_id") REFERENCES "roles"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_role_permission" ON "role_permissions" ("role_id","permission");
@@ -0,0 +1,2 @@
+-- 登录会话
+DROP TABLE IF EXISTS "user_sessions";
@@ -0,0 +1,18 @@
+-- 登录会话
+CREATE TABLE "user_sessions" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "refresh_token_hash" varchar(100) NOT NULL,
+  "previous_token_hash" varchar(100),
+  "user_agent" varchar(255),
+  "ip" varchar(64),
+  "last_used_at" timestamptz,
+  "expires_at" timestamptz NOT NULL,
+  "revoked_at" timestamptz,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_user_sessions_previous_token_hash" ON "user_sessions" ("previous_token_hash");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_sessions_refresh_token_hash" ON "user_sessions" ("refresh_token_hash");
+CREATE INDEX IF NOT EXISTS "idx_user_sessions_user_id" ON "user_sessions" ("user_id");
@@ -0,0 +1,23 @@
+-- 两步验证
+CREATE TABLE "user_two_factors" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "secret" varchar(255) NOT NULL,
+  "enabled" bigint DEFAULT 0,
+  "last_used_step" bigint DEFAULT 0,
+  "enabled_at" timestamptz,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_two_factors_user_id" ON "user_two_factors" ("user_id");
+
+CREATE TABLE "user_recovery_codes" (
+  "id" bigserial,
+  "user_id" bigint NOT NULL,
+  "code_hash" varchar(100) NOT NULL,
+  "used_at" timestamptz,
+  "create_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_user_recovery_codes_user_id" ON "user_recovery_codes" ("user_id");
@@ -0,0 +1,3 @@
+-- 登录保护
+DROP TABLE IF EXISTS "login_lockouts";
+DROP TABLE IF EXISTS "login_throttles";
@@ -0,0 +1,25 @@
+-- 登录保护
+CREATE TABLE "login_throttles" (
+  "id" bigserial,
+  "throttle_key" varchar(150) NOT NULL,
+  "failures" bigint DEFAULT 0,
+  "last_failure_at" timestamptz,
+  "locked_until" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_login_throttles_key" ON "login_throttles" ("throttle_key");
+
+CREATE TABLE "login_lockouts" (
+  "id" bigserial,
+  "user_id" bigint,
+  "username" varchar(100),
+  "ip" varchar(64),
+  "failures" bigint,
+  "locked_until" timestamptz,
+  "unlocked_at" timestamptz,
+  "unlocked_by" bigint,
+  "create_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_login_lockouts_user_id" ON "login_lockouts" ("user_id");
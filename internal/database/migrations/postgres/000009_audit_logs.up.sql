@@ -0,0 +1,23 @@
+-- 审计日志
+CREATE TABLE "audit_logs" (
+  "id" bigserial,
+  "actor_id" bigint,
+  "actor_name" varchar(50),
+  "source" varchar(20),
+  "access_token_id" bigint,
+  "ip" varchar(64),
+  "action" varchar(50) NOT NULL,
+  "entity_type" varchar(50) NOT NULL,
+  "entity_id" bigint,
+  "before_data" text,
+  "after_data" text,
+  "create_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_create_time" ON "audit_logs" ("create_time");
+CREATE INDEX IF NOT EXISTS "idx_audit_entity" ON "audit_logs" ("entity_type","entity_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_action" ON "audit_logs" ("action");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_actor_id" ON "audit_logs" ("actor_id");
+
+-- 内置审计员角色授予查看审计日志权限 (新数据库由初始化数据写入)
+INSERT INTO role_permissions (role_id, permission) SELECT id, 'audit:read' FROM roles WHERE code = 'auditor';
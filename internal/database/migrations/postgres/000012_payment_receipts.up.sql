@@ -0,0 +1,23 @@
+-- 收款记录
+CREATE TABLE "payment_receipts" (
+  "id" bigserial,
+  "payment_id" bigint NOT NULL,
+  "project_id" bigint NOT NULL,
+  "amount" real NOT NULL,
+  "received_date" date NOT NULL,
+  "method" varchar(30),
+  "reference_no" varchar(100),
+  "remark" varchar(255),
+  "user_id" bigint NOT NULL,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_payment_receipts_received_date" ON "payment_receipts" ("received_date");
+CREATE INDEX IF NOT EXISTS "idx_payment_receipts_project_id" ON "payment_receipts" ("project_id");
+CREATE INDEX IF NOT EXISTS "idx_payment_receipts_payment_id" ON "payment_receipts" ("payment_id");
+ALTER TABLE "payments" ADD "received_amount" real DEFAULT 0;
+ALTER TABLE "payments" ADD "received_percentage" real DEFAULT 0;
+INSERT INTO "payment_receipts" ("payment_id", "project_id", "amount", "received_date", "method", "reference_no", "remark", "user_id", "create_time", "update_time")
+SELECT "id", "project_id", "amount", COALESCE("actual_date", "plan_date"), "method", '', '', "user_id", "update_time", "update_time" FROM "payments" WHERE "status" = 'paid';
+UPDATE "payments" SET "received_amount" = "amount", "received_percentage" = 100 WHERE "status" = 'paid';
@@ -0,0 +1,7 @@
+-- 金额以分为单位的整数存储
+ALTER TABLE "projects" ALTER COLUMN "total_amount" TYPE real USING "total_amount" / 100.0;
+ALTER TABLE "projects" ALTER COLUMN "received_amount" TYPE real USING "received_amount" / 100.0;
+ALTER TABLE "payments" ALTER COLUMN "amount" TYPE real USING "amount" / 100.0;
+ALTER TABLE "payments" ALTER COLUMN "received_amount" TYPE real USING "received_amount" / 100.0;
+ALTER TABLE "payment_receipts" ALTER COLUMN "amount" TYPE real USING "amount" / 100.0;
+DELETE FROM "sync_checkpoints" WHERE "table_name" IN ('projects', 'payments', 'payment_receipts');
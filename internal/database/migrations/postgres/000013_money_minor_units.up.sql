@@ -0,0 +1,9 @@
+-- 金额以分为单位的整数存储
+ALTER TABLE "projects" ALTER COLUMN "total_amount" TYPE bigint USING ROUND("total_amount" * 100);
+ALTER TABLE "projects" ALTER COLUMN "received_amount" TYPE bigint USING ROUND("received_amount" * 100);
+ALTER TABLE "payments" ALTER COLUMN "amount" TYPE bigint USING ROUND("amount" * 100);
+ALTER TABLE "payments" ALTER COLUMN "received_amount" TYPE bigint USING ROUND("received_amount" * 100);
+ALTER TABLE "payment_receipts" ALTER COLUMN "amount" TYPE bigint USING ROUND("amount" * 100);
+UPDATE "projects" SET "received_amount" = (SELECT COALESCE(SUM("received_amount"), 0) FROM "payments" WHERE "payments"."project_id" = "projects"."id" AND "payments"."deleted_at" IS NULL);
+DELETE FROM "sync_checkpoints" WHERE "table_name" IN ('projects', 'payments', 'payment_receipts');
+DELETE FROM "sync_row_versions" WHERE "table_name" IN ('projects', 'payments', 'payment_receipts');
@@ -0,0 +1,5 @@
+-- 周期收款计划
+DROP TABLE IF EXISTS "payment_schedules";
+DROP INDEX IF EXISTS "idx_payments_schedule_id";
+ALTER TABLE "payments" DROP COLUMN "schedule_seq";
+ALTER TABLE "payments" DROP COLUMN "schedule_id";
@@ -0,0 +1,27 @@
+-- 周期收款计划
+CREATE TABLE "payment_schedules" (
+  "id" bigserial,
+  "project_id" bigint NOT NULL,
+  "stage" varchar(40) NOT NULL,
+  "amount" bigint NOT NULL,
+  "frequency" varchar(20) NOT NULL,
+  "day_of_month" bigint NOT NULL,
+  "start_date" date NOT NULL,
+  "end_date" date,
+  "count" bigint DEFAULT 0,
+  "method" varchar(30),
+  "remark" varchar(255),
+  "status" varchar(20) NOT NULL,
+  "generated_count" bigint DEFAULT 0,
+  "next_date" date,
+  "user_id" bigint NOT NULL,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_payment_schedules_next_date" ON "payment_schedules" ("next_date");
+CREATE INDEX IF NOT EXISTS "idx_payment_schedules_status" ON "payment_schedules" ("status");
+CREATE INDEX IF NOT EXISTS "idx_payment_schedules_project_id" ON "payment_schedules" ("project_id");
+ALTER TABLE "payments" ADD "schedule_id" bigint;
+CREATE INDEX IF NOT EXISTS "idx_payments_schedule_id" ON "payments" ("schedule_id");
+ALTER TABLE "payments" ADD "schedule_seq" bigint DEFAULT 0;
@@ -0,0 +1,27 @@
+-- 项目模板
+CREATE TABLE "project_templates" (
+  "id" bigserial,
+  "name" varchar(100) NOT NULL,
+  "type" varchar(50) NOT NULL,
+  "description" text,
+  "duration_days" bigint DEFAULT 0,
+  "user_id" bigint NOT NULL,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_project_templates_type" ON "project_templates" ("type");
+
+CREATE TABLE "project_template_stages" (
+  "id" bigserial,
+  "template_id" bigint NOT NULL,
+  "stage" varchar(50) NOT NULL,
+  "percentage" decimal NOT NULL,
+  "offset_days" bigint DEFAULT 0,
+  "sort" bigint DEFAULT 0,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_project_templates_stages" FOREIGN KEY ("template_id") REFERENCES "project_templates"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_project_template_stages_template_id" ON "project_template_stages" ("template_id");
@@ -0,0 +1,20 @@
+-- 合同变更单
+CREATE TABLE "change_orders" (
+  "id" bigserial,
+  "project_id" bigint NOT NULL,
+  "number" varchar(50) NOT NULL,
+  "change_date" date NOT NULL,
+  "amount" bigint NOT NULL,
+  "reason" varchar(500),
+  "status" varchar(20) NOT NULL,
+  "approved_by" bigint,
+  "approved_at" timestamptz,
+  "user_id" bigint NOT NULL,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_change_orders_status" ON "change_orders" ("status");
+CREATE INDEX IF NOT EXISTS "idx_change_orders_project_id" ON "change_orders" ("project_id");
+
+ALTER TABLE "projects" ADD "change_amount" bigint DEFAULT 0;
@@ -0,0 +1,3 @@
+-- 退款记录
+DROP TABLE IF EXISTS "payment_refunds";
+ALTER TABLE "payments" DROP COLUMN "refunded_amount";
@@ -0,0 +1,21 @@
+-- 退款记录
+CREATE TABLE "payment_refunds" (
+  "id" bigserial,
+  "payment_id" bigint NOT NULL,
+  "project_id" bigint NOT NULL,
+  "type" varchar(20) NOT NULL,
+  "amount" bigint NOT NULL,
+  "refund_date" date NOT NULL,
+  "method" varchar(30),
+  "reference_no" varchar(100),
+  "reason" varchar(255),
+  "user_id" bigint NOT NULL,
+  "create_time" timestamptz,
+  "update_time" timestamptz,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_payment_refunds_refund_date" ON "payment_refunds" ("refund_date");
+CREATE INDEX IF NOT EXISTS "idx_payment_refunds_project_id" ON "payment_refunds" ("project_id");
+CREATE INDEX IF NOT EXISTS "idx_payment_refunds_payment_id" ON "payment_refunds" ("payment_id");
+
+ALTER TABLE "payments" ADD "refunded_amount" bigint DEFAULT 0;
@@ -0,0 +1,122 @@
+-- 初始表结构
+CREATE TABLE `users` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `username` text NOT NULL,
+  `password` text NOT NULL,
+  `name` text NOT NULL,
+  `email` text,
+  `phone` text,
+  `avatar` text,
+  `role` text NOT NULL DEFAULT 'user',
+  `department` text,
+  `position` text,
+  `status` integer DEFAULT 1,
+  `last_login_time` datetime,
+  `create_time` datetime,
+  `update_time` datetime
+);
+CREATE UNIQUE INDEX `idx_users_username` ON `users`(`username`);
+
+CREATE TABLE `projects` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` text NOT NULL,
+  `company` text NOT NULL,
+  `total_amount` real NOT NULL,
+  `received_amount` real DEFAULT 0,
+  `status` text NOT NULL,
+  `type` text NOT NULL,
+  `contract_number` text,
+  `contract_date` date,
+  `payment_method` text,
+  `start_date` date NOT NULL,
+  `end_date` date NOT NULL,
+  `description` text,
+  `user_id` integer NOT NULL,
+  `create_time` datetime,
+  `update_time` datetime,
+  CONSTRAINT `fk_projects_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX `idx_projects_user_id` ON `projects`(`user_id`);
+
+CREATE TABLE `payments` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `project_id` integer NOT NULL,
+  `stage` text NOT NULL,
+  `amount` real NOT NULL,
+  `percentage` real,
+  `plan_date` date NOT NULL,
+  `status` text NOT NULL,
+  `actual_date` date,
+  `method` text,
+  `remark` text,
+  `user_id` integer NOT NULL,
+  `create_time` datetime,
+  `update_time` datetime,
+  CONSTRAINT `fk_projects_payments` FOREIGN KEY (`project_id`) REFERENCES `projects`(`id`)
+);
+CREATE INDEX `idx_payments_status` ON `payments`(`status`);
+CREATE INDEX `idx_payments_plan_date` ON `payments`(`plan_date`);
+CREATE INDEX `idx_payments_project_id` ON `payments`(`project_id`);
+
+CREATE TABLE `dictionaries` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `code` text NOT NULL,
+  `name` text NOT NULL,
+  `status` integer DEFAULT 1,
+  `remark` text,
+  `create_time` datetime,
+  `update_time` datetime
+);
+CREATE UNIQUE INDEX `idx_dictionaries_code` ON `dictionaries`(`code`);
+
+CREATE TABLE `dictionary_item` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `dictionary_id` integer NOT NULL,
+  `label` text NOT NULL,
+  `value` text NOT NULL,
+  `sort` integer DEFAULT 0,
+  `status` integer DEFAULT 1,
+  `remark` text,
+  `create_time` datetime,
+  `update_time` datetime,
+  CONSTRAINT `fk_dictionaries_items` FOREIGN KEY (`dictionary_id`) REFERENCES `dictionaries`(`id`)
+);
+CREATE INDEX `idx_dictionary_item_dictionary_id` ON `dictionary_item`(`dictionary_id`);
+
+CREATE TABLE `notifications` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `title` text NOT NULL,
+  `content` text NOT NULL,
+  `type` integer DEFAULT 1,
+  `sender_id` integer NOT NULL,
+  `is_global` integer DEFAULT 0,
+  `create_time` datetime,
+  `update_time` datetime,
+  `is_read` numeric
+);
+CREATE INDEX `idx_notifications_sender_id` ON `notifications`(`sender_id`);
+
+CREATE TABLE `user_notifications` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `notification_id` integer NOT NULL,
+  `is_read` integer DEFAULT 0,
+  `read_time` datetime
+);
+CREATE UNIQUE INDEX `idx_user_notification` ON `user_notifications`(`user_id`,`notification_id`);
+
+CREATE TABLE `personal_access_tokens` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `name` text NOT NULL,
+  `token_hash` text NOT NULL,
+  `scopes` text DEFAULT '',
+  `status` integer DEFAULT 1,
+  `last_used_at` datetime,
+  `expires_at` datetime,
+  `create_time` datetime,
+  `update_time` datetime,
+  CONSTRAINT `fk_personal_access_tokens_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX `idx_personal_access_tokens_token_hash` ON `personal_access_tokens`(`token_hash`);
+CREATE INDEX `idx_personal_access_tokens_user_id` ON `personal_access_tokens`(`user_id`);
@@ -0,0 +1,81 @@
+-- 数据同步相关表
+ALTER TABLE `user_notifications` ADD `update_time` datetime;
+
+CREATE TABLE `sync_tombstones` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `table_name` text NOT NULL,
+  `record_id` integer NOT NULL,
+  `delete_time` datetime NOT NULL
+);
+CREATE INDEX `idx_sync_tombstone` ON `sync_tombstones`(`table_name`,`delete_time`);
+
+CREATE TABLE `sync_checkpoints` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `target` text NOT NULL,
+  `table_name` text NOT NULL,
+  `last_sync_time` datetime NOT NULL,
+  `remote_sync_time` datetime,
+  `synced_count` integer DEFAULT 0,
+  `create_time` datetime,
+  `update_time` datetime
+);
+CREATE UNIQUE INDEX `idx_sync_checkpoint` ON `sync_checkpoints`(`target`,`table_name`);
+
+CREATE TABLE `sync_row_versions` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `target` text NOT NULL,
+  `table_name` text NOT NULL,
+  `record_id` integer NOT NULL,
+  `hash` text NOT NULL,
+  `update_time` datetime
+);
+CREATE UNIQUE INDEX `idx_sync_row_version` ON `sync_row_versions`(`target`,`table_name`,`record_id`);
+
+CREATE TABLE `sync_profiles` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` text NOT NULL,
+  `db_type` text NOT NULL,
+  `host` text,
+  `port` integer,
+  `db_user` text,
+  `password` text,
+  `db_name` text,
+  `ssl_mode` text,
+  `path` text,
+  `tables` text,
+  `direction` text NOT NULL,
+  `atomic` integer,
+  `interval_minutes` integer,
+  `enabled` integer,
+  `last_run_time` datetime,
+  `last_status` text,
+  `next_run_time` datetime,
+  `user_id` integer NOT NULL,
+  `create_time` datetime,
+  `update_time` datetime
+);
+CREATE INDEX `idx_sync_profiles_next_run_time` ON `sync_profiles`(`next_run_time`);
+CREATE UNIQUE INDEX `idx_sync_profiles_name` ON `sync_profiles`(`name`);
+
+CREATE TABLE `sync_history` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `profile_id` integer,
+  `profile_name` text,
+  `target` text,
+  `trigger` text NOT NULL,
+  `direction` text,
+  `status` text NOT NULL,
+  `start_time` datetime NOT NULL,
+  `end_time` datetime,
+  `synced_count` integer,
+  `pulled_count` integer,
+  `deleted_count` integer,
+  `conflict_count` integer,
+  `results` text,
+  `error_message` text,
+  `user_id` integer,
+  `create_time` datetime
+);
+CREATE INDEX `idx_sync_history_start_time` ON `sync_history`(`start_time`);
+CREATE INDEX `idx_sync_history_status` ON `sync_history`(`status`);
+CREATE INDEX `idx_sync_history_profile_id` ON `sync_history`(`profile_id`);
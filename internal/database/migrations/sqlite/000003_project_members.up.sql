@@ -0,0 +1,13 @@
+-- 项目成员 (项目共享)
+CREATE TABLE `project_members` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `project_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  `role` text NOT NULL,
+  `invited_by` integer,
+  `create_time` datetime,
+  `update_time` datetime,
+  CONSTRAINT `fk_project_members_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX `idx_project_members_user_id` ON `project_members`(`user_id`);
+CREATE UNIQUE INDEX `idx_project_member` ON `project_members`(`project_id`,`user_id`);
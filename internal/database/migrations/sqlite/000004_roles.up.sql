@@ -0,0 +1,19 @@
+-- 角色与权限
+CREATE TABLE `roles` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `code` text NOT NULL,
+  `name` text NOT NULL,
+  `description` text,
+  `is_builtin` integer DEFAULT 0,
+  `create_time` datetime,
+  `update_time` datetime
+);
+CREATE UNIQUE INDEX `idx_roles_code` ON `roles`(`code`);
+
+CREATE TABLE `role_permissions` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `role_id` integer NOT NULL,
+  `permission` text NOT NULL,
+  CONSTRAINT `fk_roles_permissions` FOREIGN KEY (`role_id`) REFERENCES `roles`(`id`)
+);
+CREATE UNIQUE INDEX `idx_role_permission` ON `role_permissions`(`role_id`,`permission`);
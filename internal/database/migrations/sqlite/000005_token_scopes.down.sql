@@ -0,0 +1,2 @@
+-- 访问令牌授权范围
+UPDATE personal_access_tokens SET scopes = '' WHERE scopes = '*';
@@ -0,0 +1,3 @@
+-- 访问令牌授权范围
+-- 引入授权范围前创建的令牌拥有完全访问能力，保持原有行为
+UPDATE personal_access_tokens SET scopes = '*' WHERE scopes IS NULL OR scopes = '';
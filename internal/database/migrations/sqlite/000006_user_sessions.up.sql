@@ -0,0 +1,17 @@
+-- 登录会话
+CREATE TABLE `user_sessions` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `refresh_token_hash` text NOT NULL,
+  `previous_token_hash` text,
+  `user_agent` text,
+  `ip` text,
+  `last_used_at` datetime,
+  `expires_at` datetime NOT NULL,
+  `revoked_at` datetime,
+  `create_time` datetime,
+  `update_time` datetime
+);
+CREATE INDEX `idx_user_sessions_previous_token_hash` ON `user_sessions`(`previous_token_hash`);
+CREATE UNIQUE INDEX `idx_user_sessions_refresh_token_hash` ON `user_sessions`(`refresh_token_hash`);
+CREATE INDEX `idx_user_sessions_user_id` ON `user_sessions`(`user_id`);
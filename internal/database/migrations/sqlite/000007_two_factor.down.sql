@@ -0,0 +1,3 @@
+-- 两步验证
+DROP TABLE IF EXISTS `user_recovery_codes`;
+DROP TABLE IF EXISTS `user_two_factors`;
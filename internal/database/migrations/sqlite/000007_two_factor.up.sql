@@ -0,0 +1,21 @@
+-- 两步验证
+CREATE TABLE `user_two_factors` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `secret` text NOT NULL,
+  `enabled` integer DEFAULT 0,
+  `last_used_step` integer DEFAULT 0,
+  `enabled_at` datetime,
+  `create_time` datetime,
+  `update_time` datetime
+);
+CREATE UNIQUE INDEX `idx_user_two_factors_user_id` ON `user_two_factors`(`user_id`);
+
+CREATE TABLE `user_recovery_codes` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer NOT NULL,
+  `code_hash` text NOT NULL,
+  `used_at` datetime,
+  `create_time` datetime
+);
+CREATE INDEX `idx_user_recovery_codes_user_id` ON `user_recovery_codes`(`user_id`);
@@ -0,0 +1,23 @@
+-- 登录保护
+CREATE TABLE `login_throttles` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `throttle_key` text NOT NULL,
+  `failures` integer DEFAULT 0,
+  `last_failure_at` datetime,
+  `locked_until` datetime,
+  `update_time` datetime
+);
+CREATE UNIQUE INDEX `idx_login_throttles_key` ON `login_throttles`(`throttle_key`);
+
+CREATE TABLE `login_lockouts` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `user_id` integer,
+  `username` text,
+  `ip` text,
+  `failures` integer,
+  `locked_until` datetime,
+  `unlocked_at` datetime,
+  `unlocked_by` integer,
+  `create_time` datetime
+);
+CREATE INDEX `idx_login_lockouts_user_id` ON `login_lockouts`(`user_id`);
@@ -0,0 +1,22 @@
+-- 审计日志
+CREATE TABLE `audit_logs` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `actor_id` integer,
+  `actor_name` text,
+  `source` text,
+  `access_token_id` integer,
+  `ip` text,
+  `action` text NOT NULL,
+  `entity_type` text NOT NULL,
+  `entity_id` integer,
+  `before_data` text,
+  `after_data` text,
+  `create_time` datetime
+);
+CREATE INDEX `idx_audit_logs_create_time` ON `audit_logs`(`create_time`);
+CREATE INDEX `idx_audit_entity` ON `audit_logs`(`entity_type`,`entity_id`);
+CREATE INDEX `idx_audit_logs_action` ON `audit_logs`(`action`);
+CREATE INDEX `idx_audit_logs_actor_id` ON `audit_logs`(`actor_id`);
+
+-- 内置审计员角色授予查看审计日志权限 (新数据库由初始化数据写入)
+INSERT INTO role_permissions (role_id, permission) SELECT id, 'audit:read' FROM roles WHERE code = 'auditor';
@@ -0,0 +1,2 @@
+-- 数据版本 (项目与款项历史快照)
+DROP TABLE IF EXISTS `revisions`;
@@ -0,0 +1,13 @@
+-- 数据版本 (项目与款项历史快照)
+CREATE TABLE `revisions` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `entity_type` text NOT NULL,
+  `entity_id` integer NOT NULL,
+  `version` integer NOT NULL,
+  `action` text NOT NULL,
+  `snapshot` text NOT NULL,
+  `actor_id` integer,
+  `actor_name` text,
+  `create_time` datetime
+);
+CREATE UNIQUE INDEX `idx_revision_version` ON `revisions`(`entity_type`,`entity_id`,`version`);
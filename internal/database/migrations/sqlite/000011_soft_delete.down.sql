@@ -0,0 +1,7 @@
+-- 软删除 (回收站)
+DROP INDEX IF EXISTS `idx_payments_deleted_at`;
+ALTER TABLE `payments` DROP COLUMN `deleted_at`;
+DROP INDEX IF EXISTS `idx_projects_deleted_at`;
+ALTER TABLE `projects` DROP COLUMN `deleted_at`;
+DROP INDEX IF EXISTS `idx_users_deleted_at`;
+ALTER TABLE `users` DROP COLUMN `deleted_at`;
@@ -0,0 +1,7 @@
+-- 软删除 (回收站)
+ALTER TABLE `users` ADD `deleted_at` datetime;
+CREATE INDEX `idx_users_deleted_at` ON `users`(`deleted_at`);
+ALTER TABLE `projects` ADD `deleted_at` datetime;
+CREATE INDEX `idx_projects_deleted_at` ON `projects`(`deleted_at`);
+ALTER TABLE `payments` ADD `deleted_at` datetime;
+CREATE INDEX `idx_payments_deleted_at` ON `payments`(`deleted_at`);
@@ -0,0 +1,24 @@
+-- 金额以分为单位的整数存储
+ALTER TABLE `projects` ADD `total_amount_tmp` integer NOT NULL DEFAULT 0;
+UPDATE `projects` SET `total_amount_tmp` = CAST(ROUND(`total_amount` * 100) AS INTEGER);
+ALTER TABLE `projects` DROP COLUMN `total_amount`;
+ALTER TABLE `projects` RENAME COLUMN `total_amount_tmp` TO `total_amount`;
+ALTER TABLE `projects` ADD `received_amount_tmp` integer DEFAULT 0;
+UPDATE `projects` SET `received_amount_tmp` = CAST(ROUND(`received_amount` * 100) AS INTEGER);
+ALTER TABLE `projects` DROP COLUMN `received_amount`;
+ALTER TABLE `projects` RENAME COLUMN `received_amount_tmp` TO `received_amount`;
+ALTER TABLE `payments` ADD `amount_tmp` integer NOT NULL DEFAULT 0;
+UPDATE `payments` SET `amount_tmp` = CAST(ROUND(`amount` * 100) AS INTEGER);
+ALTER TABLE `payments` DROP COLUMN `amount`;
+ALTER TABLE `payments` RENAME COLUMN `amount_tmp` TO `amount`;
+ALTER TABLE `payments` ADD `received_amount_tmp` integer DEFAULT 0;
+UPDATE `payments` SET `received_amount_tmp` = CAST(ROUND(`received_amount` * 100) AS INTEGER);
+ALTER TABLE `payments` DROP COLUMN `received_amount`;
+ALTER TABLE `payments` RENAME COLUMN `received_amount_tmp` TO `received_amount`;
+ALTER TABLE `payment_receipts` ADD `amount_tmp` integer NOT NULL DEFAULT 0;
+UPDATE `payment_receipts` SET `amount_tmp` = CAST(ROUND(`amount` * 100) AS INTEGER);
+ALTER TABLE `payment_receipts` DROP COLUMN `amount`;
+ALTER TABLE `payment_receipts` RENAME COLUMN `amount_tmp` TO `amount`;
+UPDATE `projects` SET `received_amount` = (SELECT COALESCE(SUM(`received_amount`), 0) FROM `payments` WHERE `payments`.`project_id` = `projects`.`id` AND `payments`.`deleted_at` IS NULL);
+DELETE FROM `sync_checkpoints` WHERE `table_name` IN ('projects', 'payments', 'payment_receipts');
+DELETE FROM `sync_row_versions` WHERE `table_name` IN ('projects', 'payments', 'payment_receipts');
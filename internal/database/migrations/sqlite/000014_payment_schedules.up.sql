@@ -0,0 +1,26 @@
+-- 周期收款计划
+CREATE TABLE `payment_schedules` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `project_id` integer NOT NULL,
+  `stage` text NOT NULL,
+  `amount` integer NOT NULL,
+  `frequency` text NOT NULL,
+  `day_of_month` integer NOT NULL,
+  `start_date` date NOT NULL,
+  `end_date` date,
+  `count` integer DEFAULT 0,
+  `method` text,
+  `remark` text,
+  `status` text NOT NULL,
+  `generated_count` integer DEFAULT 0,
+  `next_date` date,
+  `user_id` integer NOT NULL,
+  `create_time` datetime,
+  `update_time` datetime
+);
+CREATE INDEX `idx_payment_schedules_next_date` ON `payment_schedules`(`next_date`);
+CREATE INDEX `idx_payment_schedules_status` ON `payment_schedules`(`status`);
+CREATE INDEX `idx_payment_schedules_project_id` ON `payment_schedules`(`project_id`);
+ALTER TABLE `payments` ADD `schedule_id` integer;
+CREATE INDEX `idx_payments_schedule_id` ON `payments`(`schedule_id`);
+ALTER TABLE `payments` ADD `schedule_seq` integer DEFAULT 0;
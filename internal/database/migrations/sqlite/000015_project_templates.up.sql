@@ -0,0 +1,25 @@
+-- 项目模板
+CREATE TABLE `project_templates` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` text NOT NULL,
+  `type` text NOT NULL,
+  `description` text,
+  `duration_days` integer DEFAULT 0,
+  `user_id` integer NOT NULL,
+  `create_time` datetime,
+  `update_time` datetime
+);
+CREATE UNIQUE INDEX `idx_project_templates_type` ON `project_templates`(`type`);
+
+CREATE TABLE `project_template_stages` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `template_id` integer NOT NULL,
+  `stage` text NOT NULL,
+  `percentage` real NOT NULL,
+  `offset_days` integer DEFAULT 0,
+  `sort` integer DEFAULT 0,
+  `create_time` datetime,
+  `update_time` datetime,
+  CONSTRAINT `fk_project_templates_stages` FOREIGN KEY (`template_id`) REFERENCES `project_templates`(`id`)
+);
+CREATE INDEX `idx_project_template_stages_template_id` ON `project_template_stages`(`template_id`);
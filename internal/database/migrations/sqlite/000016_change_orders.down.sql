@@ -0,0 +1,3 @@
+-- 合同变更单
+DROP TABLE IF EXISTS `change_orders`;
+ALTER TABLE `projects` DROP COLUMN `change_amount`;
@@ -0,0 +1,19 @@
+-- 合同变更单
+CREATE TABLE `change_orders` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `project_id` integer NOT NULL,
+  `number` text NOT NULL,
+  `change_date` date NOT NULL,
+  `amount` integer NOT NULL,
+  `reason` text,
+  `status` text NOT NULL,
+  `approved_by` integer,
+  `approved_at` datetime,
+  `user_id` integer NOT NULL,
+  `create_time` datetime,
+  `update_time` datetime
+);
+CREATE INDEX `idx_change_orders_status` ON `change_orders`(`status`);
+CREATE INDEX `idx_change_orders_project_id` ON `change_orders`(`project_id`);
+
+ALTER TABLE `projects` ADD `change_amount` integer DEFAULT 0;
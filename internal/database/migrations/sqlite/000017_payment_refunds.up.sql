@@ -0,0 +1,20 @@
+-- 退款记录
+CREATE TABLE `payment_refunds` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `payment_id` integer NOT NULL,
+  `project_id` integer NOT NULL,
+  `type` text NOT NULL,
+  `amount` integer NOT NULL,
+  `refund_date` date NOT NULL,
+  `method` text,
+  `reference_no` text,
+  `reason` text,
+  `user_id` integer NOT NULL,
+  `create_time` datetime,
+  `update_time` datetime
+);
+CREATE INDEX `idx_payment_refunds_refund_date` ON `payment_refunds`(`refund_date`);
+CREATE INDEX `idx_payment_refunds_project_id` ON `payment_refunds`(`project_id`);
+CREATE INDEX `idx_payment_refunds_payment_id` ON `payment_refunds`(`payment_id`);
+
+ALTER TABLE `payments` ADD `refunded_amount` integer DEFAULT 0;
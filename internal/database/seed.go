@@ -4,6 +4,7 @@ import (
 	"log/slog"
 
 	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/permission"
 	"gorm.io/gorm"
 )
 
@@ -11,6 +12,11 @@ import (
 // 该函数在应用启动且数据库表为空时执行，用于预置管理员账号、字典数据等。
 // 类似于 Rails 的 db:seed 或 Laravel 的 seeder。
 func Seed(db *gorm.DB) error {
+	// 内置角色独立于用户数据初始化，已有数据的旧库升级后同样需要补充
+	if err := seedRoles(db); err != nil {
+		return err
+	}
+
 	var count int64
 	if err := db.Model(&models.User{}).Count(&count).Error; err != nil {
 		return err
@@ -83,6 +89,54 @@ func Seed(db *gorm.DB) error {
 			}
 		}
 
+		// 3. 初始化项目模板 (Project Templates)
+		// 包含: Web开发项目的默认 30/40/30 首付款/进度款/尾款
+		templateSQL := []string{
+			`INSERT INTO project_templates (name, type, description, duration_days, user_id, create_time) VALUES ('标准开发项目', 'web', '', 90, 1, CURRENT_TIMESTAMP);`,
+			`INSERT INTO project_template_stages (template_id, stage, percentage, offset_days, sort, create_time)
+SELECT id, 'deposit', 30, 0, 1, CURRENT_TIMESTAMP FROM project_templates WHERE type = 'web'
+UNION ALL SELECT id, 'progress', 40, 45, 2, CURRENT_TIMESTAMP FROM project_templates WHERE type = 'web'
+UNION ALL SELECT id, 'final', 30, 90, 3, CURRENT_TIMESTAMP FROM project_templates WHERE type = 'web';`,
+		}
+
+		for _, sql := range templateSQL {
+			if err := tx.Exec(sql).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// seedRoles 初始化内置角色及默认权限 (角色表为空时执行)
+func seedRoles(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&models.Role{}).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	slog.Info("Seeding builtin roles...")
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, builtin := range permission.BuiltinRoles {
+			role := models.Role{
+				Code:        builtin.Code,
+				Name:        builtin.Name,
+				Description: builtin.Description,
+				IsBuiltin:   1,
+			}
+			for _, perm := range builtin.Permissions {
+				role.Permissions = append(role.Permissions, models.RolePermission{Permission: perm})
+			}
+			if err := tx.Create(&role).Error; err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 }
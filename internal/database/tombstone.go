@@ -0,0 +1,119 @@
+package database
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// SyncTables 参与云端同步的业务表 (按依赖顺序排列)
+// 删除这些表中的记录时会自动写入墓碑日志 (sync_tombstones)，供增量同步传播删除操作。
+var SyncTables = []string{
+	"users",
+	"projects",
+	"project_members",
+	"change_orders",
+	"payment_schedules",
+	"payments",
+	"payment_receipts",
+	"payment_refunds",
+	"dictionaries",
+	"dictionary_item",
+	"project_templates",
+	"project_template_stages",
+	"notifications",
+	"user_notifications",
+	"personal_access_tokens",
+}
+
+// deletedIDsKey 在 Statement 上暂存待删除主键的键名
+const deletedIDsKey = "sync:deleted_ids"
+
+// isSyncTable 判断表是否需要记录删除墓碑
+func isSyncTable(table string) bool {
+	for _, t := range SyncTables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+// registerTombstoneCallbacks 注册删除墓碑回调
+// 在 GORM 执行 DELETE 之前，按相同条件查出即将被删除的主键；
+// 删除成功后在同一连接 (含事务) 中写入墓碑记录。
+func registerTombstoneCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Delete().Before("gorm:delete").Register("sync:collect_deleted_ids", collectDeletedIDs); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:delete").Register("sync:write_tombstones", writeTombstones)
+}
+
+// collectDeletedIDs 收集即将被删除记录的主键
+func collectDeletedIDs(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || !isSyncTable(db.Statement.Table) {
+		return
+	}
+
+	// 复用当前连接 (事务内删除时同样可见)，按删除语句的条件查询主键
+	query := db.Session(&gorm.Session{NewDB: true}).Model(db.Statement.Model).Table(db.Statement.Table)
+	hasCondition := false
+
+	if where, ok := db.Statement.Clauses["WHERE"]; ok {
+		if expr, ok := where.Expression.(clause.Where); ok && len(expr.Exprs) > 0 {
+			query = query.Clauses(expr)
+			hasCondition = true
+		}
+	}
+
+	// 与 gorm:delete 一致：Dest 中带有主键值时追加主键条件
+	_, queryValues := schema.GetIdentityFieldValuesMap(db.Statement.Context, db.Statement.ReflectValue, db.Statement.Schema.PrimaryFields)
+	column, values := schema.ToQueryValues(db.Statement.Table, db.Statement.Schema.PrimaryFieldDBNames, queryValues)
+	if len(values) > 0 {
+		query = query.Clauses(clause.Where{Exprs: []clause.Expression{clause.IN{Column: column, Values: values}}})
+		hasCondition = true
+	}
+
+	// 无条件的删除会被 GORM 拒绝，这里无需收集
+	if !hasCondition {
+		return
+	}
+
+	var ids []int64
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		db.AddError(err)
+		return
+	}
+	db.InstanceSet(deletedIDsKey, ids)
+}
+
+// writeTombstones 删除成功后写入墓碑记录
+func writeTombstones(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+
+	value, ok := db.InstanceGet(deletedIDsKey)
+	if !ok {
+		return
+	}
+	ids, _ := value.([]int64)
+	if len(ids) == 0 {
+		return
+	}
+
+	now := time.Now()
+	tombstones := make([]models.SyncTombstone, 0, len(ids))
+	for _, id := range ids {
+		tombstones = append(tombstones, models.SyncTombstone{
+			Table:      db.Statement.Table,
+			RecordID:   id,
+			DeleteTime: now,
+		})
+	}
+
+	db.AddError(db.Session(&gorm.Session{NewDB: true}).Create(&tombstones).Error)
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "github.com/FruitsAI/Orange/internal/models"
+
+// AuditLogQuery 审计日志查询条件
+type AuditLogQuery struct {
+	ActorID    int64  `form:"actor_id"`    // 操作人ID
+	Action     string `form:"action"`      // 操作类型
+	EntityType string `form:"entity_type"` // 实体类型
+	EntityID   int64  `form:"entity_id"`   // 实体ID
+	Source     string `form:"source"`      // 来源: session, token, anonymous
+	StartDate  string `form:"start_date"`  // 开始日期 (YYYY-MM-DD)
+	EndDate    string `form:"end_date"`    // 结束日期 (YYYY-MM-DD，包含当天)
+	Page       int    `form:"page"`
+	PageSize   int    `form:"page_size"`
+}
+
+// AuditLogListResult 审计日志列表结果
+type AuditLogListResult struct {
+	List     []models.AuditLog `json:"list"`
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
+}
@@ -9,9 +9,25 @@ type LoginRequest struct {
 }
 
 // LoginResult 登录结果
+// 用户启用两步验证时，密码校验通过后只返回 TwoFactorRequired 与 ChallengeToken，
+// 客户端需携带质询令牌与验证码调用 /auth/2fa/verify 完成登录。
 type LoginResult struct {
-	Token string       `json:"token"`
-	User  *models.User `json:"user"`
+	Token             string       `json:"token,omitempty"`               // 访问令牌 (JWT，短期有效)
+	RefreshToken      string       `json:"refresh_token,omitempty"`       // 刷新令牌 (每次刷新后轮换)
+	ExpiresIn         int64        `json:"expires_in,omitempty"`          // 访问令牌有效期 (秒)
+	User              *models.User `json:"user,omitempty"`                // 用户信息
+	TwoFactorRequired bool         `json:"two_factor_required,omitempty"` // 是否需要两步验证
+	ChallengeToken    string       `json:"challenge_token,omitempty"`     // 两步验证质询令牌 (短期有效)
+}
+
+// RefreshTokenRequest 刷新令牌请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest 退出登录请求
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"` // 刷新令牌 (可选，访问令牌已过期时用于定位会话)
 }
 
 // RegisterRequest 注册请求
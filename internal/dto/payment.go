@@ -1,20 +1,68 @@
 package dto
 
+import "github.com/FruitsAI/Orange/internal/pkg/money"
+
 // PaymentRequest 收款请求
 type PaymentRequest struct {
-	ProjectID  int64   `json:"project_id" binding:"required"`
-	Stage      string  `json:"stage" binding:"required"`
-	Amount     float64 `json:"amount" binding:"required"`
-	Percentage float64 `json:"percentage"`
-	PlanDate   string  `json:"plan_date" binding:"required"`
-	Status     string  `json:"status"`
-	Method     string  `json:"method"`
-	Remark     string  `json:"remark"`
-	UserID     int64   `json:"-"`
+	ProjectID  int64        `json:"project_id" binding:"required"`
+	Stage      string       `json:"stage" binding:"required"`
+	Amount     money.Amount `json:"amount" binding:"required"`
+	Percentage float64      `json:"percentage"`
+	PlanDate   string       `json:"plan_date" binding:"required"`
+	Status     string       `json:"status"`
+	Method     string       `json:"method"`
+	Remark     string       `json:"remark"`
+	UserID     int64        `json:"-"`
 }
 
-// ConfirmPaymentRequest 确认收款请求
+// ConfirmPaymentRequest 确认收款请求 (登记一笔收款记录)
 type ConfirmPaymentRequest struct {
-	ActualDate string `json:"actual_date" binding:"required"`
-	Method     string `json:"method"`
+	ActualDate string       `json:"actual_date" binding:"required"` // 到账日期 (YYYY-MM-DD)
+	Amount     money.Amount `json:"amount"`                         // 到账金额，为 0 时登记全部未收金额
+	Method     string       `json:"method"`
+	Reference  string       `json:"reference"` // 流水号/凭证号
+}
+
+// RefundPaymentRequest 登记退款请求 (退款或贷项通知)
+type RefundPaymentRequest struct {
+	Type       string       `json:"type" binding:"omitempty,oneof=refund credit_note"` // 类型: refund (默认), credit_note
+	RefundDate string       `json:"refund_date" binding:"required"`                    // 退款日期 (YYYY-MM-DD)
+	Amount     money.Amount `json:"amount" binding:"required"`                         // 退款金额 (不超过款项的实收净额)
+	Method     string       `json:"method"`
+	Reference  string       `json:"reference"` // 流水号/贷项通知单号
+	Reason     string       `json:"reason"`    // 退款原因
+}
+
+// PaymentScheduleRequest 创建/修改周期收款计划请求
+type PaymentScheduleRequest struct {
+	ProjectID  int64        `json:"project_id"` // 关联项目ID (仅创建时有效)
+	Stage      string       `json:"stage" binding:"required"`
+	Amount     money.Amount `json:"amount" binding:"required"`                                   // 每期金额
+	Frequency  string       `json:"frequency" binding:"required,oneof=monthly quarterly yearly"` // 周期
+	DayOfMonth int          `json:"day_of_month" binding:"required"`                             // 每期收款日 (1-31)
+	StartDate  string       `json:"start_date" binding:"required"`                               // 开始日期 (YYYY-MM-DD)
+	EndDate    string       `json:"end_date"`                                                    // 结束日期 (可选)
+	Count      int          `json:"count"`                                                       // 总期数 (0 表示不限)
+	Method     string       `json:"method"`
+	Remark     string       `json:"remark"`
+}
+
+// 收款计划校验结果
+const (
+	PaymentPlanBalanced       = "balanced"        // 各期款项合计等于合同总金额
+	PaymentPlanUnderAllocated = "under_allocated" // 各期款项合计小于合同总金额
+	PaymentPlanOverAllocated  = "over_allocated"  // 各期款项合计大于合同总金额
+)
+
+// PaymentPlan 项目收款计划校验结果
+type PaymentPlan struct {
+	ProjectID         int64        `json:"project_id"`
+	TotalAmount       money.Amount `json:"total_amount"`       // 有效合同金额 (合同总金额 + 已批准变更金额)
+	PlannedAmount     money.Amount `json:"planned_amount"`     // 各期款项金额合计
+	PlannedPercentage float64      `json:"planned_percentage"` // 各期款项合计占合同总金额的百分比 (%)
+	Difference        money.Amount `json:"difference"`         // 合同总金额 - 款项合计 (正数为未分配金额，负数为超额分配金额)
+	Status            string       `json:"status"`             // balanced, under_allocated, over_allocated
+	FixedAmount       money.Amount `json:"fixed_amount"`       // 不参与调整的款项合计 (已收、部分收款及周期计划生成的款项)
+	AdjustableAmount  money.Amount `json:"adjustable_amount"`  // 可等比例调整的待收款项合计
+	AdjustableCount   int          `json:"adjustable_count"`   // 可调整的待收款项数量
 }
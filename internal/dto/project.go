@@ -1,6 +1,11 @@
 package dto
 
-import "github.com/FruitsAI/Orange/internal/models"
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/money"
+)
 
 // ProjectListResult 项目列表结果
 type ProjectListResult struct {
@@ -12,16 +17,101 @@ type ProjectListResult struct {
 
 // CreateProjectRequest 创建/更新项目请求
 type CreateProjectRequest struct {
-	Name           string  `json:"name" binding:"required"`
-	Company        string  `json:"company" binding:"required"`
-	TotalAmount    float64 `json:"total_amount" binding:"required"`
-	Status         string  `json:"status"`
-	Type           string  `json:"type" binding:"required"`
-	ContractNumber string  `json:"contract_number"`
-	ContractDate   string  `json:"contract_date"`
-	PaymentMethod  string  `json:"payment_method"`
-	StartDate      string  `json:"start_date" binding:"required"`
-	EndDate        string  `json:"end_date" binding:"required"`
-	Description    string  `json:"description"`
-	UserID         int64   `json:"-"`
+	Name           string       `json:"name" binding:"required"`
+	Company        string       `json:"company" binding:"required"`
+	TotalAmount    money.Amount `json:"total_amount" binding:"required"`
+	Status         string       `json:"status"`
+	Type           string       `json:"type" binding:"required"`
+	ContractNumber string       `json:"contract_number"`
+	ContractDate   string       `json:"contract_date"`
+	PaymentMethod  string       `json:"payment_method"`
+	StartDate      string       `json:"start_date" binding:"required"`
+	EndDate        string       `json:"end_date" binding:"required"`
+	Description    string       `json:"description"`
+	UserID         int64        `json:"-"`
+
+	// RebalancePayments 更新时按新的合同总金额等比例调整待收款项 (仅更新项目时有效)
+	RebalancePayments bool `json:"rebalance_payments"`
+}
+
+// AddProjectMemberRequest 邀请项目成员请求
+type AddProjectMemberRequest struct {
+	Account string `json:"account" binding:"required"`                  // 被邀请用户的用户名或邮箱
+	Role    string `json:"role" binding:"required,oneof=editor viewer"` // 成员角色
+}
+
+// UpdateProjectMemberRequest 修改项目成员角色请求
+type UpdateProjectMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=editor viewer"` // 成员角色
+}
+
+// ProjectTemplateRequest 创建/更新项目模板请求
+type ProjectTemplateRequest struct {
+	Name         string                        `json:"name" binding:"required"`
+	Type         string                        `json:"type" binding:"required"` // 适用的项目类型 (字典项)
+	Description  string                        `json:"description"`             // 默认项目描述
+	DurationDays int                           `json:"duration_days"`           // 默认工期 (天)
+	Stages       []ProjectTemplateStageRequest `json:"stages" binding:"required,min=1,dive"`
+}
+
+// ProjectTemplateStageRequest 项目模板的收款阶段
+type ProjectTemplateStageRequest struct {
+	Stage      string  `json:"stage" binding:"required"`      // 款项阶段 (payment_stage 字典值)
+	Percentage float64 `json:"percentage" binding:"required"` // 占合同总金额的百分比 (%)
+	OffsetDays int     `json:"offset_days"`                   // 相对项目开始日期的偏移天数
+}
+
+// CreateProjectFromTemplateRequest 从模板创建项目请求
+// 项目描述与计划结束日期为空时取模板默认值；款项按模板的收款阶段生成。
+type CreateProjectFromTemplateRequest struct {
+	TemplateID     int64        `json:"template_id"` // 模板ID (为空时使用项目类型对应的模板)
+	Name           string       `json:"name" binding:"required"`
+	Company        string       `json:"company" binding:"required"`
+	TotalAmount    money.Amount `json:"total_amount" binding:"required"`
+	Status         string       `json:"status"`
+	Type           string       `json:"type"` // 项目类型 (为空时取模板的项目类型)
+	ContractNumber string       `json:"contract_number"`
+	ContractDate   string       `json:"contract_date"`
+	PaymentMethod  string       `json:"payment_method"`
+	StartDate      string       `json:"start_date" binding:"required"`
+	EndDate        string       `json:"end_date"`    // 计划结束日期 (为空时按模板工期推算)
+	Description    string       `json:"description"` // 项目描述 (为空时取模板默认描述)
+	UserID         int64        `json:"-"`
+}
+
+// ChangeOrderRequest 创建/修改合同变更单请求
+type ChangeOrderRequest struct {
+	ProjectID int64        `json:"project_id"`                // 关联项目ID (仅创建时有效)
+	Number    string       `json:"number" binding:"required"` // 变更单编号 (项目内唯一)
+	Date      string       `json:"date" binding:"required"`   // 变更日期 (YYYY-MM-DD)
+	Amount    money.Amount `json:"amount" binding:"required"` // 变更金额 (正数为增加，负数为减少)
+	Reason    string       `json:"reason"`                    // 变更原因
+}
+
+// 合同时间线条目类型
+const (
+	ContractEntryOriginal    = "original"     // 原合同
+	ContractEntryChangeOrder = "change_order" // 已批准的变更单
+)
+
+// ContractTimeline 合同金额变更时间线
+type ContractTimeline struct {
+	ProjectID       int64                   `json:"project_id"`
+	OriginalAmount  money.Amount            `json:"original_amount"`  // 原合同金额
+	ChangeAmount    money.Amount            `json:"change_amount"`    // 已批准变更金额合计
+	EffectiveAmount money.Amount            `json:"effective_amount"` // 有效合同金额
+	Entries         []ContractTimelineEntry `json:"entries"`          // 按时间顺序排列的合同金额变化
+}
+
+// ContractTimelineEntry 合同时间线条目
+type ContractTimelineEntry struct {
+	Type          string       `json:"type"`                      // original, change_order
+	Date          time.Time    `json:"date"`                      // 签订日期或变更日期
+	ChangeOrderID int64        `json:"change_order_id,omitempty"` // 变更单ID
+	Number        string       `json:"number"`                    // 合同编号或变更单编号
+	Reason        string       `json:"reason"`                    // 变更原因
+	Amount        money.Amount `json:"amount"`                    // 原合同金额或本次变更金额
+	Total         money.Amount `json:"total"`                     // 本次变更后的有效合同金额
+	ApprovedBy    *int64       `json:"approved_by,omitempty"`     // 批准人ID
+	ApprovedAt    *time.Time   `json:"approved_at,omitempty"`     // 批准时间
 }
@@ -0,0 +1,23 @@
+package dto
+
+// RevisionDiffQuery 版本对比参数
+type RevisionDiffQuery struct {
+	From int `form:"from" binding:"required,min=1"` // 起始版本号
+	To   int `form:"to" binding:"required,min=1"`   // 目标版本号
+}
+
+// FieldChange 字段差异
+type FieldChange struct {
+	Field string      `json:"field"` // 字段名 (JSON 字段名)
+	From  interface{} `json:"from"`  // 起始版本的值
+	To    interface{} `json:"to"`    // 目标版本的值
+}
+
+// RevisionDiff 两个版本之间的差异
+type RevisionDiff struct {
+	EntityType string        `json:"entity_type"`
+	EntityID   int64         `json:"entity_id"`
+	From       int           `json:"from"`
+	To         int           `json:"to"`
+	Changes    []FieldChange `json:"changes"` // 发生变化的字段 (按字段名排序)
+}
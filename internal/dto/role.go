@@ -0,0 +1,21 @@
+package dto
+
+// CreateRoleRequest 创建角色请求
+type CreateRoleRequest struct {
+	Code        string   `json:"code" binding:"required"`
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"` // 权限编码列表
+}
+
+// UpdateRoleRequest 更新角色请求
+type UpdateRoleRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"` // 权限编码列表 (整体替换)
+}
+
+// AssignRoleRequest 分配用户角色请求
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required"` // 角色编码
+}
@@ -0,0 +1,19 @@
+package dto
+
+// SyncProfileRequest 创建/更新同步配置请求
+type SyncProfileRequest struct {
+	Name            string   `json:"name" binding:"required"`
+	DBType          string   `json:"db_type" binding:"required"` // postgres, mysql, sqlite
+	Host            string   `json:"host"`
+	Port            int      `json:"port"`
+	User            string   `json:"user"`
+	Password        string   `json:"password"` // 更新时为空表示不修改
+	DBName          string   `json:"db_name"`
+	SSLMode         string   `json:"ssl_mode"`
+	Path            string   `json:"path"`             // SQLite 文件路径 (仅 sqlite)
+	Tables          []string `json:"tables"`           // 同步表，为空表示全部
+	Direction       string   `json:"direction"`        // push (默认), pull, merge
+	Atomic          bool     `json:"atomic"`           // 是否整体事务同步
+	IntervalMinutes int      `json:"interval_minutes"` // 定时同步间隔 (分钟)，0 表示不定时
+	Enabled         bool     `json:"enabled"`          // 是否启用定时同步
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+// TrashItem 回收站条目
+type TrashItem struct {
+	ID        int64       `json:"id"`         // 记录ID
+	Type      string      `json:"type"`       // 类型: project, payment, user
+	Name      string      `json:"name"`       // 显示名称 (项目名称 / 款项阶段 / 用户名)
+	Detail    string      `json:"detail"`     // 补充说明 (客户 / 所属项目 / 姓名)
+	DeletedAt time.Time   `json:"deleted_at"` // 删除时间
+	PurgeAt   *time.Time  `json:"purge_at"`   // 预计自动彻底删除时间 (未启用自动清理时为空)
+	Data      interface{} `json:"data"`       // 删除前的完整记录
+}
@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// TwoFactorVerifyRequest 两步验证登录请求
+type TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"` // 登录接口返回的质询令牌
+	Code           string `json:"code" binding:"required"`            // 验证器生成的 6 位验证码或恢复码
+}
+
+// TwoFactorEnableRequest 启用两步验证请求
+type TwoFactorEnableRequest struct {
+	Code string `json:"code" binding:"required"` // 验证器生成的 6 位验证码
+}
+
+// TwoFactorDisableRequest 关闭两步验证请求
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" binding:"required"` // 当前密码
+	Code     string `json:"code" binding:"required"`     // 验证码或恢复码
+}
+
+// RegenerateRecoveryCodesRequest 重新生成恢复码请求
+type RegenerateRecoveryCodesRequest struct {
+	Password string `json:"password" binding:"required"` // 当前密码
+}
+
+// TwoFactorStatus 两步验证状态
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`                  // 是否已启用
+	EnabledAt              *time.Time `json:"enabled_at"`               // 启用时间
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"` // 剩余可用恢复码数量
+}
+
+// TwoFactorSetupResult 两步验证密钥
+type TwoFactorSetupResult struct {
+	Secret     string `json:"secret"`      // Base32 密钥 (无法扫码时手动输入)
+	OTPAuthURI string `json:"otpauth_uri"` // otpauth:// 链接 (用于生成二维码)
+}
+
+// RecoveryCodesResult 恢复码 (仅在生成时返回一次)
+type RecoveryCodesResult struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
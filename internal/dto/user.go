@@ -9,7 +9,7 @@ type CreateUserRequest struct {
 	Email    string `json:"email"`
 	Phone    string `json:"phone"`
 	Password string `json:"password" binding:"required,min=6"`
-	Role     string `json:"role"` // admin or user
+	Role     string `json:"role"` // 角色编码，为空时默认为 user
 }
 
 // UpdateUserRequest 管理员更新用户请求
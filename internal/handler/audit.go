@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler 审计日志 HTTP Handler
+// 查询审计日志需 audit:read 权限 (由路由中间件校验)。
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+// NewAuditHandler 创建审计日志 Handler 实例
+func NewAuditHandler() *AuditHandler {
+	return &AuditHandler{
+		auditService: service.NewAuditService(),
+	}
+}
+
+// List 分页查询审计日志
+// @Summary 审计日志列表
+// @Description 按操作人、操作类型、实体、来源及日期范围筛选数据变更记录，按时间倒序
+// @Tags Audit
+// @Security Bearer
+// @Param actor_id query int false "操作人ID"
+// @Param action query string false "操作类型"
+// @Param entity_type query string false "实体类型"
+// @Param entity_id query int false "实体ID"
+// @Param source query string false "来源 (session, token, anonymous)"
+// @Param start_date query string false "开始日期 YYYY-MM-DD"
+// @Param end_date query string false "结束日期 YYYY-MM-DD"
+// @Param page query int false "页码"
+// @Param page_size query int false "每页数量"
+// @Success 200 {object} dto.AuditLogListResult
+// @Router /api/v1/audit-logs [get]
+func (h *AuditHandler) List(c *gin.Context) {
+	var query dto.AuditLogQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	result, err := h.auditService.List(query)
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+	response.Success(c, result)
+}
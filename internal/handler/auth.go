@@ -1,7 +1,14 @@
 package handler
 
 import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
 	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/middleware"
+	"github.com/FruitsAI/Orange/internal/pkg/jwt"
 	"github.com/FruitsAI/Orange/internal/pkg/response"
 	"github.com/FruitsAI/Orange/internal/service"
 	"github.com/gin-gonic/gin"
@@ -10,19 +17,21 @@ import (
 // AuthHandler 认证模块接口处理器
 // 负责处理所有与用户认证授权相关的 HTTP 请求。
 type AuthHandler struct {
-	authService *service.AuthService
+	authService    *service.AuthService
+	sessionService *service.SessionService
 }
 
 // NewAuthHandler 创建认证处理器实例
 func NewAuthHandler() *AuthHandler {
 	return &AuthHandler{
-		authService: service.NewAuthService(),
+		authService:    service.NewAuthService(),
+		sessionService: service.NewSessionService(),
 	}
 }
 
 // Login 用户登录接口
 // @Summary 用户登录
-// @Description 验证用户名密码并返回JWT Token
+// @Description 验证用户名密码，返回短期访问令牌 (JWT) 与刷新令牌
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -38,17 +47,80 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	// 2. 调用服务层登录逻辑
-	result, err := h.authService.Login(req.Username, req.Password)
+	result, err := h.authService.Login(req.Username, req.Password, c.Request.UserAgent(), c.ClientIP())
 	if err != nil {
-		response.Error(c, response.CodeUnauthorized, err.Error())
+		loginError(c, err)
 		return
 	}
 
 	// 3. 返回 Token 及用户信息
-	response.Success(c, gin.H{
-		"token": result.Token,
-		"user":  result.User,
-	})
+	response.Success(c, result)
+}
+
+// Refresh 刷新访问令牌
+// @Summary 刷新访问令牌
+// @Description 使用刷新令牌换取新的访问令牌，刷新令牌同时轮换 (旧令牌立即失效)
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param refresh body dto.RefreshTokenRequest true "刷新令牌"
+// @Success 200 {object} dto.LoginResult
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "刷新令牌不能为空")
+		return
+	}
+
+	result, err := h.sessionService.Refresh(req.RefreshToken, c.Request.UserAgent(), c.ClientIP())
+	if errors.Is(err, service.ErrRefreshTokenInvalid) {
+		response.Error(c, response.CodeUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		response.InternalError(c, "刷新令牌失败")
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// VerifyTwoFactor 两步验证登录
+// @Summary 两步验证登录
+// @Description 登录接口返回 two_factor_required 时，提交质询令牌与验证器验证码 (或恢复码) 完成登录
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param verify body dto.TwoFactorVerifyRequest true "质询令牌与验证码"
+// @Success 200 {object} dto.LoginResult
+// @Router /api/v1/auth/2fa/verify [post]
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req dto.TwoFactorVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "验证码不能为空")
+		return
+	}
+
+	result, err := h.authService.VerifyTwoFactor(req.ChallengeToken, req.Code, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		loginError(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// loginError 返回登录失败响应
+// 失败退避或账户锁定时返回 CodeTooManyTries 并设置 Retry-After 头，其余错误返回 CodeUnauthorized。
+func loginError(c *gin.Context, err error) {
+	var throttled *service.LoginThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		response.Error(c, response.CodeTooManyTries, err.Error())
+		return
+	}
+	response.Error(c, response.CodeUnauthorized, err.Error())
 }
 
 // Register 用户注册接口
@@ -67,7 +139,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	err := h.authService.Register(req)
+	err := h.authService.Register(middleware.GetActor(c), req)
 	if err != nil {
 		response.ParamError(c, err.Error())
 		return
@@ -78,10 +150,27 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Logout 退出登录
 // @Summary 退出登录
-// @Description 客户端登出（当前由前端清除Token，后端仅做预留）
+// @Description 吊销当前登录会话。访问令牌 (Authorization 头) 与刷新令牌 (请求体) 任一有效即可定位会话，
+// @Description 访问令牌已过期时仍可通过刷新令牌退出。
 // @Tags Auth
+// @Param logout body dto.LogoutRequest false "刷新令牌"
 // @Router /api/v1/auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
+	var req dto.LogoutRequest
+	_ = c.ShouldBindJSON(&req) // 请求体可选
+
+	var sessionID int64
+	if parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2); len(parts) == 2 && parts[0] == "Bearer" {
+		if claims, err := jwt.ParseToken(parts[1]); err == nil {
+			sessionID = claims.SessionID
+		}
+	}
+
+	if err := h.sessionService.Logout(sessionID, req.RefreshToken); err != nil {
+		response.InternalError(c, "退出登录失败")
+		return
+	}
+
 	response.SuccessWithMessage(c, "退出成功", nil)
 }
 
@@ -129,7 +218,7 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	user, err := h.authService.UpdateProfile(userID, req.Name, req.Email, req.Phone, req.Department, req.Position)
+	user, err := h.authService.UpdateProfile(middleware.GetActor(c), req.Name, req.Email, req.Phone, req.Department, req.Position)
 	if err != nil {
 		response.InternalError(c, err.Error())
 		return
@@ -159,10 +248,62 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	if err := h.authService.ChangePassword(userID, req.OldPassword, req.NewPassword); err != nil {
+	if err := h.authService.ChangePassword(middleware.GetActor(c), req.OldPassword, req.NewPassword); err != nil {
 		response.ParamError(c, err.Error())
 		return
 	}
 
-	response.SuccessWithMessage(c, "密码修改成功", nil)
+	response.SuccessWithMessage(c, "密码修改成功，请重新登录", nil)
+}
+
+// ListSessions 获取当前用户的登录会话 (登录设备) 列表
+// @Summary 登录设备列表
+// @Description 获取当前用户全部有效的登录会话，current 标记当前设备
+// @Tags User
+// @Security Bearer
+// @Success 200 {array} models.UserSession
+// @Router /api/v1/users/me/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	sessions, err := h.sessionService.List(middleware.GetUserID(c), middleware.GetSessionID(c))
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.Success(c, sessions)
+}
+
+// RevokeSession 注销指定登录会话 (下线某台设备)
+// @Summary 注销登录设备
+// @Tags User
+// @Security Bearer
+// @Param id path int true "会话ID"
+// @Router /api/v1/users/me/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的会话ID")
+		return
+	}
+
+	if err := h.sessionService.Revoke(middleware.GetUserID(c), id); err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "已注销该设备", nil)
+}
+
+// RevokeOtherSessions 注销除当前设备外的全部登录会话
+// @Summary 注销其他设备
+// @Tags User
+// @Security Bearer
+// @Router /api/v1/users/me/sessions [delete]
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	if err := h.sessionService.RevokeOthers(middleware.GetUserID(c), middleware.GetSessionID(c)); err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "已注销其他设备", nil)
 }
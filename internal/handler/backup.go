@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// BackupHandler 本地数据库备份 HTTP Handler
+// 备份包含全部用户数据，需 system:backup 权限 (由路由中间件校验)。
+type BackupHandler struct {
+	backupService *service.BackupService
+}
+
+// NewBackupHandler 创建备份 Handler 实例
+func NewBackupHandler() *BackupHandler {
+	return &BackupHandler{
+		backupService: service.NewBackupService(),
+	}
+}
+
+// List 获取备份列表
+// @Router /api/v1/system/backups [get]
+func (h *BackupHandler) List(c *gin.Context) {
+	backups, err := h.backupService.List()
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+	response.Success(c, backups)
+}
+
+// Create 立即创建一份备份
+// @Router /api/v1/system/backups [post]
+func (h *BackupHandler) Create(c *gin.Context) {
+	backup, err := h.backupService.Create()
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "备份成功", backup)
+}
+
+// Restore 恢复指定备份
+// 恢复前会自动备份当前数据，返回该自动备份的信息。
+// @Router /api/v1/system/backups/{name}/restore [post]
+func (h *BackupHandler) Restore(c *gin.Context) {
+	safety, err := h.backupService.Restore(c.Param("name"))
+	if err != nil {
+		if safety != nil {
+			// 恢复事务已回滚，当前数据未被修改
+			response.InternalError(c, err.Error())
+			return
+		}
+		response.ParamError(c, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "恢复成功", gin.H{"pre_restore_backup": safety})
+}
+
+// Delete 删除指定备份
+// @Router /api/v1/system/backups/{name} [delete]
+func (h *BackupHandler) Delete(c *gin.Context) {
+	if err := h.backupService.Delete(c.Param("name")); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/middleware"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ChangeOrderHandler 合同变更单 HTTP Handler
+// 合同范围变更以变更单记录，批准后计入项目的有效合同金额。
+type ChangeOrderHandler struct {
+	changeOrderService *service.ChangeOrderService
+}
+
+// NewChangeOrderHandler 创建合同变更单 Handler 实例
+func NewChangeOrderHandler() *ChangeOrderHandler {
+	return &ChangeOrderHandler{
+		changeOrderService: service.NewChangeOrderService(),
+	}
+}
+
+// changeOrderError 将合同变更单服务错误映射为 HTTP 响应
+func changeOrderError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrChangeOrderNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrChangeOrderInvalid):
+		response.ParamError(c, err.Error())
+	default:
+		projectError(c, err, fallback)
+	}
+}
+
+// ListByProject 项目的合同变更单
+// @Summary 合同变更单列表
+// @Description 获取项目的全部变更单 (含草稿)，按变更日期升序
+// @Tags ChangeOrder
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Success 200 {array} models.ChangeOrder
+// @Router /api/v1/projects/{id}/change-orders [get]
+func (h *ChangeOrderHandler) ListByProject(c *gin.Context) {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+
+	orders, err := h.changeOrderService.ListByProject(c.GetInt64("user_id"), projectID)
+	if err != nil {
+		changeOrderError(c, err, "获取变更单失败")
+		return
+	}
+	response.Success(c, orders)
+}
+
+// Timeline 合同金额变更时间线
+// @Summary 合同金额时间线
+// @Description 以原合同为起点，按变更日期列出已批准的变更单及变更后的有效合同金额
+// @Tags ChangeOrder
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Success 200 {object} dto.ContractTimeline
+// @Router /api/v1/projects/{id}/contract-timeline [get]
+func (h *ChangeOrderHandler) Timeline(c *gin.Context) {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+
+	timeline, err := h.changeOrderService.Timeline(c.GetInt64("user_id"), projectID)
+	if err != nil {
+		changeOrderError(c, err, "获取合同金额时间线失败")
+		return
+	}
+	response.Success(c, timeline)
+}
+
+// Create 创建变更单
+// @Summary 创建变更单
+// @Description 创建变更单草稿，金额为正表示增项、为负表示减项；批准前不影响合同金额
+// @Tags ChangeOrder
+// @Security Bearer
+// @Param order body dto.ChangeOrderRequest true "变更单信息"
+// @Success 200 {object} models.ChangeOrder
+// @Router /api/v1/change-orders [post]
+func (h *ChangeOrderHandler) Create(c *gin.Context) {
+	var req dto.ChangeOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	order, err := h.changeOrderService.Create(middleware.GetActor(c), req)
+	if err != nil {
+		changeOrderError(c, err, "创建变更单失败")
+		return
+	}
+	response.Success(c, order)
+}
+
+// Update 修改变更单
+// @Summary 修改变更单
+// @Description 修改变更单草稿，已批准的变更单不能修改
+// @Tags ChangeOrder
+// @Security Bearer
+// @Param id path int true "变更单ID"
+// @Param order body dto.ChangeOrderRequest true "变更单信息 (project_id 不可修改)"
+// @Success 200 {object} models.ChangeOrder
+// @Router /api/v1/change-orders/{id} [put]
+func (h *ChangeOrderHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的变更单ID")
+		return
+	}
+
+	var req dto.ChangeOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	order, err := h.changeOrderService.Update(middleware.GetActor(c), id, req)
+	if err != nil {
+		changeOrderError(c, err, "修改变更单失败")
+		return
+	}
+	response.SuccessWithMessage(c, "修改成功", order)
+}
+
+// Delete 删除变更单
+// @Summary 删除变更单
+// @Description 删除变更单草稿，已批准的变更单不能删除
+// @Tags ChangeOrder
+// @Security Bearer
+// @Param id path int true "变更单ID"
+// @Success 200 {string} string "删除成功"
+// @Router /api/v1/change-orders/{id} [delete]
+func (h *ChangeOrderHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的变更单ID")
+		return
+	}
+
+	if err := h.changeOrderService.Delete(middleware.GetActor(c), id); err != nil {
+		changeOrderError(c, err, "删除变更单失败")
+		return
+	}
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// Approve 批准变更单
+// @Summary 批准变更单
+// @Description 项目所有者批准变更单，变更金额计入有效合同金额并重新计算款项占比
+// @Tags ChangeOrder
+// @Security Bearer
+// @Param id path int true "变更单ID"
+// @Success 200 {object} models.ChangeOrder
+// @Router /api/v1/change-orders/{id}/approve [post]
+func (h *ChangeOrderHandler) Approve(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的变更单ID")
+		return
+	}
+
+	order, err := h.changeOrderService.Approve(middleware.GetActor(c), id)
+	if err != nil {
+		changeOrderError(c, err, "批准变更单失败")
+		return
+	}
+	response.SuccessWithMessage(c, "已批准", order)
+}
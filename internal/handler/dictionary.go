@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/middleware"
 	"github.com/FruitsAI/Orange/internal/pkg/response"
 	"github.com/FruitsAI/Orange/internal/service"
 	"github.com/gin-gonic/gin"
@@ -61,7 +62,7 @@ func (h *DictionaryHandler) GetItems(c *gin.Context) {
 
 // CreateItem 新增字典选项
 // @Summary 创建字典项
-// @Description 为指定字典添加一个新的选项值(仅限管理员)
+// @Description 为指定字典添加一个新的选项值(需 dictionaries:manage 权限)
 // @Tags Dictionary
 // @Security Bearer
 // @Param code path string true "字典编码"
@@ -70,22 +71,17 @@ func (h *DictionaryHandler) GetItems(c *gin.Context) {
 // @Failure 403 {string} string "无权操作"
 // @Router /api/v1/dictionaries/{code}/items [post]
 func (h *DictionaryHandler) CreateItem(c *gin.Context) {
-	// 1. 权限校验
-	if c.GetString("role") != "admin" {
-		response.Forbidden(c, "无权操作")
-		return
-	}
 	code := c.Param("code")
 
-	// 2. 参数绑定
+	// 1. 参数绑定
 	var req dto.CreateDictionaryItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ParamError(c, err.Error())
 		return
 	}
 
-	// 3. 执行创建
-	item, err := h.dictService.CreateItem(code, req.Label, req.Value, req.Sort)
+	// 2. 执行创建
+	item, err := h.dictService.CreateItem(middleware.GetActor(c), code, req.Label, req.Value, req.Sort)
 	if err != nil {
 		response.InternalError(c, "创建字典项失败")
 		return
@@ -96,7 +92,7 @@ func (h *DictionaryHandler) CreateItem(c *gin.Context) {
 
 // UpdateItem 更新字典选项
 // @Summary 更新字典项
-// @Description 更新现有字典选项的名称、值或排序(仅限管理员)
+// @Description 更新现有字典选项的名称、值或排序(需 dictionaries:manage 权限)
 // @Tags Dictionary
 // @Security Bearer
 // @Param code path string true "字典编码 (仅作路由占位)"
@@ -106,28 +102,22 @@ func (h *DictionaryHandler) CreateItem(c *gin.Context) {
 // @Failure 403 {string} string "无权操作"
 // @Router /api/v1/dictionaries/{code}/items/{id} [put]
 func (h *DictionaryHandler) UpdateItem(c *gin.Context) {
-	// 1. 权限校验
-	if c.GetString("role") != "admin" {
-		response.Forbidden(c, "无权操作")
-		return
-	}
-
-	// 2. ID解析
+	// 1. ID解析
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的字典项ID")
 		return
 	}
 
-	// 3. 参数绑定
+	// 2. 参数绑定
 	var req dto.CreateDictionaryItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ParamError(c, err.Error())
 		return
 	}
 
-	// 4. 执行更新
-	item, err := h.dictService.UpdateItem(id, req.Label, req.Value, req.Sort)
+	// 3. 执行更新
+	item, err := h.dictService.UpdateItem(middleware.GetActor(c), id, req.Label, req.Value, req.Sort)
 	if err != nil {
 		response.InternalError(c, "更新字典项失败")
 		return
@@ -138,7 +128,7 @@ func (h *DictionaryHandler) UpdateItem(c *gin.Context) {
 
 // DeleteItem 删除字典选项
 // @Summary 删除字典项
-// @Description 物理删除指定的字典选项(仅限管理员)
+// @Description 物理删除指定的字典选项(需 dictionaries:manage 权限)
 // @Tags Dictionary
 // @Security Bearer
 // @Param code path string true "字典编码 (仅作路由占位)"
@@ -147,17 +137,13 @@ func (h *DictionaryHandler) UpdateItem(c *gin.Context) {
 // @Failure 403 {string} string "无权操作"
 // @Router /api/v1/dictionaries/{code}/items/{id} [delete]
 func (h *DictionaryHandler) DeleteItem(c *gin.Context) {
-	if c.GetString("role") != "admin" {
-		response.Forbidden(c, "无权操作")
-		return
-	}
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的字典项ID")
 		return
 	}
 
-	if err := h.dictService.DeleteItem(id); err != nil {
+	if err := h.dictService.DeleteItem(middleware.GetActor(c), id); err != nil {
 		response.InternalError(c, "删除字典项失败")
 		return
 	}
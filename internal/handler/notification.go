@@ -3,6 +3,7 @@ package handler
 import (
 	"strconv"
 
+	"github.com/FruitsAI/Orange/internal/middleware"
 	"github.com/FruitsAI/Orange/internal/models"
 	"github.com/FruitsAI/Orange/internal/pkg/response"
 	"github.com/FruitsAI/Orange/internal/service"
@@ -40,24 +41,15 @@ type CreateNotificationRequest struct {
 // @Failure 403 {string} string "无权操作"
 // @Router /api/v1/notifications [post]
 func (h *NotificationHandler) Create(c *gin.Context) {
-	userID := c.GetInt64("user_id")
-	role := c.GetString("role")
-
-	// 1. 权限校验
-	if role != "admin" {
-		response.Forbidden(c)
-		return
-	}
-
-	// 2. 参数绑定
+	// 1. 参数绑定
 	var req CreateNotificationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ParamError(c, err.Error())
 		return
 	}
 
-	// 3. 调用服务层
-	notification, err := h.notificationService.Create(userID, req.Title, req.Content, req.Type, req.TargetUserID)
+	// 2. 调用服务层
+	notification, err := h.notificationService.Create(middleware.GetActor(c), req.Title, req.Content, req.Type, req.TargetUserID)
 	if err != nil {
 		response.InternalError(c, err.Error())
 		return
@@ -68,7 +60,7 @@ func (h *NotificationHandler) Create(c *gin.Context) {
 
 // Update 更新通知内容
 // @Summary 更新通知
-// @Description 修改现有通知的标题、内容等信息(仅限管理员)
+// @Description 修改现有通知的标题、内容等信息(需 notifications:manage 权限)
 // @Tags Notification
 // @Security Bearer
 // @Param id path int true "通知ID"
@@ -77,29 +69,21 @@ func (h *NotificationHandler) Create(c *gin.Context) {
 // @Failure 403 {string} string "无权操作"
 // @Router /api/v1/notifications/{id} [put]
 func (h *NotificationHandler) Update(c *gin.Context) {
-	role := c.GetString("role")
-
-	// 1. 权限校验
-	if role != "admin" {
-		response.Forbidden(c)
-		return
-	}
-
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的通知ID")
 		return
 	}
 
-	// 2. 参数绑定
+	// 1. 参数绑定
 	var req CreateNotificationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ParamError(c, err.Error())
 		return
 	}
 
-	// 3. 执行更新
-	notification, err := h.notificationService.Update(id, req.Title, req.Content, req.Type, req.TargetUserID)
+	// 2. 执行更新
+	notification, err := h.notificationService.Update(middleware.GetActor(c), id, req.Title, req.Content, req.Type, req.TargetUserID)
 	if err != nil {
 		response.InternalError(c, err.Error())
 		return
@@ -172,21 +156,13 @@ func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
 // @Failure 403 {string} string "无权操作"
 // @Router /api/v1/notifications/{id} [delete]
 func (h *NotificationHandler) Delete(c *gin.Context) {
-	role := c.GetString("role")
-
-	// 1. 权限校验
-	if role != "admin" {
-		response.Forbidden(c)
-		return
-	}
-
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的通知ID")
 		return
 	}
 
-	if err := h.notificationService.Delete(id); err != nil {
+	if err := h.notificationService.Delete(middleware.GetActor(c), id); err != nil {
 		response.InternalError(c, err.Error())
 		return
 	}
@@ -215,21 +191,13 @@ func (h *NotificationHandler) UnreadCount(c *gin.Context) {
 
 // ListUsers 获取可选用户列表
 // @Summary 获取用户列表
-// @Description 获取所有用户列表，用于发送通知时选择目标(仅限管理员)
+// @Description 获取所有用户列表，用于发送通知时选择目标(需 notifications:manage 权限)
 // @Tags Notification
 // @Security Bearer
 // @Success 200 {array} models.User
 // @Failure 403 {string} string "无权操作"
 // @Router /api/v1/notifications/users [get]
 func (h *NotificationHandler) ListUsers(c *gin.Context) {
-	role := c.GetString("role")
-
-	// 权限校验
-	if role != "admin" {
-		response.Forbidden(c)
-		return
-	}
-
 	users, err := h.notificationService.ListUsers()
 	if err != nil {
 		response.InternalError(c, err.Error())
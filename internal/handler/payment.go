@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/middleware"
 	"github.com/FruitsAI/Orange/internal/pkg/response"
 	"github.com/FruitsAI/Orange/internal/service"
 	"github.com/gin-gonic/gin"
@@ -31,15 +32,16 @@ func NewPaymentHandler() *PaymentHandler {
 // @Success 200 {array} models.Payment
 // @Router /api/v1/projects/{id}/payments [get]
 func (h *PaymentHandler) GetByProject(c *gin.Context) {
+	userID := c.GetInt64("user_id")
 	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的项目ID")
 		return
 	}
 
-	payments, err := h.paymentService.ListByProject(projectID)
+	payments, err := h.paymentService.ListByProject(userID, projectID)
 	if err != nil {
-		response.InternalError(c, "获取收款列表失败")
+		projectError(c, err, "获取收款列表失败")
 		return
 	}
 
@@ -80,9 +82,9 @@ func (h *PaymentHandler) List(c *gin.Context) {
 			response.ParamError(c, "无效的项目ID")
 			return
 		}
-		payments, err := h.paymentService.ListByProject(projectID)
+		payments, err := h.paymentService.ListByProject(userID, projectID)
 		if err != nil {
-			response.InternalError(c, "获取收款列表失败")
+			projectError(c, err, "获取收款列表失败")
 			return
 		}
 		response.Success(c, payments)
@@ -112,9 +114,9 @@ func (h *PaymentHandler) Create(c *gin.Context) {
 
 	req.UserID = userID // 手动设置 UserID，确保数据归属正确
 
-	payment, err := h.paymentService.Create(req)
+	payment, err := h.paymentService.Create(middleware.GetActor(c), req)
 	if err != nil {
-		response.InternalError(c, "创建收款失败")
+		projectError(c, err, "创建收款失败")
 		return
 	}
 
@@ -143,9 +145,9 @@ func (h *PaymentHandler) Update(c *gin.Context) {
 		return
 	}
 
-	payment, err := h.paymentService.Update(id, req)
+	payment, err := h.paymentService.Update(middleware.GetActor(c), id, req)
 	if err != nil {
-		response.InternalError(c, "更新收款失败")
+		projectError(c, err, "更新收款失败")
 		return
 	}
 
@@ -167,22 +169,68 @@ func (h *PaymentHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.paymentService.Delete(id); err != nil {
-		response.InternalError(c, "删除收款失败")
+	if err := h.paymentService.Delete(middleware.GetActor(c), id); err != nil {
+		projectError(c, err, "删除收款失败")
 		return
 	}
 
 	response.SuccessWithMessage(c, "删除成功", nil)
 }
 
+// ValidatePlan 校验项目收款计划
+// @Summary 校验收款计划
+// @Description 比较各期款项合计与合同总金额，报告未分配 (under_allocated) 或超额分配 (over_allocated) 的金额
+// @Tags Payment
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Success 200 {object} dto.PaymentPlan
+// @Router /api/v1/projects/{id}/payment-plan [get]
+func (h *PaymentHandler) ValidatePlan(c *gin.Context) {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+
+	plan, err := h.paymentService.ValidatePlan(c.GetInt64("user_id"), projectID)
+	if err != nil {
+		projectError(c, err, "校验收款计划失败")
+		return
+	}
+	response.Success(c, plan)
+}
+
+// RebalancePlan 调整项目收款计划
+// @Summary 调整收款计划
+// @Description 按合同总金额等比例调整尚未收款的款项，使各期款项合计等于合同总金额；已收款、部分收款及周期计划生成的款项保持不变
+// @Tags Payment
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Success 200 {object} dto.PaymentPlan
+// @Router /api/v1/projects/{id}/payment-plan/rebalance [post]
+func (h *PaymentHandler) RebalancePlan(c *gin.Context) {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+
+	plan, err := h.paymentService.RebalancePlan(middleware.GetActor(c), projectID)
+	if err != nil {
+		projectError(c, err, "调整收款计划失败")
+		return
+	}
+	response.SuccessWithMessage(c, "调整成功", plan)
+}
+
 // Confirm 确认收款到位
 // @Summary 确认收款
-// @Description 将款项状态流转为"已收款"，并记录实际收款日期和方式
+// @Description 为款项登记一笔收款记录 (金额为空时登记全部未收金额)，款项状态随之流转为"部分收款"或"已收款"
 // @Tags Payment
 // @Security Bearer
 // @Param id path int true "款项ID"
 // @Param confirm body dto.ConfirmPaymentRequest true "确认信息"
-// @Success 200 {string} string "确认成功"
+// @Success 200 {object} models.PaymentReceipt
 // @Router /api/v1/payments/{id}/confirm [post]
 func (h *PaymentHandler) Confirm(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -197,10 +245,234 @@ func (h *PaymentHandler) Confirm(c *gin.Context) {
 		return
 	}
 
-	if err := h.paymentService.Confirm(id, req.ActualDate, req.Method); err != nil {
-		response.InternalError(c, "确认收款失败")
+	receipt, err := h.paymentService.Confirm(middleware.GetActor(c), id, req)
+	if err != nil {
+		projectError(c, err, "确认收款失败")
+		return
+	}
+
+	response.SuccessWithMessage(c, "确认成功", receipt)
+}
+
+// ListReceipts 获取款项的收款记录
+// @Summary 收款记录列表
+// @Description 获取款项下登记的每笔收款 (金额、到账日期、方式、流水号)，按到账日期倒序
+// @Tags Payment
+// @Security Bearer
+// @Param id path int true "款项ID"
+// @Success 200 {array} models.PaymentReceipt
+// @Router /api/v1/payments/{id}/receipts [get]
+func (h *PaymentHandler) ListReceipts(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的收款ID")
 		return
 	}
 
-	response.SuccessWithMessage(c, "确认成功", nil)
+	receipts, err := h.paymentService.ListReceipts(middleware.GetUserID(c), id)
+	if err != nil {
+		projectError(c, err, "获取收款记录失败")
+		return
+	}
+
+	response.Success(c, receipts)
+}
+
+// DeleteReceipt 删除收款记录
+// @Summary 删除收款记录
+// @Description 撤销登记错误的收款，款项的已收金额与状态随之重新计算
+// @Tags Payment
+// @Security Bearer
+// @Param id path int true "款项ID"
+// @Param receipt_id path int true "收款记录ID"
+// @Success 200 {string} string "删除成功"
+// @Router /api/v1/payments/{id}/receipts/{receipt_id} [delete]
+func (h *PaymentHandler) DeleteReceipt(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的收款ID")
+		return
+	}
+	receiptID, err := strconv.ParseInt(c.Param("receipt_id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的收款记录ID")
+		return
+	}
+
+	if err := h.paymentService.DeleteReceipt(middleware.GetActor(c), id, receiptID); err != nil {
+		projectError(c, err, "删除收款记录失败")
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// Refund 登记退款
+// @Summary 登记退款
+// @Description 为已收款项登记一笔退款或贷项通知，金额不能超过款项的实收净额；款项与项目的实收净额随之冲减
+// @Tags Payment
+// @Security Bearer
+// @Param id path int true "款项ID"
+// @Param refund body dto.RefundPaymentRequest true "退款信息"
+// @Success 200 {object} models.PaymentRefund
+// @Router /api/v1/payments/{id}/refunds [post]
+func (h *PaymentHandler) Refund(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的收款ID")
+		return
+	}
+
+	var req dto.RefundPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	refund, err := h.paymentService.Refund(middleware.GetActor(c), id, req)
+	if err != nil {
+		projectError(c, err, "登记退款失败")
+		return
+	}
+
+	response.SuccessWithMessage(c, "退款已登记", refund)
+}
+
+// ListRefunds 获取款项的退款记录
+// @Summary 退款记录列表
+// @Description 获取款项下登记的退款与贷项通知，按退款日期倒序
+// @Tags Payment
+// @Security Bearer
+// @Param id path int true "款项ID"
+// @Success 200 {array} models.PaymentRefund
+// @Router /api/v1/payments/{id}/refunds [get]
+func (h *PaymentHandler) ListRefunds(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的收款ID")
+		return
+	}
+
+	refunds, err := h.paymentService.ListRefunds(middleware.GetUserID(c), id)
+	if err != nil {
+		projectError(c, err, "获取退款记录失败")
+		return
+	}
+
+	response.Success(c, refunds)
+}
+
+// DeleteRefund 删除退款记录
+// @Summary 删除退款记录
+// @Description 撤销登记错误的退款，款项与项目的实收净额随之重新计算
+// @Tags Payment
+// @Security Bearer
+// @Param id path int true "款项ID"
+// @Param refund_id path int true "退款记录ID"
+// @Success 200 {string} string "删除成功"
+// @Router /api/v1/payments/{id}/refunds/{refund_id} [delete]
+func (h *PaymentHandler) DeleteRefund(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的收款ID")
+		return
+	}
+	refundID, err := strconv.ParseInt(c.Param("refund_id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的退款记录ID")
+		return
+	}
+
+	if err := h.paymentService.DeleteRefund(middleware.GetActor(c), id, refundID); err != nil {
+		projectError(c, err, "删除退款记录失败")
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// ListRevisions 获取款项历史版本
+// @Summary 款项历史版本
+// @Description 获取款项每次变更后保存的快照，按版本号倒序
+// @Tags Payment
+// @Security Bearer
+// @Param id path int true "款项ID"
+// @Success 200 {array} models.Revision
+// @Router /api/v1/payments/{id}/revisions [get]
+func (h *PaymentHandler) ListRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的收款ID")
+		return
+	}
+
+	revisions, err := h.paymentService.ListRevisions(middleware.GetUserID(c), id)
+	if err != nil {
+		projectError(c, err, "获取历史版本失败")
+		return
+	}
+
+	response.Success(c, revisions)
+}
+
+// DiffRevisions 对比款项的两个历史版本
+// @Summary 对比款项版本
+// @Description 返回两个版本之间值不同的字段
+// @Tags Payment
+// @Security Bearer
+// @Param id path int true "款项ID"
+// @Param from query int true "起始版本号"
+// @Param to query int true "目标版本号"
+// @Success 200 {object} dto.RevisionDiff
+// @Router /api/v1/payments/{id}/revisions/diff [get]
+func (h *PaymentHandler) DiffRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的收款ID")
+		return
+	}
+
+	var query dto.RevisionDiffQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	diff, err := h.paymentService.DiffRevisions(middleware.GetUserID(c), id, query.From, query.To)
+	if err != nil {
+		projectError(c, err, "对比版本失败")
+		return
+	}
+
+	response.Success(c, diff)
+}
+
+// RestoreRevision 将款项恢复到指定版本
+// @Summary 恢复款项版本
+// @Description 以指定版本的快照覆盖款项当前信息，并重新同步项目已收款总额
+// @Tags Payment
+// @Security Bearer
+// @Param id path int true "款项ID"
+// @Param version path int true "版本号"
+// @Success 200 {object} models.Payment
+// @Router /api/v1/payments/{id}/revisions/{version}/restore [post]
+func (h *PaymentHandler) RestoreRevision(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的收款ID")
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		response.ParamError(c, "无效的版本号")
+		return
+	}
+
+	payment, err := h.paymentService.RestoreRevision(middleware.GetActor(c), id, version)
+	if err != nil {
+		projectError(c, err, "恢复版本失败")
+		return
+	}
+
+	response.Success(c, payment)
 }
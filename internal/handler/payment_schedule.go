@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/middleware"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduleHandler 周期收款计划 HTTP Handler
+// 按月/季/年固定收费的项目可设置周期收款计划，款项由后台任务按计划自动生成。
+type ScheduleHandler struct {
+	scheduleService *service.ScheduleService
+}
+
+// NewScheduleHandler 创建周期收款计划 Handler 实例
+func NewScheduleHandler() *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: service.NewScheduleService(),
+	}
+}
+
+// scheduleError 将周期收款计划服务错误映射为 HTTP 响应
+func scheduleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrScheduleNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrScheduleInvalid):
+		response.ParamError(c, err.Error())
+	default:
+		projectError(c, err, fallback)
+	}
+}
+
+// ListByProject 项目的周期收款计划
+// @Summary 周期收款计划列表
+// @Description 获取项目的周期收款计划，按创建时间倒序
+// @Tags PaymentSchedule
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Success 200 {array} models.PaymentSchedule
+// @Router /api/v1/projects/{id}/payment-schedules [get]
+func (h *ScheduleHandler) ListByProject(c *gin.Context) {
+	projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+
+	schedules, err := h.scheduleService.ListByProject(c.GetInt64("user_id"), projectID)
+	if err != nil {
+		scheduleError(c, err, "获取周期收款计划失败")
+		return
+	}
+	response.Success(c, schedules)
+}
+
+// Create 创建周期收款计划
+// @Summary 创建周期收款计划
+// @Description 按月/季/年创建周期收款计划，并立即生成未来 PAYMENT_SCHEDULE_HORIZON_DAYS 天内到期的款项
+// @Tags PaymentSchedule
+// @Security Bearer
+// @Param schedule body dto.PaymentScheduleRequest true "计划信息"
+// @Success 200 {object} models.PaymentSchedule
+// @Router /api/v1/payment-schedules [post]
+func (h *ScheduleHandler) Create(c *gin.Context) {
+	var req dto.PaymentScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	schedule, err := h.scheduleService.Create(middleware.GetActor(c), req)
+	if err != nil {
+		scheduleError(c, err, "创建周期收款计划失败")
+		return
+	}
+	response.Success(c, schedule)
+}
+
+// Update 修改周期收款计划
+// @Summary 修改周期收款计划
+// @Description 修改计划规则，尚未到期的待收款项按新规则重新生成；已收款、部分收款与已逾期的款项保留不动
+// @Tags PaymentSchedule
+// @Security Bearer
+// @Param id path int true "计划ID"
+// @Param schedule body dto.PaymentScheduleRequest true "计划信息 (project_id 不可修改)"
+// @Success 200 {object} models.PaymentSchedule
+// @Router /api/v1/payment-schedules/{id} [put]
+func (h *ScheduleHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的计划ID")
+		return
+	}
+
+	var req dto.PaymentScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	schedule, err := h.scheduleService.Update(middleware.GetActor(c), id, req)
+	if err != nil {
+		scheduleError(c, err, "修改周期收款计划失败")
+		return
+	}
+	response.SuccessWithMessage(c, "修改成功", schedule)
+}
+
+// Stop 停止周期收款计划
+// @Summary 停止周期收款计划
+// @Description 停止生成新的款项，并移除尚未到期的待收款项
+// @Tags PaymentSchedule
+// @Security Bearer
+// @Param id path int true "计划ID"
+// @Success 200 {object} models.PaymentSchedule
+// @Router /api/v1/payment-schedules/{id}/stop [post]
+func (h *ScheduleHandler) Stop(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的计划ID")
+		return
+	}
+
+	schedule, err := h.scheduleService.Stop(middleware.GetActor(c), id)
+	if err != nil {
+		scheduleError(c, err, "停止周期收款计划失败")
+		return
+	}
+	response.SuccessWithMessage(c, "已停止", schedule)
+}
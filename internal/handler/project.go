@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/middleware"
 	"github.com/FruitsAI/Orange/internal/pkg/response"
 	"github.com/FruitsAI/Orange/internal/service"
 	"github.com/gin-gonic/gin"
@@ -22,9 +24,27 @@ func NewProjectHandler() *ProjectHandler {
 	}
 }
 
+// projectError 输出项目相关错误
+// 项目、款项或版本不存在 (含无访问权限) 返回 NotFound，权限不足返回 Forbidden，其余返回 fallback 提示。
+func projectError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound), errors.Is(err, service.ErrPaymentNotFound),
+		errors.Is(err, service.ErrRevisionNotFound), errors.Is(err, service.ErrReceiptNotFound),
+		errors.Is(err, service.ErrRefundNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrProjectForbidden):
+		response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrReceiptInvalid), errors.Is(err, service.ErrRefundInvalid),
+		errors.Is(err, service.ErrPlanRebalance):
+		response.ParamError(c, err.Error())
+	default:
+		response.InternalError(c, fallback)
+	}
+}
+
 // List 获取项目列表
 // @Summary 获取项目列表
-// @Description 分页查询项目，支持按状态(status)和关键词(keyword)搜索
+// @Description 分页查询当前用户负责或被共享的项目，支持按状态(status)和关键词(keyword)搜索
 // @Tags Project
 // @Security Bearer
 // @Param page query int false "页码" default(1)
@@ -58,15 +78,16 @@ func (h *ProjectHandler) List(c *gin.Context) {
 // @Success 200 {object} models.Project
 // @Router /api/v1/projects/{id} [get]
 func (h *ProjectHandler) Get(c *gin.Context) {
+	userID := c.GetInt64("user_id")
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的项目ID")
 		return
 	}
 
-	project, err := h.projectService.Get(id)
+	project, err := h.projectService.Get(userID, id)
 	if err != nil {
-		response.NotFound(c, "项目不存在")
+		projectError(c, err, "获取项目失败")
 		return
 	}
 
@@ -92,7 +113,7 @@ func (h *ProjectHandler) Create(c *gin.Context) {
 
 	req.UserID = userID // 手动设置 UserID
 
-	project, err := h.projectService.Create(req)
+	project, err := h.projectService.Create(middleware.GetActor(c), req)
 	if err != nil {
 		response.InternalError(c, "创建项目失败")
 		return
@@ -123,9 +144,9 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 		return
 	}
 
-	project, err := h.projectService.Update(id, req)
+	project, err := h.projectService.Update(middleware.GetActor(c), id, req)
 	if err != nil {
-		response.InternalError(c, "更新项目失败")
+		projectError(c, err, "更新项目失败")
 		return
 	}
 
@@ -134,7 +155,7 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 
 // Delete 删除项目
 // @Summary 删除项目
-// @Description 软删除或硬删除项目记录(视Repo实现而定)，通常会级联删除关联款项
+// @Description 将项目及其关联款项移入回收站，可在保留期内恢复，仅项目所有者可操作
 // @Tags Project
 // @Security Bearer
 // @Param id path int true "项目ID"
@@ -147,8 +168,8 @@ func (h *ProjectHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.projectService.Delete(id); err != nil {
-		response.InternalError(c, "删除项目失败")
+	if err := h.projectService.Delete(middleware.GetActor(c), id); err != nil {
+		projectError(c, err, "删除项目失败")
 		return
 	}
 
@@ -170,8 +191,8 @@ func (h *ProjectHandler) Archive(c *gin.Context) {
 		return
 	}
 
-	if err := h.projectService.Archive(id); err != nil {
-		response.InternalError(c, "归档项目失败")
+	if err := h.projectService.Archive(middleware.GetActor(c), id); err != nil {
+		projectError(c, err, "归档项目失败")
 		return
 	}
 
@@ -230,3 +251,223 @@ func (h *ProjectHandler) GenerateContractNumber(c *gin.Context) {
 
 	response.Success(c, gin.H{"contract_number": contractNumber})
 }
+
+// ListMembers 获取项目成员列表
+// @Summary 项目成员列表
+// @Description 获取项目所有者及共享成员，项目的任何成员均可查看
+// @Tags Project
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Success 200 {array} models.ProjectMember
+// @Router /api/v1/projects/{id}/members [get]
+func (h *ProjectHandler) ListMembers(c *gin.Context) {
+	userID := c.GetInt64("user_id")
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+
+	members, err := h.projectService.ListMembers(userID, id)
+	if err != nil {
+		projectError(c, err, "获取项目成员失败")
+		return
+	}
+
+	response.Success(c, members)
+}
+
+// AddMember 邀请项目成员
+// @Summary 邀请成员
+// @Description 按用户名或邮箱将项目共享给其他用户，仅项目所有者可操作
+// @Tags Project
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Param member body dto.AddProjectMemberRequest true "成员信息"
+// @Success 200 {object} models.ProjectMember
+// @Router /api/v1/projects/{id}/members [post]
+func (h *ProjectHandler) AddMember(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+
+	var req dto.AddProjectMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	member, err := h.projectService.AddMember(middleware.GetActor(c), id, req)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) || errors.Is(err, service.ErrProjectForbidden) {
+			projectError(c, err, "")
+			return
+		}
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.Success(c, member)
+}
+
+// UpdateMember 修改项目成员角色
+// @Summary 修改成员角色
+// @Description 调整共享成员的角色 (editor/viewer)，仅项目所有者可操作
+// @Tags Project
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Param user_id path int true "成员用户ID"
+// @Param member body dto.UpdateProjectMemberRequest true "角色"
+// @Success 200 {object} models.ProjectMember
+// @Router /api/v1/projects/{id}/members/{user_id} [put]
+func (h *ProjectHandler) UpdateMember(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+	memberUserID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	var req dto.UpdateProjectMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	member, err := h.projectService.UpdateMember(middleware.GetActor(c), id, memberUserID, req.Role)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) || errors.Is(err, service.ErrProjectForbidden) {
+			projectError(c, err, "")
+			return
+		}
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.Success(c, member)
+}
+
+// RemoveMember 移除项目成员
+// @Summary 移除成员
+// @Description 所有者可移除任意成员，成员可将自己移出项目
+// @Tags Project
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Param user_id path int true "成员用户ID"
+// @Success 200 {string} string "移除成功"
+// @Router /api/v1/projects/{id}/members/{user_id} [delete]
+func (h *ProjectHandler) RemoveMember(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+	memberUserID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	if err := h.projectService.RemoveMember(middleware.GetActor(c), id, memberUserID); err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) || errors.Is(err, service.ErrProjectForbidden) {
+			projectError(c, err, "")
+			return
+		}
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "移除成功", nil)
+}
+
+// ListRevisions 获取项目历史版本
+// @Summary 项目历史版本
+// @Description 获取项目每次变更后保存的快照，按版本号倒序
+// @Tags Project
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Success 200 {array} models.Revision
+// @Router /api/v1/projects/{id}/revisions [get]
+func (h *ProjectHandler) ListRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+
+	revisions, err := h.projectService.ListRevisions(middleware.GetUserID(c), id)
+	if err != nil {
+		projectError(c, err, "获取历史版本失败")
+		return
+	}
+
+	response.Success(c, revisions)
+}
+
+// DiffRevisions 对比项目的两个历史版本
+// @Summary 对比项目版本
+// @Description 返回两个版本之间值不同的字段
+// @Tags Project
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Param from query int true "起始版本号"
+// @Param to query int true "目标版本号"
+// @Success 200 {object} dto.RevisionDiff
+// @Router /api/v1/projects/{id}/revisions/diff [get]
+func (h *ProjectHandler) DiffRevisions(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+
+	var query dto.RevisionDiffQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	diff, err := h.projectService.DiffRevisions(middleware.GetUserID(c), id, query.From, query.To)
+	if err != nil {
+		projectError(c, err, "对比版本失败")
+		return
+	}
+
+	response.Success(c, diff)
+}
+
+// RestoreRevision 将项目恢复到指定版本
+// @Summary 恢复项目版本
+// @Description 以指定版本的快照覆盖项目当前信息，恢复操作本身会生成新版本
+// @Tags Project
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Param version path int true "版本号"
+// @Success 200 {object} models.Project
+// @Router /api/v1/projects/{id}/revisions/{version}/restore [post]
+func (h *ProjectHandler) RestoreRevision(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		response.ParamError(c, "无效的版本号")
+		return
+	}
+
+	project, err := h.projectService.RestoreRevision(middleware.GetActor(c), id, version)
+	if err != nil {
+		projectError(c, err, "恢复版本失败")
+		return
+	}
+
+	response.Success(c, project)
+}
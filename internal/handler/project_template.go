@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/middleware"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// TemplateHandler 项目模板 HTTP Handler
+// 维护按项目类型预置的收款阶段，并支持从模板一次性创建项目及其款项。
+type TemplateHandler struct {
+	templateService *service.TemplateService
+}
+
+// NewTemplateHandler 创建项目模板 Handler 实例
+func NewTemplateHandler() *TemplateHandler {
+	return &TemplateHandler{
+		templateService: service.NewTemplateService(),
+	}
+}
+
+// templateError 将项目模板服务错误映射为 HTTP 响应
+func templateError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrTemplateInvalid):
+		response.ParamError(c, err.Error())
+	default:
+		projectError(c, err, fallback)
+	}
+}
+
+// List 项目模板列表
+// @Summary 项目模板列表
+// @Description 获取全部项目模板及其收款阶段
+// @Tags ProjectTemplate
+// @Security Bearer
+// @Success 200 {array} models.ProjectTemplate
+// @Router /api/v1/project-templates [get]
+func (h *TemplateHandler) List(c *gin.Context) {
+	templates, err := h.templateService.List()
+	if err != nil {
+		response.InternalError(c, "获取项目模板失败")
+		return
+	}
+	response.Success(c, templates)
+}
+
+// Get 项目模板详情
+// @Summary 项目模板详情
+// @Description 获取项目模板及其收款阶段
+// @Tags ProjectTemplate
+// @Security Bearer
+// @Param id path int true "模板ID"
+// @Success 200 {object} models.ProjectTemplate
+// @Router /api/v1/project-templates/{id} [get]
+func (h *TemplateHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的模板ID")
+		return
+	}
+
+	template, err := h.templateService.Get(id)
+	if err != nil {
+		templateError(c, err, "获取项目模板失败")
+		return
+	}
+	response.Success(c, template)
+}
+
+// Create 创建项目模板
+// @Summary 创建项目模板
+// @Description 为项目类型创建模板 (每种类型一个)，收款阶段百分比合计不超过 100%
+// @Tags ProjectTemplate
+// @Security Bearer
+// @Param template body dto.ProjectTemplateRequest true "模板信息"
+// @Success 200 {object} models.ProjectTemplate
+// @Router /api/v1/project-templates [post]
+func (h *TemplateHandler) Create(c *gin.Context) {
+	var req dto.ProjectTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	template, err := h.templateService.Create(middleware.GetActor(c), req)
+	if err != nil {
+		templateError(c, err, "创建项目模板失败")
+		return
+	}
+	response.Success(c, template)
+}
+
+// Update 更新项目模板
+// @Summary 更新项目模板
+// @Description 更新模板信息，收款阶段按请求整体替换；已从模板创建的项目不受影响
+// @Tags ProjectTemplate
+// @Security Bearer
+// @Param id path int true "模板ID"
+// @Param template body dto.ProjectTemplateRequest true "模板信息"
+// @Success 200 {object} models.ProjectTemplate
+// @Router /api/v1/project-templates/{id} [put]
+func (h *TemplateHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的模板ID")
+		return
+	}
+
+	var req dto.ProjectTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	template, err := h.templateService.Update(middleware.GetActor(c), id, req)
+	if err != nil {
+		templateError(c, err, "更新项目模板失败")
+		return
+	}
+	response.SuccessWithMessage(c, "更新成功", template)
+}
+
+// Delete 删除项目模板
+// @Summary 删除项目模板
+// @Description 删除项目模板及其收款阶段；已从模板创建的项目不受影响
+// @Tags ProjectTemplate
+// @Security Bearer
+// @Param id path int true "模板ID"
+// @Success 200 {string} string "删除成功"
+// @Router /api/v1/project-templates/{id} [delete]
+func (h *TemplateHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的模板ID")
+		return
+	}
+
+	if err := h.templateService.Delete(middleware.GetActor(c), id); err != nil {
+		templateError(c, err, "删除项目模板失败")
+		return
+	}
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// CreateProject 从模板创建项目
+// @Summary 从模板创建项目
+// @Description 按模板 (为空时取项目类型对应的模板) 在同一事务中创建项目及各期款项，描述与结束日期为空时取模板默认值
+// @Tags Project
+// @Security Bearer
+// @Param project body dto.CreateProjectFromTemplateRequest true "项目信息"
+// @Success 200 {object} models.Project
+// @Router /api/v1/projects/from-template [post]
+func (h *TemplateHandler) CreateProject(c *gin.Context) {
+	var req dto.CreateProjectFromTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	req.UserID = c.GetInt64("user_id") // 手动设置 UserID
+
+	project, err := h.templateService.CreateProject(middleware.GetActor(c), req)
+	if err != nil {
+		templateError(c, err, "创建项目失败")
+		return
+	}
+	response.Success(c, project)
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/middleware"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// RoleHandler 角色与权限 HTTP Handler
+// 角色管理与用户角色分配需 roles:manage 权限 (由路由中间件校验)。
+type RoleHandler struct {
+	roleService *service.RoleService
+}
+
+// NewRoleHandler 创建角色 Handler 实例
+func NewRoleHandler() *RoleHandler {
+	return &RoleHandler{
+		roleService: service.NewRoleService(),
+	}
+}
+
+// ListPermissions 获取系统定义的全部权限
+// @Summary 权限列表
+// @Tags Role
+// @Security Bearer
+// @Router /api/v1/permissions [get]
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	response.Success(c, h.roleService.ListPermissions())
+}
+
+// List 获取角色列表
+// @Summary 角色列表
+// @Description 获取全部角色及其权限、使用人数
+// @Tags Role
+// @Security Bearer
+// @Success 200 {array} models.Role
+// @Router /api/v1/roles [get]
+func (h *RoleHandler) List(c *gin.Context) {
+	roles, err := h.roleService.List()
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+	response.Success(c, roles)
+}
+
+// Create 创建角色
+// @Summary 创建角色
+// @Tags Role
+// @Security Bearer
+// @Param role body dto.CreateRoleRequest true "角色参数"
+// @Success 200 {object} models.Role
+// @Router /api/v1/roles [post]
+func (h *RoleHandler) Create(c *gin.Context) {
+	var req dto.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	role, err := h.roleService.Create(req)
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+	response.Success(c, role)
+}
+
+// Update 更新角色
+// @Summary 更新角色
+// @Description 更新角色名称、说明，并整体替换权限列表 (管理员角色的权限不可修改)
+// @Tags Role
+// @Security Bearer
+// @Param id path int true "角色ID"
+// @Param role body dto.UpdateRoleRequest true "角色参数"
+// @Success 200 {object} models.Role
+// @Router /api/v1/roles/{id} [put]
+func (h *RoleHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的角色ID")
+		return
+	}
+
+	var req dto.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	role, err := h.roleService.Update(id, req)
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+	response.Success(c, role)
+}
+
+// Delete 删除角色
+// @Summary 删除角色
+// @Description 删除自定义角色 (内置角色及仍在使用的角色不可删除)
+// @Tags Role
+// @Security Bearer
+// @Param id path int true "角色ID"
+// @Router /api/v1/roles/{id} [delete]
+func (h *RoleHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的角色ID")
+		return
+	}
+
+	if err := h.roleService.Delete(id); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// AssignUserRole 分配用户角色
+// @Summary 分配用户角色
+// @Tags Role
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Param role body dto.AssignRoleRequest true "角色编码"
+// @Router /api/v1/users/{id}/role [put]
+func (h *RoleHandler) AssignUserRole(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	var req dto.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	if err := h.roleService.AssignRole(id, req.Role); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "角色分配成功", nil)
+}
+
+// MyPermissions 获取当前用户的角色与权限
+// 前端据此控制菜单与按钮的显示。
+// @Summary 我的权限
+// @Tags Role
+// @Security Bearer
+// @Router /api/v1/users/me/permissions [get]
+func (h *RoleHandler) MyPermissions(c *gin.Context) {
+	role, permissions, err := h.roleService.UserPermissions(middleware.GetUserID(c))
+	if err != nil {
+		response.Forbidden(c, err.Error())
+		return
+	}
+	response.Success(c, gin.H{"role": role, "permissions": permissions})
+}
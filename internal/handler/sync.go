@@ -11,7 +11,8 @@ import (
 
 // SyncHandler 数据同步 HTTP Handler
 type SyncHandler struct {
-	syncService *service.SyncService
+	syncService    *service.SyncService
+	profileService *service.SyncProfileService
 }
 
 // GetConfig 获取同步配置 (从环境变量)
@@ -37,19 +38,36 @@ func (h *SyncHandler) GetConfig(c *gin.Context) {
 // NewSyncHandler 创建同步 Handler 实例
 func NewSyncHandler() *SyncHandler {
 	return &SyncHandler{
-		syncService: service.NewSyncService(),
+		syncService:    service.NewSyncService(),
+		profileService: service.NewSyncProfileService(),
 	}
 }
 
 // TestConnectionRequest 测试连接请求
+// 网络数据库 (postgres/mysql) 的必填项由服务层按类型校验，sqlite 离线目标只需 path。
 type TestConnectionRequest struct {
 	DBType   string `json:"db_type" binding:"required"`
-	Host     string `json:"host" binding:"required"`
-	Port     int    `json:"port" binding:"required"`
-	User     string `json:"user" binding:"required"`
-	Password string `json:"password" binding:"required"`
-	DBName   string `json:"db_name" binding:"required"`
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+	DBName   string `json:"db_name"`
 	SSLMode  string `json:"ssl_mode"`
+	Path     string `json:"path"` // SQLite 文件路径 (仅 sqlite)
+}
+
+// config 转换为同步服务的连接配置
+func (r TestConnectionRequest) config() service.SyncConfig {
+	return service.SyncConfig{
+		DBType:   r.DBType,
+		Host:     r.Host,
+		Port:     r.Port,
+		User:     r.User,
+		Password: r.Password,
+		DBName:   r.DBName,
+		SSLMode:  r.SSLMode,
+		Path:     r.Path,
+	}
 }
 
 // TestConnection 测试云端数据库连接
@@ -61,15 +79,7 @@ func (h *SyncHandler) TestConnection(c *gin.Context) {
 		return
 	}
 
-	cfg := service.SyncConfig{
-		DBType:   req.DBType,
-		Host:     req.Host,
-		Port:     req.Port,
-		User:     req.User,
-		Password: req.Password,
-		DBName:   req.DBName,
-		SSLMode:  req.SSLMode,
-	}
+	cfg := req.config()
 
 	if err := h.syncService.TestConnection(cfg); err != nil {
 		c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error()})
@@ -79,26 +89,28 @@ func (h *SyncHandler) TestConnection(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"code": 0, "message": "连接成功"})
 }
 
-// Compare 对比本地与云端表的记录数
+// CompareRequest 数据对比请求
+type CompareRequest struct {
+	TestConnectionRequest
+	Tables    []string `json:"tables"`     // 要对比的表列表 (为空表示全部)
+	FieldDiff bool     `json:"field_diff"` // 是否返回字段级差异
+}
+
+// Compare 逐行对比本地与云端数据
 // @Router /api/v1/sync/compare [post]
 func (h *SyncHandler) Compare(c *gin.Context) {
-	var req TestConnectionRequest
+	var req CompareRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "参数错误: " + err.Error()})
 		return
 	}
 
-	cfg := service.SyncConfig{
-		DBType:   req.DBType,
-		Host:     req.Host,
-		Port:     req.Port,
-		User:     req.User,
-		Password: req.Password,
-		DBName:   req.DBName,
-		SSLMode:  req.SSLMode,
-	}
+	cfg := req.config()
 
-	results, err := h.syncService.CompareData(cfg)
+	results, err := h.syncService.CompareData(cfg, service.CompareOptions{
+		Tables:    req.Tables,
+		FieldDiff: req.FieldDiff,
+	})
 	if err != nil {
 		c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error()})
 		return
@@ -107,16 +119,44 @@ func (h *SyncHandler) Compare(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"code": 0, "data": results})
 }
 
+// SchemaRequest 云端表结构初始化请求
+type SchemaRequest struct {
+	TestConnectionRequest
+	Tables []string `json:"tables"`  // 要处理的表列表 (为空表示全部)
+	DryRun bool     `json:"dry_run"` // 是否仅预览 DDL
+}
+
+// Schema 创建或迁移云端表结构 (支持预览)
+// @Router /api/v1/sync/schema [post]
+func (h *SyncHandler) Schema(c *gin.Context) {
+	var req SchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "参数错误: " + err.Error()})
+		return
+	}
+
+	cfg := req.config()
+
+	changes, err := h.syncService.ProvisionSchema(cfg, req.Tables, req.DryRun)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error(), "data": changes})
+		return
+	}
+
+	message := "表结构已同步"
+	if req.DryRun {
+		message = "预览完成"
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "data": changes, "message": message})
+}
+
 // ExecuteRequest 执行同步请求
 type ExecuteRequest struct {
-	DBType   string   `json:"db_type" binding:"required"`
-	Host     string   `json:"host" binding:"required"`
-	Port     int      `json:"port" binding:"required"`
-	User     string   `json:"user" binding:"required"`
-	Password string   `json:"password" binding:"required"`
-	DBName   string   `json:"db_name" binding:"required"`
-	SSLMode  string   `json:"ssl_mode"`
-	Tables   []string `json:"tables" binding:"required"` // 要同步的表列表
+	TestConnectionRequest
+	Tables    []string `json:"tables" binding:"required"` // 要同步的表列表
+	Full      bool     `json:"full"`                      // 是否全量同步 (忽略检查点)
+	Direction string   `json:"direction"`                 // 同步方向: push (默认), pull, merge
+	Atomic    bool     `json:"atomic"`                    // 是否整体事务同步 (任一表失败全部回滚)
 }
 
 // Execute 执行数据同步
@@ -128,21 +168,101 @@ func (h *SyncHandler) Execute(c *gin.Context) {
 		return
 	}
 
-	cfg := service.SyncConfig{
-		DBType:   req.DBType,
-		Host:     req.Host,
-		Port:     req.Port,
-		User:     req.User,
-		Password: req.Password,
-		DBName:   req.DBName,
-		SSLMode:  req.SSLMode,
+	cfg := req.config()
+
+	// 通过配置服务执行，记录同步历史
+	results, err := h.profileService.Execute(cfg, req.Tables, service.SyncOptions{
+		Direction: req.Direction,
+		Full:      req.Full,
+		Atomic:    req.Atomic,
+	}, c.GetInt64("user_id"))
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	// 汇总各表冲突，便于前端统一展示
+	conflicts := make([]service.SyncConflict, 0)
+	for _, r := range results {
+		conflicts = append(conflicts, r.Conflicts...)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"code": 0, "data": results, "conflicts": conflicts, "message": "同步完成"})
+}
+
+// ResolveRequest 处理同步冲突请求
+type ResolveRequest struct {
+	TestConnectionRequest
+	Table      string `json:"table" binding:"required"`      // 表名
+	RecordID   int64  `json:"record_id" binding:"required"`  // 记录ID
+	Resolution string `json:"resolution" binding:"required"` // 处理方式: local (保留本地), remote (保留云端)
+}
+
+// Resolve 处理同步冲突
+// @Router /api/v1/sync/resolve [post]
+func (h *SyncHandler) Resolve(c *gin.Context) {
+	var req ResolveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "参数错误: " + err.Error()})
+		return
+	}
+
+	cfg := req.config()
+
+	if err := h.syncService.ResolveConflict(cfg, req.Table, req.RecordID, req.Resolution); err != nil {
+		c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"code": 0, "message": "冲突已处理"})
+}
+
+// ExportRequest 离线导出请求
+type ExportRequest struct {
+	Format string   `json:"format" binding:"required"` // 导出格式: sqlite (SQLite 文件), bundle (JSON 数据包)
+	Name   string   `json:"name" binding:"required"`   // 目标文件名 (写入离线同步目录，不含目录)
+	Tables []string `json:"tables"`                    // 要导出的表列表 (为空表示全部)
+}
+
+// Export 导出离线同步文件
+// 导出内容包含用户密码 Hash 等敏感数据，需 sync:manage 权限。
+// @Router /api/v1/sync/export [post]
+func (h *SyncHandler) Export(c *gin.Context) {
+	var req ExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "参数错误: " + err.Error()})
+		return
+	}
+
+	results, err := h.syncService.Export(req.Format, req.Name, req.Tables)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"code": 0, "data": results, "message": "导出完成"})
+}
+
+// ImportRequest 离线导入请求
+type ImportRequest struct {
+	Name string `json:"name" binding:"required"` // 离线文件名 (位于离线同步目录的 SQLite 文件或 JSON 数据包)
+}
+
+// Import 导入离线同步文件
+// 导入会覆盖同 ID 的本地记录 (含用户)，需 sync:manage 权限。
+// @Router /api/v1/sync/import [post]
+func (h *SyncHandler) Import(c *gin.Context) {
+	var req ImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "message": "参数错误: " + err.Error()})
+		return
 	}
 
-	results, err := h.syncService.SyncTables(cfg, req.Tables)
+	results, err := h.syncService.Import(req.Name)
 	if err != nil {
 		c.JSON(http.StatusOK, gin.H{"code": 1, "message": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"code": 0, "data": results, "message": "同步完成"})
+	c.JSON(http.StatusOK, gin.H{"code": 0, "data": results, "message": "导入完成"})
 }
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// SyncProfileHandler 同步配置与同步历史 HTTP Handler
+// 同步配置保存云端数据库凭证，管理需 sync:manage 权限，执行需 sync:execute 权限。
+type SyncProfileHandler struct {
+	profileService *service.SyncProfileService
+}
+
+// NewSyncProfileHandler 创建同步配置 Handler 实例
+func NewSyncProfileHandler() *SyncProfileHandler {
+	return &SyncProfileHandler{
+		profileService: service.NewSyncProfileService(),
+	}
+}
+
+// List 获取同步配置列表
+// @Router /api/v1/sync/profiles [get]
+func (h *SyncProfileHandler) List(c *gin.Context) {
+	profiles, err := h.profileService.List()
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+	response.Success(c, profiles)
+}
+
+// Get 获取同步配置详情
+// @Router /api/v1/sync/profiles/{id} [get]
+func (h *SyncProfileHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的配置ID")
+		return
+	}
+
+	profile, err := h.profileService.Get(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+	response.Success(c, profile)
+}
+
+// Create 创建同步配置
+// @Router /api/v1/sync/profiles [post]
+func (h *SyncProfileHandler) Create(c *gin.Context) {
+	var req dto.SyncProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	profile, err := h.profileService.Create(&req, c.GetInt64("user_id"))
+	if err != nil {
+		response.Error(c, response.CodeParamError, err.Error())
+		return
+	}
+	response.Success(c, profile)
+}
+
+// Update 更新同步配置
+// @Router /api/v1/sync/profiles/{id} [put]
+func (h *SyncProfileHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的配置ID")
+		return
+	}
+
+	var req dto.SyncProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	profile, err := h.profileService.Update(id, &req)
+	if err != nil {
+		response.Error(c, response.CodeParamError, err.Error())
+		return
+	}
+	response.Success(c, profile)
+}
+
+// Delete 删除同步配置
+// @Router /api/v1/sync/profiles/{id} [delete]
+func (h *SyncProfileHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的配置ID")
+		return
+	}
+
+	if err := h.profileService.Delete(id); err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// Run 立即执行同步配置
+// @Router /api/v1/sync/profiles/{id}/run [post]
+func (h *SyncProfileHandler) Run(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的配置ID")
+		return
+	}
+
+	history, err := h.profileService.Run(id, service.SyncTriggerManual, c.GetInt64("user_id"))
+	if err != nil {
+		response.Error(c, response.CodeInternalError, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "同步完成", history)
+}
+
+// ListHistory 分页获取同步历史
+// @Param profile_id query int false "同步配置ID"
+// @Router /api/v1/sync/history [get]
+func (h *SyncProfileHandler) ListHistory(c *gin.Context) {
+	profileID, _ := strconv.ParseInt(c.Query("profile_id"), 10, 64)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	histories, total, err := h.profileService.ListHistory(profileID, page, pageSize)
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+	response.SuccessPage(c, histories, total, page, pageSize)
+}
+
+// GetHistory 获取同步历史详情
+// @Router /api/v1/sync/history/{id} [get]
+func (h *SyncProfileHandler) GetHistory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的历史ID")
+		return
+	}
+
+	history, err := h.profileService.GetHistory(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+	response.Success(c, history)
+}
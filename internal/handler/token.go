@@ -10,6 +10,7 @@ import (
 
 	"github.com/FruitsAI/Orange/internal/middleware"
 	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/permission"
 	"github.com/FruitsAI/Orange/internal/repository"
 	"github.com/gin-gonic/gin"
 )
@@ -24,8 +25,9 @@ func NewTokenHandler() *TokenHandler {
 
 // CreateRequest 创建令牌请求参数
 type CreateTokenRequest struct {
-	Name      string `json:"name" binding:"required"`
-	ExpiresIn int    `json:"expires_in"` // 过期时间 (天)，0 表示永不过期
+	Name      string   `json:"name" binding:"required"`
+	ExpiresIn int      `json:"expires_in"`                      // 过期时间 (天)，0 表示永不过期
+	Scopes    []string `json:"scopes" binding:"required,min=1"` // 授权范围 (如 read, projects:write；* 表示完全访问)
 }
 
 // CreateResponse 创建令牌响应 (包含原始 Token)
@@ -58,6 +60,12 @@ func (h *TokenHandler) Create(c *gin.Context) {
 		return
 	}
 
+	scopes, err := permission.NormalizeScopes(req.Scopes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 400, "message": err.Error()})
+		return
+	}
+
 	userID := middleware.GetUserID(c)
 
 	// 1. 生成原始 Token
@@ -82,6 +90,7 @@ func (h *TokenHandler) Create(c *gin.Context) {
 		UserID:    userID,
 		Name:      req.Name,
 		TokenHash: tokenHash,
+		Scopes:    scopes,
 		Status:    1,
 		ExpiresAt: expiresAt,
 	}
@@ -103,6 +112,15 @@ func (h *TokenHandler) Create(c *gin.Context) {
 	})
 }
 
+// Scopes 获取可授予令牌的授权范围列表
+func (h *TokenHandler) Scopes(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "success",
+		"data":    permission.Scopes,
+	})
+}
+
 // List 获取令牌列表
 func (h *TokenHandler) List(c *gin.Context) {
 	userID := middleware.GetUserID(c)
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/FruitsAI/Orange/internal/middleware"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// TrashHandler 回收站 HTTP Handler
+// 项目、款项与用户删除后进入回收站，可恢复或彻底删除。各类记录所需权限由路由中间件校验。
+type TrashHandler struct {
+	trashService *service.TrashService
+}
+
+// NewTrashHandler 创建回收站 Handler 实例
+func NewTrashHandler() *TrashHandler {
+	return &TrashHandler{
+		trashService: service.NewTrashService(),
+	}
+}
+
+// trashError 将回收站服务错误映射为 HTTP 响应
+func trashError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrTrashItemNotFound):
+		response.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrTrashConflict):
+		response.ParamError(c, err.Error())
+	default:
+		projectError(c, err, fallback)
+	}
+}
+
+// ListProjects 回收站中的项目
+// @Summary 回收站项目列表
+// @Description 获取当前用户负责的已删除项目 (拥有 projects:read_all 权限时返回全部)，按删除时间倒序
+// @Tags Trash
+// @Security Bearer
+// @Success 200 {array} dto.TrashItem
+// @Router /api/v1/trash/projects [get]
+func (h *TrashHandler) ListProjects(c *gin.Context) {
+	items, err := h.trashService.ListProjects(c.GetInt64("user_id"))
+	if err != nil {
+		response.InternalError(c, "获取回收站失败")
+		return
+	}
+	response.Success(c, items)
+}
+
+// RestoreProject 恢复项目
+// @Summary 恢复项目
+// @Description 从回收站恢复项目及随其一并删除的款项，仅项目所有者可操作
+// @Tags Trash
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Success 200 {object} models.Project
+// @Router /api/v1/trash/projects/{id}/restore [post]
+func (h *TrashHandler) RestoreProject(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+
+	project, err := h.trashService.RestoreProject(middleware.GetActor(c), id)
+	if err != nil {
+		trashError(c, err, "恢复项目失败")
+		return
+	}
+	response.SuccessWithMessage(c, "恢复成功", project)
+}
+
+// PurgeProject 彻底删除项目
+// @Summary 彻底删除项目
+// @Description 彻底删除回收站中的项目及其款项、共享成员与历史版本，不可恢复，仅项目所有者可操作
+// @Tags Trash
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Success 200 {string} string "删除成功"
+// @Router /api/v1/trash/projects/{id} [delete]
+func (h *TrashHandler) PurgeProject(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的项目ID")
+		return
+	}
+
+	if err := h.trashService.PurgeProject(middleware.GetActor(c), id); err != nil {
+		trashError(c, err, "彻底删除项目失败")
+		return
+	}
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// ListPayments 回收站中的款项
+// @Summary 回收站款项列表
+// @Description 获取当前用户参与的项目中单独删除的款项 (随项目删除的款项跟随项目)，按删除时间倒序
+// @Tags Trash
+// @Security Bearer
+// @Success 200 {array} dto.TrashItem
+// @Router /api/v1/trash/payments [get]
+func (h *TrashHandler) ListPayments(c *gin.Context) {
+	items, err := h.trashService.ListPayments(c.GetInt64("user_id"))
+	if err != nil {
+		response.InternalError(c, "获取回收站失败")
+		return
+	}
+	response.Success(c, items)
+}
+
+// RestorePayment 恢复款项
+// @Summary 恢复款项
+// @Description 从回收站恢复款项并重新计算项目已收款金额，需为所属项目的所有者或编辑者
+// @Tags Trash
+// @Security Bearer
+// @Param id path int true "款项ID"
+// @Success 200 {object} models.Payment
+// @Router /api/v1/trash/payments/{id}/restore [post]
+func (h *TrashHandler) RestorePayment(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的款项ID")
+		return
+	}
+
+	payment, err := h.trashService.RestorePayment(middleware.GetActor(c), id)
+	if err != nil {
+		trashError(c, err, "恢复款项失败")
+		return
+	}
+	response.SuccessWithMessage(c, "恢复成功", payment)
+}
+
+// PurgePayment 彻底删除款项
+// @Summary 彻底删除款项
+// @Description 彻底删除回收站中的款项及其历史版本，不可恢复
+// @Tags Trash
+// @Security Bearer
+// @Param id path int true "款项ID"
+// @Success 200 {string} string "删除成功"
+// @Router /api/v1/trash/payments/{id} [delete]
+func (h *TrashHandler) PurgePayment(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的款项ID")
+		return
+	}
+
+	if err := h.trashService.PurgePayment(middleware.GetActor(c), id); err != nil {
+		trashError(c, err, "彻底删除款项失败")
+		return
+	}
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// ListUsers 回收站中的用户
+// @Summary 回收站用户列表
+// @Description 获取已删除的用户，按删除时间倒序 (管理员)
+// @Tags Trash
+// @Security Bearer
+// @Success 200 {array} dto.TrashItem
+// @Router /api/v1/trash/users [get]
+func (h *TrashHandler) ListUsers(c *gin.Context) {
+	items, err := h.trashService.ListUsers()
+	if err != nil {
+		response.InternalError(c, "获取回收站失败")
+		return
+	}
+	response.Success(c, items)
+}
+
+// RestoreUser 恢复用户
+// @Summary 恢复用户
+// @Description 从回收站恢复用户，恢复后可重新登录 (管理员)
+// @Tags Trash
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Success 200 {object} models.User
+// @Router /api/v1/trash/users/{id}/restore [post]
+func (h *TrashHandler) RestoreUser(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	user, err := h.trashService.RestoreUser(middleware.GetActor(c), id)
+	if err != nil {
+		trashError(c, err, "恢复用户失败")
+		return
+	}
+	response.SuccessWithMessage(c, "恢复成功", user)
+}
+
+// PurgeUser 彻底删除用户
+// @Summary 彻底删除用户
+// @Description 彻底删除回收站中的用户及其两步验证配置，不可恢复，用户名随之释放 (管理员)
+// @Tags Trash
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Success 200 {string} string "删除成功"
+// @Router /api/v1/trash/users/{id} [delete]
+func (h *TrashHandler) PurgeUser(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	if err := h.trashService.PurgeUser(middleware.GetActor(c), id); err != nil {
+		trashError(c, err, "彻底删除用户失败")
+		return
+	}
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
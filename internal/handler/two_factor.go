@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/middleware"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorHandler 两步验证 HTTP Handler
+// 两步验证属于账户凭据，只能在登录会话中管理，个人访问令牌无权操作。
+type TwoFactorHandler struct {
+	twoFactorService *service.TwoFactorService
+}
+
+// NewTwoFactorHandler 创建两步验证 Handler 实例
+func NewTwoFactorHandler() *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: service.NewTwoFactorService(),
+	}
+}
+
+// Status 获取当前用户的两步验证状态
+// @Summary 两步验证状态
+// @Tags User
+// @Security Bearer
+// @Success 200 {object} dto.TwoFactorStatus
+// @Router /api/v1/users/me/2fa [get]
+func (h *TwoFactorHandler) Status(c *gin.Context) {
+	status, err := h.twoFactorService.Status(middleware.GetUserID(c))
+	if err != nil {
+		response.InternalError(c, err.Error())
+		return
+	}
+	response.Success(c, status)
+}
+
+// Setup 生成两步验证密钥
+// @Summary 生成两步验证密钥
+// @Description 生成新的 TOTP 密钥及 otpauth 链接，需调用启用接口提交验证码后才会生效
+// @Tags User
+// @Security Bearer
+// @Success 200 {object} dto.TwoFactorSetupResult
+// @Router /api/v1/users/me/2fa/setup [post]
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	if !requireLoginSession(c) {
+		return
+	}
+
+	result, err := h.twoFactorService.Setup(middleware.GetUserID(c))
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+	response.Success(c, result)
+}
+
+// Enable 启用两步验证
+// @Summary 启用两步验证
+// @Description 提交验证器生成的验证码以启用两步验证，返回一次性恢复码 (仅显示一次)
+// @Tags User
+// @Security Bearer
+// @Param body body dto.TwoFactorEnableRequest true "验证码"
+// @Success 200 {object} dto.RecoveryCodesResult
+// @Router /api/v1/users/me/2fa/enable [post]
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	if !requireLoginSession(c) {
+		return
+	}
+
+	var req dto.TwoFactorEnableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "验证码不能为空")
+		return
+	}
+
+	codes, err := h.twoFactorService.Enable(middleware.GetUserID(c), req.Code)
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "两步验证已启用", dto.RecoveryCodesResult{RecoveryCodes: codes})
+}
+
+// Disable 关闭两步验证
+// @Summary 关闭两步验证
+// @Tags User
+// @Security Bearer
+// @Param body body dto.TwoFactorDisableRequest true "密码与验证码"
+// @Router /api/v1/users/me/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	if !requireLoginSession(c) {
+		return
+	}
+
+	var req dto.TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "密码和验证码不能为空")
+		return
+	}
+
+	if err := h.twoFactorService.Disable(middleware.GetUserID(c), req.Password, req.Code); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "两步验证已关闭", nil)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// @Summary 重新生成恢复码
+// @Description 旧恢复码全部作废，新恢复码仅显示一次
+// @Tags User
+// @Security Bearer
+// @Param body body dto.RegenerateRecoveryCodesRequest true "当前密码"
+// @Success 200 {object} dto.RecoveryCodesResult
+// @Router /api/v1/users/me/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	if !requireLoginSession(c) {
+		return
+	}
+
+	var req dto.RegenerateRecoveryCodesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ParamError(c, "密码不能为空")
+		return
+	}
+
+	codes, err := h.twoFactorService.RegenerateRecoveryCodes(middleware.GetUserID(c), req.Password)
+	if err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+	response.Success(c, dto.RecoveryCodesResult{RecoveryCodes: codes})
+}
+
+// Reset 重置用户的两步验证 (管理员)
+// 用户丢失验证器且恢复码用尽时，由管理员关闭其两步验证。
+// @Summary 重置用户两步验证
+// @Tags User
+// @Security Bearer
+// @Param id path int true "用户ID"
+// @Router /api/v1/users/{id}/2fa [delete]
+func (h *TwoFactorHandler) Reset(c *gin.Context) {
+	if !requireLoginSession(c) {
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	if err := h.twoFactorService.Reset(id); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+	response.SuccessWithMessage(c, "两步验证已重置", nil)
+}
+
+// requireLoginSession 校验请求来自登录会话 (而非个人访问令牌)
+func requireLoginSession(c *gin.Context) bool {
+	if middleware.GetSessionID(c) == 0 {
+		response.Forbidden(c, "个人访问令牌不能管理两步验证")
+		return false
+	}
+	return true
+}
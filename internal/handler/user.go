@@ -20,22 +20,8 @@ func NewUserHandler() *UserHandler {
 	}
 }
 
-// ensureAdmin 检查当前用户是否为管理员
-func (h *UserHandler) ensureAdmin(c *gin.Context) bool {
-	role := middleware.GetRole(c)
-	if role != "admin" {
-		response.Error(c, response.CodeForbidden, "权限不足")
-		return false
-	}
-	return true
-}
-
 // List 获取用户列表
 func (h *UserHandler) List(c *gin.Context) {
-	if !h.ensureAdmin(c) {
-		return
-	}
-
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
 	keyword := c.Query("keyword")
@@ -51,17 +37,13 @@ func (h *UserHandler) List(c *gin.Context) {
 
 // Create 创建用户
 func (h *UserHandler) Create(c *gin.Context) {
-	if !h.ensureAdmin(c) {
-		return
-	}
-
 	var req dto.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.ParamError(c, err.Error())
 		return
 	}
 
-	if err := h.authService.CreateUser(req); err != nil {
+	if err := h.authService.CreateUser(middleware.GetActor(c), req); err != nil {
 		response.ParamError(c, err.Error())
 		return
 	}
@@ -71,10 +53,6 @@ func (h *UserHandler) Create(c *gin.Context) {
 
 // Update 更新用户
 func (h *UserHandler) Update(c *gin.Context) {
-	if !h.ensureAdmin(c) {
-		return
-	}
-
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的用户ID")
@@ -87,7 +65,7 @@ func (h *UserHandler) Update(c *gin.Context) {
 		return
 	}
 
-	if err := h.authService.UpdateUser(id, req); err != nil {
+	if err := h.authService.UpdateUser(middleware.GetActor(c), id, req); err != nil {
 		response.InternalError(c, err.Error())
 		return
 	}
@@ -97,10 +75,6 @@ func (h *UserHandler) Update(c *gin.Context) {
 
 // Delete 删除用户
 func (h *UserHandler) Delete(c *gin.Context) {
-	if !h.ensureAdmin(c) {
-		return
-	}
-
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的用户ID")
@@ -114,7 +88,7 @@ func (h *UserHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.authService.DeleteUser(id); err != nil {
+	if err := h.authService.DeleteUser(middleware.GetActor(c), id); err != nil {
 		response.InternalError(c, err.Error())
 		return
 	}
@@ -124,10 +98,6 @@ func (h *UserHandler) Delete(c *gin.Context) {
 
 // ResetPassword 重置密码
 func (h *UserHandler) ResetPassword(c *gin.Context) {
-	if !h.ensureAdmin(c) {
-		return
-	}
-
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
 		response.ParamError(c, "无效的用户ID")
@@ -140,10 +110,43 @@ func (h *UserHandler) ResetPassword(c *gin.Context) {
 		return
 	}
 
-	if err := h.authService.ResetPassword(id, req.NewPassword); err != nil {
+	if err := h.authService.ResetPassword(middleware.GetActor(c), id, req.NewPassword); err != nil {
 		response.InternalError(c, err.Error())
 		return
 	}
 
 	response.SuccessWithMessage(c, "密码重置成功", nil)
 }
+
+// Unlock 解除用户登录锁定
+func (h *UserHandler) Unlock(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	if err := h.authService.UnlockUser(middleware.GetActor(c), id); err != nil {
+		response.ParamError(c, err.Error())
+		return
+	}
+
+	response.SuccessWithMessage(c, "账户已解锁", nil)
+}
+
+// Lockouts 获取用户登录锁定记录
+func (h *UserHandler) Lockouts(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	lockouts, err := h.authService.ListLockouts(id)
+	if err != nil {
+		response.NotFound(c, err.Error())
+		return
+	}
+
+	response.Success(c, lockouts)
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
+	"github.com/gin-gonic/gin"
+)
+
+// GetActor 从上下文构造当前操作人 (用于审计日志)
+// 经 JWTAuth 认证的请求区分登录会话与个人访问令牌，未认证的请求 (如注册) 来源为 anonymous。
+func GetActor(c *gin.Context) audit.Actor {
+	actor := audit.Actor{
+		UserID:   GetUserID(c),
+		Username: GetUsername(c),
+		Source:   audit.SourceAnonymous,
+		IP:       c.ClientIP(),
+	}
+	if tokenID, exists := c.Get("access_token_id"); exists {
+		actor.Source = audit.SourceToken
+		actor.AccessTokenID = tokenID.(int64)
+	} else if actor.UserID > 0 {
+		actor.Source = audit.SourceSession
+	}
+	return actor
+}
@@ -10,8 +10,10 @@ import (
 	"github.com/FruitsAI/Orange/internal/database"
 	"github.com/FruitsAI/Orange/internal/models"
 	"github.com/FruitsAI/Orange/internal/pkg/jwt"
+	"github.com/FruitsAI/Orange/internal/pkg/permission"
 	"github.com/FruitsAI/Orange/internal/pkg/response"
 	"github.com/FruitsAI/Orange/internal/repository"
+	"github.com/FruitsAI/Orange/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -19,8 +21,9 @@ import (
 // 拦截 HTTP 请求，验证 Request Header 中的 Authorization 字段。
 // 支持:
 // 1. 标准 JWT (Bearer <token>)
-// 2. 个人访问令牌 (Bearer pat_<token>)
+// 2. 个人访问令牌 (Bearer pat_<token>)，授权范围写入上下文，由 RequireScope 校验
 func JWTAuth() gin.HandlerFunc {
+	sessionService := service.NewSessionService()
 	return func(c *gin.Context) {
 		// 1. 从 Header 获取 Token
 		authHeader := c.GetHeader("Authorization")
@@ -76,23 +79,32 @@ func JWTAuth() gin.HandlerFunc {
 			c.Set("username", token.User.Username)
 			c.Set("role", token.User.Role)
 			c.Set("access_token_id", token.ID) // 标记来源
+			c.Set("token_scopes", permission.ParseScopes(token.Scopes))
 
 			c.Next()
 			return
 		}
 
 		// 3.2 校验并解析标准 JWT
+		// 不携带会话ID的旧版 Token 一律视为过期，需重新登录
 		claims, err := jwt.ParseToken(tokenString)
-		if err != nil {
+		if err != nil || claims.SessionID == 0 {
 			response.Error(c, response.CodeTokenExpired, "Token已过期或无效")
 			c.Abort()
 			return
 		}
 
+		// 3.3 校验登录会话未被吊销 (退出登录、修改密码后立即失效)
+		if err := sessionService.Validate(claims.SessionID, claims.UserID); err != nil {
+			response.Unauthorized(c, err.Error())
+			return
+		}
+
 		// 4. 将用户信息注入上下文 (Context)
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
 
 		c.Next()
 	}
@@ -114,6 +126,14 @@ func GetUsername(c *gin.Context) string {
 	return ""
 }
 
+// GetSessionID 从上下文获取登录会话ID (个人访问令牌认证时为 0)
+func GetSessionID(c *gin.Context) int64 {
+	if sessionID, exists := c.Get("session_id"); exists {
+		return sessionID.(int64)
+	}
+	return 0
+}
+
 // GetRole 从上下文获取角色
 func GetRole(c *gin.Context) string {
 	if role, exists := c.Get("role"); exists {
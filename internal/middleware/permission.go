@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/FruitsAI/Orange/internal/pkg/permission"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/FruitsAI/Orange/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission 权限校验中间件
+// 需在 JWTAuth 之后使用。以数据库中用户当前的角色判定权限 (Token 中的角色可能已过时)，
+// 并将最新角色写回上下文供后续处理使用。
+//
+// 参数:
+//   - perm: 所需权限编码 (如 payments:confirm)，未定义的编码会在注册路由时 panic
+func RequirePermission(perm string) gin.HandlerFunc {
+	if !permission.IsValid(perm) {
+		panic(fmt.Sprintf("未定义的权限: %s", perm))
+	}
+
+	roleService := service.NewRoleService()
+	return func(c *gin.Context) {
+		role, err := roleService.UserRole(GetUserID(c))
+		if err != nil {
+			response.Forbidden(c, err.Error())
+			return
+		}
+
+		ok, err := roleService.HasPermission(role, perm)
+		if err != nil {
+			slog.Error("权限校验失败", "permission", perm, "error", err)
+			response.InternalError(c, "权限校验失败")
+			c.Abort()
+			return
+		}
+		if !ok {
+			response.Forbidden(c, "权限不足")
+			return
+		}
+
+		c.Set("role", role)
+		c.Next()
+	}
+}
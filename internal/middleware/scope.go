@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/FruitsAI/Orange/internal/pkg/permission"
+	"github.com/FruitsAI/Orange/internal/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope 令牌授权范围校验中间件
+// 挂载在路由组上，GET/HEAD 请求需要 <resource>:read 范围，其余请求需要 <resource>:write 范围。
+// 仅对个人访问令牌生效，登录会话 (JWT) 不受范围限制。
+//
+// 参数:
+//   - resource: 路由组对应的资源名称 (如 projects)
+func RequireScope(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, ok := GetScopes(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		action := permission.ActionWrite
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			action = permission.ActionRead
+		}
+		if !permission.ScopeAllows(scopes, resource, action) {
+			response.Forbidden(c, "访问令牌缺少授权范围: "+resource+":"+action)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetScopes 从上下文获取访问令牌的授权范围
+// 第二个返回值为 false 表示当前请求不是通过访问令牌认证的。
+func GetScopes(c *gin.Context) ([]string, bool) {
+	if scopes, exists := c.Get("token_scopes"); exists {
+		return scopes.([]string), true
+	}
+	return nil, false
+}
@@ -2,26 +2,33 @@ package models
 
 import (
 	"time"
+
+	"github.com/FruitsAI/Orange/internal/pkg/money"
+	"gorm.io/gorm"
 )
 
 // User 用户模型
 // 对应可能是系统管理员或普通员工。
 // 包含用户的基本信息、登录凭证（密码Hash）以及角色权限信息。
 type User struct {
-	ID            int64      `json:"id" gorm:"primaryKey;autoIncrement"`
-	Username      string     `json:"username" gorm:"size:50;not null;uniqueIndex"` // 用户名，唯一
-	Password      string     `json:"-" gorm:"size:100;not null"`                   // 密码 Hash 值，JSON 序列化时忽略
-	Name          string     `json:"name" gorm:"size:50;not null"`                 // 真实姓名
-	Email         string     `json:"email" gorm:"size:100"`                        // 邮箱
-	Phone         string     `json:"phone" gorm:"size:20"`                         // 手机号
-	Avatar        string     `json:"avatar" gorm:"size:255"`                       // 头像 URL
-	Role          string     `json:"role" gorm:"size:20;not null;default:'user'"`  // 角色: admin, user
-	Department    string     `json:"department" gorm:"size:50"`                    // 部门
-	Position      string     `json:"position" gorm:"size:50"`                      // 职位
-	Status        int        `json:"status" gorm:"default:1"`                      // 状态: 1=正常, 0=禁用
-	LastLoginTime *time.Time `json:"last_login_time"`                              // 最后登录时间
-	CreateTime    time.Time  `json:"create_time" gorm:"autoCreateTime"`            // 创建时间
-	UpdateTime    time.Time  `json:"update_time" gorm:"autoUpdateTime"`            // 更新时间
+	ID            int64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	Username      string         `json:"username" gorm:"size:50;not null;uniqueIndex"` // 用户名，唯一
+	Password      string         `json:"-" gorm:"size:100;not null"`                   // 密码 Hash 值，JSON 序列化时忽略
+	Name          string         `json:"name" gorm:"size:50;not null"`                 // 真实姓名
+	Email         string         `json:"email" gorm:"size:100"`                        // 邮箱
+	Phone         string         `json:"phone" gorm:"size:20"`                         // 手机号
+	Avatar        string         `json:"avatar" gorm:"size:255"`                       // 头像 URL
+	Role          string         `json:"role" gorm:"size:20;not null;default:'user'"`  // 角色: admin, user
+	Department    string         `json:"department" gorm:"size:50"`                    // 部门
+	Position      string         `json:"position" gorm:"size:50"`                      // 职位
+	Status        int            `json:"status" gorm:"default:1"`                      // 状态: 1=正常, 0=禁用
+	LastLoginTime *time.Time     `json:"last_login_time"`                              // 最后登录时间
+	CreateTime    time.Time      `json:"create_time" gorm:"autoCreateTime"`            // 创建时间
+	UpdateTime    time.Time      `json:"update_time" gorm:"autoUpdateTime"`            // 更新时间
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`                               // 删除时间 (软删除，位于回收站)
+
+	// 非数据库字段，用于管理员查看
+	LockedUntil *time.Time `json:"locked_until,omitempty" gorm:"-"` // 登录锁定截止时间 (连续登录失败被临时锁定时)
 }
 
 // TableName 指定表名
@@ -29,29 +36,71 @@ func (User) TableName() string {
 	return "users"
 }
 
+// Role 角色
+// 用户通过 users.role 关联角色编码，权限项保存在 role_permissions 中。
+type Role struct {
+	ID          int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Code        string    `json:"code" gorm:"size:50;not null;uniqueIndex"` // 角色编码 (对应 users.role)
+	Name        string    `json:"name" gorm:"size:50;not null"`             // 角色名称
+	Description string    `json:"description" gorm:"size:255"`              // 角色说明
+	IsBuiltin   int       `json:"is_builtin" gorm:"default:0"`              // 是否内置角色: 1=是 (不可删除), 0=否
+	CreateTime  time.Time `json:"create_time" gorm:"autoCreateTime"`        // 创建时间
+	UpdateTime  time.Time `json:"update_time" gorm:"autoUpdateTime"`        // 更新时间
+
+	// 关联
+	Permissions []RolePermission `json:"-" gorm:"foreignKey:RoleID"` // 权限项
+
+	// 非数据库字段，用于前端展示
+	PermissionCodes []string `json:"permissions" gorm:"-"` // 权限编码列表
+	UserCount       int64    `json:"user_count" gorm:"-"`  // 使用该角色的用户数
+}
+
+// TableName 指定表名
+func (Role) TableName() string {
+	return "roles"
+}
+
+// RolePermission 角色权限项
+type RolePermission struct {
+	ID         int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	RoleID     int64  `json:"role_id" gorm:"not null;uniqueIndex:idx_role_permission"`            // 角色ID
+	Permission string `json:"permission" gorm:"size:50;not null;uniqueIndex:idx_role_permission"` // 权限编码 (如 payments:confirm)
+}
+
+// TableName 指定表名
+func (RolePermission) TableName() string {
+	return "role_permissions"
+}
+
 // Project 项目模型
 // 核心业务对象，记录项目基本信息、合同详情及财务汇总。
 type Project struct {
-	ID             int64      `json:"id" gorm:"primaryKey;autoIncrement"`
-	Name           string     `json:"name" gorm:"size:100;not null"`              // 项目名称
-	Company        string     `json:"company" gorm:"size:100;not null"`           // 建设单位/客户
-	TotalAmount    float64    `json:"total_amount" gorm:"type:real;not null"`     // 合同总金额
-	ReceivedAmount float64    `json:"received_amount" gorm:"type:real;default:0"` // 已回款金额
-	Status         string     `json:"status" gorm:"size:20;not null"`             // 状态: pending, processing, completed, archived
-	Type           string     `json:"type" gorm:"size:50;not null"`               // 项目类型 (字典项)
-	ContractNumber string     `json:"contract_number" gorm:"size:50"`             // 合同编号
-	ContractDate   *time.Time `json:"contract_date" gorm:"type:date"`             // 签订日期
-	PaymentMethod  string     `json:"payment_method" gorm:"size:30"`              // 支付方式 (字典项)
-	StartDate      time.Time  `json:"start_date" gorm:"type:date;not null"`       // 计划开始日期
-	EndDate        time.Time  `json:"end_date" gorm:"type:date;not null"`         // 计划结束日期
-	Description    string     `json:"description"`                                // 项目描述
-	UserID         int64      `json:"user_id" gorm:"not null;index"`              // 负责人ID
-	CreateTime     time.Time  `json:"create_time" gorm:"autoCreateTime"`          // 创建时间
-	UpdateTime     time.Time  `json:"update_time" gorm:"autoUpdateTime"`          // 更新时间
+	ID             int64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name           string         `json:"name" gorm:"size:100;not null"`        // 项目名称
+	Company        string         `json:"company" gorm:"size:100;not null"`     // 建设单位/客户
+	TotalAmount    money.Amount   `json:"total_amount" gorm:"not null"`         // 合同总金额 (分)
+	ReceivedAmount money.Amount   `json:"received_amount" gorm:"default:0"`     // 已回款净额 (分，已收金额扣除退款)
+	ChangeAmount   money.Amount   `json:"change_amount" gorm:"default:0"`       // 已批准变更单的金额合计 (分)
+	Status         string         `json:"status" gorm:"size:20;not null"`       // 状态: pending, processing, completed, archived
+	Type           string         `json:"type" gorm:"size:50;not null"`         // 项目类型 (字典项)
+	ContractNumber string         `json:"contract_number" gorm:"size:50"`       // 合同编号
+	ContractDate   *time.Time     `json:"contract_date" gorm:"type:date"`       // 签订日期
+	PaymentMethod  string         `json:"payment_method" gorm:"size:30"`        // 支付方式 (字典项)
+	StartDate      time.Time      `json:"start_date" gorm:"type:date;not null"` // 计划开始日期
+	EndDate        time.Time      `json:"end_date" gorm:"type:date;not null"`   // 计划结束日期
+	Description    string         `json:"description"`                          // 项目描述
+	UserID         int64          `json:"user_id" gorm:"not null;index"`        // 负责人ID
+	CreateTime     time.Time      `json:"create_time" gorm:"autoCreateTime"`    // 创建时间
+	UpdateTime     time.Time      `json:"update_time" gorm:"autoUpdateTime"`    // 更新时间
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`                       // 删除时间 (软删除，位于回收站)
 
 	// 关联
 	User     *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`        // 关联负责人
 	Payments []Payment `json:"payments,omitempty" gorm:"foreignKey:ProjectID"` // 关联款项列表
+
+	// 非数据库字段，用于前端展示
+	Role            string       `json:"role,omitempty" gorm:"-"`   // 当前用户在项目中的角色: owner, editor, viewer
+	EffectiveAmount money.Amount `json:"effective_amount" gorm:"-"` // 有效合同金额 (合同总金额 + 已批准变更金额)，读取与保存后自动计算
 }
 
 // TableName 指定表名
@@ -59,22 +108,66 @@ func (Project) TableName() string {
 	return "projects"
 }
 
+// ContractAmount 有效合同金额: 原合同总金额加上已批准变更单的金额
+// 款项占比、收款计划校验与仪表盘统计均以此为合同总金额。
+func (p *Project) ContractAmount() money.Amount {
+	return p.TotalAmount + p.ChangeAmount
+}
+
+// AfterFind 查询后计算有效合同金额
+func (p *Project) AfterFind(tx *gorm.DB) error {
+	p.EffectiveAmount = p.ContractAmount()
+	return nil
+}
+
+// AfterSave 保存后计算有效合同金额
+func (p *Project) AfterSave(tx *gorm.DB) error {
+	p.EffectiveAmount = p.ContractAmount()
+	return nil
+}
+
+// ProjectMember 项目成员
+// 记录项目的共享关系。项目负责人 (projects.user_id) 即为所有者，不在此表中重复记录。
+type ProjectMember struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProjectID  int64     `json:"project_id" gorm:"not null;uniqueIndex:idx_project_member"`    // 项目ID
+	UserID     int64     `json:"user_id" gorm:"not null;uniqueIndex:idx_project_member;index"` // 成员用户ID
+	Role       string    `json:"role" gorm:"size:20;not null"`                                 // 角色: editor, viewer
+	InvitedBy  int64     `json:"invited_by"`                                                   // 邀请人ID
+	CreateTime time.Time `json:"create_time" gorm:"autoCreateTime"`                            // 加入时间
+	UpdateTime time.Time `json:"update_time" gorm:"autoUpdateTime"`                            // 更新时间
+
+	// 关联
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"` // 成员用户信息
+}
+
+// TableName 指定表名
+func (ProjectMember) TableName() string {
+	return "project_members"
+}
+
 // Payment 款项模型
 // 记录项目分期付款的计划与实际执行情况。
 type Payment struct {
-	ID         int64      `json:"id" gorm:"primaryKey;autoIncrement"`
-	ProjectID  int64      `json:"project_id" gorm:"not null;index"`          // 关联项目ID
-	Stage      string     `json:"stage" gorm:"size:50;not null"`             // 款项阶段 (如: 首付款, 进度款, 尾款)
-	Amount     float64    `json:"amount" gorm:"type:real;not null"`          // 金额
-	Percentage float64    `json:"percentage" gorm:"type:real"`               // 占总金额百分比
-	PlanDate   time.Time  `json:"plan_date" gorm:"type:date;not null;index"` // 计划收款日期
-	Status     string     `json:"status" gorm:"size:20;not null;index"`      // 状态: uncollected, collected
-	ActualDate *time.Time `json:"actual_date" gorm:"type:date"`              // 实际收款日期
-	Method     string     `json:"method" gorm:"size:30"`                     // 收款方式 (如: 银行转账)
-	Remark     string     `json:"remark" gorm:"size:255"`                    // 备注
-	UserID     int64      `json:"user_id" gorm:"not null"`                   // 经办人ID (通常为创建者或当前负责人)
-	CreateTime time.Time  `json:"create_time" gorm:"autoCreateTime"`         // 创建时间
-	UpdateTime time.Time  `json:"update_time" gorm:"autoUpdateTime"`         // 更新时间
+	ID                 int64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProjectID          int64          `json:"project_id" gorm:"not null;index"`               // 关联项目ID
+	Stage              string         `json:"stage" gorm:"size:50;not null"`                  // 款项阶段 (如: 首付款, 进度款, 尾款)
+	Amount             money.Amount   `json:"amount" gorm:"not null"`                         // 金额 (分)
+	Percentage         float64        `json:"percentage" gorm:"type:real"`                    // 占总金额百分比
+	PlanDate           time.Time      `json:"plan_date" gorm:"type:date;not null;index"`      // 计划收款日期
+	Status             string         `json:"status" gorm:"size:20;not null;index"`           // 状态: pending, partially_paid, paid (由收款记录推导)
+	ActualDate         *time.Time     `json:"actual_date" gorm:"type:date"`                   // 实际收款日期 (最近一笔收款的日期)
+	Method             string         `json:"method" gorm:"size:30"`                          // 收款方式 (如: 银行转账，有收款记录时为最近一笔的方式)
+	ReceivedAmount     money.Amount   `json:"received_amount" gorm:"default:0"`               // 已收金额 (分，收款记录之和)
+	ReceivedPercentage float64        `json:"received_percentage" gorm:"type:real;default:0"` // 已收金额占本期金额的百分比
+	RefundedAmount     money.Amount   `json:"refunded_amount" gorm:"default:0"`               // 已退金额 (分，退款与贷项通知之和，实收净额 = 已收金额 - 已退金额)
+	ScheduleID         *int64         `json:"schedule_id" gorm:"index"`                       // 所属周期收款计划ID (手工录入的款项为空)
+	ScheduleSeq        int            `json:"schedule_seq" gorm:"default:0"`                  // 在周期收款计划中的期数 (从 1 开始)
+	Remark             string         `json:"remark" gorm:"size:255"`                         // 备注
+	UserID             int64          `json:"user_id" gorm:"not null"`                        // 经办人ID (通常为创建者或当前负责人)
+	CreateTime         time.Time      `json:"create_time" gorm:"autoCreateTime"`              // 创建时间
+	UpdateTime         time.Time      `json:"update_time" gorm:"autoUpdateTime"`              // 更新时间
+	DeletedAt          gorm.DeletedAt `json:"-" gorm:"index"`                                 // 删除时间 (软删除，位于回收站)
 
 	// 关联
 	Project *Project `json:"project,omitempty" gorm:"foreignKey:ProjectID"` // 关联项目
@@ -85,6 +178,165 @@ func (Payment) TableName() string {
 	return "payments"
 }
 
+// 款项状态 (由收款记录推导)
+const (
+	PaymentStatusPending       = "pending"        // 待收款: 尚无收款记录
+	PaymentStatusPartiallyPaid = "partially_paid" // 部分收款: 已收金额小于本期金额
+	PaymentStatusPaid          = "paid"           // 已收款: 已收金额达到本期金额
+)
+
+// PaymentReceipt 收款记录
+// 一期款项可分多笔到账，每笔到账记录一条；款项的已收金额与状态由收款记录推导。
+type PaymentReceipt struct {
+	ID           int64        `json:"id" gorm:"primaryKey;autoIncrement"`
+	PaymentID    int64        `json:"payment_id" gorm:"not null;index"`              // 关联款项ID
+	ProjectID    int64        `json:"project_id" gorm:"not null;index"`              // 关联项目ID (冗余，便于统计)
+	Amount       money.Amount `json:"amount" gorm:"not null"`                        // 到账金额 (分)
+	ReceivedDate time.Time    `json:"received_date" gorm:"type:date;not null;index"` // 到账日期
+	Method       string       `json:"method" gorm:"size:30"`                         // 收款方式 (如: 银行转账)
+	Reference    string       `json:"reference" gorm:"column:reference_no;size:100"` // 流水号/凭证号
+	Remark       string       `json:"remark" gorm:"size:255"`                        // 备注
+	UserID       int64        `json:"user_id" gorm:"not null"`                       // 登记人ID
+	CreateTime   time.Time    `json:"create_time" gorm:"autoCreateTime"`             // 创建时间
+	UpdateTime   time.Time    `json:"update_time" gorm:"autoUpdateTime"`             // 更新时间
+}
+
+// TableName 指定表名
+func (PaymentReceipt) TableName() string {
+	return "payment_receipts"
+}
+
+// PaymentRefund 退款记录
+// 已收款项退还给客户的款项 (退款) 或抵减的金额 (贷项通知)，不能超过款项的已收金额。
+// 退款冲减款项与项目的实收净额，不改变款项的收款状态。
+type PaymentRefund struct {
+	ID         int64        `json:"id" gorm:"primaryKey;autoIncrement"`
+	PaymentID  int64        `json:"payment_id" gorm:"not null;index"`              // 关联款项ID
+	ProjectID  int64        `json:"project_id" gorm:"not null;index"`              // 关联项目ID (冗余，便于统计)
+	Type       string       `json:"type" gorm:"size:20;not null"`                  // 类型: refund, credit_note
+	Amount     money.Amount `json:"amount" gorm:"not null"`                        // 退款金额 (分，正数)
+	RefundDate time.Time    `json:"refund_date" gorm:"type:date;not null;index"`   // 退款日期
+	Method     string       `json:"method" gorm:"size:30"`                         // 退款方式 (如: 银行转账)
+	Reference  string       `json:"reference" gorm:"column:reference_no;size:100"` // 流水号/贷项通知单号
+	Reason     string       `json:"reason" gorm:"size:255"`                        // 退款原因
+	UserID     int64        `json:"user_id" gorm:"not null"`                       // 登记人ID
+	CreateTime time.Time    `json:"create_time" gorm:"autoCreateTime"`             // 创建时间
+	UpdateTime time.Time    `json:"update_time" gorm:"autoUpdateTime"`             // 更新时间
+}
+
+// TableName 指定表名
+func (PaymentRefund) TableName() string {
+	return "payment_refunds"
+}
+
+// 退款记录类型
+const (
+	PaymentRefundTypeRefund     = "refund"      // 退款: 将已收款项退还给客户
+	PaymentRefundTypeCreditNote = "credit_note" // 贷项通知: 抵减客户应付金额
+)
+
+// PaymentSchedule 周期收款计划
+// 用于按月/季/年固定收费的项目 (如运维、驻场服务)，后台任务按规则提前生成未来一段时间内的款项。
+type PaymentSchedule struct {
+	ID             int64        `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProjectID      int64        `json:"project_id" gorm:"not null;index"`     // 关联项目ID
+	Stage          string       `json:"stage" gorm:"size:40;not null"`        // 款项阶段名称 (生成的款项命名为 "阶段 第N期")
+	Amount         money.Amount `json:"amount" gorm:"not null"`               // 每期金额 (分)
+	Frequency      string       `json:"frequency" gorm:"size:20;not null"`    // 周期: monthly, quarterly, yearly
+	DayOfMonth     int          `json:"day_of_month" gorm:"not null"`         // 每期收款日 (1-31，超过当月天数时取月末)
+	StartDate      time.Time    `json:"start_date" gorm:"type:date;not null"` // 开始日期 (首期不早于该日期)
+	EndDate        *time.Time   `json:"end_date" gorm:"type:date"`            // 结束日期 (为空表示不限)
+	Count          int          `json:"count" gorm:"default:0"`               // 总期数 (0 表示不限)
+	Method         string       `json:"method" gorm:"size:30"`                // 收款方式
+	Remark         string       `json:"remark" gorm:"size:255"`               // 备注
+	Status         string       `json:"status" gorm:"size:20;not null;index"` // 状态: active, stopped, completed
+	GeneratedCount int          `json:"generated_count" gorm:"default:0"`     // 已生成的期数
+	NextDate       *time.Time   `json:"next_date" gorm:"type:date;index"`     // 下一期的计划收款日期 (已结束时为空)
+	UserID         int64        `json:"user_id" gorm:"not null"`              // 创建人ID
+	CreateTime     time.Time    `json:"create_time" gorm:"autoCreateTime"`    // 创建时间
+	UpdateTime     time.Time    `json:"update_time" gorm:"autoUpdateTime"`    // 更新时间
+}
+
+// TableName 指定表名
+func (PaymentSchedule) TableName() string {
+	return "payment_schedules"
+}
+
+// 周期收款计划状态
+const (
+	PaymentScheduleActive    = "active"    // 进行中: 后台任务持续生成款项
+	PaymentScheduleStopped   = "stopped"   // 已停止: 不再生成，未到期的待收款项已移除
+	PaymentScheduleCompleted = "completed" // 已完成: 达到结束日期或总期数
+)
+
+// ProjectTemplate 项目模板
+// 按项目类型预置项目描述、工期与收款阶段，从模板创建项目时一并生成各期款项。
+type ProjectTemplate struct {
+	ID           int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name         string    `json:"name" gorm:"size:100;not null"`            // 模板名称
+	Type         string    `json:"type" gorm:"size:50;not null;uniqueIndex"` // 适用的项目类型 (字典项，每种类型一个模板)
+	Description  string    `json:"description"`                              // 默认项目描述
+	DurationDays int       `json:"duration_days" gorm:"default:0"`           // 默认工期 (天)，用于推算计划结束日期
+	UserID       int64     `json:"user_id" gorm:"not null"`                  // 创建人ID
+	CreateTime   time.Time `json:"create_time" gorm:"autoCreateTime"`        // 创建时间
+	UpdateTime   time.Time `json:"update_time" gorm:"autoUpdateTime"`        // 更新时间
+
+	// 关联
+	Stages []ProjectTemplateStage `json:"stages" gorm:"foreignKey:TemplateID"` // 收款阶段 (按 sort 排序)
+}
+
+// TableName 指定表名
+func (ProjectTemplate) TableName() string {
+	return "project_templates"
+}
+
+// ProjectTemplateStage 项目模板的收款阶段
+// 款项金额按合同总金额的百分比计算，计划收款日期为项目开始日期加偏移天数。
+type ProjectTemplateStage struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	TemplateID int64     `json:"template_id" gorm:"not null;index"` // 归属模板ID
+	Stage      string    `json:"stage" gorm:"size:50;not null"`     // 款项阶段 (payment_stage 字典值)
+	Percentage float64   `json:"percentage" gorm:"not null"`        // 占合同总金额的百分比 (%)
+	OffsetDays int       `json:"offset_days" gorm:"default:0"`      // 计划收款日期相对项目开始日期的偏移天数
+	Sort       int       `json:"sort" gorm:"default:0"`             // 排序字段
+	CreateTime time.Time `json:"create_time" gorm:"autoCreateTime"`
+	UpdateTime time.Time `json:"update_time" gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (ProjectTemplateStage) TableName() string {
+	return "project_template_stages"
+}
+
+// ChangeOrder 合同变更单
+// 记录范围变更带来的合同金额增减，原合同金额 (projects.total_amount) 保持不变。
+// 变更单批准后计入项目的有效合同金额 (projects.change_amount)，且不能再修改或删除。
+type ChangeOrder struct {
+	ID         int64        `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProjectID  int64        `json:"project_id" gorm:"not null;index"`                  // 关联项目ID
+	Number     string       `json:"number" gorm:"size:50;not null"`                    // 变更单编号 (项目内唯一)
+	Date       time.Time    `json:"date" gorm:"column:change_date;type:date;not null"` // 变更日期
+	Amount     money.Amount `json:"amount" gorm:"not null"`                            // 变更金额 (分，正数为增加，负数为减少)
+	Reason     string       `json:"reason" gorm:"size:500"`                            // 变更原因
+	Status     string       `json:"status" gorm:"size:20;not null;index"`              // 状态: draft, approved
+	ApprovedBy *int64       `json:"approved_by"`                                       // 批准人ID
+	ApprovedAt *time.Time   `json:"approved_at"`                                       // 批准时间
+	UserID     int64        `json:"user_id" gorm:"not null"`                           // 创建人ID
+	CreateTime time.Time    `json:"create_time" gorm:"autoCreateTime"`                 // 创建时间
+	UpdateTime time.Time    `json:"update_time" gorm:"autoUpdateTime"`                 // 更新时间
+}
+
+// TableName 指定表名
+func (ChangeOrder) TableName() string {
+	return "change_orders"
+}
+
+// 合同变更单状态
+const (
+	ChangeOrderDraft    = "draft"    // 草稿: 可修改、删除，不计入有效合同金额
+	ChangeOrderApproved = "approved" // 已批准: 计入有效合同金额，不可修改
+)
+
 // Dictionary 字典主表 (分类)
 // 用于管理系统中的枚举值配置，如项目类型、支付方式等。
 type Dictionary struct {
@@ -152,8 +404,9 @@ type UserNotification struct {
 	ID             int64      `json:"id" gorm:"primaryKey;autoIncrement"`
 	UserID         int64      `json:"user_id" gorm:"not null;uniqueIndex:idx_user_notification"`
 	NotificationID int64      `json:"notification_id" gorm:"not null;uniqueIndex:idx_user_notification"`
-	IsRead         int        `json:"is_read" gorm:"default:0"` // 阅读状态: 0:未读, 1:已读
-	ReadTime       *time.Time `json:"read_time"`                // 阅读时间
+	IsRead         int        `json:"is_read" gorm:"default:0"`          // 阅读状态: 0:未读, 1:已读
+	ReadTime       *time.Time `json:"read_time"`                         // 阅读时间
+	UpdateTime     time.Time  `json:"update_time" gorm:"autoUpdateTime"` // 更新时间 (用于增量同步)
 }
 
 // TableName 指定表名
@@ -169,7 +422,7 @@ type PersonalAccessToken struct {
 	UserID     int64      `json:"user_id" gorm:"not null;index"`     // 关联用户ID
 	Name       string     `json:"name" gorm:"size:50;not null"`      // 令牌名称 (用途描述)
 	TokenHash  string     `json:"-" gorm:"size:100;not null;index"`  // 令牌 Hash (SHA256)
-	Scopes     string     `json:"scopes" gorm:"size:255;default:''"` // 权限范围 (暂留，逗号分隔)
+	Scopes     string     `json:"scopes" gorm:"size:255;default:''"` // 授权范围 (逗号分隔，* 表示完全访问)
 	Status     int        `json:"status" gorm:"default:1"`           // 状态: 1=正常, 0=撤销
 	LastUsedAt *time.Time `json:"last_used_at"`                      // 最后使用时间
 	ExpiresAt  *time.Time `json:"expires_at"`                        // 过期时间 (Null 表示永不过期)
@@ -184,3 +437,246 @@ type PersonalAccessToken struct {
 func (PersonalAccessToken) TableName() string {
 	return "personal_access_tokens"
 }
+
+// UserSession 登录会话
+// 每次登录创建一个会话，客户端持有短期访问令牌 (JWT) 与长期刷新令牌。
+// 刷新令牌每次使用后轮换，数据库只存储 Hash 值；会话仅在本机有效，不参与数据同步。
+type UserSession struct {
+	ID                int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID            int64      `json:"user_id" gorm:"not null;index"`          // 关联用户ID
+	RefreshTokenHash  string     `json:"-" gorm:"size:100;not null;uniqueIndex"` // 当前刷新令牌 Hash (SHA256)
+	PreviousTokenHash string     `json:"-" gorm:"size:100;index"`                // 上一个 (已轮换) 刷新令牌 Hash，用于识别重放
+	UserAgent         string     `json:"user_agent" gorm:"size:255"`             // 客户端 User-Agent
+	IP                string     `json:"ip" gorm:"column:ip;size:64"`            // 客户端 IP
+	LastUsedAt        time.Time  `json:"last_used_at"`                           // 最后刷新时间
+	ExpiresAt         time.Time  `json:"expires_at" gorm:"not null"`             // 过期时间 (每次刷新后顺延)
+	RevokedAt         *time.Time `json:"revoked_at"`                             // 吊销时间 (Null 表示有效)
+	CreateTime        time.Time  `json:"create_time" gorm:"autoCreateTime"`      // 创建时间 (登录时间)
+	UpdateTime        time.Time  `json:"update_time" gorm:"autoUpdateTime"`      // 更新时间
+
+	// 非数据库字段，用于前端展示
+	Current bool `json:"current" gorm:"-"` // 是否为当前请求所在的会话
+}
+
+// TableName 指定表名
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+// UserTwoFactor 用户两步验证 (TOTP) 配置
+// 密钥加密存储；与登录会话一样仅在本机有效，不参与数据同步 (避免密钥随用户表同步到云端)。
+type UserTwoFactor struct {
+	ID           int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID       int64      `json:"user_id" gorm:"not null;uniqueIndex"` // 关联用户ID
+	Secret       string     `json:"-" gorm:"size:255;not null"`          // TOTP 密钥 (AES 加密)
+	Enabled      int        `json:"enabled" gorm:"default:0"`            // 是否已启用: 1=已启用, 0=待验证
+	LastUsedStep int64      `json:"-" gorm:"default:0"`                  // 最近一次使用的时间步 (防止验证码重放)
+	EnabledAt    *time.Time `json:"enabled_at"`                          // 启用时间
+	CreateTime   time.Time  `json:"create_time" gorm:"autoCreateTime"`   // 创建时间
+	UpdateTime   time.Time  `json:"update_time" gorm:"autoUpdateTime"`   // 更新时间
+}
+
+// TableName 指定表名
+func (UserTwoFactor) TableName() string {
+	return "user_two_factors"
+}
+
+// UserRecoveryCode 两步验证恢复码
+// 丢失验证器时用于登录，每个恢复码只能使用一次，数据库只存储 Hash 值。
+type UserRecoveryCode struct {
+	ID         int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     int64      `json:"user_id" gorm:"not null;index"`     // 关联用户ID
+	CodeHash   string     `json:"-" gorm:"size:100;not null"`        // 恢复码 Hash (SHA256)
+	UsedAt     *time.Time `json:"used_at"`                           // 使用时间 (Null 表示未使用)
+	CreateTime time.Time  `json:"create_time" gorm:"autoCreateTime"` // 创建时间
+}
+
+// TableName 指定表名
+func (UserRecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+// LoginThrottle 登录失败计数
+// 按账户 (user:<ID> 或 name:<登录名>) 与来源 IP (ip:<地址>) 分别计数，用于失败退避与账户锁定。
+// 仅在本机有效，不参与数据同步。
+type LoginThrottle struct {
+	ID            int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Key           string     `json:"key" gorm:"column:throttle_key;size:150;not null;uniqueIndex"` // 计数键
+	Failures      int        `json:"failures" gorm:"default:0"`                                    // 连续失败次数
+	LastFailureAt time.Time  `json:"last_failure_at"`                                              // 最后一次失败时间
+	LockedUntil   *time.Time `json:"locked_until"`                                                 // 锁定截止时间 (仅账户计数)
+	UpdateTime    time.Time  `json:"update_time" gorm:"autoUpdateTime"`                            // 更新时间
+}
+
+// TableName 指定表名
+func (LoginThrottle) TableName() string {
+	return "login_throttles"
+}
+
+// LoginLockout 账户锁定记录
+// 每次因连续登录失败触发锁定时写入一条记录，便于管理员审查。
+type LoginLockout struct {
+	ID          int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID      int64      `json:"user_id" gorm:"index"`              // 关联用户ID (登录名不存在时为 0)
+	Username    string     `json:"username" gorm:"size:100"`          // 尝试登录的用户名/邮箱/手机号
+	IP          string     `json:"ip" gorm:"column:ip;size:64"`       // 触发锁定的请求 IP
+	Failures    int        `json:"failures"`                          // 触发锁定时的连续失败次数
+	LockedUntil time.Time  `json:"locked_until"`                      // 锁定截止时间
+	UnlockedAt  *time.Time `json:"unlocked_at"`                       // 管理员手动解锁时间
+	UnlockedBy  int64      `json:"unlocked_by"`                       // 解锁操作人ID
+	CreateTime  time.Time  `json:"create_time" gorm:"autoCreateTime"` // 锁定时间
+}
+
+// TableName 指定表名
+func (LoginLockout) TableName() string {
+	return "login_lockouts"
+}
+
+// AuditLog 审计日志
+// 记录数据变更操作的操作人、来源、对象及变更前后的数据快照。
+// 仅在本机有效，不参与数据同步。
+type AuditLog struct {
+	ID            int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	ActorID       int64     `json:"actor_id" gorm:"index"`                                      // 操作人ID (未登录操作为 0)
+	ActorName     string    `json:"actor_name" gorm:"size:50"`                                  // 操作人用户名
+	Source        string    `json:"source" gorm:"size:20"`                                      // 来源: session, token, anonymous
+	AccessTokenID int64     `json:"access_token_id"`                                            // 个人访问令牌ID (来源为 token 时)
+	IP            string    `json:"ip" gorm:"column:ip;size:64"`                                // 客户端 IP
+	Action        string    `json:"action" gorm:"size:50;not null;index"`                       // 操作类型
+	EntityType    string    `json:"entity_type" gorm:"size:50;not null;index:idx_audit_entity"` // 实体类型
+	EntityID      int64     `json:"entity_id" gorm:"index:idx_audit_entity"`                    // 实体ID
+	Before        string    `json:"before" gorm:"column:before_data;type:text"`                 // 变更前数据 (JSON)
+	After         string    `json:"after" gorm:"column:after_data;type:text"`                   // 变更后数据 (JSON)
+	CreateTime    time.Time `json:"create_time" gorm:"autoCreateTime;index"`                    // 操作时间
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// Revision 数据版本
+// 项目与款项每次变更后保存一份完整快照，用于查看历史、对比差异及恢复到指定版本。
+// 仅在本机有效，不参与数据同步。
+type Revision struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	EntityType string    `json:"entity_type" gorm:"size:20;not null;uniqueIndex:idx_revision_version"` // 实体类型: project, payment
+	EntityID   int64     `json:"entity_id" gorm:"not null;uniqueIndex:idx_revision_version"`           // 实体ID
+	Version    int       `json:"version" gorm:"not null;uniqueIndex:idx_revision_version"`             // 版本号 (同一实体内从 1 递增)
+	Action     string    `json:"action" gorm:"size:20;not null"`                                       // 产生版本的操作: baseline, create, update, archive, confirm, restore
+	Snapshot   string    `json:"snapshot" gorm:"type:text;not null"`                                   // 实体快照 (JSON)
+	ActorID    int64     `json:"actor_id"`                                                             // 操作人ID
+	ActorName  string    `json:"actor_name" gorm:"size:50"`                                            // 操作人用户名
+	CreateTime time.Time `json:"create_time" gorm:"autoCreateTime"`                                    // 创建时间
+}
+
+// TableName 指定表名
+func (Revision) TableName() string {
+	return "revisions"
+}
+
+// SyncTombstone 同步删除墓碑
+// 记录同步表中被删除的记录，增量同步时据此删除云端对应数据。
+type SyncTombstone struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Table      string    `json:"table_name" gorm:"column:table_name;size:50;not null;index:idx_sync_tombstone"` // 表名
+	RecordID   int64     `json:"record_id" gorm:"not null"`                                                     // 被删除记录的ID
+	DeleteTime time.Time `json:"delete_time" gorm:"not null;index:idx_sync_tombstone"`                          // 删除时间
+}
+
+// TableName 指定表名
+func (SyncTombstone) TableName() string {
+	return "sync_tombstones"
+}
+
+// SyncCheckpoint 同步检查点
+// 按 "同步目标 + 表" 记录最近一次成功同步的高水位时间，下次同步仅推送其后变更的数据。
+type SyncCheckpoint struct {
+	ID             int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Target         string     `json:"target" gorm:"size:255;not null;uniqueIndex:idx_sync_checkpoint"`                      // 同步目标标识 (类型://用户@主机:端口/库名)
+	Table          string     `json:"table_name" gorm:"column:table_name;size:50;not null;uniqueIndex:idx_sync_checkpoint"` // 表名
+	LastSyncTime   time.Time  `json:"last_sync_time" gorm:"not null"`                                                       // 高水位: 上次成功同步的开始时间
+	RemoteSyncTime *time.Time `json:"remote_sync_time"`                                                                     // 云端高水位: 上次拉取到的云端记录最大更新时间
+	SyncedCount    int64      `json:"synced_count" gorm:"default:0"`                                                        // 上次同步的记录数
+	CreateTime     time.Time  `json:"create_time" gorm:"autoCreateTime"`                                                    // 创建时间
+	UpdateTime     time.Time  `json:"update_time" gorm:"autoUpdateTime"`                                                    // 更新时间
+}
+
+// TableName 指定表名
+func (SyncCheckpoint) TableName() string {
+	return "sync_checkpoints"
+}
+
+// SyncRowVersion 同步行基准版本
+// 记录每条记录上次双向同步完成时的内容哈希，用于判断本地与云端自上次同步以来是否各自发生了修改。
+type SyncRowVersion struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Target     string    `json:"target" gorm:"size:255;not null;uniqueIndex:idx_sync_row_version"`                      // 同步目标标识
+	Table      string    `json:"table_name" gorm:"column:table_name;size:50;not null;uniqueIndex:idx_sync_row_version"` // 表名
+	RecordID   int64     `json:"record_id" gorm:"not null;uniqueIndex:idx_sync_row_version"`                            // 记录ID
+	Hash       string    `json:"hash" gorm:"size:64;not null"`                                                          // 内容哈希 (SHA256)
+	UpdateTime time.Time `json:"update_time" gorm:"autoUpdateTime"`                                                     // 更新时间
+}
+
+// TableName 指定表名
+func (SyncRowVersion) TableName() string {
+	return "sync_row_versions"
+}
+
+// SyncProfile 同步配置
+// 保存命名的云端数据库连接与同步参数，可由后台调度器按间隔自动执行。
+type SyncProfile struct {
+	ID              int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name            string     `json:"name" gorm:"size:50;not null;uniqueIndex"` // 配置名称，唯一
+	DBType          string     `json:"db_type" gorm:"size:20;not null"`          // 目标数据库类型: postgres, mysql, sqlite
+	Host            string     `json:"host" gorm:"size:255"`                     // 主机地址
+	Port            int        `json:"port"`                                     // 端口号
+	User            string     `json:"user" gorm:"column:db_user;size:100"`      // 数据库用户名
+	Password        string     `json:"-" gorm:"size:500"`                        // 数据库密码 (AES-GCM 加密存储)，JSON 序列化时忽略
+	DBName          string     `json:"db_name" gorm:"size:100"`                  // 数据库名
+	SSLMode         string     `json:"ssl_mode" gorm:"size:20"`                  // SSL 模式
+	Path            string     `json:"path" gorm:"size:500"`                     // SQLite 文件路径 (仅 sqlite)
+	Tables          string     `json:"tables" gorm:"size:500"`                   // 同步表 (逗号分隔，空表示全部)
+	Direction       string     `json:"direction" gorm:"size:20;not null"`        // 同步方向: push, pull, merge
+	Atomic          int        `json:"atomic"`                                   // 是否整体事务同步: 1=是, 0=否
+	IntervalMinutes int        `json:"interval_minutes"`                         // 定时同步间隔 (分钟)，0 表示不定时
+	Enabled         int        `json:"enabled"`                                  // 是否启用定时同步: 1=启用, 0=停用
+	LastRunTime     *time.Time `json:"last_run_time"`                            // 上次执行时间
+	LastStatus      string     `json:"last_status" gorm:"size:20"`               // 上次执行状态: success, partial, failed
+	NextRunTime     *time.Time `json:"next_run_time" gorm:"index"`               // 下次计划执行时间
+	UserID          int64      `json:"user_id" gorm:"not null"`                  // 创建者ID
+	CreateTime      time.Time  `json:"create_time" gorm:"autoCreateTime"`        // 创建时间
+	UpdateTime      time.Time  `json:"update_time" gorm:"autoUpdateTime"`        // 更新时间
+}
+
+// TableName 指定表名
+func (SyncProfile) TableName() string {
+	return "sync_profiles"
+}
+
+// SyncHistory 同步历史
+// 记录每次同步 (手动或定时) 的起止时间、各表统计与错误信息。
+type SyncHistory struct {
+	ID            int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	ProfileID     int64      `json:"profile_id" gorm:"index"`              // 同步配置ID (0 表示未保存配置的手动同步)
+	ProfileName   string     `json:"profile_name" gorm:"size:50"`          // 同步配置名称 (快照)
+	Target        string     `json:"target" gorm:"size:255"`               // 同步目标标识
+	Trigger       string     `json:"trigger" gorm:"size:20;not null"`      // 触发方式: manual, schedule
+	Direction     string     `json:"direction" gorm:"size:20"`             // 同步方向
+	Status        string     `json:"status" gorm:"size:20;not null;index"` // 状态: running, success, partial, failed
+	StartTime     time.Time  `json:"start_time" gorm:"not null;index"`     // 开始时间
+	EndTime       *time.Time `json:"end_time"`                             // 结束时间
+	SyncedCount   int64      `json:"synced_count"`                         // 推送记录数合计
+	PulledCount   int64      `json:"pulled_count"`                         // 拉取记录数合计
+	DeletedCount  int64      `json:"deleted_count"`                        // 删除记录数合计
+	ConflictCount int64      `json:"conflict_count"`                       // 冲突数合计
+	Results       string     `json:"results"`                              // 各表同步结果 (JSON)
+	ErrorMessage  string     `json:"error_message"`                        // 错误信息
+	UserID        int64      `json:"user_id"`                              // 触发用户ID (定时同步为 0)
+	CreateTime    time.Time  `json:"create_time" gorm:"autoCreateTime"`    // 创建时间
+}
+
+// TableName 指定表名
+func (SyncHistory) TableName() string {
+	return "sync_history"
+}
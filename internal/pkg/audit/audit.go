@@ -0,0 +1,51 @@
+package audit
+
+// 操作来源
+const (
+	SourceSession   = "session"   // 登录会话 (JWT)
+	SourceToken     = "token"     // 个人访问令牌 (PAT)
+	SourceAnonymous = "anonymous" // 未登录 (如自助注册)
+	SourceSystem    = "system"    // 系统任务 (如回收站自动清理)
+)
+
+// 操作类型
+const (
+	ActionCreate         = "create"          // 创建
+	ActionUpdate         = "update"          // 更新
+	ActionDelete         = "delete"          // 删除
+	ActionArchive        = "archive"         // 归档项目
+	ActionConfirm        = "confirm"         // 确认收款
+	ActionRefund         = "refund"          // 登记退款
+	ActionApprove        = "approve"         // 批准合同变更单
+	ActionRegister       = "register"        // 自助注册
+	ActionChangePassword = "change_password" // 修改本人密码
+	ActionResetPassword  = "reset_password"  // 管理员重置密码
+	ActionUnlock         = "unlock"          // 解除登录锁定
+	ActionRestore        = "restore"         // 恢复到历史版本 / 从回收站恢复
+	ActionPurge          = "purge"           // 从回收站彻底删除
+)
+
+// 实体类型
+const (
+	EntityProject         = "project"          // 项目
+	EntityProjectMember   = "project_member"   // 项目成员
+	EntityProjectTemplate = "project_template" // 项目模板
+	EntityChangeOrder     = "change_order"     // 合同变更单
+	EntityPayment         = "payment"          // 款项
+	EntityPaymentReceipt  = "payment_receipt"  // 收款记录
+	EntityPaymentRefund   = "payment_refund"   // 退款记录
+	EntityPaymentSchedule = "payment_schedule" // 周期收款计划
+	EntityUser            = "user"             // 用户
+	EntityDictionaryItem  = "dictionary_item"  // 字典选项
+	EntityNotification    = "notification"     // 通知
+)
+
+// Actor 操作人
+// 由 Handler 从请求上下文构造并传入服务层，服务层据此写入审计日志。
+type Actor struct {
+	UserID        int64  // 操作人ID (未登录时为 0)
+	Username      string // 操作人用户名
+	Source        string // 操作来源: session, token, anonymous, system
+	AccessTokenID int64  // 个人访问令牌ID (来源为 token 时)
+	IP            string // 客户端 IP
+}
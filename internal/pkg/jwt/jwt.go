@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -13,17 +15,25 @@ var (
 	// 注意: 生产环境应从配置文件或环境变量读取，而非硬编码。
 	SecretKey = []byte("orange-secret-key-xu")
 
-	// TokenExpiry Token 有效期时长
+	// TokenExpiry 访问令牌有效期时长 (短期有效，过期后通过刷新令牌续期)
 	// 此变量通常由 main.go 在启动时根据配置注入初始化。
 	TokenExpiry time.Duration
+
+	// ChallengeExpiry 两步验证质询令牌有效期
+	// 密码校验通过后签发，用户须在有效期内提交验证码完成登录。
+	ChallengeExpiry = 5 * time.Minute
 )
 
+// challengeSubject 两步验证质询令牌的 Subject，用于与访问令牌区分
+const challengeSubject = "2fa_challenge"
+
 // Claims 自定义 JWT 载荷结构
 // 包含业务需要的用户信息以及 JWT 标准声明 (RegisteredClaims)。
 type Claims struct {
 	UserID               int64  `json:"user_id"`  // 用户ID
 	Username             string `json:"username"` // 用户名
 	Role                 string `json:"role"`     // 用户角色
+	SessionID            int64  `json:"sid"`      // 登录会话ID (用于服务端吊销)
 	jwt.RegisteredClaims        // 内嵌标准声明 (如过期时间、签发人等)
 }
 
@@ -32,15 +42,17 @@ type Claims struct {
 //   - userID: 用户ID
 //   - username: 用户名
 //   - role: 用户角色
+//   - sessionID: 登录会话ID
 //
 // 返回:
 //   - string: 签名后的 Token 字符串
 //   - error: 签名过程中可能出现的错误
-func GenerateToken(userID int64, username, role string) (string, error) {
+func GenerateToken(userID int64, username, role string, sessionID int64) (string, error) {
 	claims := Claims{
-		UserID:   userID,
-		Username: username,
-		Role:     role,
+		UserID:    userID,
+		Username:  username,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenExpiry)), // 过期时间
 			IssuedAt:  jwt.NewNumericDate(time.Now()),                  // 签发时间
@@ -73,8 +85,59 @@ func ParseToken(tokenString string) (*Claims, error) {
 
 	// 验证 Token 有效性并提取 Claims
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		// 质询令牌不能作为访问令牌使用
+		if claims.Subject == challengeSubject {
+			return nil, errors.New("invalid token")
+		}
 		return claims, nil
 	}
 
 	return nil, errors.New("invalid token")
 }
+
+// GenerateChallengeToken 生成两步验证质询令牌
+// 质询令牌只证明密码校验已通过，不能访问任何业务接口；
+// 每个令牌带有唯一ID (jti)，便于服务端限制验证次数并保证一次性使用。
+//
+// 返回:
+//   - string: 签名后的质询令牌
+//   - error: 签名过程中可能出现的错误
+func GenerateChallengeToken(userID int64, username string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := Claims{
+		UserID:   userID,
+		Username: username,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        hex.EncodeToString(buf),
+			Subject:   challengeSubject,
+			ExpiresAt: jwt.NewNumericDate(now.Add(ChallengeExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "orange",
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(SecretKey)
+}
+
+// ParseChallengeToken 解析并验证两步验证质询令牌
+func ParseChallengeToken(tokenString string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid signing method")
+		}
+		return SecretKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && claims.Subject == challengeSubject && claims.ID != "" {
+		return claims, nil
+	}
+	return nil, errors.New("invalid challenge token")
+}
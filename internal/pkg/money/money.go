@@ -0,0 +1,175 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Scale 每元对应的最小货币单位数 (分)
+const Scale = 100
+
+// ErrInvalidAmount 金额格式错误
+var ErrInvalidAmount = errors.New("金额格式错误")
+
+// Amount 金额，以最小货币单位 (分) 的整数表示
+// 数据库中存为 BIGINT，求和与比较均为整数运算，不产生浮点累加误差；
+// JSON 中以元为单位的十进制数字读写 (如 1234.5)，与原 float64 字段保持兼容。
+type Amount int64
+
+// FromYuan 将以元为单位的浮点数转换为金额 (四舍五入到分)
+func FromYuan(yuan float64) Amount {
+	return Amount(math.Round(yuan * Scale))
+}
+
+// Yuan 以元为单位的浮点数 (仅用于展示或比率计算)
+func (a Amount) Yuan() float64 {
+	return float64(a) / Scale
+}
+
+// Percent 计算 a 占 whole 的百分比，whole 不大于 0 时返回 0
+func (a Amount) Percent(whole Amount) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return float64(a) / float64(whole) * 100
+}
+
+// Portion 按百分比计算 a 的份额 (四舍五入到分)
+func (a Amount) Portion(percent float64) Amount {
+	return Amount(math.Round(float64(a) * percent / 100))
+}
+
+// String 格式化为两位小数的元 (如 "1234.50")
+func (a Amount) String() string {
+	sign := ""
+	v := int64(a)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/Scale, v%Scale)
+}
+
+// MarshalJSON 序列化为以元为单位的 JSON 数字，省略末尾的 0 (如 1234.5、100)
+func (a Amount) MarshalJSON() ([]byte, error) {
+	s := a.String()
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || s == "-" {
+		s = "0"
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalJSON 解析以元为单位的 JSON 数字或数字字符串，超过两位的小数四舍五入到分
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	if s == "null" {
+		return nil
+	}
+	s = strings.Trim(s, `"`)
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// Parse 解析以元为单位的十进制字符串 (如 "1234.56")，超过两位的小数四舍五入到分
+// 按十进制逐位解析，不经过浮点数；科学计数法按浮点数解析后取整。
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidAmount
+	}
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, ErrInvalidAmount
+		}
+		return FromYuan(f), nil
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return 0, ErrInvalidAmount
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return 0, ErrInvalidAmount
+			}
+		}
+	}
+
+	// 小数补齐到三位: 前两位为分，第三位用于四舍五入
+	frac := (fracPart + "000")[:3]
+	yuan, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || yuan > math.MaxInt64/Scale-1 {
+		return 0, ErrInvalidAmount
+	}
+	cents, _ := strconv.ParseInt(frac[:2], 10, 64)
+	v := yuan*Scale + cents
+	if frac[2] >= '5' {
+		v++
+	}
+	if negative {
+		v = -v
+	}
+	return Amount(v), nil
+}
+
+// Value 实现 driver.Valuer，以分为单位写入数据库
+func (a Amount) Value() (driver.Value, error) {
+	return int64(a), nil
+}
+
+// Scan 实现 sql.Scanner，读取以分为单位的整数
+// 兼容聚合查询返回的浮点数与十进制字符串 (如 PostgreSQL / MySQL 的 SUM 结果)。
+func (a *Amount) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = 0
+	case int64:
+		*a = Amount(v)
+	case float64:
+		*a = Amount(math.Round(v))
+	case []byte:
+		return a.scanString(string(v))
+	case string:
+		return a.scanString(v)
+	default:
+		return fmt.Errorf("money: 无法将 %T 转换为金额", value)
+	}
+	return nil
+}
+
+// scanString 解析数据库返回的以分为单位的数字字符串
+func (a *Amount) scanString(s string) error {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*a = Amount(n)
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("money: 无法将 %q 转换为金额", s)
+	}
+	*a = Amount(math.Round(f))
+	return nil
+}
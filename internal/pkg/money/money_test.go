@@ -0,0 +1,109 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    Amount
+		wantErr bool
+	}{
+		{name: "整数", in: "100", want: 10000},
+		{name: "两位小数", in: "1234.56", want: 123456},
+		{name: "一位小数", in: "1234.5", want: 123450},
+		{name: "无整数部分", in: ".5", want: 50},
+		{name: "无小数部分", in: "5.", want: 500},
+		{name: "第三位四舍五入进位", in: "0.125", want: 13},
+		{name: "第三位四舍五入舍去", in: "0.124", want: 12},
+		{name: "进位到元", in: "1.999", want: 200},
+		{name: "负数", in: "-1.005", want: -101},
+		{name: "正号", in: "+2.5", want: 250},
+		{name: "首尾空白", in: "  3.10 ", want: 310},
+		{name: "浮点数无法精确表示的值", in: "0.29", want: 29},
+		{name: "科学计数法", in: "1.5e2", want: 15000},
+		{name: "空字符串", in: "", wantErr: true},
+		{name: "仅符号", in: "-", wantErr: true},
+		{name: "仅小数点", in: ".", wantErr: true},
+		{name: "非数字", in: "12a", wantErr: true},
+		{name: "多个小数点", in: "1.2.3", wantErr: true},
+		{name: "溢出", in: "999999999999999999999", wantErr: true},
+		{name: "非法科学计数法", in: "1e", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAmount) {
+					t.Fatalf("Parse(%q) error = %v, want ErrInvalidAmount", tt.in, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmountMarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   Amount
+		want string
+	}{
+		{in: 0, want: "0"},
+		{in: 10000, want: "100"},
+		{in: 123450, want: "1234.5"},
+		{in: 123456, want: "1234.56"},
+		{in: 5, want: "0.05"},
+		{in: 50, want: "0.5"},
+		{in: -101, want: "-1.01"},
+		{in: -10000, want: "-100"},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.in)
+		if err != nil {
+			t.Fatalf("Marshal(%d) unexpected error: %v", tt.in, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("Marshal(%d) = %s, want %s", tt.in, got, tt.want)
+		}
+
+		// 序列化结果应能无损解析回原值
+		var back Amount
+		if err := json.Unmarshal(got, &back); err != nil {
+			t.Fatalf("Unmarshal(%s) unexpected error: %v", got, err)
+		}
+		if back != tt.in {
+			t.Errorf("Unmarshal(%s) = %d, want %d", got, back, tt.in)
+		}
+	}
+}
+
+func TestAmountUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+	}{
+		{in: `1234.56`, want: 123456},
+		{in: `"1234.56"`, want: 123456},
+		{in: `null`, want: 0},
+		{in: `0.105`, want: 11},
+	}
+	for _, tt := range tests {
+		var got Amount
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Fatalf("Unmarshal(%s) unexpected error: %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package permission
+
+// 权限编码 (模块:操作)
+const (
+	UsersManage         = "users:manage"         // 用户管理 (增删改、重置密码)
+	RolesManage         = "roles:manage"         // 角色与权限分配管理
+	ProjectsRead        = "projects:read"        // 查看项目 (本人负责或被共享的项目)
+	ProjectsReadAll     = "projects:read_all"    // 只读查看全部项目 (审计)
+	ProjectsWrite       = "projects:write"       // 创建、编辑、归档项目及管理成员
+	ProjectsDelete      = "projects:delete"      // 删除项目
+	TemplatesManage     = "templates:manage"     // 项目模板管理
+	PaymentsRead        = "payments:read"        // 查看款项
+	PaymentsWrite       = "payments:write"       // 创建、编辑、删除款项
+	PaymentsConfirm     = "payments:confirm"     // 确认收款
+	DictionariesManage  = "dictionaries:manage"  // 字典选项管理
+	NotificationsManage = "notifications:manage" // 发布与管理通知
+	SyncExecute         = "sync:execute"         // 执行数据同步
+	SyncManage          = "sync:manage"          // 同步配置、离线导入导出
+	SystemBackup        = "system:backup"        // 数据库备份与恢复
+	AuditRead           = "audit:read"           // 查看审计日志
+)
+
+// Definition 权限定义
+type Definition struct {
+	Code  string `json:"code"`  // 权限编码
+	Name  string `json:"name"`  // 显示名称
+	Group string `json:"group"` // 所属模块
+}
+
+// All 全部权限定义 (按模块排列)
+var All = []Definition{
+	{Code: UsersManage, Name: "用户管理", Group: "系统"},
+	{Code: RolesManage, Name: "角色管理", Group: "系统"},
+	{Code: SystemBackup, Name: "备份与恢复", Group: "系统"},
+	{Code: DictionariesManage, Name: "字典管理", Group: "系统"},
+	{Code: NotificationsManage, Name: "通知管理", Group: "系统"},
+	{Code: AuditRead, Name: "查看审计日志", Group: "系统"},
+	{Code: ProjectsRead, Name: "查看项目", Group: "项目"},
+	{Code: ProjectsReadAll, Name: "查看全部项目", Group: "项目"},
+	{Code: ProjectsWrite, Name: "编辑项目", Group: "项目"},
+	{Code: ProjectsDelete, Name: "删除项目", Group: "项目"},
+	{Code: TemplatesManage, Name: "项目模板管理", Group: "项目"},
+	{Code: PaymentsRead, Name: "查看款项", Group: "款项"},
+	{Code: PaymentsWrite, Name: "编辑款项", Group: "款项"},
+	{Code: PaymentsConfirm, Name: "确认收款", Group: "款项"},
+	{Code: SyncExecute, Name: "执行同步", Group: "数据同步"},
+	{Code: SyncManage, Name: "同步配置管理", Group: "数据同步"},
+}
+
+// IsValid 判断权限编码是否已定义
+func IsValid(code string) bool {
+	for _, d := range All {
+		if d.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// 内置角色编码
+const (
+	RoleAdmin          = "admin"           // 系统管理员 (隐式拥有全部权限)
+	RoleUser           = "user"            // 普通用户
+	RoleFinance        = "finance"         // 财务
+	RoleProjectManager = "project_manager" // 项目经理
+	RoleAuditor        = "auditor"         // 审计员 (只读)
+)
+
+// BuiltinRole 内置角色定义
+type BuiltinRole struct {
+	Code        string
+	Name        string
+	Description string
+	Permissions []string
+}
+
+// BuiltinRoles 内置角色及默认权限 (用于初始化数据)
+// 管理员角色不存储权限项，始终拥有全部权限，新增权限无需重新授权。
+var BuiltinRoles = []BuiltinRole{
+	{
+		Code:        RoleAdmin,
+		Name:        "管理员",
+		Description: "拥有全部权限",
+	},
+	{
+		Code:        RoleUser,
+		Name:        "普通用户",
+		Description: "管理本人负责或被共享的项目与款项",
+		Permissions: []string{ProjectsRead, ProjectsWrite, ProjectsDelete, PaymentsRead, PaymentsWrite, PaymentsConfirm},
+	},
+	{
+		Code:        RoleFinance,
+		Name:        "财务",
+		Description: "查看项目，维护款项并确认收款",
+		Permissions: []string{ProjectsRead, PaymentsRead, PaymentsWrite, PaymentsConfirm},
+	},
+	{
+		Code:        RoleProjectManager,
+		Name:        "项目经理",
+		Description: "维护项目与收款计划，不能确认收款",
+		Permissions: []string{ProjectsRead, ProjectsWrite, ProjectsDelete, TemplatesManage, PaymentsRead, PaymentsWrite},
+	},
+	{
+		Code:        RoleAuditor,
+		Name:        "审计员",
+		Description: "只读查看全部项目、款项与审计日志",
+		Permissions: []string{ProjectsRead, ProjectsReadAll, PaymentsRead, AuditRead},
+	},
+}
@@ -0,0 +1,113 @@
+package permission
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 个人访问令牌 (PAT) 的授权范围
+// 范围只能收窄令牌的能力，令牌仍受所属用户角色权限的约束。
+// 细分范围的格式为 <资源>:<read|write>，其中 write 隐含同一资源的 read。
+const (
+	ScopeAll  = "*"    // 完全访问 (与所属用户权限一致)
+	ScopeRead = "read" // 全部资源只读
+)
+
+// 授权范围动作
+const (
+	ActionRead  = "read"  // 读取 (GET/HEAD 请求)
+	ActionWrite = "write" // 写入 (其余请求)
+)
+
+// ScopeDefinition 授权范围定义
+type ScopeDefinition struct {
+	Code string `json:"code"` // 范围编码
+	Name string `json:"name"` // 显示名称
+}
+
+// Scopes 可授予令牌的全部范围
+var Scopes = []ScopeDefinition{
+	{Code: ScopeAll, Name: "完全访问"},
+	{Code: ScopeRead, Name: "只读访问"},
+	{Code: "dashboard:read", Name: "查看仪表盘"},
+	{Code: "projects:read", Name: "查看项目"},
+	{Code: "projects:write", Name: "管理项目"},
+	{Code: "payments:read", Name: "查看款项"},
+	{Code: "payments:write", Name: "管理款项"},
+	{Code: "notifications:read", Name: "查看通知"},
+	{Code: "notifications:write", Name: "管理通知"},
+	{Code: "dictionaries:read", Name: "查看字典"},
+	{Code: "dictionaries:write", Name: "管理字典"},
+	{Code: "users:read", Name: "查看用户"},
+	{Code: "users:write", Name: "管理用户"},
+	{Code: "roles:read", Name: "查看角色"},
+	{Code: "roles:write", Name: "管理角色"},
+	{Code: "sync:read", Name: "查看同步配置"},
+	{Code: "sync:write", Name: "执行数据同步"},
+	{Code: "system:read", Name: "查看系统信息"},
+	{Code: "system:write", Name: "系统维护 (备份与恢复)"},
+	{Code: "audit:read", Name: "查看审计日志"},
+}
+
+// IsValidScope 判断授权范围是否可授予
+func IsValidScope(code string) bool {
+	for _, d := range Scopes {
+		if d.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeScopes 校验并去重授权范围，返回逗号分隔的存储格式
+func NormalizeScopes(scopes []string) (string, error) {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !IsValidScope(scope) {
+			return "", fmt.Errorf("未知的授权范围: %s", scope)
+		}
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		result = append(result, scope)
+	}
+	if len(result) == 0 {
+		return "", errors.New("至少需要一个授权范围")
+	}
+	return strings.Join(result, ","), nil
+}
+
+// ParseScopes 解析逗号分隔的授权范围
+func ParseScopes(s string) []string {
+	var scopes []string
+	for _, scope := range strings.Split(s, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// ScopeAllows 判断已授予的范围是否允许对资源执行指定动作
+//
+// 参数:
+//   - granted: 令牌已授予的范围
+//   - resource: 资源名称 (如 projects)
+//   - action: 动作 (read 或 write)
+func ScopeAllows(granted []string, resource, action string) bool {
+	for _, scope := range granted {
+		switch scope {
+		case ScopeAll, resource + ":" + action:
+			return true
+		case ScopeRead, resource + ":" + ActionWrite:
+			if action == ActionRead {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package permission
+
+import "testing"
+
+func TestScopeAllows(t *testing.T) {
+	tests := []struct {
+		name     string
+		granted  []string
+		resource string
+		action   string
+		want     bool
+	}{
+		{name: "完全访问允许写入", granted: []string{ScopeAll}, resource: "projects", action: ActionWrite, want: true},
+		{name: "完全访问允许读取", granted: []string{ScopeAll}, resource: "audit", action: ActionRead, want: true},
+		{name: "只读允许读取", granted: []string{ScopeRead}, resource: "payments", action: ActionRead, want: true},
+		{name: "只读拒绝写入", granted: []string{ScopeRead}, resource: "payments", action: ActionWrite, want: false},
+		{name: "资源读取范围允许读取", granted: []string{"projects:read"}, resource: "projects", action: ActionRead, want: true},
+		{name: "资源读取范围拒绝写入", granted: []string{"projects:read"}, resource: "projects", action: ActionWrite, want: false},
+		{name: "资源写入范围允许写入", granted: []string{"projects:write"}, resource: "projects", action: ActionWrite, want: true},
+		{name: "写入隐含读取", granted: []string{"projects:write"}, resource: "projects", action: ActionRead, want: true},
+		{name: "其他资源的范围不生效", granted: []string{"payments:write"}, resource: "projects", action: ActionRead, want: false},
+		{name: "多个范围任一满足", granted: []string{"dashboard:read", "projects:write"}, resource: "projects", action: ActionWrite, want: true},
+		{name: "只读与写入范围组合", granted: []string{ScopeRead, "sync:write"}, resource: "sync", action: ActionWrite, want: true},
+		{name: "资源名前缀不匹配", granted: []string{"project:read"}, resource: "projects", action: ActionRead, want: false},
+		{name: "未授予任何范围", granted: nil, resource: "projects", action: ActionRead, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScopeAllows(tt.granted, tt.resource, tt.action); got != tt.want {
+				t.Errorf("ScopeAllows(%v, %q, %q) = %v, want %v", tt.granted, tt.resource, tt.action, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []string
+		want    string
+		wantErr bool
+	}{
+		{name: "去重并保持顺序", in: []string{"projects:read", " payments:write ", "projects:read"}, want: "projects:read,payments:write"},
+		{name: "完全访问", in: []string{ScopeAll}, want: ScopeAll},
+		{name: "未知范围", in: []string{"projects:delete"}, wantErr: true},
+		{name: "空列表", in: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeScopes(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeScopes(%v) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeScopes(%v) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
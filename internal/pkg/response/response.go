@@ -32,6 +32,7 @@ const (
 	CodeUnauthorized  = 2001 // 未授权 (未登录或 Token 无效)
 	CodeTokenExpired  = 2002 // Token 已过期
 	CodeForbidden     = 2003 // 禁止访问 (无权限)
+	CodeTooManyTries  = 2004 // 登录尝试过多 (失败退避中或账户被临时锁定)
 	CodeInternalError = 5000 // 服务器内部错误
 )
 
@@ -43,6 +44,7 @@ var codeMessages = map[int]string{
 	CodeUnauthorized:  "未授权",
 	CodeTokenExpired:  "Token已过期",
 	CodeForbidden:     "禁止访问",
+	CodeTooManyTries:  "尝试次数过多",
 	CodeInternalError: "服务器内部错误",
 }
 
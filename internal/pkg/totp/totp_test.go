@@ -0,0 +1,68 @@
+package totp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret RFC 6238 附录 B 的 SHA1 测试密钥 "12345678901234567890" 的 Base32 编码
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		unix     int64
+		wantStep int64
+		wantOK   bool
+	}{
+		// RFC 6238 测试向量取后 6 位
+		{name: "RFC 向量 59", secret: rfcSecret, code: "287082", unix: 59, wantStep: 1, wantOK: true},
+		{name: "RFC 向量 1111111109", secret: rfcSecret, code: "081804", unix: 1111111109, wantStep: 37037036, wantOK: true},
+		{name: "RFC 向量 1111111111", secret: rfcSecret, code: "050471", unix: 1111111111, wantStep: 37037037, wantOK: true},
+		{name: "RFC 向量 1234567890", secret: rfcSecret, code: "005924", unix: 1234567890, wantStep: 41152263, wantOK: true},
+		{name: "RFC 向量 2000000000", secret: rfcSecret, code: "279037", unix: 2000000000, wantStep: 66666666, wantOK: true},
+		{name: "小写密钥", secret: strings.ToLower(rfcSecret), code: "287082", unix: 59, wantStep: 1, wantOK: true},
+		{name: "验证码首尾空白", secret: rfcSecret, code: " 287082 ", unix: 59, wantStep: 1, wantOK: true},
+		{name: "允许慢一个时间步", secret: rfcSecret, code: "287082", unix: 59 + Period, wantStep: 1, wantOK: true},
+		{name: "允许快一个时间步", secret: rfcSecret, code: "287082", unix: 59 - Period, wantStep: 1, wantOK: true},
+		{name: "超出时钟偏差", secret: rfcSecret, code: "287082", unix: 59 + 2*Period},
+		{name: "验证码错误", secret: rfcSecret, code: "287083", unix: 59},
+		{name: "验证码位数不足", secret: rfcSecret, code: "28708", unix: 59},
+		{name: "验证码位数过多", secret: rfcSecret, code: "94287082", unix: 59},
+		{name: "密钥非法", secret: "not-base32!", code: "287082", unix: 59},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(tt.secret, tt.code, time.Unix(tt.unix, 0))
+			if ok != tt.wantOK {
+				t.Fatalf("Validate() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if step != tt.wantStep {
+				t.Errorf("Validate() step = %d, want %d", step, tt.wantStep)
+			}
+		})
+	}
+}
+
+func TestGenerateSecretRoundTrip(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() unexpected error: %v", err)
+	}
+	key, err := encoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret %q is not valid base32: %v", secret, err)
+	}
+	if len(key) != secretSize {
+		t.Fatalf("secret length = %d, want %d", len(key), secretSize)
+	}
+
+	now := time.Now()
+	code := generate(key, now.Unix()/Period)
+	if _, ok := Validate(secret, code, now); !ok {
+		t.Errorf("Validate() rejected freshly generated code %s", code)
+	}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditLogFilter 审计日志筛选条件 (零值表示不限)
+type AuditLogFilter struct {
+	ActorID    int64
+	Action     string
+	EntityType string
+	EntityID   int64
+	Source     string
+	Start      *time.Time
+	End        *time.Time // 不包含
+}
+
+// AuditRepository 审计日志数据仓库
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository 创建审计日志仓库
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{db: database.GetDB()}
+}
+
+// Create 写入审计日志
+func (r *AuditRepository) Create(log *models.AuditLog) error {
+	return r.db.Create(log).Error
+}
+
+// List 分页查询审计日志，按时间倒序
+func (r *AuditRepository) List(filter AuditLogFilter, page, pageSize int) ([]models.AuditLog, int64, error) {
+	var logs []models.AuditLog
+	var total int64
+
+	query := r.db.Model(&models.AuditLog{})
+	if filter.ActorID > 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID > 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Source != "" {
+		query = query.Where("source = ?", filter.Source)
+	}
+	if filter.Start != nil {
+		query = query.Where("create_time >= ?", *filter.Start)
+	}
+	if filter.End != nil {
+		query = query.Where("create_time < ?", *filter.End)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("create_time DESC, id DESC").Offset(offset).Limit(pageSize).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ChangeOrderRepository 合同变更单数据仓库
+type ChangeOrderRepository struct {
+	db *gorm.DB
+}
+
+// NewChangeOrderRepository 创建合同变更单仓库
+func NewChangeOrderRepository() *ChangeOrderRepository {
+	return &ChangeOrderRepository{db: database.GetDB()}
+}
+
+// WithTx 返回绑定到指定事务的仓库副本
+func (r *ChangeOrderRepository) WithTx(tx *gorm.DB) *ChangeOrderRepository {
+	return &ChangeOrderRepository{db: tx}
+}
+
+// FindByID 根据ID查找变更单
+func (r *ChangeOrderRepository) FindByID(id int64) (*models.ChangeOrder, error) {
+	var order models.ChangeOrder
+	if err := r.db.First(&order, id).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// FindForUpdate 根据ID查找并锁定变更单 (需在事务中调用)
+func (r *ChangeOrderRepository) FindForUpdate(id int64) (*models.ChangeOrder, error) {
+	var order models.ChangeOrder
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, id).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// ListByProject 获取项目的变更单 (按变更日期升序)
+func (r *ChangeOrderRepository) ListByProject(projectID int64) ([]models.ChangeOrder, error) {
+	var orders []models.ChangeOrder
+	if err := r.db.Where("project_id = ?", projectID).
+		Order("change_date ASC, id ASC").
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// ListApproved 获取项目已批准的变更单 (按变更日期、批准时间升序)
+func (r *ChangeOrderRepository) ListApproved(projectID int64) ([]models.ChangeOrder, error) {
+	var orders []models.ChangeOrder
+	if err := r.db.Where("project_id = ? AND status = ?", projectID, models.ChangeOrderApproved).
+		Order("change_date ASC, approved_at ASC, id ASC").
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// SumApproved 计算项目已批准变更单的金额合计
+func (r *ChangeOrderRepository) SumApproved(projectID int64) (money.Amount, error) {
+	var total money.Amount
+	err := r.db.Model(&models.ChangeOrder{}).
+		Where("project_id = ? AND status = ?", projectID, models.ChangeOrderApproved).
+		Select("COALESCE(SUM(amount), 0)").Scan(&total).Error
+	return total, err
+}
+
+// ExistsByNumber 检查项目内变更单编号是否已存在
+func (r *ChangeOrderRepository) ExistsByNumber(projectID int64, number string, excludeID int64) (bool, error) {
+	var count int64
+	query := r.db.Model(&models.ChangeOrder{}).Where("project_id = ? AND number = ?", projectID, number)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// Create 创建变更单
+func (r *ChangeOrderRepository) Create(order *models.ChangeOrder) error {
+	return r.db.Create(order).Error
+}
+
+// Update 更新变更单
+func (r *ChangeOrderRepository) Update(order *models.ChangeOrder) error {
+	return r.db.Save(order).Error
+}
+
+// Delete 删除变更单
+func (r *ChangeOrderRepository) Delete(id int64) error {
+	return r.db.Delete(&models.ChangeOrder{}, id).Error
+}
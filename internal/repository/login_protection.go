@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginProtectionRepository 登录保护数据仓库 (失败计数与锁定记录)
+type LoginProtectionRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginProtectionRepository 创建登录保护仓库
+func NewLoginProtectionRepository() *LoginProtectionRepository {
+	return &LoginProtectionRepository{db: database.GetDB()}
+}
+
+// WithTx 返回绑定到指定事务的仓库副本
+func (r *LoginProtectionRepository) WithTx(tx *gorm.DB) *LoginProtectionRepository {
+	return &LoginProtectionRepository{db: tx}
+}
+
+// FindThrottle 查找计数记录，不存在时返回 nil
+func (r *LoginProtectionRepository) FindThrottle(key string) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	err := r.db.Where("throttle_key = ?", key).First(&throttle).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// SaveThrottle 保存计数记录 (不存在时创建)
+func (r *LoginProtectionRepository) SaveThrottle(throttle *models.LoginThrottle) error {
+	return r.db.Save(throttle).Error
+}
+
+// FindThrottleForUpdate 查找并锁定计数记录，不存在时先创建 (需在事务中调用)
+// 并发的首次失败只有一个插入生效，其余请求等待行锁后读取同一条记录。
+func (r *LoginProtectionRepository) FindThrottleForUpdate(key string) (*models.LoginThrottle, error) {
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.LoginThrottle{Key: key}).Error; err != nil {
+		return nil, err
+	}
+	var throttle models.LoginThrottle
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("throttle_key = ?", key).First(&throttle).Error; err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// DeleteStaleThrottles 删除已过失败窗口且未处于锁定中的计数记录
+//
+// 参数:
+//   - before: 最后一次失败早于该时间的记录视为过期
+//   - now: 当前时间 (锁定截止时间晚于该时间的记录保留)
+func (r *LoginProtectionRepository) DeleteStaleThrottles(before, now time.Time) error {
+	return r.db.Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?)", before, now).
+		Delete(&models.LoginThrottle{}).Error
+}
+
+// DeleteThrottle 删除计数记录 (登录成功或管理员解锁时清零)
+func (r *LoginProtectionRepository) DeleteThrottle(key string) error {
+	return r.db.Where("throttle_key = ?", key).Delete(&models.LoginThrottle{}).Error
+}
+
+// ListLockedUsers 获取指定用户中仍处于锁定状态的用户及锁定截止时间
+func (r *LoginProtectionRepository) ListLockedUsers(userIDs []int64) (map[int64]time.Time, error) {
+	result := make(map[int64]time.Time)
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	keys := make([]string, 0, len(userIDs))
+	byKey := make(map[string]int64, len(userIDs))
+	for _, id := range userIDs {
+		key := UserThrottleKey(id)
+		keys = append(keys, key)
+		byKey[key] = id
+	}
+
+	var throttles []models.LoginThrottle
+	if err := r.db.Where("throttle_key IN ? AND locked_until > ?", keys, time.Now()).Find(&throttles).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range throttles {
+		if id, ok := byKey[t.Key]; ok && t.LockedUntil != nil {
+			result[id] = *t.LockedUntil
+		}
+	}
+	return result, nil
+}
+
+// CreateLockout 写入账户锁定记录
+func (r *LoginProtectionRepository) CreateLockout(lockout *models.LoginLockout) error {
+	return r.db.Create(lockout).Error
+}
+
+// ListLockouts 获取用户的锁定记录 (按时间倒序)
+func (r *LoginProtectionRepository) ListLockouts(userID int64, limit int) ([]models.LoginLockout, error) {
+	var lockouts []models.LoginLockout
+	if err := r.db.Where("user_id = ?", userID).
+		Order("create_time DESC, id DESC").
+		Limit(limit).
+		Find(&lockouts).Error; err != nil {
+		return nil, err
+	}
+	return lockouts, nil
+}
+
+// MarkUnlocked 将用户尚未到期的锁定记录标记为已手动解锁
+func (r *LoginProtectionRepository) MarkUnlocked(userID, operatorID int64) error {
+	return r.db.Model(&models.LoginLockout{}).
+		Where("user_id = ? AND unlocked_at IS NULL AND locked_until > ?", userID, time.Now()).
+		Updates(map[string]interface{}{
+			"unlocked_at": time.Now(),
+			"unlocked_by": operatorID,
+		}).Error
+}
+
+// UserThrottleKey 用户账户的计数键
+func UserThrottleKey(userID int64) string {
+	return "user:" + strconv.FormatInt(userID, 10)
+}
+
+// NameThrottleKey 不存在的登录名的计数键 (与真实账户同样计数，避免通过锁定行为探测账户是否存在)
+func NameThrottleKey(name string) string {
+	return "name:" + truncateKey(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// IPThrottleKey 来源 IP 的计数键
+func IPThrottleKey(ip string) string {
+	return "ip:" + truncateKey(ip)
+}
+
+// truncateKey 截断过长的键值 (计数键最大 150 字符)
+func truncateKey(s string) string {
+	if len(s) > 100 {
+		return s[:100]
+	}
+	return s
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/FruitsAI/Orange/internal/database"
 	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
+	"github.com/FruitsAI/Orange/internal/pkg/money"
 	"gorm.io/gorm"
 )
 
@@ -57,6 +59,11 @@ func NewPaymentRepository() *PaymentRepository {
 	return &PaymentRepository{db: database.GetDB()}
 }
 
+// WithTx 返回绑定到指定事务的仓库副本
+func (r *PaymentRepository) WithTx(tx *gorm.DB) *PaymentRepository {
+	return &PaymentRepository{db: tx}
+}
+
 // FindByID 根据ID查找收款
 func (r *PaymentRepository) FindByID(id int64) (*models.Payment, error) {
 	var payment models.Payment
@@ -86,13 +93,32 @@ func (r *PaymentRepository) ListByProject(projectID int64) ([]models.Payment, er
 	return payments, nil
 }
 
-// ListUpcoming 获取指定天数内即将到期待收款项
+// ListAllByProject 获取项目的全部款项 (含回收站中的款项，按计划日期与ID升序)
+func (r *PaymentRepository) ListAllByProject(projectID int64) ([]models.Payment, error) {
+	var payments []models.Payment
+	if err := r.db.Unscoped().Where("project_id = ?", projectID).
+		Order("plan_date ASC, id ASC").
+		Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// UpdateAmount 更新款项金额与占比 (收款计划调整时使用)
+func (r *PaymentRepository) UpdateAmount(id int64, amount money.Amount, percentage float64) error {
+	return r.db.Unscoped().Model(&models.Payment{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"amount":     amount,
+		"percentage": percentage,
+	}).Error
+}
+
+// ListUpcoming 获取指定天数内即将到期待收款项 (含部分收款)
 func (r *PaymentRepository) ListUpcoming(userID int64, days int, limit int) ([]models.Payment, error) {
 	var payments []models.Payment
 	endDate := time.Now().AddDate(0, 0, days).Format("2006-01-02")
 
 	if err := r.db.Preload("Project").
-		Where("user_id = ? AND status = ? AND plan_date <= ?", userID, "pending", endDate).
+		Where("project_id IN (?) AND status <> ? AND plan_date <= ?", visibleProjects(r.db, userID), models.PaymentStatusPaid, endDate).
 		Order("plan_date ASC").
 		Limit(limit).
 		Find(&payments).Error; err != nil {
@@ -102,12 +128,12 @@ func (r *PaymentRepository) ListUpcoming(userID int64, days int, limit int) ([]m
 }
 
 // ListOverdue 获取当前已逾期的待收款项
-// 逾期定义: status="pending" 且 plan_date 小于今天
+// 逾期定义: 未收齐 (status 不为 "paid") 且 plan_date 小于今天
 func (r *PaymentRepository) ListOverdue(userID int64) ([]models.Payment, error) {
 	var payments []models.Payment
 	today := time.Now().Format("2006-01-02")
 
-	if err := r.db.Where("user_id = ? AND status = ? AND plan_date < ?", userID, "pending", today).
+	if err := r.db.Where("project_id IN (?) AND status <> ? AND plan_date < ?", visibleProjects(r.db, userID), models.PaymentStatusPaid, today).
 		Find(&payments).Error; err != nil {
 		return nil, err
 	}
@@ -124,38 +150,124 @@ func (r *PaymentRepository) Update(payment *models.Payment) error {
 	return r.db.Save(payment).Error
 }
 
-// Delete 删除收款
+// Delete 删除收款 (移入回收站)
 func (r *PaymentRepository) Delete(id int64) error {
 	return r.db.Delete(&models.Payment{}, id).Error
 }
 
-// Confirm 执行确认收款
-// 将状态更新为 'paid' 并记录实际收款信息
-func (r *PaymentRepository) Confirm(id int64, actualDate, method string) error {
-	return r.db.Model(&models.Payment{}).Where("id = ?", id).
-		Updates(map[string]interface{}{
-			"status":      "paid",
-			"actual_date": actualDate,
-			"method":      method,
-		}).Error
+// FindDeleted 根据ID查找回收站中的款项
+func (r *PaymentRepository) FindDeleted(id int64) (*models.Payment, error) {
+	var payment models.Payment
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&payment, id).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// ListDeleted 获取单独删除 (所属项目未删除) 的款项 (按删除时间倒序)
+// 随项目一并删除的款项在回收站中归属于项目，不单独列出。
+// includeAll 为 false 时仅返回用户负责或参与的项目下的款项。
+func (r *PaymentRepository) ListDeleted(userID int64, includeAll bool) ([]models.Payment, error) {
+	var payments []models.Payment
+	projects := r.db.Model(&models.Project{}).Select("id")
+	if !includeAll {
+		projects = visibleProjects(r.db, userID)
+	}
+	if err := r.db.Unscoped().Preload("Project").
+		Where("deleted_at IS NOT NULL AND project_id IN (?)", projects).
+		Order("deleted_at DESC").
+		Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// ListDeletedBefore 获取删除时间早于指定时间、且所属项目未删除的款项ID (回收站自动清理)
+// 随项目一并删除的款项在清理项目时一并删除。
+func (r *PaymentRepository) ListDeletedBefore(before time.Time) ([]int64, error) {
+	var ids []int64
+	projects := r.db.Model(&models.Project{}).Select("id")
+	err := r.db.Unscoped().Model(&models.Payment{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND project_id IN (?)", before, projects).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Restore 从回收站恢复款项
+// 恢复时刷新更新时间，增量同步会将其重新推送至云端。
+func (r *PaymentRepository) Restore(id int64) error {
+	return r.db.Unscoped().Model(&models.Payment{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+// Purge 彻底删除款项及其收款记录、退款记录、历史版本 (事务，不可恢复)
+func (r *PaymentRepository) Purge(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("payment_id = ?", id).Delete(&models.PaymentReceipt{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("payment_id = ?", id).Delete(&models.PaymentRefund{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("entity_type = ? AND entity_id = ?", audit.EntityPayment, id).
+			Delete(&models.Revision{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Payment{}, id).Error
+	})
+}
+
+// LastBySchedule 获取周期收款计划最近一期已生成的款项 (含回收站中的款项)
+func (r *PaymentRepository) LastBySchedule(scheduleID int64) (*models.Payment, error) {
+	var payment models.Payment
+	if err := r.db.Unscoped().Where("schedule_id = ?", scheduleID).
+		Order("schedule_seq DESC").
+		First(&payment).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// PurgePendingBySchedule 彻底删除周期收款计划中计划日期不早于 from 的待收款项及其历史版本
+// 已有收款记录 (部分收款或已收款) 的款项保留不动。
+//
+// 返回:
+//   - []int64: 删除的款项ID
+func (r *PaymentRepository) PurgePendingBySchedule(scheduleID int64, from time.Time) ([]int64, error) {
+	var ids []int64
+	if err := r.db.Unscoped().Model(&models.Payment{}).
+		Where("schedule_id = ? AND status = ? AND plan_date >= ?", scheduleID, models.PaymentStatusPending, from).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if err := r.db.Where("entity_type = ? AND entity_id IN ?", audit.EntityPayment, ids).
+		Delete(&models.Revision{}).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Unscoped().Delete(&models.Payment{}, ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
 }
 
 // SumByStatus 按状态统计金额
-func (r *PaymentRepository) SumByStatus(userID int64, status string) float64 {
-	var sum float64
+func (r *PaymentRepository) SumByStatus(userID int64, status string) money.Amount {
+	var sum money.Amount
 	r.db.Model(&models.Payment{}).
-		Where("user_id = ? AND status = ?", userID, status).
+		Where("project_id IN (?) AND status = ?", visibleProjects(r.db, userID), status).
 		Select("COALESCE(SUM(amount), 0)").Scan(&sum)
 	return sum
 }
 
-// SumOverdue 统计逾期金额
-func (r *PaymentRepository) SumOverdue(userID int64) float64 {
-	var sum float64
+// SumOverdue 统计逾期金额 (部分收款的款项仅计未收部分，已退款的部分重新计入未收)
+func (r *PaymentRepository) SumOverdue(userID int64) money.Amount {
+	var sum money.Amount
 	today := time.Now().Format("2006-01-02")
 	r.db.Model(&models.Payment{}).
-		Where("user_id = ? AND status = ? AND plan_date < ?", userID, "pending", today).
-		Select("COALESCE(SUM(amount), 0)").Scan(&sum)
+		Where("project_id IN (?) AND status <> ? AND plan_date < ?", visibleProjects(r.db, userID), models.PaymentStatusPaid, today).
+		Select("COALESCE(SUM(amount - (received_amount - refunded_amount)), 0)").Scan(&sum)
 	return sum
 }
 
@@ -163,7 +275,7 @@ func (r *PaymentRepository) SumOverdue(userID int64) float64 {
 func (r *PaymentRepository) ListByDateRange(userID int64, startDate, endDate string) ([]models.Payment, error) {
 	var payments []models.Payment
 	if err := r.db.Preload("Project").
-		Where("user_id = ? AND plan_date BETWEEN ? AND ?", userID, startDate, endDate).
+		Where("project_id IN (?) AND plan_date BETWEEN ? AND ?", visibleProjects(r.db, userID), startDate, endDate).
 		Order("plan_date ASC").
 		Find(&payments).Error; err != nil {
 		return nil, err
@@ -175,26 +287,27 @@ func (r *PaymentRepository) ListByDateRange(userID int64, startDate, endDate str
 // 分组聚合查询，支持按日或按月统计。
 // 返回:
 //   - expected: map[日期]计划收款金额
-//   - actual: map[日期]实际已收款金额
-func (r *PaymentRepository) GetIncomeStats(userID int64, startDate, endDate, interval string) (map[string]float64, map[string]float64, error) {
-	expected := make(map[string]float64)
-	actual := make(map[string]float64)
+//   - actual: map[日期]实收净额 (到账金额减去退款金额，只有退款的日期为负数)
+func (r *PaymentRepository) GetIncomeStats(userID int64, startDate, endDate, interval string) (map[string]money.Amount, map[string]money.Amount, error) {
+	expected := make(map[string]money.Amount)
+	actual := make(map[string]money.Amount)
 
 	// 根据数据库类型选择日期格式化表达式
 	dbType := database.GetDBType()
 	dateExpr := getDateFormatExpr("plan_date", interval, dbType)
-	actualDateExpr := getDateFormatExpr("actual_date", interval, dbType)
+	actualDateExpr := getDateFormatExpr("payment_receipts.received_date", interval, dbType)
+	refundDateExpr := getDateFormatExpr("payment_refunds.refund_date", interval, dbType)
 
 	type Result struct {
 		Date  string
-		Total float64
+		Total money.Amount
 	}
 
 	// 1. 预期收入: 依据 plan_date 统计所有款项
 	var expectedResults []Result
 	if err := r.db.Model(&models.Payment{}).
 		Select(dateExpr+" as date, COALESCE(SUM(amount), 0) as total").
-		Where("user_id = ? AND plan_date BETWEEN ? AND ?", userID, startDate, endDate).
+		Where("project_id IN (?) AND plan_date BETWEEN ? AND ?", visibleProjects(r.db, userID), startDate, endDate).
 		Group("date").
 		Scan(&expectedResults).Error; err != nil {
 		return nil, nil, err
@@ -203,11 +316,11 @@ func (r *PaymentRepository) GetIncomeStats(userID int64, startDate, endDate, int
 		expected[res.Date] = res.Total
 	}
 
-	// 2. 实际收入: 依据 actual_date 统计已完成(paid)的款项
+	// 2. 实际收入: 依据收款记录的到账日期统计 (含部分收款)
 	var actualResults []Result
-	if err := r.db.Model(&models.Payment{}).
-		Select(actualDateExpr+" as date, COALESCE(SUM(amount), 0) as total").
-		Where("user_id = ? AND status = 'paid' AND actual_date BETWEEN ? AND ?", userID, startDate, endDate).
+	if err := r.receipts(userID).
+		Select(actualDateExpr+" as date, COALESCE(SUM(payment_receipts.amount), 0) as total").
+		Where("payment_receipts.received_date BETWEEN ? AND ?", startDate, endDate).
 		Group("date").
 		Scan(&actualResults).Error; err != nil {
 		return nil, nil, err
@@ -216,55 +329,87 @@ func (r *PaymentRepository) GetIncomeStats(userID int64, startDate, endDate, int
 		actual[res.Date] = res.Total
 	}
 
+	// 3. 退款: 依据退款日期冲减实际收入
+	var refundResults []Result
+	if err := r.refunds(userID).
+		Select(refundDateExpr+" as date, COALESCE(SUM(payment_refunds.amount), 0) as total").
+		Where("payment_refunds.refund_date BETWEEN ? AND ?", startDate, endDate).
+		Group("date").
+		Scan(&refundResults).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, res := range refundResults {
+		actual[res.Date] -= res.Total
+	}
+
 	return expected, actual, nil
 }
 
 // GetStatsByPeriod 获取指定时间周期内的综合指标
 // 返回值:
 //   - totalExpected: 计划在此期间应收总额
-//   - paid: 实际在此期间收到的金额
-//   - pending: 计划在此期间但尚未收到的金额 (包含逾期)
-//   - overdue: 计划在此期间且已逾期的金额 (plan_date < today)
+//   - paid: 实际在此期间收到的净额 (到账金额减去此期间的退款，可能为负数)
+//   - pending: 计划在此期间但尚未收到的金额 (包含逾期，部分收款的款项仅计扣除实收净额后的未收部分)
+//   - overdue: 计划在此期间且已逾期的未收金额 (plan_date < today)
 //   - avgPeriod: 平均回款周期 (天)
-func (r *PaymentRepository) GetStatsByPeriod(userID int64, startDate, endDate string) (total, paid, pending, overdue, avgPeriod float64, err error) {
+func (r *PaymentRepository) GetStatsByPeriod(userID int64, startDate, endDate string) (total, paid, pending, overdue money.Amount, avgPeriod float64, err error) {
 	// 1. Total (TotalExpected): 计划日期在范围内的款项总和
 	r.db.Model(&models.Payment{}).
-		Where("user_id = ? AND plan_date BETWEEN ? AND ?", userID, startDate, endDate).
+		Where("project_id IN (?) AND plan_date BETWEEN ? AND ?", visibleProjects(r.db, userID), startDate, endDate).
 		Select("COALESCE(SUM(amount), 0)").Scan(&total)
 
-	// 2. Paid: 实际日期在范围内已支付的款项
-	r.db.Model(&models.Payment{}).
-		Where("user_id = ? AND status = 'paid' AND actual_date BETWEEN ? AND ?", userID, startDate, endDate).
-		Select("COALESCE(SUM(amount), 0)").Scan(&paid)
-
-	// 3. Pending: 计划日期在范围内，当前状态仍为 pending 的款项
+	// 2. Paid: 到账日期在范围内的收款记录，减去退款日期在范围内的退款记录
+	var refunded money.Amount
+	r.receipts(userID).
+		Where("payment_receipts.received_date BETWEEN ? AND ?", startDate, endDate).
+		Select("COALESCE(SUM(payment_receipts.amount), 0)").Scan(&paid)
+	r.refunds(userID).
+		Where("payment_refunds.refund_date BETWEEN ? AND ?", startDate, endDate).
+		Select("COALESCE(SUM(payment_refunds.amount), 0)").Scan(&refunded)
+	paid -= refunded
+
+	// 3. Pending: 计划日期在范围内，尚未收齐的款项的未收部分 (金额 - 实收净额)
 	r.db.Model(&models.Payment{}).
-		Where("user_id = ? AND status = 'pending' AND plan_date BETWEEN ? AND ?", userID, startDate, endDate).
-		Select("COALESCE(SUM(amount), 0)").Scan(&pending)
+		Where("project_id IN (?) AND status <> 'paid' AND plan_date BETWEEN ? AND ?", visibleProjects(r.db, userID), startDate, endDate).
+		Select("COALESCE(SUM(amount - (received_amount - refunded_amount)), 0)").Scan(&pending)
 
 	// 4. Overdue: 计划日期在范围内，且已逾期 (plan_date < today)
 	//    这是 Pending 的子集
 	today := time.Now().Format("2006-01-02")
 	r.db.Model(&models.Payment{}).
-		Where("user_id = ? AND status = 'pending' AND plan_date BETWEEN ? AND ? AND plan_date < ?", userID, startDate, endDate, today).
-		Select("COALESCE(SUM(amount), 0)").Scan(&overdue)
+		Where("project_id IN (?) AND status <> 'paid' AND plan_date BETWEEN ? AND ? AND plan_date < ?", visibleProjects(r.db, userID), startDate, endDate, today).
+		Select("COALESCE(SUM(amount - (received_amount - refunded_amount)), 0)").Scan(&overdue)
 
 	// 5. AvgPeriod: 平均回款周期 (Actual Date - Plan Date)
 	//    仅统计在此期间实际到账的款项
 	dbType := database.GetDBType()
 	dateDiffExpr := getDateDiffExpr("actual_date", "plan_date", dbType)
 	r.db.Model(&models.Payment{}).
-		Where("user_id = ? AND status = 'paid' AND actual_date BETWEEN ? AND ?", userID, startDate, endDate).
+		Where("project_id IN (?) AND status = 'paid' AND actual_date BETWEEN ? AND ?", visibleProjects(r.db, userID), startDate, endDate).
 		Select("COALESCE(AVG(" + dateDiffExpr + "), 0)").Scan(&avgPeriod)
 
 	return total, paid, pending, overdue, avgPeriod, nil
 }
 
-// SumPaidByProject 计算项目中已支付的总金额
-func (r *PaymentRepository) SumPaidByProject(projectID int64) (float64, error) {
-	var total float64
+// SumPaidByProject 计算项目的实收净额 (各款项已收金额减去已退金额之和，含部分收款)
+func (r *PaymentRepository) SumPaidByProject(projectID int64) (money.Amount, error) {
+	var total money.Amount
 	err := r.db.Model(&models.Payment{}).
-		Where("project_id = ? AND status = ?", projectID, "paid").
-		Select("COALESCE(SUM(amount), 0)").Scan(&total).Error
+		Where("project_id = ?", projectID).
+		Select("COALESCE(SUM(received_amount - refunded_amount), 0)").Scan(&total).Error
 	return total, err
 }
+
+// receipts 用户负责或参与的项目下未删除款项的收款记录查询 (收入统计用)
+func (r *PaymentRepository) receipts(userID int64) *gorm.DB {
+	return r.db.Model(&models.PaymentReceipt{}).
+		Joins("JOIN payments ON payments.id = payment_receipts.payment_id AND payments.deleted_at IS NULL").
+		Where("payments.project_id IN (?)", visibleProjects(r.db, userID))
+}
+
+// refunds 用户负责或参与的项目下未删除款项的退款记录查询 (收入统计用)
+func (r *PaymentRepository) refunds(userID int64) *gorm.DB {
+	return r.db.Model(&models.PaymentRefund{}).
+		Joins("JOIN payments ON payments.id = payment_refunds.payment_id AND payments.deleted_at IS NULL").
+		Where("payments.project_id IN (?)", visibleProjects(r.db, userID))
+}
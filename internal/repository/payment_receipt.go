@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+// ReceiptRepository 收款记录数据仓库
+type ReceiptRepository struct {
+	db *gorm.DB
+}
+
+// NewReceiptRepository 创建收款记录仓库
+func NewReceiptRepository() *ReceiptRepository {
+	return &ReceiptRepository{db: database.GetDB()}
+}
+
+// WithTx 返回绑定到指定事务的仓库副本
+func (r *ReceiptRepository) WithTx(tx *gorm.DB) *ReceiptRepository {
+	return &ReceiptRepository{db: tx}
+}
+
+// FindByID 根据ID查找收款记录
+func (r *ReceiptRepository) FindByID(id int64) (*models.PaymentReceipt, error) {
+	var receipt models.PaymentReceipt
+	if err := r.db.First(&receipt, id).Error; err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// ListByPayment 获取款项的收款记录 (按到账日期倒序)
+func (r *ReceiptRepository) ListByPayment(paymentID int64) ([]models.PaymentReceipt, error) {
+	var receipts []models.PaymentReceipt
+	if err := r.db.Where("payment_id = ?", paymentID).
+		Order("received_date DESC, id DESC").
+		Find(&receipts).Error; err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+// Latest 获取款项最近一笔收款记录
+func (r *ReceiptRepository) Latest(paymentID int64) (*models.PaymentReceipt, error) {
+	var receipt models.PaymentReceipt
+	if err := r.db.Where("payment_id = ?", paymentID).
+		Order("received_date DESC, id DESC").
+		First(&receipt).Error; err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// SumByPayment 计算款项的已收金额
+func (r *ReceiptRepository) SumByPayment(paymentID int64) (money.Amount, error) {
+	var total money.Amount
+	err := r.db.Model(&models.PaymentReceipt{}).
+		Where("payment_id = ?", paymentID).
+		Select("COALESCE(SUM(amount), 0)").Scan(&total).Error
+	return total, err
+}
+
+// Create 创建收款记录
+func (r *ReceiptRepository) Create(receipt *models.PaymentReceipt) error {
+	return r.db.Create(receipt).Error
+}
+
+// Delete 删除收款记录
+func (r *ReceiptRepository) Delete(id int64) error {
+	return r.db.Delete(&models.PaymentReceipt{}, id).Error
+}
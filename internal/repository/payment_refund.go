@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+// RefundRepository 退款记录数据仓库
+type RefundRepository struct {
+	db *gorm.DB
+}
+
+// NewRefundRepository 创建退款记录仓库
+func NewRefundRepository() *RefundRepository {
+	return &RefundRepository{db: database.GetDB()}
+}
+
+// WithTx 返回绑定到指定事务的仓库副本
+func (r *RefundRepository) WithTx(tx *gorm.DB) *RefundRepository {
+	return &RefundRepository{db: tx}
+}
+
+// FindByID 根据ID查找退款记录
+func (r *RefundRepository) FindByID(id int64) (*models.PaymentRefund, error) {
+	var refund models.PaymentRefund
+	if err := r.db.First(&refund, id).Error; err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// ListByPayment 获取款项的退款记录 (按退款日期倒序)
+func (r *RefundRepository) ListByPayment(paymentID int64) ([]models.PaymentRefund, error) {
+	var refunds []models.PaymentRefund
+	if err := r.db.Where("payment_id = ?", paymentID).
+		Order("refund_date DESC, id DESC").
+		Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// SumByPayment 计算款项的已退金额
+func (r *RefundRepository) SumByPayment(paymentID int64) (money.Amount, error) {
+	var total money.Amount
+	err := r.db.Model(&models.PaymentRefund{}).
+		Where("payment_id = ?", paymentID).
+		Select("COALESCE(SUM(amount), 0)").Scan(&total).Error
+	return total, err
+}
+
+// Create 创建退款记录
+func (r *RefundRepository) Create(refund *models.PaymentRefund) error {
+	return r.db.Create(refund).Error
+}
+
+// Delete 删除退款记录
+func (r *RefundRepository) Delete(id int64) error {
+	return r.db.Delete(&models.PaymentRefund{}, id).Error
+}
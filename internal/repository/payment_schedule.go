@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ScheduleRepository 周期收款计划数据仓库
+type ScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduleRepository 创建周期收款计划仓库
+func NewScheduleRepository() *ScheduleRepository {
+	return &ScheduleRepository{db: database.GetDB()}
+}
+
+// WithTx 返回绑定到指定事务的仓库副本
+func (r *ScheduleRepository) WithTx(tx *gorm.DB) *ScheduleRepository {
+	return &ScheduleRepository{db: tx}
+}
+
+// FindByID 根据ID查找周期收款计划
+func (r *ScheduleRepository) FindByID(id int64) (*models.PaymentSchedule, error) {
+	var schedule models.PaymentSchedule
+	if err := r.db.First(&schedule, id).Error; err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// FindForUpdate 根据ID查找并锁定周期收款计划 (需在事务中调用)
+func (r *ScheduleRepository) FindForUpdate(id int64) (*models.PaymentSchedule, error) {
+	var schedule models.PaymentSchedule
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&schedule, id).Error; err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// ListByProject 获取项目的周期收款计划 (按创建时间倒序)
+func (r *ScheduleRepository) ListByProject(projectID int64) ([]models.PaymentSchedule, error) {
+	var schedules []models.PaymentSchedule
+	if err := r.db.Where("project_id = ?", projectID).
+		Order("create_time DESC").
+		Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// ListDueIDs 获取下一期计划日期不晚于指定日期、且所属项目未删除的进行中计划ID (后台生成用)
+func (r *ScheduleRepository) ListDueIDs(until time.Time) ([]int64, error) {
+	var ids []int64
+	projects := r.db.Model(&models.Project{}).Select("id")
+	err := r.db.Model(&models.PaymentSchedule{}).
+		Where("status = ? AND next_date IS NOT NULL AND next_date <= ? AND project_id IN (?)",
+			models.PaymentScheduleActive, until, projects).
+		Order("id ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Create 创建周期收款计划
+func (r *ScheduleRepository) Create(schedule *models.PaymentSchedule) error {
+	return r.db.Create(schedule).Error
+}
+
+// Update 更新周期收款计划
+func (r *ScheduleRepository) Update(schedule *models.PaymentSchedule) error {
+	return r.db.Save(schedule).Error
+}
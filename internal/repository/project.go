@@ -1,9 +1,14 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/FruitsAI/Orange/internal/database"
 	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
+	"github.com/FruitsAI/Orange/internal/pkg/money"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ProjectRepository 项目数据仓库
@@ -16,6 +21,17 @@ func NewProjectRepository() *ProjectRepository {
 	return &ProjectRepository{db: database.GetDB()}
 }
 
+// WithTx 返回绑定到指定事务的仓库副本
+func (r *ProjectRepository) WithTx(tx *gorm.DB) *ProjectRepository {
+	return &ProjectRepository{db: tx}
+}
+
+// visibleProjects 用户负责或参与的未删除项目ID子查询
+func visibleProjects(db *gorm.DB, userID int64) *gorm.DB {
+	memberProjects := db.Model(&models.ProjectMember{}).Select("project_id").Where("user_id = ?", userID)
+	return db.Model(&models.Project{}).Select("id").Where("user_id = ? OR id IN (?)", userID, memberProjects)
+}
+
 // FindByID 根据ID查找项目
 func (r *ProjectRepository) FindByID(id int64) (*models.Project, error) {
 	var project models.Project
@@ -25,6 +41,16 @@ func (r *ProjectRepository) FindByID(id int64) (*models.Project, error) {
 	return &project, nil
 }
 
+// FindForUpdate 根据ID查找并锁定项目 (需在事务中调用)
+// 收款、退款、变更单审批等会更新项目的汇总金额，修改项目前需在事务内重新读取并加锁，避免写回过期的金额。
+func (r *ProjectRepository) FindForUpdate(id int64) (*models.Project, error) {
+	var project models.Project
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&project, id).Error; err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
 // FindByIDWithPayments 根据ID查找项目（包含收款列表）
 func (r *ProjectRepository) FindByIDWithPayments(id int64) (*models.Project, error) {
 	var project models.Project
@@ -38,13 +64,17 @@ func (r *ProjectRepository) FindByIDWithPayments(id int64) (*models.Project, err
 
 // List 分页查询项目列表
 // 支持按用户ID(数据隔离)、状态、关键词(名称或公司名)进行筛选。
+// 结果包含用户负责的项目以及共享给该用户的项目；includeAll 为 true 时不限定用户 (只读查看全部项目)。
 // Preload("User"): 预加载关联的用户信息。
-func (r *ProjectRepository) List(userID int64, status, keyword string, page, pageSize int) ([]models.Project, int64, error) {
+func (r *ProjectRepository) List(userID int64, includeAll bool, status, keyword string, page, pageSize int) ([]models.Project, int64, error) {
 	var projects []models.Project
 	var total int64
 
-	// 构建基础查询：限定用户，预加载关联
-	query := r.db.Model(&models.Project{}).Preload("User").Where("user_id = ?", userID)
+	// 构建基础查询：限定用户 (负责人或成员)，预加载关联
+	query := r.db.Model(&models.Project{}).Preload("User")
+	if !includeAll {
+		query = query.Where("id IN (?)", visibleProjects(r.db, userID))
+	}
 
 	// 动态条件筛选
 	if status != "" && status != "all" {
@@ -66,10 +96,10 @@ func (r *ProjectRepository) List(userID int64, status, keyword string, page, pag
 	return projects, total, nil
 }
 
-// ListRecent 获取最近项目
+// ListRecent 获取最近项目 (用户负责或参与的项目)
 func (r *ProjectRepository) ListRecent(userID int64, limit int) ([]models.Project, error) {
 	var projects []models.Project
-	if err := r.db.Where("user_id = ?", userID).
+	if err := r.db.Where("id IN (?)", visibleProjects(r.db, userID)).
 		Order("create_time DESC").
 		Limit(limit).
 		Find(&projects).Error; err != nil {
@@ -88,9 +118,98 @@ func (r *ProjectRepository) Update(project *models.Project) error {
 	return r.db.Save(project).Error
 }
 
-// Delete 删除项目
+// Delete 删除项目 (移入回收站)
+// 项目及其下款项在同一事务中软删除，并使用相同的删除时间，恢复项目时据此一并恢复这些款项；
+// 共享成员保留，恢复后成员关系不变。
 func (r *ProjectRepository) Delete(id int64) error {
-	return r.db.Delete(&models.Project{}, id).Error
+	now := time.Now()
+	db := r.db.Session(&gorm.Session{NowFunc: func() time.Time { return now }})
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ?", id).Delete(&models.Payment{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Project{}, id).Error
+	})
+}
+
+// FindDeleted 根据ID查找回收站中的项目
+func (r *ProjectRepository) FindDeleted(id int64) (*models.Project, error) {
+	var project models.Project
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&project, id).Error; err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// ListDeleted 获取回收站中的项目 (按删除时间倒序)
+// includeAll 为 false 时仅返回用户负责的项目。
+func (r *ProjectRepository) ListDeleted(userID int64, includeAll bool) ([]models.Project, error) {
+	var projects []models.Project
+	query := r.db.Unscoped().Where("deleted_at IS NOT NULL")
+	if !includeAll {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Order("deleted_at DESC").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// ListDeletedBefore 获取删除时间早于指定时间的项目ID (回收站自动清理)
+func (r *ProjectRepository) ListDeletedBefore(before time.Time) ([]int64, error) {
+	var ids []int64
+	err := r.db.Unscoped().Model(&models.Project{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Restore 从回收站恢复项目，以及随项目一并删除的款项 (事务)
+// 恢复时刷新更新时间，增量同步会将其重新推送至云端。
+func (r *ProjectRepository) Restore(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		deletedAt := tx.Unscoped().Model(&models.Project{}).Select("deleted_at").Where("id = ?", id)
+		if err := tx.Unscoped().Model(&models.Payment{}).
+			Where("project_id = ? AND deleted_at = (?)", id, deletedAt).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.Project{}).Where("id = ?", id).Update("deleted_at", nil).Error
+	})
+}
+
+// Purge 彻底删除项目，及其全部款项、收款记录、退款记录、周期收款计划、合同变更单、共享成员与历史版本 (事务，不可恢复)
+func (r *ProjectRepository) Purge(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		paymentIDs := tx.Unscoped().Model(&models.Payment{}).Select("id").Where("project_id = ?", id)
+		if err := tx.Where("entity_type = ? AND entity_id IN (?)", audit.EntityPayment, paymentIDs).
+			Delete(&models.Revision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("entity_type = ? AND entity_id = ?", audit.EntityProject, id).
+			Delete(&models.Revision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ?", id).Delete(&models.PaymentReceipt{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ?", id).Delete(&models.PaymentRefund{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ?", id).Delete(&models.PaymentSchedule{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ?", id).Delete(&models.ChangeOrder{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("project_id = ?", id).Delete(&models.Payment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("project_id = ?", id).Delete(&models.ProjectMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.Project{}, id).Error
+	})
 }
 
 // UpdateStatus 更新项目状态
@@ -98,21 +217,20 @@ func (r *ProjectRepository) UpdateStatus(id int64, status string) error {
 	return r.db.Model(&models.Project{}).Where("id = ?", id).Update("status", status).Error
 }
 
-// GetStats 获取用户维度的项目财务统计
+// GetStats 获取用户维度的项目财务统计 (用户负责或参与的项目)
 // 返回:
-//   - totalAmount: 所有项目的总合同金额之和
-//   - paidAmount: 所有实收金额之和 (关联 Payments 表统计)
+//   - totalAmount: 所有项目的有效合同金额之和 (合同总金额 + 已批准变更金额)
+//   - paidAmount: 所有实收净额之和 (关联 Payments 表统计，含部分收款，扣除退款)
 //   - pendingAmount: 待收金额 (total - paid)
-func (r *ProjectRepository) GetStats(userID int64) (totalAmount, paidAmount, pendingAmount float64, err error) {
-	// 1. 统计总合同金额 (SUM project.total_amount)
-	r.db.Model(&models.Project{}).Where("user_id = ?", userID).
-		Select("COALESCE(SUM(total_amount), 0)").Scan(&totalAmount)
+func (r *ProjectRepository) GetStats(userID int64) (totalAmount, paidAmount, pendingAmount money.Amount, err error) {
+	// 1. 统计有效合同金额 (SUM project.total_amount + project.change_amount)
+	r.db.Model(&models.Project{}).Where("id IN (?)", visibleProjects(r.db, userID)).
+		Select("COALESCE(SUM(total_amount + change_amount), 0)").Scan(&totalAmount)
 
-	// 2. 统计已收金额 (关联查询 payment 表中 status='paid' 的记录)
+	// 2. 统计实收净额 (关联查询 payment 表的已收金额减去已退金额，含部分收款)
 	r.db.Model(&models.Payment{}).
-		Joins("JOIN projects ON payments.project_id = projects.id").
-		Where("projects.user_id = ? AND payments.status = ?", userID, "paid").
-		Select("COALESCE(SUM(payments.amount), 0)").Scan(&paidAmount)
+		Where("project_id IN (?)", visibleProjects(r.db, userID)).
+		Select("COALESCE(SUM(payments.received_amount - payments.refunded_amount), 0)").Scan(&paidAmount)
 
 	// 3. 计算待收金额
 	pendingAmount = totalAmount - paidAmount
@@ -139,7 +257,8 @@ func (r *ProjectRepository) ExistsByContractNumber(userID int64, contractNumber
 //   - maxContractNumber: 存在的最大编号 (如 "HT202310010005")，如果没有则返回空字符串。
 func (r *ProjectRepository) GetMaxContractNumberByPrefix(userID int64, prefix string) (string, error) {
 	var contractNumber string
-	err := r.db.Model(&models.Project{}).
+	// 包含回收站中的项目，避免恢复后出现重复编号
+	err := r.db.Unscoped().Model(&models.Project{}).
 		Where("user_id = ? AND contract_number LIKE ?", userID, prefix+"%").
 		Order("contract_number DESC").
 		Limit(1).
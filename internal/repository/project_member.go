@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// ProjectMemberRepository 项目成员数据仓库
+type ProjectMemberRepository struct {
+	db *gorm.DB
+}
+
+// NewProjectMemberRepository 创建项目成员仓库
+func NewProjectMemberRepository() *ProjectMemberRepository {
+	return &ProjectMemberRepository{db: database.GetDB()}
+}
+
+// Find 查找指定项目中的成员记录
+func (r *ProjectMemberRepository) Find(projectID, userID int64) (*models.ProjectMember, error) {
+	var member models.ProjectMember
+	if err := r.db.Where("project_id = ? AND user_id = ?", projectID, userID).First(&member).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// ListByProject 获取项目的成员列表 (包含用户信息)
+func (r *ProjectMemberRepository) ListByProject(projectID int64) ([]models.ProjectMember, error) {
+	var members []models.ProjectMember
+	if err := r.db.Preload("User").
+		Where("project_id = ?", projectID).
+		Order("create_time ASC").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// RolesByUser 获取用户在指定项目中的成员角色 (项目ID -> 角色)
+func (r *ProjectMemberRepository) RolesByUser(userID int64, projectIDs []int64) (map[int64]string, error) {
+	roles := make(map[int64]string)
+	if len(projectIDs) == 0 {
+		return roles, nil
+	}
+	var members []models.ProjectMember
+	if err := r.db.Where("user_id = ? AND project_id IN ?", userID, projectIDs).Find(&members).Error; err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		roles[m.ProjectID] = m.Role
+	}
+	return roles, nil
+}
+
+// Create 添加项目成员
+func (r *ProjectMemberRepository) Create(member *models.ProjectMember) error {
+	return r.db.Create(member).Error
+}
+
+// Update 更新项目成员
+func (r *ProjectMemberRepository) Update(member *models.ProjectMember) error {
+	return r.db.Save(member).Error
+}
+
+// Delete 移除项目成员
+func (r *ProjectMemberRepository) Delete(projectID, userID int64) error {
+	return r.db.Where("project_id = ? AND user_id = ?", projectID, userID).Delete(&models.ProjectMember{}).Error
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// TemplateRepository 项目模板数据仓库
+// 封装对 `project_templates` 与 `project_template_stages` 表的数据库操作，模板与其收款阶段总是一并读写。
+type TemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewTemplateRepository 创建项目模板仓库
+func NewTemplateRepository() *TemplateRepository {
+	return &TemplateRepository{db: database.GetDB()}
+}
+
+// WithTx 返回绑定到指定事务的仓库副本
+func (r *TemplateRepository) WithTx(tx *gorm.DB) *TemplateRepository {
+	return &TemplateRepository{db: tx}
+}
+
+// withStages 预加载收款阶段 (按 sort 升序)
+func withStages(db *gorm.DB) *gorm.DB {
+	return db.Preload("Stages", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort ASC, id ASC")
+	})
+}
+
+// List 获取全部项目模板 (含收款阶段，按项目类型排序)
+func (r *TemplateRepository) List() ([]models.ProjectTemplate, error) {
+	var templates []models.ProjectTemplate
+	if err := withStages(r.db).Order("type ASC").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// FindByID 根据ID查找项目模板 (含收款阶段)
+func (r *TemplateRepository) FindByID(id int64) (*models.ProjectTemplate, error) {
+	var template models.ProjectTemplate
+	if err := withStages(r.db).First(&template, id).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindByType 根据项目类型查找项目模板 (含收款阶段)
+func (r *TemplateRepository) FindByType(projectType string) (*models.ProjectTemplate, error) {
+	var template models.ProjectTemplate
+	if err := withStages(r.db).Where("type = ?", projectType).First(&template).Error; err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// ExistsByType 检查项目类型是否已有模板
+func (r *TemplateRepository) ExistsByType(projectType string, excludeID int64) (bool, error) {
+	var count int64
+	query := r.db.Model(&models.ProjectTemplate{}).Where("type = ?", projectType)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// Create 创建项目模板及其收款阶段
+func (r *TemplateRepository) Create(template *models.ProjectTemplate) error {
+	return r.db.Create(template).Error
+}
+
+// Update 更新项目模板，并以 template.Stages 整体替换原有收款阶段
+func (r *TemplateRepository) Update(template *models.ProjectTemplate) error {
+	if err := r.db.Omit("Stages").Save(template).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("template_id = ?", template.ID).Delete(&models.ProjectTemplateStage{}).Error; err != nil {
+		return err
+	}
+	for i := range template.Stages {
+		template.Stages[i].ID = 0
+		template.Stages[i].TemplateID = template.ID
+	}
+	if len(template.Stages) == 0 {
+		return nil
+	}
+	return r.db.Create(&template.Stages).Error
+}
+
+// Delete 删除项目模板及其收款阶段
+func (r *TemplateRepository) Delete(id int64) error {
+	if err := r.db.Where("template_id = ?", id).Delete(&models.ProjectTemplateStage{}).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(&models.ProjectTemplate{}, id).Error
+}
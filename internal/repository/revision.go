@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// RevisionRepository 数据版本仓库
+type RevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewRevisionRepository 创建数据版本仓库
+func NewRevisionRepository() *RevisionRepository {
+	return &RevisionRepository{db: database.GetDB()}
+}
+
+// Create 写入新版本，版本号为该实体当前最大版本号加一 (事务)
+func (r *RevisionRepository) Create(revision *models.Revision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var maxVersion int
+		if err := tx.Model(&models.Revision{}).
+			Where("entity_type = ? AND entity_id = ?", revision.EntityType, revision.EntityID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&maxVersion).Error; err != nil {
+			return err
+		}
+		revision.Version = maxVersion + 1
+		return tx.Create(revision).Error
+	})
+}
+
+// Exists 判断实体是否已有版本记录
+func (r *RevisionRepository) Exists(entityType string, entityID int64) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Revision{}).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// List 获取实体的全部版本 (按版本号倒序)
+func (r *RevisionRepository) List(entityType string, entityID int64) ([]models.Revision, error) {
+	var revisions []models.Revision
+	if err := r.db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("version DESC").
+		Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// FindVersion 查找实体的指定版本
+func (r *RevisionRepository) FindVersion(entityType string, entityID int64, version int) (*models.Revision, error) {
+	var revision models.Revision
+	if err := r.db.Where("entity_type = ? AND entity_id = ? AND version = ?", entityType, entityID, version).
+		First(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// RoleRepository 角色数据仓库
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository 创建角色仓库
+func NewRoleRepository() *RoleRepository {
+	return &RoleRepository{db: database.GetDB()}
+}
+
+// List 获取全部角色 (包含权限项)
+func (r *RoleRepository) List() ([]models.Role, error) {
+	var roles []models.Role
+	if err := r.db.Preload("Permissions").Order("id ASC").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// FindByID 根据ID查找角色 (包含权限项)
+func (r *RoleRepository) FindByID(id int64) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Preload("Permissions").First(&role, id).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// FindByCode 根据角色编码查找角色 (包含权限项)
+func (r *RoleRepository) FindByCode(code string) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Preload("Permissions").Where("code = ?", code).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// ExistsByCode 检查角色编码是否存在
+func (r *RoleRepository) ExistsByCode(code string) bool {
+	var count int64
+	r.db.Model(&models.Role{}).Where("code = ?", code).Count(&count)
+	return count > 0
+}
+
+// Create 创建角色 (同时写入权限项)
+func (r *RoleRepository) Create(role *models.Role) error {
+	return r.db.Create(role).Error
+}
+
+// Update 更新角色基本信息并整体替换权限项
+func (r *RoleRepository) Update(role *models.Role, permissions []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(role).Select("name", "description", "update_time").Updates(role).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", role.ID).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(permissions) == 0 {
+			return nil
+		}
+		items := make([]models.RolePermission, 0, len(permissions))
+		for _, p := range permissions {
+			items = append(items, models.RolePermission{RoleID: role.ID, Permission: p})
+		}
+		return tx.Create(&items).Error
+	})
+}
+
+// Delete 删除角色及其权限项
+func (r *RoleRepository) Delete(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Role{}, id).Error
+	})
+}
+
+// ListPermissions 获取角色编码对应的权限编码列表
+func (r *RoleRepository) ListPermissions(code string) ([]string, error) {
+	var permissions []string
+	err := r.db.Model(&models.RolePermission{}).
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.code = ?", code).
+		Pluck("role_permissions.permission", &permissions).Error
+	if err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// SessionRepository 登录会话数据仓库
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+// NewSessionRepository 创建登录会话仓库
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{db: database.GetDB()}
+}
+
+// Create 创建会话
+func (r *SessionRepository) Create(session *models.UserSession) error {
+	return r.db.Create(session).Error
+}
+
+// FindByID 根据ID查找会话
+func (r *SessionRepository) FindByID(id int64) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := r.db.First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindByRefreshHash 根据当前刷新令牌 Hash 查找会话
+func (r *SessionRepository) FindByRefreshHash(hash string) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := r.db.Where("refresh_token_hash = ?", hash).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindByPreviousHash 根据已轮换的刷新令牌 Hash 查找会话
+func (r *SessionRepository) FindByPreviousHash(hash string) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := r.db.Where("previous_token_hash = ?", hash).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActive 获取用户的有效会话 (未吊销且未过期)，按最后使用时间倒序
+func (r *SessionRepository) ListActive(userID int64) ([]models.UserSession, error) {
+	var sessions []models.UserSession
+	if err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Rotate 轮换刷新令牌
+// 以旧 Hash 作为条件更新，并发刷新时只有一个请求能成功。
+//
+// 返回:
+//   - bool: 是否轮换成功 (false 表示旧令牌已被其他请求轮换)
+//   - error: 数据库错误
+func (r *SessionRepository) Rotate(id int64, oldHash, newHash, userAgent, ip string, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&models.UserSession{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", id, oldHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  newHash,
+			"previous_token_hash": oldHash,
+			"user_agent":          userAgent,
+			"ip":                  ip,
+			"last_used_at":        time.Now(),
+			"expires_at":          expiresAt,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// Revoke 吊销指定会话 (userID 为 0 时不限定用户)
+func (r *SessionRepository) Revoke(id, userID int64) error {
+	query := r.db.Model(&models.UserSession{}).Where("id = ? AND revoked_at IS NULL", id)
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+	return query.Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAllByUser 吊销用户的全部会话 (exceptID 大于 0 时保留该会话)
+func (r *SessionRepository) RevokeAllByUser(userID, exceptID int64) error {
+	query := r.db.Model(&models.UserSession{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptID > 0 {
+		query = query.Where("id != ?", exceptID)
+	}
+	return query.Update("revoked_at", time.Now()).Error
+}
+
+// DeleteStale 清理用户在指定时间之前已过期或已吊销的会话
+func (r *SessionRepository) DeleteStale(userID int64, before time.Time) error {
+	return r.db.Where("user_id = ? AND (expires_at < ? OR revoked_at < ?)", userID, before, before).
+		Delete(&models.UserSession{}).Error
+}
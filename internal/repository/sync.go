@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SyncRepository 同步元数据仓库
+// 封装同步检查点 (sync_checkpoints) 与删除墓碑 (sync_tombstones) 的读写。
+type SyncRepository struct {
+	db *gorm.DB
+}
+
+// NewSyncRepository 创建同步元数据仓库
+func NewSyncRepository() *SyncRepository {
+	return &SyncRepository{db: database.GetDB()}
+}
+
+// WithTx 返回绑定到指定事务的仓库副本
+func (r *SyncRepository) WithTx(tx *gorm.DB) *SyncRepository {
+	return &SyncRepository{db: tx}
+}
+
+// FindCheckpoint 查找指定目标与表的同步检查点
+func (r *SyncRepository) FindCheckpoint(target, table string) (*models.SyncCheckpoint, error) {
+	var checkpoint models.SyncCheckpoint
+	if err := r.db.Where("target = ? AND table_name = ?", target, table).First(&checkpoint).Error; err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// SaveCheckpoint 保存同步检查点 (按 target + table_name 存在则更新)
+func (r *SyncRepository) SaveCheckpoint(checkpoint *models.SyncCheckpoint) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "target"}, {Name: "table_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_sync_time", "remote_sync_time", "synced_count", "update_time"}),
+	}).Create(checkpoint).Error
+}
+
+// ListCheckpoints 获取指定目标的全部检查点
+func (r *SyncRepository) ListCheckpoints(target string) ([]models.SyncCheckpoint, error) {
+	var checkpoints []models.SyncCheckpoint
+	if err := r.db.Where("target = ?", target).Order("table_name ASC").Find(&checkpoints).Error; err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
+
+// ListTombstoneIDs 获取指定表在某时间之后被删除的记录ID
+func (r *SyncRepository) ListTombstoneIDs(table string, since time.Time) ([]int64, error) {
+	var ids []int64
+	err := r.db.Model(&models.SyncTombstone{}).
+		Where("table_name = ? AND delete_time > ?", table, since).
+		Distinct().
+		Pluck("record_id", &ids).Error
+	return ids, err
+}
+
+// PurgeTombstones 清理已被所有同步目标消费或超出保留期的墓碑
+// 以该表所有检查点中最早的高水位为界，之前的墓碑不会再被使用；
+// 早于 cutoff 的墓碑无论是否被消费均清理，避免长期未同步的目标使墓碑无限累积。
+func (r *SyncRepository) PurgeTombstones(table string, cutoff time.Time) error {
+	boundary := cutoff
+	var oldest models.SyncCheckpoint
+	err := r.db.Where("table_name = ?", table).Order("last_sync_time ASC").First(&oldest).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	if err == nil && oldest.LastSyncTime.After(boundary) {
+		boundary = oldest.LastSyncTime
+	}
+	return r.db.Where("table_name = ? AND delete_time < ?", table, boundary).Delete(&models.SyncTombstone{}).Error
+}
+
+// DeleteTarget 删除指定目标的全部检查点与行基准版本
+// 用于同步目标不再使用时释放其对墓碑清理的占用；再次同步该目标时按首次同步处理。
+func (r *SyncRepository) DeleteTarget(target string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("target = ?", target).Delete(&models.SyncCheckpoint{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("target = ?", target).Delete(&models.SyncRowVersion{}).Error
+	})
+}
+
+// ListRowVersions 获取指定目标与表的行基准版本 (记录ID -> 内容哈希)
+func (r *SyncRepository) ListRowVersions(target, table string) (map[int64]string, error) {
+	var versions []models.SyncRowVersion
+	if err := r.db.Where("target = ? AND table_name = ?", target, table).Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[int64]string, len(versions))
+	for _, v := range versions {
+		result[v.RecordID] = v.Hash
+	}
+	return result, nil
+}
+
+// SaveRowVersions 批量保存行基准版本 (存在则更新)
+func (r *SyncRepository) SaveRowVersions(target, table string, versions map[int64]string) error {
+	if len(versions) == 0 {
+		return nil
+	}
+	rows := make([]models.SyncRowVersion, 0, len(versions))
+	for id, hash := range versions {
+		rows = append(rows, models.SyncRowVersion{Target: target, Table: table, RecordID: id, Hash: hash})
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "target"}, {Name: "table_name"}, {Name: "record_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"hash", "update_time"}),
+	}).CreateInBatches(&rows, 200).Error
+}
+
+// DeleteRowVersions 删除指定记录的行基准版本
+func (r *SyncRepository) DeleteRowVersions(target, table string, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Where("target = ? AND table_name = ? AND record_id IN ?", target, table, ids).
+		Delete(&models.SyncRowVersion{}).Error
+}
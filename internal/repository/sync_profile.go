@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// SyncProfileRepository 同步配置与同步历史仓库
+type SyncProfileRepository struct {
+	db *gorm.DB
+}
+
+// NewSyncProfileRepository 创建同步配置仓库
+func NewSyncProfileRepository() *SyncProfileRepository {
+	return &SyncProfileRepository{db: database.GetDB()}
+}
+
+// Create 创建同步配置
+func (r *SyncProfileRepository) Create(profile *models.SyncProfile) error {
+	return r.db.Create(profile).Error
+}
+
+// Update 更新同步配置
+func (r *SyncProfileRepository) Update(profile *models.SyncProfile) error {
+	return r.db.Save(profile).Error
+}
+
+// Delete 删除同步配置
+func (r *SyncProfileRepository) Delete(id int64) error {
+	return r.db.Delete(&models.SyncProfile{}, id).Error
+}
+
+// FindByID 根据ID查找同步配置
+func (r *SyncProfileRepository) FindByID(id int64) (*models.SyncProfile, error) {
+	var profile models.SyncProfile
+	if err := r.db.First(&profile, id).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// ExistsByName 检查配置名称是否已被其他配置使用
+func (r *SyncProfileRepository) ExistsByName(name string, excludeID int64) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.SyncProfile{}).Where("name = ? AND id <> ?", name, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+// List 获取全部同步配置
+func (r *SyncProfileRepository) List() ([]models.SyncProfile, error) {
+	var profiles []models.SyncProfile
+	if err := r.db.Order("id ASC").Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// ListDue 获取已到执行时间的定时同步配置
+func (r *SyncProfileRepository) ListDue(now time.Time) ([]models.SyncProfile, error) {
+	var profiles []models.SyncProfile
+	err := r.db.Where("enabled = 1 AND interval_minutes > 0 AND next_run_time IS NOT NULL AND next_run_time <= ?", now).
+		Order("next_run_time ASC").
+		Find(&profiles).Error
+	return profiles, err
+}
+
+// UpdateRunState 更新配置的执行状态 (上次执行时间/状态、下次执行时间)
+func (r *SyncProfileRepository) UpdateRunState(id int64, lastRunTime time.Time, lastStatus string, nextRunTime *time.Time) error {
+	return r.db.Model(&models.SyncProfile{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_run_time": lastRunTime,
+		"last_status":   lastStatus,
+		"next_run_time": nextRunTime,
+	}).Error
+}
+
+// CreateHistory 创建同步历史记录
+func (r *SyncProfileRepository) CreateHistory(history *models.SyncHistory) error {
+	return r.db.Create(history).Error
+}
+
+// UpdateHistory 更新同步历史记录
+func (r *SyncProfileRepository) UpdateHistory(history *models.SyncHistory) error {
+	return r.db.Save(history).Error
+}
+
+// FindHistoryByID 根据ID查找同步历史
+func (r *SyncProfileRepository) FindHistoryByID(id int64) (*models.SyncHistory, error) {
+	var history models.SyncHistory
+	if err := r.db.First(&history, id).Error; err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// ListHistory 分页获取同步历史 (profileID 为 0 时不过滤)
+func (r *SyncProfileRepository) ListHistory(profileID int64, offset, limit int) ([]models.SyncHistory, int64, error) {
+	var histories []models.SyncHistory
+	var total int64
+
+	query := r.db.Model(&models.SyncHistory{})
+	if profileID > 0 {
+		query = query.Where("profile_id = ?", profileID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("start_time DESC").Offset(offset).Limit(limit).Find(&histories).Error; err != nil {
+		return nil, 0, err
+	}
+	return histories, total, nil
+}
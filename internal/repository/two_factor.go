@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+)
+
+// TwoFactorRepository 两步验证数据仓库
+type TwoFactorRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorRepository 创建两步验证仓库
+func NewTwoFactorRepository() *TwoFactorRepository {
+	return &TwoFactorRepository{db: database.GetDB()}
+}
+
+// FindByUser 查找用户的两步验证配置
+func (r *TwoFactorRepository) FindByUser(userID int64) (*models.UserTwoFactor, error) {
+	var tf models.UserTwoFactor
+	if err := r.db.Where("user_id = ?", userID).First(&tf).Error; err != nil {
+		return nil, err
+	}
+	return &tf, nil
+}
+
+// IsEnabled 判断用户是否已启用两步验证
+func (r *TwoFactorRepository) IsEnabled(userID int64) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.UserTwoFactor{}).Where("user_id = ? AND enabled = 1", userID).Count(&count).Error
+	return count > 0, err
+}
+
+// SavePending 保存待验证的密钥 (覆盖尚未启用的旧密钥)
+func (r *TwoFactorRepository) SavePending(userID int64, secret string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserTwoFactor{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.UserTwoFactor{UserID: userID, Secret: secret}).Error
+	})
+}
+
+// Enable 启用两步验证并写入恢复码 (事务)
+//
+// 参数:
+//   - userID: 用户ID
+//   - step: 启用时使用的验证码时间步 (此后不可重复使用)
+//   - codeHashes: 恢复码 Hash 列表
+func (r *TwoFactorRepository) Enable(userID, step int64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&models.UserTwoFactor{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+			"enabled":        1,
+			"enabled_at":     now,
+			"last_used_step": step,
+		}).Error; err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// UseStep 记录已使用的验证码时间步
+// 仅当时间步大于上次记录时更新，返回 false 表示验证码已被使用过 (重放)。
+func (r *TwoFactorRepository) UseStep(userID, step int64) (bool, error) {
+	result := r.db.Model(&models.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Delete 删除用户的两步验证配置及全部恢复码 (事务)
+func (r *TwoFactorRepository) Delete(userID int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.UserTwoFactor{}).Error
+	})
+}
+
+// ReplaceRecoveryCodes 重新生成恢复码 (旧恢复码全部作废)
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(userID int64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// UseRecoveryCode 使用恢复码
+// 以条件更新保证每个恢复码只能使用一次，返回 false 表示恢复码不存在或已使用。
+func (r *TwoFactorRepository) UseRecoveryCode(userID int64, codeHash string) (bool, error) {
+	result := r.db.Model(&models.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountUnusedRecoveryCodes 统计用户剩余可用的恢复码数量
+func (r *TwoFactorRepository) CountUnusedRecoveryCodes(userID int64) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.UserRecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// replaceRecoveryCodes 删除用户的旧恢复码并写入新恢复码
+func replaceRecoveryCodes(tx *gorm.DB, userID int64, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]models.UserRecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, models.UserRecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
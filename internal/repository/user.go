@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/FruitsAI/Orange/internal/database"
 	"github.com/FruitsAI/Orange/internal/models"
 	"gorm.io/gorm"
@@ -56,9 +58,10 @@ func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
 }
 
 // ExistsByUsername 检查用户名是否存在
+// 回收站中的用户同样占用用户名 (唯一索引)，彻底删除后才可重新使用。
 func (r *UserRepository) ExistsByUsername(username string) bool {
 	var count int64
-	r.db.Model(&models.User{}).Where("username = ?", username).Count(&count)
+	r.db.Unscoped().Model(&models.User{}).Where("username = ?", username).Count(&count)
 	return count > 0
 }
 
@@ -110,7 +113,76 @@ func (r *UserRepository) List(page, pageSize int, keyword string) ([]models.User
 	return users, total, nil
 }
 
-// Delete 删除用户
+// CountByRole 统计各角色的用户数 (角色编码 -> 用户数)
+func (r *UserRepository) CountByRole() (map[string]int64, error) {
+	var rows []struct {
+		Role  string
+		Total int64
+	}
+	if err := r.db.Model(&models.User{}).Select("role, COUNT(*) AS total").Group("role").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Role] = row.Total
+	}
+	return counts, nil
+}
+
+// CountActiveByRole 统计指定角色下启用状态的用户数
+func (r *UserRepository) CountActiveByRole(role string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Where("role = ? AND status = ?", role, 1).Count(&count).Error
+	return count, err
+}
+
+// Delete 删除用户 (移入回收站)
 func (r *UserRepository) Delete(id int64) error {
 	return r.db.Delete(&models.User{}, id).Error
 }
+
+// FindDeleted 根据ID查找回收站中的用户
+func (r *UserRepository) FindDeleted(id int64) (*models.User, error) {
+	var user models.User
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ListDeleted 获取回收站中的用户 (按删除时间倒序)
+func (r *UserRepository) ListDeleted() ([]models.User, error) {
+	var users []models.User
+	if err := r.db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ListDeletedBefore 获取删除时间早于指定时间的用户ID (回收站自动清理)
+func (r *UserRepository) ListDeletedBefore(before time.Time) ([]int64, error) {
+	var ids []int64
+	err := r.db.Unscoped().Model(&models.User{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Restore 从回收站恢复用户
+// 恢复时刷新更新时间，增量同步会将其重新推送至云端。
+func (r *UserRepository) Restore(id int64) error {
+	return r.db.Unscoped().Model(&models.User{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+// Purge 彻底删除用户及其两步验证配置 (事务，不可恢复)
+func (r *UserRepository) Purge(id int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", id).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.UserTwoFactor{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&models.User{}, id).Error
+	})
+}
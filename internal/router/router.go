@@ -5,6 +5,7 @@ import (
 
 	"github.com/FruitsAI/Orange/internal/handler"
 	"github.com/FruitsAI/Orange/internal/middleware"
+	"github.com/FruitsAI/Orange/internal/pkg/permission"
 	"github.com/gin-gonic/gin"
 )
 
@@ -32,68 +33,196 @@ func NewRouter() *gin.Engine {
 			authHandler := handler.NewAuthHandler()
 			auth.POST("/login", authHandler.Login)       // 登录获取 Token
 			auth.POST("/register", authHandler.Register) // 用户注册
-			auth.POST("/logout", authHandler.Logout)     // 注销 (客户端清除)
+			auth.POST("/refresh", authHandler.Refresh)   // 刷新访问令牌 (轮换刷新令牌)
+			auth.POST("/logout", authHandler.Logout)     // 注销 (吊销当前会话)
+
+			auth.POST("/2fa/verify", authHandler.VerifyTwoFactor) // 两步验证登录 (提交验证码)
 		}
 
 		// 3.2 受保护路由 (需要 JWT 鉴权)
-		// 使用 JWTAuth 中间件验证 Authorization 头
+		// 使用 JWTAuth 中间件验证 Authorization 头，各路由通过 RequirePermission 声明所需权限，
+		// 各路由组通过 RequireScope 声明访问令牌所需的授权范围
 		authorized := v1.Group("")
 		authorized.Use(middleware.JWTAuth())
 		{
+			can := middleware.RequirePermission
+			scope := middleware.RequireScope
+
 			// 用户路由
-			users := authorized.Group("/users")
+			users := authorized.Group("/users", scope("users"))
 			{
 				authHandler := handler.NewAuthHandler()
 				userHandler := handler.NewUserHandler()
+				roleHandler := handler.NewRoleHandler()
+				twoFactorHandler := handler.NewTwoFactorHandler()
 
 				// 普通用户接口
 				users.GET("/me", authHandler.GetCurrentUser)
 				users.PUT("/me", authHandler.UpdateProfile)
 				users.PUT("/me/password", authHandler.ChangePassword)
+				users.GET("/me/sessions", authHandler.ListSessions)
+				users.DELETE("/me/sessions", authHandler.RevokeOtherSessions)
+				users.DELETE("/me/sessions/:id", authHandler.RevokeSession)
+				users.GET("/me/permissions", roleHandler.MyPermissions)
+				users.GET("/me/2fa", twoFactorHandler.Status)
+				users.POST("/me/2fa/setup", twoFactorHandler.Setup)
+				users.POST("/me/2fa/enable", twoFactorHandler.Enable)
+				users.POST("/me/2fa/disable", twoFactorHandler.Disable)
+				users.POST("/me/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
 
-				// 管理员接口 (内部已做权限校验)
-				users.GET("", userHandler.List)
-				users.POST("", userHandler.Create)
-				users.PUT("/:id", userHandler.Update)
-				users.DELETE("/:id", userHandler.Delete)
-				users.PUT("/:id/password", userHandler.ResetPassword)
+				// 用户管理接口
+				users.GET("", can(permission.UsersManage), userHandler.List)
+				users.POST("", can(permission.UsersManage), userHandler.Create)
+				users.PUT("/:id", can(permission.UsersManage), userHandler.Update)
+				users.DELETE("/:id", can(permission.UsersManage), userHandler.Delete)
+				users.PUT("/:id/password", can(permission.UsersManage), userHandler.ResetPassword)
+				users.POST("/:id/unlock", can(permission.UsersManage), userHandler.Unlock)
+				users.GET("/:id/lockouts", can(permission.UsersManage), userHandler.Lockouts)
+				users.PUT("/:id/role", can(permission.RolesManage), roleHandler.AssignUserRole)
+				users.DELETE("/:id/2fa", can(permission.UsersManage), twoFactorHandler.Reset)
+			}
+
+			// 角色与权限管理模块
+			roles := authorized.Group("/roles", scope("roles"), can(permission.RolesManage))
+			{
+				roleHandler := handler.NewRoleHandler()
+				roles.GET("", roleHandler.List)          // 角色列表
+				roles.POST("", roleHandler.Create)       // 创建角色
+				roles.PUT("/:id", roleHandler.Update)    // 更新角色及权限
+				roles.DELETE("/:id", roleHandler.Delete) // 删除角色
+
+				authorized.GET("/permissions", scope("roles"), can(permission.RolesManage), roleHandler.ListPermissions) // 权限定义列表
 			}
 
 			// 项目管理模块
-			projects := authorized.Group("/projects")
+			projects := authorized.Group("/projects", scope("projects"))
 			{
 				projectHandler := handler.NewProjectHandler()
-				projects.GET("", projectHandler.List) // 项目列表
+				projects.GET("", can(permission.ProjectsRead), projectHandler.List) // 项目列表
 
 				// 工具类接口：合同编号检查与生成
 				// 注意：这两个特定路径的路由必须放在 /:id 通配符之前，否则会被 /:id 优先匹配拦截
-				projects.GET("/check-contract-number", projectHandler.CheckContractNumber)
-				projects.GET("/generate-contract-number", projectHandler.GenerateContractNumber)
+				projects.GET("/check-contract-number", can(permission.ProjectsWrite), projectHandler.CheckContractNumber)
+				projects.GET("/generate-contract-number", can(permission.ProjectsWrite), projectHandler.GenerateContractNumber)
+
+				projects.GET("/:id", can(permission.ProjectsRead), projectHandler.Get)               // 项目详情
+				projects.POST("", can(permission.ProjectsWrite), projectHandler.Create)              // 创建项目
+				projects.PUT("/:id", can(permission.ProjectsWrite), projectHandler.Update)           // 更新项目
+				projects.DELETE("/:id", can(permission.ProjectsDelete), projectHandler.Delete)       // 删除项目
+				projects.POST("/:id/archive", can(permission.ProjectsWrite), projectHandler.Archive) // 归档项目
 
-				projects.GET("/:id", projectHandler.Get)              // 项目详情
-				projects.POST("", projectHandler.Create)              // 创建项目
-				projects.PUT("/:id", projectHandler.Update)           // 更新项目
-				projects.DELETE("/:id", projectHandler.Delete)        // 删除项目
-				projects.POST("/:id/archive", projectHandler.Archive) // 归档项目
+				// 项目成员 (共享)，退出共享 (移除自己) 仅需查看权限
+				projects.GET("/:id/members", can(permission.ProjectsRead), projectHandler.ListMembers)
+				projects.POST("/:id/members", can(permission.ProjectsWrite), projectHandler.AddMember)
+				projects.PUT("/:id/members/:user_id", can(permission.ProjectsWrite), projectHandler.UpdateMember)
+				projects.DELETE("/:id/members/:user_id", can(permission.ProjectsRead), projectHandler.RemoveMember)
+
+				// 项目历史版本
+				projects.GET("/:id/revisions", can(permission.ProjectsRead), projectHandler.ListRevisions)
+				projects.GET("/:id/revisions/diff", can(permission.ProjectsRead), projectHandler.DiffRevisions)
+				projects.POST("/:id/revisions/:version/restore", can(permission.ProjectsWrite), projectHandler.RestoreRevision)
 
 				// 项目收款
 				paymentHandler := handler.NewPaymentHandler()
-				projects.GET("/:id/payments", paymentHandler.GetByProject)
+				projects.GET("/:id/payments", scope("payments"), can(permission.PaymentsRead), paymentHandler.GetByProject)
+				projects.GET("/:id/payment-plan", scope("payments"), can(permission.PaymentsRead), paymentHandler.ValidatePlan)              // 校验收款计划
+				projects.POST("/:id/payment-plan/rebalance", scope("payments"), can(permission.PaymentsWrite), paymentHandler.RebalancePlan) // 按合同总金额调整收款计划
+
+				// 从模板创建项目 (同时生成款项，需同时拥有款项编辑权限)
+				templateHandler := handler.NewTemplateHandler()
+				projects.POST("/from-template", scope("payments"), can(permission.ProjectsWrite), can(permission.PaymentsWrite), templateHandler.CreateProject)
+
+				// 项目周期收款计划
+				scheduleHandler := handler.NewScheduleHandler()
+				projects.GET("/:id/payment-schedules", scope("payments"), can(permission.PaymentsRead), scheduleHandler.ListByProject)
+
+				// 合同变更单与合同金额时间线
+				changeOrderHandler := handler.NewChangeOrderHandler()
+				projects.GET("/:id/change-orders", can(permission.ProjectsRead), changeOrderHandler.ListByProject)
+				projects.GET("/:id/contract-timeline", can(permission.ProjectsRead), changeOrderHandler.Timeline)
 			}
 
 			// 款项管理模块
-			payments := authorized.Group("/payments")
+			payments := authorized.Group("/payments", scope("payments"))
 			{
 				paymentHandler := handler.NewPaymentHandler()
-				payments.GET("", paymentHandler.List)                 // 款项列表
-				payments.POST("", paymentHandler.Create)              // 创建款项
-				payments.PUT("/:id", paymentHandler.Update)           // 更新款项
-				payments.DELETE("/:id", paymentHandler.Delete)        // 删除款项
-				payments.POST("/:id/confirm", paymentHandler.Confirm) // 确认收款
+				payments.GET("", can(permission.PaymentsRead), paymentHandler.List)                    // 款项列表
+				payments.POST("", can(permission.PaymentsWrite), paymentHandler.Create)                // 创建款项
+				payments.PUT("/:id", can(permission.PaymentsWrite), paymentHandler.Update)             // 更新款项
+				payments.DELETE("/:id", can(permission.PaymentsWrite), paymentHandler.Delete)          // 删除款项
+				payments.POST("/:id/confirm", can(permission.PaymentsConfirm), paymentHandler.Confirm) // 确认收款 (登记收款记录)
+
+				// 收款记录
+				payments.GET("/:id/receipts", can(permission.PaymentsRead), paymentHandler.ListReceipts)
+				payments.DELETE("/:id/receipts/:receipt_id", can(permission.PaymentsConfirm), paymentHandler.DeleteReceipt)
+
+				// 退款记录 (退款与贷项通知)
+				payments.GET("/:id/refunds", can(permission.PaymentsRead), paymentHandler.ListRefunds)
+				payments.POST("/:id/refunds", can(permission.PaymentsConfirm), paymentHandler.Refund)
+				payments.DELETE("/:id/refunds/:refund_id", can(permission.PaymentsConfirm), paymentHandler.DeleteRefund)
+
+				// 款项历史版本
+				payments.GET("/:id/revisions", can(permission.PaymentsRead), paymentHandler.ListRevisions)
+				payments.GET("/:id/revisions/diff", can(permission.PaymentsRead), paymentHandler.DiffRevisions)
+				payments.POST("/:id/revisions/:version/restore", can(permission.PaymentsWrite), paymentHandler.RestoreRevision)
+			}
+
+			// 项目模板模块
+			// 所有可查看项目的用户均可读取模板，维护模板需 templates:manage 权限
+			templates := authorized.Group("/project-templates", scope("projects"))
+			{
+				templateHandler := handler.NewTemplateHandler()
+				templates.GET("", can(permission.ProjectsRead), templateHandler.List)             // 模板列表
+				templates.GET("/:id", can(permission.ProjectsRead), templateHandler.Get)          // 模板详情
+				templates.POST("", can(permission.TemplatesManage), templateHandler.Create)       // 创建模板
+				templates.PUT("/:id", can(permission.TemplatesManage), templateHandler.Update)    // 更新模板
+				templates.DELETE("/:id", can(permission.TemplatesManage), templateHandler.Delete) // 删除模板
+			}
+
+			// 周期收款计划模块
+			// 按计划自动生成款项，读写权限与款项一致
+			schedules := authorized.Group("/payment-schedules", scope("payments"), can(permission.PaymentsWrite))
+			{
+				scheduleHandler := handler.NewScheduleHandler()
+				schedules.POST("", scheduleHandler.Create)        // 创建计划
+				schedules.PUT("/:id", scheduleHandler.Update)     // 修改计划
+				schedules.POST("/:id/stop", scheduleHandler.Stop) // 停止计划
+			}
+
+			// 合同变更单模块
+			// 草稿可由项目所有者或编辑者维护，批准仅限项目所有者
+			changeOrders := authorized.Group("/change-orders", scope("projects"), can(permission.ProjectsWrite))
+			{
+				changeOrderHandler := handler.NewChangeOrderHandler()
+				changeOrders.POST("", changeOrderHandler.Create)              // 创建变更单
+				changeOrders.PUT("/:id", changeOrderHandler.Update)           // 修改变更单
+				changeOrders.DELETE("/:id", changeOrderHandler.Delete)        // 删除变更单
+				changeOrders.POST("/:id/approve", changeOrderHandler.Approve) // 批准变更单
+			}
+
+			// 回收站模块
+			// 删除的项目、款项与用户进入回收站，可恢复或彻底删除，所需权限与删除对应记录一致
+			trash := authorized.Group("/trash")
+			{
+				trashHandler := handler.NewTrashHandler()
+				trashProjects := trash.Group("/projects", scope("projects"), can(permission.ProjectsDelete))
+				trashProjects.GET("", trashHandler.ListProjects)                // 已删除项目列表
+				trashProjects.POST("/:id/restore", trashHandler.RestoreProject) // 恢复项目 (含随项目删除的款项)
+				trashProjects.DELETE("/:id", trashHandler.PurgeProject)         // 彻底删除项目
+
+				trashPayments := trash.Group("/payments", scope("payments"), can(permission.PaymentsWrite))
+				trashPayments.GET("", trashHandler.ListPayments)                // 已删除款项列表
+				trashPayments.POST("/:id/restore", trashHandler.RestorePayment) // 恢复款项
+				trashPayments.DELETE("/:id", trashHandler.PurgePayment)         // 彻底删除款项
+
+				trashUsers := trash.Group("/users", scope("users"), can(permission.UsersManage))
+				trashUsers.GET("", trashHandler.ListUsers)                // 已删除用户列表
+				trashUsers.POST("/:id/restore", trashHandler.RestoreUser) // 恢复用户
+				trashUsers.DELETE("/:id", trashHandler.PurgeUser)         // 彻底删除用户
 			}
 
 			// 仪表盘统计模块
-			dashboard := authorized.Group("/dashboard")
+			dashboard := authorized.Group("/dashboard", scope("dashboard"))
 			{
 				dashboardHandler := handler.NewDashboardHandler()
 				dashboard.GET("/stats", dashboardHandler.Stats)
@@ -103,55 +232,86 @@ func NewRouter() *gin.Engine {
 			}
 
 			// 字典管理模块 (用于下拉选项)
-			dictionaries := authorized.Group("/dictionaries")
+			dictionaries := authorized.Group("/dictionaries", scope("dictionaries"))
 			{
 				dictHandler := handler.NewDictionaryHandler()
-				dictionaries.GET("", dictHandler.List)                          // 字典类型列表
-				dictionaries.GET("/:code/items", dictHandler.GetItems)          // 获取指定字典的选项
-				dictionaries.POST("/:code/items", dictHandler.CreateItem)       // 新增选项
-				dictionaries.PUT("/:code/items/:id", dictHandler.UpdateItem)    // 更新选项
-				dictionaries.DELETE("/:code/items/:id", dictHandler.DeleteItem) // 删除选项
+				dictionaries.GET("", dictHandler.List)                                                              // 字典类型列表
+				dictionaries.GET("/:code/items", dictHandler.GetItems)                                              // 获取指定字典的选项
+				dictionaries.POST("/:code/items", can(permission.DictionariesManage), dictHandler.CreateItem)       // 新增选项
+				dictionaries.PUT("/:code/items/:id", can(permission.DictionariesManage), dictHandler.UpdateItem)    // 更新选项
+				dictionaries.DELETE("/:code/items/:id", can(permission.DictionariesManage), dictHandler.DeleteItem) // 删除选项
 			}
 
 			// 通知中心模块
-			notifications := authorized.Group("/notifications")
+			notifications := authorized.Group("/notifications", scope("notifications"))
 			{
 				notificationHandler := handler.NewNotificationHandler()
-				notifications.GET("", notificationHandler.List)                     // 通知列表
-				notifications.POST("", notificationHandler.Create)                  // 发送通知 (私信/广播)
-				notifications.GET("/:id", notificationHandler.Get)                  // 通知详情
-				notifications.PUT("/:id", notificationHandler.Update)               // 更新通知
-				notifications.GET("/unread-count", notificationHandler.UnreadCount) // 未读数
-				notifications.GET("/users", notificationHandler.ListUsers)          // 可通知用户列表
-				notifications.PUT("/:id/read", notificationHandler.MarkAsRead)      // 标记已读
-				notifications.DELETE("/:id", notificationHandler.Delete)            // 删除通知
+				notifications.GET("", notificationHandler.List)                                                 // 通知列表
+				notifications.POST("", can(permission.NotificationsManage), notificationHandler.Create)         // 发送通知 (私信/广播)
+				notifications.GET("/:id", notificationHandler.Get)                                              // 通知详情
+				notifications.PUT("/:id", can(permission.NotificationsManage), notificationHandler.Update)      // 更新通知
+				notifications.GET("/unread-count", notificationHandler.UnreadCount)                             // 未读数
+				notifications.GET("/users", can(permission.NotificationsManage), notificationHandler.ListUsers) // 可通知用户列表
+				notifications.PUT("/:id/read", notificationHandler.MarkAsRead)                                  // 标记已读
+				notifications.DELETE("/:id", can(permission.NotificationsManage), notificationHandler.Delete)   // 删除通知
 			}
 
 			// 个人访问令牌模块
-			tokens := authorized.Group("/tokens")
+			// 不提供 tokens 细分范围，访问令牌需完全访问范围才能签发或撤销令牌，避免以窄范围令牌签发更宽的令牌
+			tokens := authorized.Group("/tokens", scope("tokens"))
 			{
 				tokenHandler := handler.NewTokenHandler()
+				tokens.GET("/scopes", tokenHandler.Scopes)      // 可授予的授权范围
 				tokens.POST("", tokenHandler.Create)            // 创建令牌
 				tokens.GET("", tokenHandler.List)               // 令牌列表
 				tokens.POST("/:id/revoke", tokenHandler.Revoke) // 撤销令牌 (软删/禁用)
 				tokens.DELETE("/:id", tokenHandler.Delete)      // 删除令牌 (硬删)
 			}
 
+			// 审计日志模块
+			// 记录项目、款项、用户、字典及通知的全部数据变更，仅供拥有 audit:read 权限的角色查询
+			auditLogs := authorized.Group("/audit-logs", scope("audit"), can(permission.AuditRead))
+			{
+				auditHandler := handler.NewAuditHandler()
+				auditLogs.GET("", auditHandler.List) // 审计日志列表 (支持筛选)
+			}
+
 			// 系统级功能模块
-			system := authorized.Group("/system")
+			system := authorized.Group("/system", scope("system"))
 			{
 				systemHandler := handler.NewSystemHandler()
 				system.GET("/updates/check", systemHandler.CheckUpdate)
+
+				backupHandler := handler.NewBackupHandler()
+				system.GET("/backups", can(permission.SystemBackup), backupHandler.List)
+				system.POST("/backups", can(permission.SystemBackup), backupHandler.Create)
+				system.POST("/backups/:name/restore", can(permission.SystemBackup), backupHandler.Restore)
+				system.DELETE("/backups/:name", can(permission.SystemBackup), backupHandler.Delete)
 			}
 
 			// 数据同步模块
-			sync := authorized.Group("/sync")
+			sync := authorized.Group("/sync", scope("sync"))
 			{
 				syncHandler := handler.NewSyncHandler()
-				sync.GET("/config", syncHandler.GetConfig)                // 获取配置
-				sync.POST("/test-connection", syncHandler.TestConnection) // 测试云端数据库连接
-				sync.POST("/compare", syncHandler.Compare)                // 对比本地与云端数据
-				sync.POST("/execute", syncHandler.Execute)                // 执行数据同步
+				sync.GET("/config", can(permission.SyncManage), syncHandler.GetConfig)                 // 获取配置
+				sync.POST("/test-connection", can(permission.SyncExecute), syncHandler.TestConnection) // 测试云端数据库连接
+				sync.POST("/compare", can(permission.SyncExecute), syncHandler.Compare)                // 对比本地与云端数据
+				sync.POST("/schema", can(permission.SyncExecute), syncHandler.Schema)                  // 创建/迁移云端表结构 (支持预览)
+				sync.POST("/execute", can(permission.SyncExecute), syncHandler.Execute)                // 执行数据同步
+				sync.POST("/resolve", can(permission.SyncExecute), syncHandler.Resolve)                // 处理同步冲突
+				sync.POST("/export", can(permission.SyncManage), syncHandler.Export)                   // 导出离线文件 (SQLite/JSON 数据包)
+				sync.POST("/import", can(permission.SyncManage), syncHandler.Import)                   // 导入离线文件
+
+				// 同步配置与历史
+				profileHandler := handler.NewSyncProfileHandler()
+				sync.GET("/profiles", can(permission.SyncManage), profileHandler.List)          // 同步配置列表
+				sync.POST("/profiles", can(permission.SyncManage), profileHandler.Create)       // 创建同步配置
+				sync.GET("/profiles/:id", can(permission.SyncManage), profileHandler.Get)       // 同步配置详情
+				sync.PUT("/profiles/:id", can(permission.SyncManage), profileHandler.Update)    // 更新同步配置
+				sync.DELETE("/profiles/:id", can(permission.SyncManage), profileHandler.Delete) // 删除同步配置
+				sync.POST("/profiles/:id/run", can(permission.SyncExecute), profileHandler.Run) // 立即执行同步配置
+				sync.GET("/history", can(permission.SyncManage), profileHandler.ListHistory)    // 同步历史列表
+				sync.GET("/history/:id", can(permission.SyncManage), profileHandler.GetHistory) // 同步历史详情
 			}
 		}
 	}
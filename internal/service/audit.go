@@ -0,0 +1,121 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
+	"github.com/FruitsAI/Orange/internal/repository"
+)
+
+// systemActor 后台任务 (回收站自动清理、周期款项生成等) 的操作人
+var systemActor = audit.Actor{Username: "system", Source: audit.SourceSystem}
+
+// AuditService 审计日志服务
+// 业务服务在数据变更成功后调用 Record 写入审计日志；管理员通过 List 按条件查询。
+//
+// 依赖:
+//   - AuditRepository: 审计日志数据操作
+type AuditService struct {
+	auditRepo *repository.AuditRepository
+}
+
+// NewAuditService 创建审计日志服务实例
+func NewAuditService() *AuditService {
+	return &AuditService{
+		auditRepo: repository.NewAuditRepository(),
+	}
+}
+
+// Record 记录一次数据变更
+// 审计日志写入失败只记录系统日志，不影响已完成的业务操作。
+//
+// 参数:
+//   - actor: 操作人
+//   - action: 操作类型 (audit.Action*)
+//   - entityType: 实体类型 (audit.Entity*)
+//   - entityID: 实体ID
+//   - before: 变更前的数据 (创建时为 nil)
+//   - after: 变更后的数据 (删除时为 nil)
+func (s *AuditService) Record(actor audit.Actor, action, entityType string, entityID int64, before, after interface{}) {
+	log := &models.AuditLog{
+		ActorID:       actor.UserID,
+		ActorName:     actor.Username,
+		Source:        actor.Source,
+		AccessTokenID: actor.AccessTokenID,
+		IP:            actor.IP,
+		Action:        action,
+		EntityType:    entityType,
+		EntityID:      entityID,
+		Before:        snapshot(before),
+		After:         snapshot(after),
+	}
+	if err := s.auditRepo.Create(log); err != nil {
+		slog.Error("写入审计日志失败", "action", action, "entity_type", entityType, "entity_id", entityID, "error", err)
+	}
+}
+
+// List 分页查询审计日志
+func (s *AuditService) List(query dto.AuditLogQuery) (*dto.AuditLogListResult, error) {
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 || query.PageSize > 100 {
+		query.PageSize = 20
+	}
+
+	filter := repository.AuditLogFilter{
+		ActorID:    query.ActorID,
+		Action:     query.Action,
+		EntityType: query.EntityType,
+		EntityID:   query.EntityID,
+		Source:     query.Source,
+	}
+	if query.StartDate != "" {
+		start, err := time.ParseInLocation("2006-01-02", query.StartDate, time.Local)
+		if err != nil {
+			return nil, errors.New("开始日期格式错误")
+		}
+		filter.Start = &start
+	}
+	if query.EndDate != "" {
+		end, err := time.ParseInLocation("2006-01-02", query.EndDate, time.Local)
+		if err != nil {
+			return nil, errors.New("结束日期格式错误")
+		}
+		end = end.AddDate(0, 0, 1)
+		filter.End = &end
+	}
+
+	logs, total, err := s.auditRepo.List(filter, query.Page, query.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AuditLogListResult{
+		List:     logs,
+		Total:    total,
+		Page:     query.Page,
+		PageSize: query.PageSize,
+	}, nil
+}
+
+// snapshot 将实体序列化为 JSON 快照 (nil 返回空字符串)
+// 敏感字段 (如密码 Hash) 在模型上已标记 json:"-"，不会写入审计日志。
+func snapshot(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.Warn("审计快照序列化失败", "error", err)
+		return ""
+	}
+	if string(data) == "null" {
+		return ""
+	}
+	return string(data)
+}
@@ -6,8 +6,9 @@ import (
 
 	"github.com/FruitsAI/Orange/internal/dto"
 	"github.com/FruitsAI/Orange/internal/models"
-	"github.com/FruitsAI/Orange/internal/pkg/jwt"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
 	"github.com/FruitsAI/Orange/internal/pkg/password"
+	"github.com/FruitsAI/Orange/internal/pkg/permission"
 	"github.com/FruitsAI/Orange/internal/repository"
 )
 
@@ -16,8 +17,18 @@ import (
 //
 // 依赖:
 //   - UserRepository: 用户数据操作接口
+//   - RoleService: 角色校验 (创建/更新用户时)
+//   - SessionService: 登录会话 (登录签发令牌，修改密码时吊销会话)
+//   - TwoFactorService: 两步验证 (登录第二步校验验证码)
+//   - LoginProtectionService: 登录失败退避与账户锁定
+//   - AuditService: 记录用户数据变更审计日志
 type AuthService struct {
-	userRepo *repository.UserRepository
+	userRepo         *repository.UserRepository
+	roleService      *RoleService
+	sessionService   *SessionService
+	twoFactorService *TwoFactorService
+	loginProtection  *LoginProtectionService
+	auditService     *AuditService
 }
 
 // NewAuthService 创建认证服务实例
@@ -26,62 +37,139 @@ type AuthService struct {
 //   - *AuthService: 初始化的服务实例
 func NewAuthService() *AuthService {
 	return &AuthService{
-		userRepo: repository.NewUserRepository(),
+		userRepo:         repository.NewUserRepository(),
+		roleService:      NewRoleService(),
+		sessionService:   NewSessionService(),
+		twoFactorService: NewTwoFactorService(),
+		loginProtection:  NewLoginProtectionService(),
+		auditService:     NewAuditService(),
 	}
 }
 
 // Login 用户登录
-// 验证用户名和密码，成功后颁发 JWT Token 并更新最后登录时间。
+// 验证用户名和密码，成功后创建登录会话，颁发访问令牌与刷新令牌并更新最后登录时间。
+// 用户已启用两步验证时，仅返回质询令牌，需调用 VerifyTwoFactor 完成登录。
+// 连续失败时按账户与来源 IP 退避，账户失败次数过多时临时锁定。
 //
 // 参数:
 //   - username: 用户名
 //   - pwd: 密码 (明文)
+//   - userAgent: 客户端 User-Agent (记录于会话，便于用户识别设备)
+//   - ip: 客户端 IP
 //
 // 返回:
 //   - *dto.LoginResult: 包含 Token 和用户信息的结构体
-//   - error: 认证失败（用户名/密码错误或账户被禁用）
-func (s *AuthService) Login(username, pwd string) (*dto.LoginResult, error) {
+//   - error: 认证失败（用户名/密码错误、账户被禁用或被锁定）
+func (s *AuthService) Login(username, pwd, userAgent, ip string) (*dto.LoginResult, error) {
 	// 1. 查找用户
+	// 登录名不存在时同样计数，避免通过锁定行为探测账户是否存在
 	user, err := s.userRepo.FindByCredential(username)
-	if err != nil {
-		return nil, errors.New("用户名或密码错误")
+	var userID int64
+	accountKey := repository.NameThrottleKey(username)
+	if err == nil {
+		userID = user.ID
+		accountKey = repository.UserThrottleKey(user.ID)
+	}
+
+	// 2. 检查失败退避与账户锁定
+	if err := s.loginProtection.Check(accountKey, ip); err != nil {
+		return nil, err
 	}
 
-	// 2. 验证密码 (比对哈希)
-	if !password.CheckPassword(pwd, user.Password) {
+	// 3. 验证密码 (比对哈希)
+	if user == nil || !password.CheckPassword(pwd, user.Password) {
+		if err := s.loginProtection.RecordFailure(accountKey, userID, username, ip); err != nil {
+			return nil, err
+		}
 		return nil, errors.New("用户名或密码错误")
 	}
 
-	// 3. 检查账户状态
+	// 4. 检查账户状态
+	if user.Status != 1 {
+		return nil, errors.New("账户已被禁用")
+	}
+
+	// 5. 已启用两步验证的用户返回质询令牌，等待提交验证码 (验证码通过后才清零失败计数)
+	enabled, err := s.twoFactorService.IsEnabled(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return s.twoFactorService.Challenge(user)
+	}
+
+	return s.completeLogin(user, userAgent, ip)
+}
+
+// VerifyTwoFactor 两步验证登录 (第二步)
+// 校验质询令牌与验证码 (或恢复码)，通过后创建登录会话。
+//
+// 参数:
+//   - challengeToken: 登录接口返回的质询令牌
+//   - code: 验证码或恢复码
+//   - userAgent: 客户端 User-Agent
+//   - ip: 客户端 IP
+func (s *AuthService) VerifyTwoFactor(challengeToken, code, userAgent, ip string) (*dto.LoginResult, error) {
+	userID, err := s.twoFactorService.VerifyChallenge(challengeToken, code)
+	if errors.Is(err, ErrTwoFactorCodeWrong) || errors.Is(err, ErrChallengeExhausted) {
+		// 验证码错误同样计入账户登录失败
+		if err := s.loginProtection.RecordFailure(repository.UserThrottleKey(userID), userID, "", ip); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 质询有效期内账户可能已因连续失败被锁定
+	if err := s.loginProtection.Check(repository.UserThrottleKey(userID), ""); err != nil {
+		return nil, err
+	}
+
+	// 质询有效期内账户可能已被禁用或删除
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrChallengeInvalid
+	}
 	if user.Status != 1 {
 		return nil, errors.New("账户已被禁用")
 	}
 
-	// 4. 生成 JWT Token
-	// Payload 包含: ID, Username, Role
-	token, err := jwt.GenerateToken(user.ID, user.Username, user.Role)
+	return s.completeLogin(user, userAgent, ip)
+}
+
+// completeLogin 创建登录会话、生成令牌、清零失败计数并更新最后登录时间
+func (s *AuthService) completeLogin(user *models.User, userAgent, ip string) (*dto.LoginResult, error) {
+	if err := s.loginProtection.Reset(repository.UserThrottleKey(user.ID)); err != nil {
+		return nil, err
+	}
+
+	// 访问令牌 Payload 包含: ID, Username, Role, SessionID
+	result, err := s.sessionService.Create(user, userAgent, ip)
 	if err != nil {
-		return nil, errors.New("生成Token失败")
+		return nil, err
 	}
 
-	// 5. 异步更新最后登录时间 (非关键路径，暂同步执行，可优化)
+	// 异步更新最后登录时间 (非关键路径，暂同步执行，可优化)
 	now := time.Now()
 	s.userRepo.UpdateFields(user.ID, map[string]interface{}{
 		"last_login_time": now,
 	})
 
-	return &dto.LoginResult{Token: token, User: user}, nil
+	return result, nil
 }
 
 // Register 用户注册
 // 创建新用户账号，检查用户名和邮箱唯一性，并对密码进行加密存储。
 //
 // 参数:
+//   - actor: 操作人 (未登录，来源为 anonymous)
 //   - input: 注册请求DTO
 //
 // 返回:
 //   - error: 注册失败（如信息已存在或加密失败）
-func (s *AuthService) Register(input dto.RegisterRequest) error {
+func (s *AuthService) Register(actor audit.Actor, input dto.RegisterRequest) error {
 	// 1. 唯一性检查
 	if s.userRepo.ExistsByUsername(input.Username) {
 		return errors.New("用户名已被注册")
@@ -103,12 +191,16 @@ func (s *AuthService) Register(input dto.RegisterRequest) error {
 		Email:    input.Email,
 		Phone:    input.Phone,
 		Password: hashedPassword,
-		Role:     "user", // 默认为普通用户
-		Status:   1,      // 默认启用
+		Role:     permission.RoleUser, // 默认为普通用户
+		Status:   1,                   // 默认启用
 	}
 
 	// 4. 保存至数据库
-	return s.userRepo.Create(user)
+	if err := s.userRepo.Create(user); err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionRegister, audit.EntityUser, user.ID, nil, user)
+	return nil
 }
 
 // GetCurrentUser 获取当前登录用户详情
@@ -120,13 +212,19 @@ func (s *AuthService) GetCurrentUser(userID int64) (*models.User, error) {
 // 支持部分更新（Name, Email, Phone, Department, Position）。
 //
 // 参数:
-//   - userID: 用户ID
+//   - actor: 操作人 (即被更新的用户本人)
 //   - name, email...: 待更新字段，为空则不更新
 //
 // 返回:
 //   - *models.User: 更新后的用户实体
 //   - error: 数据库错误
-func (s *AuthService) UpdateProfile(userID int64, name, email, phone, department, position string) (*models.User, error) {
+func (s *AuthService) UpdateProfile(actor audit.Actor, name, email, phone, department, position string) (*models.User, error) {
+	userID := actor.UserID
+	before, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
 	updates := map[string]interface{}{}
 
 	if name != "" {
@@ -145,26 +243,33 @@ func (s *AuthService) UpdateProfile(userID int64, name, email, phone, department
 		updates["position"] = position
 	}
 
-	if len(updates) > 0 {
-		if err := s.userRepo.UpdateFields(userID, updates); err != nil {
-			return nil, err
-		}
+	if len(updates) == 0 {
+		return before, nil
+	}
+	if err := s.userRepo.UpdateFields(userID, updates); err != nil {
+		return nil, err
 	}
 
-	return s.userRepo.FindByID(userID)
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.Record(actor, audit.ActionUpdate, audit.EntityUser, userID, before, user)
+	return user, nil
 }
 
 // ChangePassword 修改密码
-// 验证旧密码正确性后，更新为新密码（加密存储）。
+// 验证旧密码正确性后，更新为新密码（加密存储），并吊销该用户的全部登录会话。
 //
 // 参数:
-//   - userID: 用户ID
+//   - actor: 操作人 (即修改密码的用户本人)
 //   - oldPassword: 旧密码
 //   - newPassword: 新密码
 //
 // 返回:
 //   - error: 验证失败或更新错误
-func (s *AuthService) ChangePassword(userID int64, oldPassword, newPassword string) error {
+func (s *AuthService) ChangePassword(actor audit.Actor, oldPassword, newPassword string) error {
+	userID := actor.UserID
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
 		return errors.New("用户不存在")
@@ -182,9 +287,16 @@ func (s *AuthService) ChangePassword(userID int64, oldPassword, newPassword stri
 	}
 
 	// 3. 更新数据库
-	return s.userRepo.UpdateFields(userID, map[string]interface{}{
+	if err := s.userRepo.UpdateFields(userID, map[string]interface{}{
 		"password": hashedPassword,
-	})
+	}); err != nil {
+		return err
+	}
+	// 密码 Hash 不写入审计日志，仅记录操作本身
+	s.auditService.Record(actor, audit.ActionChangePassword, audit.EntityUser, userID, nil, nil)
+
+	// 4. 吊销全部会话 (包括当前会话)，所有设备需使用新密码重新登录
+	return s.sessionService.RevokeAll(userID)
 }
 
 // ListUsers 获取用户列表 (管理员)
@@ -193,6 +305,9 @@ func (s *AuthService) ListUsers(page, pageSize int, keyword string) (*dto.UserPa
 	if err != nil {
 		return nil, err
 	}
+	if err := s.loginProtection.FillLockStatus(users); err != nil {
+		return nil, err
+	}
 	return &dto.UserPageResult{
 		List:  users,
 		Total: total,
@@ -200,7 +315,7 @@ func (s *AuthService) ListUsers(page, pageSize int, keyword string) (*dto.UserPa
 }
 
 // CreateUser 创建用户 (管理员)
-func (s *AuthService) CreateUser(input dto.CreateUserRequest) error {
+func (s *AuthService) CreateUser(actor audit.Actor, input dto.CreateUserRequest) error {
 	if s.userRepo.ExistsByUsername(input.Username) {
 		return errors.New("用户名已被注册")
 	}
@@ -214,8 +329,11 @@ func (s *AuthService) CreateUser(input dto.CreateUserRequest) error {
 	}
 
 	role := input.Role
-	if role != "admin" {
-		role = "user"
+	if role == "" {
+		role = permission.RoleUser
+	}
+	if !s.roleService.roleRepo.ExistsByCode(role) {
+		return errors.New("角色不存在")
 	}
 
 	user := &models.User{
@@ -228,11 +346,31 @@ func (s *AuthService) CreateUser(input dto.CreateUserRequest) error {
 		Status:   1,
 	}
 
-	return s.userRepo.Create(user)
+	if err := s.userRepo.Create(user); err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionCreate, audit.EntityUser, user.ID, nil, user)
+	return nil
 }
 
 // UpdateUser 更新用户 (管理员)
-func (s *AuthService) UpdateUser(id int64, input dto.UpdateUserRequest) error {
+func (s *AuthService) UpdateUser(actor audit.Actor, id int64, input dto.UpdateUserRequest) error {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return errors.New("用户不存在")
+	}
+
+	role := user.Role
+	if input.Role != "" {
+		if !s.roleService.roleRepo.ExistsByCode(input.Role) {
+			return errors.New("角色不存在")
+		}
+		role = input.Role
+	}
+	if err := s.roleService.ensureAdminRemains(user, role, input.Status); err != nil {
+		return err
+	}
+
 	updates := map[string]interface{}{}
 	if input.Name != "" {
 		updates["name"] = input.Name
@@ -258,23 +396,71 @@ func (s *AuthService) UpdateUser(id int64, input dto.UpdateUserRequest) error {
 	// DTO status is int.
 	updates["status"] = input.Status
 
-	return s.userRepo.UpdateFields(id, updates)
+	if err := s.userRepo.UpdateFields(id, updates); err != nil {
+		return err
+	}
+	if after, err := s.userRepo.FindByID(id); err == nil {
+		s.auditService.Record(actor, audit.ActionUpdate, audit.EntityUser, id, user, after)
+	}
+	// 禁用用户时立即吊销其登录会话
+	if input.Status != 1 {
+		return s.sessionService.RevokeAll(id)
+	}
+	return nil
 }
 
-// DeleteUser 删除用户 (管理员)
-func (s *AuthService) DeleteUser(id int64) error {
+// DeleteUser 删除用户 (管理员，移入回收站)
+// 吊销该用户的全部登录会话；两步验证配置保留至彻底删除，恢复后无需重新绑定。
+func (s *AuthService) DeleteUser(actor audit.Actor, id int64) error {
 	// Optional: Check if admin is deleting themselves?
 	// Handler layer might handle "cannot delete self" logic or here.
-	return s.userRepo.Delete(id)
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return errors.New("用户不存在")
+	}
+	if err := s.roleService.ensureAdminRemains(user, "", 0); err != nil {
+		return err
+	}
+	if err := s.userRepo.Delete(id); err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionDelete, audit.EntityUser, id, user, nil)
+	return s.sessionService.RevokeAll(id)
 }
 
 // ResetPassword 重置用户密码 (管理员)
-func (s *AuthService) ResetPassword(id int64, newPassword string) error {
+// 重置后吊销该用户的全部登录会话。
+func (s *AuthService) ResetPassword(actor audit.Actor, id int64, newPassword string) error {
 	hashedPassword, err := password.HashPassword(newPassword)
 	if err != nil {
 		return errors.New("密码加密失败")
 	}
-	return s.userRepo.UpdateFields(id, map[string]interface{}{
+	if err := s.userRepo.UpdateFields(id, map[string]interface{}{
 		"password": hashedPassword,
-	})
+	}); err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionResetPassword, audit.EntityUser, id, nil, nil)
+	return s.sessionService.RevokeAll(id)
+}
+
+// UnlockUser 解除用户的登录锁定 (管理员)
+// 清零该账户的连续失败计数，并在锁定记录中标注解锁时间与操作人。
+func (s *AuthService) UnlockUser(actor audit.Actor, id int64) error {
+	if _, err := s.userRepo.FindByID(id); err != nil {
+		return errors.New("用户不存在")
+	}
+	if err := s.loginProtection.Unlock(id, actor.UserID); err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionUnlock, audit.EntityUser, id, nil, nil)
+	return nil
+}
+
+// ListLockouts 获取用户最近的登录锁定记录 (管理员)
+func (s *AuthService) ListLockouts(id int64) ([]models.LoginLockout, error) {
+	if _, err := s.userRepo.FindByID(id); err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	return s.loginProtection.Lockouts(id)
 }
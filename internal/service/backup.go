@@ -0,0 +1,600 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 备份文件格式
+const (
+	BackupFormatSQLite = "sqlite" // SQLite 快照 (VACUUM INTO)
+	BackupFormatDump   = "dump"   // gzip 压缩的 JSON 逻辑转储 (MySQL/PostgreSQL)
+)
+
+// 备份文件标识
+const (
+	backupPrefix      = "orange-"
+	backupSQLiteExt   = ".db"
+	backupDumpExt     = ".json.gz"
+	backupDumpFormat  = "orange-backup"
+	backupDumpVersion = 2
+	backupBatchSize   = 500
+)
+
+// BackupInfo 备份文件信息
+type BackupInfo struct {
+	Name       string    `json:"name"`        // 文件名
+	Format     string    `json:"format"`      // 格式: sqlite, dump
+	Size       int64     `json:"size"`        // 文件大小 (字节)
+	CreateTime time.Time `json:"create_time"` // 创建时间
+}
+
+// backupDump 逻辑转储文件结构
+type backupDump struct {
+	Format        string        `json:"format"`         // 固定为 orange-backup
+	Version       int           `json:"version"`        // 格式版本
+	SchemaVersion int64         `json:"schema_version"` // 数据库迁移版本 (格式版本 1 未记录，从迁移版本表读取)
+	DBType        string        `json:"db_type"`        // 来源数据库类型
+	CreatedAt     time.Time     `json:"created_at"`     // 备份时间
+	Tables        []bundleTable `json:"tables"`         // 全部表数据
+}
+
+// BackupService 本地数据库备份服务
+// 备份文件保存在配置的备份目录 (默认位于数据库文件同级)，按数量轮转。
+type BackupService struct{}
+
+// NewBackupService 创建备份服务实例
+func NewBackupService() *BackupService {
+	return &BackupService{}
+}
+
+// List 获取备份列表 (按创建时间倒序)
+func (s *BackupService) List() ([]BackupInfo, error) {
+	entries, err := os.ReadDir(config.AppConfig.BackupDir)
+	if os.IsNotExist(err) {
+		return []BackupInfo{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取备份目录失败: %w", err)
+	}
+
+	backups := make([]BackupInfo, 0, len(entries))
+	for _, entry := range entries {
+		format := backupFormat(entry.Name())
+		if entry.IsDir() || format == "" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, BackupInfo{
+			Name:       entry.Name(),
+			Format:     format,
+			Size:       info.Size(),
+			CreateTime: info.ModTime(),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].CreateTime.Equal(backups[j].CreateTime) {
+			return backups[i].Name > backups[j].Name
+		}
+		return backups[i].CreateTime.After(backups[j].CreateTime)
+	})
+	return backups, nil
+}
+
+// Create 创建一份在线一致性备份，并按保留数量清理旧备份
+func (s *BackupService) Create() (*BackupInfo, error) {
+	return s.create("")
+}
+
+// Restore 校验并恢复指定备份
+// 恢复前会自动创建一份当前数据的备份，恢复在单个事务中完成，失败时数据保持不变。
+// 返回恢复前自动创建的备份信息。
+func (s *BackupService) Restore(name string) (*BackupInfo, error) {
+	path, err := s.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 读取并校验备份内容
+	var tables []bundleTable
+	if backupFormat(name) == BackupFormatSQLite {
+		tables, err = readSQLiteBackup(path)
+	} else {
+		tables, err = readDumpBackup(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := validateBackupTables(tables); err != nil {
+		return nil, err
+	}
+
+	// 恢复期间暂停同步任务，避免同步读写到恢复中途的数据
+	syncRunMu.Lock()
+	defer syncRunMu.Unlock()
+
+	// 2. 恢复前保存当前数据
+	safety, err := s.create("pre-restore")
+	if err != nil {
+		return nil, fmt.Errorf("创建恢复前备份失败: %w", err)
+	}
+
+	// 3. 在事务中恢复
+	if err := s.restoreTables(tables); err != nil {
+		return safety, err
+	}
+	return safety, nil
+}
+
+// Delete 删除指定备份
+func (s *BackupService) Delete(name string) error {
+	path, err := s.resolve(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// create 生成备份文件，suffix 用于标记备份来源 (如恢复前自动备份)
+func (s *BackupService) create(suffix string) (*BackupInfo, error) {
+	dir := config.AppConfig.BackupDir
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建备份目录失败: %w", err)
+	}
+
+	now := time.Now()
+	base := fmt.Sprintf("%s%s-%03d", backupPrefix, now.Format("20060102-150405"), now.Nanosecond()/int(time.Millisecond))
+	if suffix != "" {
+		base += "-" + suffix
+	}
+
+	var name string
+	var err error
+	if database.GetDBType() == "sqlite" {
+		name = base + backupSQLiteExt
+		err = s.snapshotSQLite(filepath.Join(dir, name))
+	} else {
+		name = base + backupDumpExt
+		err = s.dumpDatabase(filepath.Join(dir, name), now)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.rotate(); err != nil {
+		return nil, fmt.Errorf("清理旧备份失败: %w", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	return &BackupInfo{Name: name, Format: backupFormat(name), Size: info.Size(), CreateTime: info.ModTime()}, nil
+}
+
+// snapshotSQLite 使用 VACUUM INTO 生成 SQLite 在线快照
+// VACUUM INTO 在读事务中执行，得到的文件是一致的且已整理碎片。
+func (s *BackupService) snapshotSQLite(path string) error {
+	tmpPath := path + ".tmp"
+	os.Remove(tmpPath) // VACUUM INTO 要求目标文件不存在
+
+	if err := database.GetDB().Exec("VACUUM INTO ?", tmpPath).Error; err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("创建快照失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("保存备份失败: %w", err)
+	}
+	return nil
+}
+
+// dumpDatabase 在只读可重复读事务中逻辑转储全部表 (MySQL/PostgreSQL)
+func (s *BackupService) dumpDatabase(path string, now time.Time) error {
+	dump := backupDump{
+		Format:    backupDumpFormat,
+		Version:   backupDumpVersion,
+		DBType:    database.GetDBType(),
+		CreatedAt: now,
+	}
+
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		version, err := database.SchemaVersion(tx)
+		if err != nil {
+			return fmt.Errorf("读取数据库版本失败: %w", err)
+		}
+		dump.SchemaVersion = version
+
+		tables, err := tx.Migrator().GetTables()
+		if err != nil {
+			return fmt.Errorf("获取表列表失败: %w", err)
+		}
+		sort.Strings(tables)
+
+		for _, table := range tables {
+			rows, err := tx.Raw("SELECT * FROM ?", clause.Table{Name: table}).Rows()
+			if err != nil {
+				return fmt.Errorf("读取表 %s 失败: %w", table, err)
+			}
+			data, err := scanTableRows(rows)
+			if err != nil {
+				return fmt.Errorf("读取表 %s 失败: %w", table, err)
+			}
+			data.Name = table
+			dump.Tables = append(dump.Tables, data)
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := writeGzipJSON(tmpPath, &dump); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("写入备份失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("保存备份失败: %w", err)
+	}
+	return nil
+}
+
+// rotate 仅保留最新的 BackupKeep 份备份
+func (s *BackupService) rotate() error {
+	keep := config.AppConfig.BackupKeep
+	if keep <= 0 {
+		return nil
+	}
+	backups, err := s.List()
+	if err != nil {
+		return err
+	}
+	for i := keep; i < len(backups); i++ {
+		if err := os.Remove(filepath.Join(config.AppConfig.BackupDir, backups[i].Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve 校验备份文件名并返回完整路径
+// 只接受备份目录下由本服务生成的文件名，防止路径穿越。
+func (s *BackupService) resolve(name string) (string, error) {
+	if name == "" || filepath.Base(name) != name || !strings.HasPrefix(name, backupPrefix) || backupFormat(name) == "" {
+		return "", errors.New("无效的备份文件名")
+	}
+	path := filepath.Join(config.AppConfig.BackupDir, name)
+	if _, err := os.Stat(path); err != nil {
+		return "", errors.New("备份不存在")
+	}
+	return path, nil
+}
+
+// restoreTables 在单个事务中用备份数据替换当前数据
+// 先按依赖逆序清空当前库的全部数据表 (含备份中没有的表，避免残留记录引用被替换的项目与款项)，
+// 再按依赖顺序写入备份与当前库共有的表与列 (可恢复旧版本的备份)。
+func (s *BackupService) restoreTables(tables []bundleTable) error {
+	db := database.GetDB()
+	current, err := db.Migrator().GetTables()
+	if err != nil {
+		return fmt.Errorf("获取表列表失败: %w", err)
+	}
+	existing := make(map[string]bool, len(current))
+	var cleared []string
+	for _, t := range current {
+		existing[t] = true
+		// 迁移版本表与 SQLite 内部表不属于业务数据
+		if t != database.MigrationTable && !strings.HasPrefix(t, "sqlite_") {
+			cleared = append(cleared, t)
+		}
+	}
+	cleared = orderBackupTables(cleared)
+
+	byName := make(map[string]bundleTable, len(tables))
+	var names []string
+	for _, t := range tables {
+		// 迁移版本表描述的是当前表结构，不随数据恢复
+		if existing[t.Name] && t.Name != database.MigrationTable {
+			byName[t.Name] = t
+			names = append(names, t.Name)
+		}
+	}
+	names = orderBackupTables(names)
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// 直接执行 DELETE，不触发删除墓碑回调
+		for i := len(cleared) - 1; i >= 0; i-- {
+			if err := tx.Exec("DELETE FROM ?", clause.Table{Name: cleared[i]}).Error; err != nil {
+				return fmt.Errorf("清空表 %s 失败: %w", cleared[i], err)
+			}
+		}
+		for _, name := range names {
+			if err := s.restoreTable(tx, byName[name]); err != nil {
+				return fmt.Errorf("恢复表 %s 失败: %w", name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// restoreTable 写入单表备份数据
+func (s *BackupService) restoreTable(tx *gorm.DB, table bundleTable) error {
+	columnTypes, err := tx.Migrator().ColumnTypes(table.Name)
+	if err != nil {
+		return err
+	}
+	timeColumns := make(map[string]bool, len(columnTypes))
+	known := make(map[string]bool, len(columnTypes))
+	for _, ct := range columnTypes {
+		known[ct.Name()] = true
+		typeName := strings.ToLower(ct.DatabaseTypeName())
+		if strings.Contains(typeName, "time") || strings.Contains(typeName, "date") {
+			timeColumns[ct.Name()] = true
+		}
+	}
+
+	batch := make([]map[string]interface{}, 0, backupBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := tx.Table(table.Name).Create(&batch).Error
+		batch = batch[:0]
+		return err
+	}
+
+	for _, row := range table.Rows {
+		record := make(map[string]interface{}, len(table.Columns))
+		for i, col := range table.Columns {
+			if !known[col] || i >= len(row) {
+				continue
+			}
+			value, err := importValue(row[i], timeColumns[col])
+			if err != nil {
+				return fmt.Errorf("列 %s: %w", col, err)
+			}
+			record[col] = value
+		}
+		batch = append(batch, record)
+		if len(batch) >= backupBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	// PostgreSQL 自增序列不随显式写入的 id 前进，需要重置
+	if database.GetDBType() == "postgres" && known["id"] {
+		return tx.Exec("SELECT setval(pg_get_serial_sequence(?, 'id'), COALESCE(MAX(id), 0) + 1, false) FROM ?",
+			table.Name, clause.Table{Name: table.Name}).Error
+	}
+	return nil
+}
+
+// orderBackupTables 同步表按依赖顺序排在前面，其余表按名称排序
+func orderBackupTables(names []string) []string {
+	present := make(map[string]bool, len(names))
+	for _, n := range names {
+		present[n] = true
+	}
+	ordered := make([]string, 0, len(names))
+	for _, t := range database.SyncTables {
+		if present[t] {
+			ordered = append(ordered, t)
+			delete(present, t)
+		}
+	}
+	var rest []string
+	for n := range present {
+		rest = append(rest, n)
+	}
+	sort.Strings(rest)
+	return append(ordered, rest...)
+}
+
+// backupFormat 根据文件名判断备份格式 (非备份文件返回空)
+func backupFormat(name string) string {
+	switch {
+	case strings.HasSuffix(name, backupSQLiteExt):
+		return BackupFormatSQLite
+	case strings.HasSuffix(name, backupDumpExt):
+		return BackupFormatDump
+	default:
+		return ""
+	}
+}
+
+// validateBackupTables 校验备份中包含可用的用户数据，避免恢复后无法登录
+func validateBackupTables(tables []bundleTable) error {
+	for _, t := range tables {
+		if t.Name == "users" {
+			if len(t.Rows) == 0 {
+				return errors.New("备份中没有用户数据")
+			}
+			return nil
+		}
+	}
+	return errors.New("备份中缺少用户表")
+}
+
+// readSQLiteBackup 校验并读取 SQLite 快照中的全部表
+func readSQLiteBackup(path string) ([]bundleTable, error) {
+	header := make([]byte, len(sqliteFileHeader))
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.ReadFull(f, header)
+	f.Close()
+	if err != nil || !bytes.Equal(header, sqliteFileHeader) {
+		return nil, errors.New("备份文件不是有效的 SQLite 数据库")
+	}
+
+	src, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("打开备份失败: %w", err)
+	}
+	defer src.Close()
+
+	var check string
+	if err := src.QueryRow("PRAGMA integrity_check").Scan(&check); err != nil {
+		return nil, fmt.Errorf("校验备份失败: %w", err)
+	}
+	if check != "ok" {
+		return nil, fmt.Errorf("备份文件已损坏: %s", check)
+	}
+
+	rows, err := src.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("读取备份失败: %w", err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+
+	tables := make([]bundleTable, 0, len(names))
+	for _, name := range names {
+		rows, err := src.Query(fmt.Sprintf(`SELECT * FROM "%s"`, name))
+		if err != nil {
+			return nil, fmt.Errorf("读取表 %s 失败: %w", name, err)
+		}
+		data, err := scanTableRows(rows)
+		if err != nil {
+			return nil, fmt.Errorf("读取表 %s 失败: %w", name, err)
+		}
+		data.Name = name
+		tables = append(tables, data)
+	}
+
+	// 快照包含迁移版本表，记录了与数据一致的表结构版本
+	if err := checkBackupSchema(backupSchemaVersion(tables)); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+// readDumpBackup 校验并读取逻辑转储文件
+func readDumpBackup(path string) ([]bundleTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("解压备份失败: %w", err)
+	}
+	defer gz.Close()
+
+	var dump backupDump
+	decoder := json.NewDecoder(gz)
+	decoder.UseNumber()
+	if err := decoder.Decode(&dump); err != nil {
+		return nil, fmt.Errorf("解析备份失败: %w", err)
+	}
+	if dump.Format != backupDumpFormat {
+		return nil, errors.New("无法识别的备份格式")
+	}
+	if dump.Version > backupDumpVersion {
+		return nil, fmt.Errorf("备份版本 %d 过高，请升级应用后再恢复", dump.Version)
+	}
+
+	version := dump.SchemaVersion
+	if version == 0 {
+		version = backupSchemaVersion(dump.Tables)
+	}
+	if err := checkBackupSchema(version); err != nil {
+		return nil, err
+	}
+	return dump.Tables, nil
+}
+
+// backupSchemaVersion 从备份中的迁移版本表读取数据库版本 (不存在时返回 0)
+func backupSchemaVersion(tables []bundleTable) int64 {
+	var version int64
+	for _, t := range tables {
+		if t.Name != database.MigrationTable {
+			continue
+		}
+		index := -1
+		for i, col := range t.Columns {
+			if col == "version" {
+				index = i
+			}
+		}
+		if index < 0 {
+			return 0
+		}
+		for _, row := range t.Rows {
+			// JSON 转储中为 json.Number，SQLite 快照中为 int64
+			if v, err := strconv.ParseInt(fmt.Sprint(normalizeSyncValue(row[index])), 10, 64); err == nil && v > version {
+				version = v
+			}
+		}
+	}
+	return version
+}
+
+// checkBackupSchema 校验备份的数据库版本可以直接恢复
+// 恢复只复制数据、不执行数据迁移: 早于金额单位迁移的备份中金额以元存储，恢复后会相差 100 倍；
+// 高于程序支持版本的备份包含当前程序未知的列，恢复时会被静默丢弃。
+func checkBackupSchema(version int64) error {
+	if version < database.MoneyMinorUnitsVersion {
+		return fmt.Errorf("备份的数据库版本 (%d) 早于金额单位迁移 (%d)，金额以元存储，无法直接恢复；请先用创建该备份的应用版本恢复，再升级应用", version, database.MoneyMinorUnitsVersion)
+	}
+	latest, err := database.LatestVersion()
+	if err != nil {
+		return err
+	}
+	if version > latest {
+		return fmt.Errorf("备份的数据库版本 (%d) 高于程序支持的版本 (%d)，请升级应用后再恢复", version, latest)
+	}
+	return nil
+}
+
+// scanTableRows 读取查询结果的列名与全部行，并关闭结果集
+func scanTableRows(rows *sql.Rows) (bundleTable, error) {
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return bundleTable{}, err
+	}
+	data, err := scanOrderedRows(rows, len(columns))
+	if err != nil {
+		return bundleTable{}, err
+	}
+	return bundleTable{Columns: columns, Rows: data}, nil
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/FruitsAI/Orange/internal/database"
+)
+
+func TestCheckBackupSchema(t *testing.T) {
+	latest, err := database.LatestVersion()
+	if err != nil {
+		t.Fatalf("LatestVersion() unexpected error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		version int64
+		wantErr bool
+	}{
+		{name: "无版本信息", version: 0, wantErr: true},
+		{name: "早于金额单位迁移", version: database.MoneyMinorUnitsVersion - 1, wantErr: true},
+		{name: "金额单位迁移版本", version: database.MoneyMinorUnitsVersion},
+		{name: "当前版本", version: latest},
+		{name: "高于程序支持的版本", version: latest + 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkBackupSchema(tt.version); (err != nil) != tt.wantErr {
+				t.Errorf("checkBackupSchema(%d) error = %v, wantErr %v", tt.version, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+// 合同变更单错误
+var (
+	ErrChangeOrderNotFound = errors.New("变更单不存在")
+	ErrChangeOrderInvalid  = errors.New("变更单无效")
+)
+
+// ChangeOrderService 合同变更单服务
+// 范围变更通过变更单记录，原合同金额保持不变；变更单批准后计入项目的有效合同金额
+// (合同总金额 + 已批准变更金额)，并在同一事务中重新计算款项占比。
+//
+// 权限规则:
+//   - 查看: 项目成员
+//   - 创建、修改、删除草稿: 项目所有者或编辑者
+//   - 批准: 项目所有者；已批准的变更单不能再修改或删除
+type ChangeOrderService struct {
+	orderRepo       *repository.ChangeOrderRepository
+	projectRepo     *repository.ProjectRepository
+	paymentService  *PaymentService
+	access          *projectAccess
+	auditService    *AuditService
+	revisionService *RevisionService
+}
+
+// NewChangeOrderService 创建合同变更单服务实例
+func NewChangeOrderService() *ChangeOrderService {
+	return &ChangeOrderService{
+		orderRepo:       repository.NewChangeOrderRepository(),
+		projectRepo:     repository.NewProjectRepository(),
+		paymentService:  NewPaymentService(),
+		access:          newProjectAccess(),
+		auditService:    NewAuditService(),
+		revisionService: NewRevisionService(),
+	}
+}
+
+// ListByProject 获取项目的变更单 (需为项目成员)，按变更日期升序
+func (s *ChangeOrderService) ListByProject(userID, projectID int64) ([]models.ChangeOrder, error) {
+	if _, err := s.access.authorize(userID, projectID, ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.ListByProject(projectID)
+}
+
+// Create 创建变更单草稿 (需为项目所有者或编辑者)
+func (s *ChangeOrderService) Create(actor audit.Actor, input dto.ChangeOrderRequest) (*models.ChangeOrder, error) {
+	if _, err := s.access.authorize(actor.UserID, input.ProjectID, ProjectRoleEditor); err != nil {
+		return nil, err
+	}
+
+	order := &models.ChangeOrder{
+		ProjectID: input.ProjectID,
+		Status:    models.ChangeOrderDraft,
+		UserID:    actor.UserID,
+	}
+	if err := s.applyInput(order, input); err != nil {
+		return nil, err
+	}
+	if err := s.orderRepo.Create(order); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(actor, audit.ActionCreate, audit.EntityChangeOrder, order.ID, nil, order)
+	return order, nil
+}
+
+// Update 修改变更单草稿 (需为项目所有者或编辑者)
+func (s *ChangeOrderService) Update(actor audit.Actor, id int64, input dto.ChangeOrderRequest) (*models.ChangeOrder, error) {
+	order, err := s.authorizeDraft(actor.UserID, id, ProjectRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	before := *order
+	if err := s.applyInput(order, input); err != nil {
+		return nil, err
+	}
+	if err := s.orderRepo.Update(order); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(actor, audit.ActionUpdate, audit.EntityChangeOrder, id, &before, order)
+	return order, nil
+}
+
+// Delete 删除变更单草稿 (需为项目所有者或编辑者)
+func (s *ChangeOrderService) Delete(actor audit.Actor, id int64) error {
+	order, err := s.authorizeDraft(actor.UserID, id, ProjectRoleEditor)
+	if err != nil {
+		return err
+	}
+	if err := s.orderRepo.Delete(id); err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionDelete, audit.EntityChangeOrder, id, order, nil)
+	return nil
+}
+
+// Approve 批准变更单 (需为项目所有者)
+// 在同一事务中更新项目的已批准变更金额并重新计算款项占比；有效合同金额必须大于 0。
+//
+// 返回:
+//   - *models.ChangeOrder: 已批准的变更单
+//   - error: 变更单不存在、已批准、无权操作或有效合同金额不大于 0
+func (s *ChangeOrderService) Approve(actor audit.Actor, id int64) (*models.ChangeOrder, error) {
+	order, err := s.authorize(actor.UserID, id, ProjectRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	var project *models.Project
+	var beforeProject models.Project
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		orders := s.orderRepo.WithTx(tx)
+		locked, err := orders.FindForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if locked.Status != models.ChangeOrderDraft {
+			return fmt.Errorf("%w: 变更单已批准", ErrChangeOrderInvalid)
+		}
+		// 在事务内锁定项目，合同金额校验与占比计算以最新的合同总金额为准
+		project, err = s.projectRepo.WithTx(tx).FindForUpdate(locked.ProjectID)
+		if err != nil {
+			return err
+		}
+		beforeProject = *project
+
+		now := time.Now()
+		locked.Status = models.ChangeOrderApproved
+		locked.ApprovedBy = &actor.UserID
+		locked.ApprovedAt = &now
+		if err := orders.Update(locked); err != nil {
+			return err
+		}
+		order = locked
+
+		changeAmount, err := orders.SumApproved(project.ID)
+		if err != nil {
+			return err
+		}
+		project.ChangeAmount = changeAmount
+		if project.ContractAmount() <= 0 {
+			return fmt.Errorf("%w: 批准后有效合同金额 %s 必须大于 0", ErrChangeOrderInvalid, project.ContractAmount())
+		}
+		if err := tx.Model(&models.Project{}).
+			Where("id = ?", project.ID).
+			Update("change_amount", changeAmount).Error; err != nil {
+			return err
+		}
+		return s.paymentService.refreshPercentages(tx, project)
+	})
+	if err != nil {
+		return nil, err
+	}
+	project.EffectiveAmount = project.ContractAmount()
+
+	s.auditService.Record(actor, audit.ActionApprove, audit.EntityChangeOrder, id, nil, order)
+	s.revisionService.Record(actor, audit.EntityProject, project.ID, audit.ActionUpdate, projectSnapshot(&beforeProject), projectSnapshot(project))
+	return order, nil
+}
+
+// Timeline 获取合同金额变更时间线 (需为项目成员)
+// 以原合同为起点，按变更日期依次列出已批准的变更单及变更后的有效合同金额。
+func (s *ChangeOrderService) Timeline(userID, projectID int64) (*dto.ContractTimeline, error) {
+	project, err := s.access.authorize(userID, projectID, ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := s.orderRepo.ListApproved(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 原合同以签订日期为准，未填写时取计划开始日期
+	signed := project.StartDate
+	if project.ContractDate != nil {
+		signed = *project.ContractDate
+	}
+	timeline := &dto.ContractTimeline{
+		ProjectID:      project.ID,
+		OriginalAmount: project.TotalAmount,
+		Entries: []dto.ContractTimelineEntry{{
+			Type:   dto.ContractEntryOriginal,
+			Date:   signed,
+			Number: project.ContractNumber,
+			Amount: project.TotalAmount,
+			Total:  project.TotalAmount,
+		}},
+	}
+
+	total := project.TotalAmount
+	for _, order := range orders {
+		total += order.Amount
+		timeline.ChangeAmount += order.Amount
+		timeline.Entries = append(timeline.Entries, dto.ContractTimelineEntry{
+			Type:          dto.ContractEntryChangeOrder,
+			Date:          order.Date,
+			ChangeOrderID: order.ID,
+			Number:        order.Number,
+			Reason:        order.Reason,
+			Amount:        order.Amount,
+			Total:         total,
+			ApprovedBy:    order.ApprovedBy,
+			ApprovedAt:    order.ApprovedAt,
+		})
+	}
+	timeline.EffectiveAmount = total
+	return timeline, nil
+}
+
+// authorize 加载变更单并校验用户对其所属项目的权限
+// 无访问权限时按变更单不存在处理。
+func (s *ChangeOrderService) authorize(userID, id int64, required string) (*models.ChangeOrder, error) {
+	order, err := s.orderRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrChangeOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.access.authorize(userID, order.ProjectID, required); err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return nil, ErrChangeOrderNotFound
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+// authorizeDraft 加载变更单草稿并校验权限，已批准的变更单不能修改或删除
+func (s *ChangeOrderService) authorizeDraft(userID, id int64, required string) (*models.ChangeOrder, error) {
+	order, err := s.authorize(userID, id, required)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.ChangeOrderDraft {
+		return nil, fmt.Errorf("%w: 已批准的变更单不能修改或删除", ErrChangeOrderInvalid)
+	}
+	return order, nil
+}
+
+// applyInput 校验请求并写入变更单字段
+func (s *ChangeOrderService) applyInput(order *models.ChangeOrder, input dto.ChangeOrderRequest) error {
+	date, err := time.Parse("2006-01-02", input.Date)
+	if err != nil {
+		return fmt.Errorf("%w: 变更日期格式应为 YYYY-MM-DD", ErrChangeOrderInvalid)
+	}
+	exists, err := s.orderRepo.ExistsByNumber(order.ProjectID, input.Number, order.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w: 变更单编号 %s 已存在", ErrChangeOrderInvalid, input.Number)
+	}
+
+	order.Number = input.Number
+	order.Date = date
+	order.Amount = input.Amount
+	order.Reason = input.Reason
+	return nil
+}
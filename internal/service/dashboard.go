@@ -6,6 +6,7 @@ import (
 
 	"github.com/FruitsAI/Orange/internal/dto"
 	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/money"
 	"github.com/FruitsAI/Orange/internal/repository"
 )
 
@@ -110,10 +111,10 @@ func (s *DashboardService) GetStats(userID int64, period string) (*dto.Stats, er
 			PendingAmount:          pendingAmount, // 全量
 			OverdueAmount:          overdueAmount, // 全量
 			AvgCollectionDays:      0,             // 全局模式下暂不计算
-			TotalTrend:             calcTrend(currTotal, prevTotal),
-			PaidTrend:              calcTrend(currPaid, prevPaid),
-			PendingTrend:           calcTrend(currPending, prevPending),
-			OverdueTrend:           calcTrend(currOverdue, prevOverdue), // 计算逾期金额的环比趋势
+			TotalTrend:             calcTrend(currTotal.Yuan(), prevTotal.Yuan()),
+			PaidTrend:              calcTrend(currPaid.Yuan(), prevPaid.Yuan()),
+			PendingTrend:           calcTrend(currPending.Yuan(), prevPending.Yuan()),
+			OverdueTrend:           calcTrend(currOverdue.Yuan(), prevOverdue.Yuan()), // 计算逾期金额的环比趋势
 			AvgCollectionDaysTrend: calcTrend(currAvgDays, prevAvgDays),
 		}, nil
 	}
@@ -192,10 +193,10 @@ func (s *DashboardService) GetStats(userID int64, period string) (*dto.Stats, er
 		PendingAmount:          currPending,
 		OverdueAmount:          currOverdue,
 		AvgCollectionDays:      currAvgDays,
-		TotalTrend:             calcTrend(currTotal, prevTotal),
-		PaidTrend:              calcTrend(currPaid, prevPaid),
-		PendingTrend:           calcTrend(currPending, prevPending),
-		OverdueTrend:           calcTrend(currOverdue, prevOverdue), // 计算逾期金额的环比趋势
+		TotalTrend:             calcTrend(currTotal.Yuan(), prevTotal.Yuan()),
+		PaidTrend:              calcTrend(currPaid.Yuan(), prevPaid.Yuan()),
+		PendingTrend:           calcTrend(currPending.Yuan(), prevPending.Yuan()),
+		OverdueTrend:           calcTrend(currOverdue.Yuan(), prevOverdue.Yuan()), // 计算逾期金额的环比趋势
 		AvgCollectionDaysTrend: calcTrend(currAvgDays, prevAvgDays),
 	}, nil
 }
@@ -269,8 +270,8 @@ func (s *DashboardService) GetIncomeTrend(userID int64, period string) (*dto.Inc
 	}
 
 	var labels []string
-	var actualValues []float64
-	var expectedValues []float64
+	var actualValues []money.Amount
+	var expectedValues []money.Amount
 
 	// 数据补全: 数据库只返回有数据的日期，需要遍历完整时间轴填补0值
 	if interval == "day" {
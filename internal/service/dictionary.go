@@ -2,19 +2,22 @@ package service
 
 import (
 	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
 	"github.com/FruitsAI/Orange/internal/repository"
 )
 
 // DictionaryService 数据字典服务
 // 提供通用字典数据的查询和维护功能，支持字典项的增删改查。
 type DictionaryService struct {
-	dictRepo *repository.DictionaryRepository
+	dictRepo     *repository.DictionaryRepository
+	auditService *AuditService
 }
 
 // NewDictionaryService 创建字典服务实例
 func NewDictionaryService() *DictionaryService {
 	return &DictionaryService{
-		dictRepo: repository.NewDictionaryRepository(),
+		dictRepo:     repository.NewDictionaryRepository(),
+		auditService: NewAuditService(),
 	}
 }
 
@@ -39,6 +42,7 @@ func (s *DictionaryService) GetItems(code string) ([]models.DictionaryItem, erro
 // CreateItem 为指定字典创建新选项
 //
 // 参数:
+//   - actor: 操作人
 //   - code: 字典编码 (确定归属哪个字典)
 //   - label: 显示名称
 //   - value: 数据值
@@ -46,7 +50,7 @@ func (s *DictionaryService) GetItems(code string) ([]models.DictionaryItem, erro
 //
 // 返回:
 //   - *models.DictionaryItem: 创建的字典项
-func (s *DictionaryService) CreateItem(code, label, value string, sort int) (*models.DictionaryItem, error) {
+func (s *DictionaryService) CreateItem(actor audit.Actor, code, label, value string, sort int) (*models.DictionaryItem, error) {
 	// 1. 查找父级字典
 	dict, err := s.dictRepo.FindByCode(code)
 	if err != nil {
@@ -66,6 +70,7 @@ func (s *DictionaryService) CreateItem(code, label, value string, sort int) (*mo
 	if err := s.dictRepo.CreateItem(item); err != nil {
 		return nil, err
 	}
+	s.auditService.Record(actor, audit.ActionCreate, audit.EntityDictionaryItem, item.ID, nil, item)
 
 	return item, nil
 }
@@ -73,6 +78,7 @@ func (s *DictionaryService) CreateItem(code, label, value string, sort int) (*mo
 // UpdateItem 更新字典项信息
 //
 // 参数:
+//   - actor: 操作人
 //   - id: 字典项ID
 //   - label: 新的显示名称
 //   - value: 新的数据值
@@ -80,12 +86,13 @@ func (s *DictionaryService) CreateItem(code, label, value string, sort int) (*mo
 //
 // 返回:
 //   - *models.DictionaryItem: 更新后的实体
-func (s *DictionaryService) UpdateItem(id int64, label, value string, sort int) (*models.DictionaryItem, error) {
+func (s *DictionaryService) UpdateItem(actor audit.Actor, id int64, label, value string, sort int) (*models.DictionaryItem, error) {
 	// 1. 获取现有记录 (确保ID存在且保留DictionaryID等字段)
 	item, err := s.dictRepo.FindItemByID(id)
 	if err != nil {
 		return nil, err
 	}
+	before := *item
 
 	// 2. 更新字段
 	item.Label = label
@@ -96,10 +103,19 @@ func (s *DictionaryService) UpdateItem(id int64, label, value string, sort int)
 	if err := s.dictRepo.UpdateItem(item); err != nil {
 		return nil, err
 	}
+	s.auditService.Record(actor, audit.ActionUpdate, audit.EntityDictionaryItem, item.ID, &before, item)
 	return item, nil
 }
 
 // DeleteItem 删除指定字典项
-func (s *DictionaryService) DeleteItem(id int64) error {
-	return s.dictRepo.DeleteItem(id)
+func (s *DictionaryService) DeleteItem(actor audit.Actor, id int64) error {
+	item, err := s.dictRepo.FindItemByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.dictRepo.DeleteItem(id); err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionDelete, audit.EntityDictionaryItem, id, item, nil)
+	return nil
 }
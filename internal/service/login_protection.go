@@ -0,0 +1,272 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	// accountBackoffAfter 同一账户连续失败达到该次数后开始退避
+	accountBackoffAfter = 3
+
+	// ipBackoffAfter 同一 IP 连续失败达到该次数后开始退避 (IP 可能被多人共享，阈值更宽松)
+	ipBackoffAfter = 10
+
+	// backoffBase 首次退避时长，此后每失败一次翻倍
+	backoffBase = time.Second
+
+	// backoffMax 单次退避时长上限
+	backoffMax = 5 * time.Minute
+
+	// failureWindow 失败计数的有效窗口，最后一次失败超过该时长后计数重新开始
+	failureWindow = time.Hour
+
+	// defaultLockoutDuration 未配置锁定时长时的默认值
+	defaultLockoutDuration = 15 * time.Minute
+
+	// lockoutHistoryLimit 查询锁定记录的最大条数
+	lockoutHistoryLimit = 50
+)
+
+// LoginThrottledError 登录被限制错误 (退避等待中或账户已锁定)
+type LoginThrottledError struct {
+	RetryAfter time.Duration // 距可再次尝试的剩余时长
+	Locked     bool          // 是否为账户锁定 (否则为失败退避)
+}
+
+// Error 实现 error 接口
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		minutes := int(math.Ceil(e.RetryAfter.Minutes()))
+		return fmt.Sprintf("连续登录失败次数过多，账户已被临时锁定，请 %d 分钟后重试或联系管理员解锁", minutes)
+	}
+	seconds := int(math.Ceil(e.RetryAfter.Seconds()))
+	return fmt.Sprintf("登录失败次数过多，请 %d 秒后重试", seconds)
+}
+
+// LoginProtectionService 登录保护服务
+// 按账户与来源 IP 分别统计连续登录失败次数:
+//   - 达到退避阈值后，每次失败需等待的时长按指数增长 (1s, 2s, 4s ... 上限 5 分钟)
+//   - 同一账户连续失败达到 LOGIN_MAX_FAILURES 次后临时锁定 LOGIN_LOCKOUT_MINUTES 分钟，并记录锁定事件
+//
+// 登录成功后清零账户计数；IP 计数在失败窗口过后自然失效。
+type LoginProtectionService struct {
+	repo *repository.LoginProtectionRepository
+}
+
+// NewLoginProtectionService 创建登录保护服务实例
+func NewLoginProtectionService() *LoginProtectionService {
+	return &LoginProtectionService{
+		repo: repository.NewLoginProtectionRepository(),
+	}
+}
+
+// Check 校验账户与来源 IP 当前是否允许尝试登录
+//
+// 参数:
+//   - accountKey: 账户计数键
+//   - ip: 客户端 IP (为空时不校验)
+//
+// 返回:
+//   - error: 被限制时返回 *LoginThrottledError
+func (s *LoginProtectionService) Check(accountKey, ip string) error {
+	now := time.Now()
+
+	account, err := s.repo.FindThrottle(accountKey)
+	if err != nil {
+		return err
+	}
+	if account != nil {
+		if account.LockedUntil != nil && account.LockedUntil.After(now) {
+			return &LoginThrottledError{RetryAfter: account.LockedUntil.Sub(now), Locked: true}
+		}
+		if wait := backoffRemaining(account, accountBackoffAfter, now); wait > 0 {
+			return &LoginThrottledError{RetryAfter: wait}
+		}
+	}
+
+	if ip == "" {
+		return nil
+	}
+	source, err := s.repo.FindThrottle(repository.IPThrottleKey(ip))
+	if err != nil {
+		return err
+	}
+	if source != nil {
+		if wait := backoffRemaining(source, ipBackoffAfter, now); wait > 0 {
+			return &LoginThrottledError{RetryAfter: wait}
+		}
+	}
+	return nil
+}
+
+// RecordFailure 记录一次登录失败
+// 账户连续失败达到锁定阈值时锁定账户并写入锁定记录。
+//
+// 参数:
+//   - accountKey: 账户计数键
+//   - userID: 用户ID (登录名不存在时为 0)
+//   - username: 尝试登录的用户名
+//   - ip: 客户端 IP
+//
+// 返回:
+//   - error: 本次失败触发锁定或账户已锁定时返回 *LoginThrottledError，其余情况为数据库错误或 nil
+func (s *LoginProtectionService) RecordFailure(accountKey string, userID int64, username, ip string) error {
+	now := time.Now()
+
+	// 顺带清理已过失败窗口的计数，避免不存在的登录名与来源 IP 的记录无限增长
+	if err := s.repo.DeleteStaleThrottles(now.Add(-failureWindow), now); err != nil {
+		slog.Warn("清理过期登录失败计数失败", "error", err)
+	}
+
+	if ip != "" {
+		if _, _, err := s.increment(repository.IPThrottleKey(ip), now, 0); err != nil {
+			return err
+		}
+	}
+
+	account, lockedNow, err := s.increment(accountKey, now, config.AppConfig.LoginMaxFailures)
+	if err != nil {
+		return err
+	}
+	if account.LockedUntil == nil || !account.LockedUntil.After(now) {
+		return nil
+	}
+	if !lockedNow {
+		// 并发请求已触发锁定
+		return &LoginThrottledError{RetryAfter: account.LockedUntil.Sub(now), Locked: true}
+	}
+
+	if err := s.repo.CreateLockout(&models.LoginLockout{
+		UserID:      userID,
+		Username:    username,
+		IP:          ip,
+		Failures:    account.Failures,
+		LockedUntil: *account.LockedUntil,
+	}); err != nil {
+		return err
+	}
+	slog.Warn("连续登录失败，账户已临时锁定", "username", username, "user_id", userID, "ip", ip, "failures", account.Failures)
+
+	return &LoginThrottledError{RetryAfter: account.LockedUntil.Sub(now), Locked: true}
+}
+
+// Reset 登录成功后清零账户失败计数
+func (s *LoginProtectionService) Reset(accountKey string) error {
+	return s.repo.DeleteThrottle(accountKey)
+}
+
+// Unlock 管理员手动解锁账户
+//
+// 参数:
+//   - userID: 被解锁的用户ID
+//   - operatorID: 操作人ID
+func (s *LoginProtectionService) Unlock(userID, operatorID int64) error {
+	if err := s.repo.DeleteThrottle(repository.UserThrottleKey(userID)); err != nil {
+		return err
+	}
+	return s.repo.MarkUnlocked(userID, operatorID)
+}
+
+// Lockouts 获取用户最近的锁定记录
+func (s *LoginProtectionService) Lockouts(userID int64) ([]models.LoginLockout, error) {
+	return s.repo.ListLockouts(userID, lockoutHistoryLimit)
+}
+
+// FillLockStatus 填充用户列表的锁定状态 (LockedUntil)
+func (s *LoginProtectionService) FillLockStatus(users []models.User) error {
+	ids := make([]int64, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	locked, err := s.repo.ListLockedUsers(ids)
+	if err != nil {
+		return err
+	}
+	for i := range users {
+		if until, ok := locked[users[i].ID]; ok {
+			users[i].LockedUntil = &until
+		}
+	}
+	return nil
+}
+
+// increment 失败计数加一 (最后一次失败超过失败窗口时重新计数)
+// 在事务中锁定计数记录后读写，并发的失败请求逐个累加，不会读到相同的计数。
+//
+// 参数:
+//   - key: 计数键
+//   - now: 当前时间
+//   - maxFailures: 达到该次数时锁定 (0 表示不锁定)
+//
+// 返回:
+//   - *models.LoginThrottle: 累加后的计数
+//   - bool: 是否由本次失败触发锁定
+//   - error: 数据库错误
+func (s *LoginProtectionService) increment(key string, now time.Time, maxFailures int) (*models.LoginThrottle, bool, error) {
+	var throttle *models.LoginThrottle
+	lockedNow := false
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		var err error
+		throttle, err = repo.FindThrottleForUpdate(key)
+		if err != nil {
+			return err
+		}
+
+		locked := throttle.LockedUntil != nil && throttle.LockedUntil.After(now)
+		if !locked && now.Sub(throttle.LastFailureAt) > failureWindow {
+			throttle.Failures = 0
+			throttle.LockedUntil = nil
+		}
+		throttle.Failures++
+		throttle.LastFailureAt = now
+
+		if maxFailures > 0 && !locked && throttle.Failures >= maxFailures {
+			lockedUntil := now.Add(lockoutDuration())
+			throttle.LockedUntil = &lockedUntil
+			lockedNow = true
+		}
+		return repo.SaveThrottle(throttle)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return throttle, lockedNow, nil
+}
+
+// backoffRemaining 计算距可再次尝试的剩余退避时长
+//
+// 参数:
+//   - throttle: 失败计数
+//   - after: 开始退避的失败次数
+//   - now: 当前时间
+func backoffRemaining(throttle *models.LoginThrottle, after int, now time.Time) time.Duration {
+	if throttle.Failures < after || now.Sub(throttle.LastFailureAt) > failureWindow {
+		return 0
+	}
+
+	delay := backoffMax
+	if shift := throttle.Failures - after; shift < 16 {
+		if d := backoffBase << shift; d < backoffMax {
+			delay = d
+		}
+	}
+	return throttle.LastFailureAt.Add(delay).Sub(now)
+}
+
+// lockoutDuration 账户锁定时长
+func lockoutDuration() time.Duration {
+	if minutes := config.AppConfig.LoginLockoutMinutes; minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+	return defaultLockoutDuration
+}
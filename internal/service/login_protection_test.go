@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/repository"
+)
+
+func TestLoginProtectionServiceRecordFailure(t *testing.T) {
+	prevMax := config.AppConfig.LoginMaxFailures
+	config.AppConfig.LoginMaxFailures = 5
+	t.Cleanup(func() { config.AppConfig.LoginMaxFailures = prevMax })
+	s := NewLoginProtectionService()
+
+	tests := []struct {
+		name         string
+		attempts     int
+		concurrent   bool
+		wantFailures int
+		wantLocked   bool
+		wantLockouts int64
+	}{
+		{name: "未达锁定阈值", attempts: 4, wantFailures: 4},
+		{name: "达到锁定阈值", attempts: 5, wantFailures: 5, wantLocked: true, wantLockouts: 1},
+		{name: "并发失败逐个计数", attempts: 20, concurrent: true, wantFailures: 20, wantLocked: true, wantLockouts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			username := "throttle_" + tt.name
+			key := repository.NameThrottleKey(username)
+			db := database.GetDB()
+			t.Cleanup(func() {
+				db.Where("throttle_key IN ?", []string{key, repository.IPThrottleKey("10.0.0.1")}).Delete(&models.LoginThrottle{})
+				db.Where("username = ?", username).Delete(&models.LoginLockout{})
+			})
+
+			record := func() {
+				err := s.RecordFailure(key, 0, username, "10.0.0.1")
+				var throttled *LoginThrottledError
+				if err != nil && !errors.As(err, &throttled) {
+					t.Errorf("RecordFailure() unexpected error: %v", err)
+				}
+			}
+			if tt.concurrent {
+				var wg sync.WaitGroup
+				for i := 0; i < tt.attempts; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						record()
+					}()
+				}
+				wg.Wait()
+			} else {
+				for i := 0; i < tt.attempts; i++ {
+					record()
+				}
+			}
+
+			throttle, err := s.repo.FindThrottle(key)
+			if err != nil || throttle == nil {
+				t.Fatalf("FindThrottle() = %v, %v", throttle, err)
+			}
+			if throttle.Failures != tt.wantFailures {
+				t.Errorf("failures = %d, want %d", throttle.Failures, tt.wantFailures)
+			}
+			if locked := throttle.LockedUntil != nil && throttle.LockedUntil.After(time.Now()); locked != tt.wantLocked {
+				t.Errorf("locked = %v, want %v", locked, tt.wantLocked)
+			}
+			var lockouts int64
+			mustExec(t, db.Model(&models.LoginLockout{}).Where("username = ?", username).Count(&lockouts).Error)
+			if lockouts != tt.wantLockouts {
+				t.Errorf("lockouts = %d, want %d", lockouts, tt.wantLockouts)
+			}
+		})
+	}
+}
+
+func TestLoginProtectionRepositoryDeleteStaleThrottles(t *testing.T) {
+	repo := repository.NewLoginProtectionRepository()
+	now := time.Now()
+	stale := now.Add(-2 * failureWindow)
+	future := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		throttle models.LoginThrottle
+		wantKept bool
+	}{
+		{name: "窗口内的计数", throttle: models.LoginThrottle{Key: "stale:recent", Failures: 1, LastFailureAt: now}, wantKept: true},
+		{name: "过期计数", throttle: models.LoginThrottle{Key: "stale:old", Failures: 1, LastFailureAt: stale}},
+		{name: "锁定已结束的过期计数", throttle: models.LoginThrottle{Key: "stale:unlocked", Failures: 5, LastFailureAt: stale, LockedUntil: &past}},
+		{name: "仍在锁定中的计数", throttle: models.LoginThrottle{Key: "stale:locked", Failures: 5, LastFailureAt: stale, LockedUntil: &future}, wantKept: true},
+	}
+	db := database.GetDB()
+	for _, tt := range tests {
+		throttle := tt.throttle
+		mustExec(t, db.Create(&throttle).Error)
+	}
+	t.Cleanup(func() { db.Where("throttle_key LIKE ?", "stale:%").Delete(&models.LoginThrottle{}) })
+
+	if err := repo.DeleteStaleThrottles(now.Add(-failureWindow), now); err != nil {
+		t.Fatalf("DeleteStaleThrottles() unexpected error: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := repo.FindThrottle(tt.throttle.Key)
+			if err != nil {
+				t.Fatalf("FindThrottle() unexpected error: %v", err)
+			}
+			if kept := found != nil; kept != tt.wantKept {
+				t.Errorf("kept = %v, want %v", kept, tt.wantKept)
+			}
+		})
+	}
+}
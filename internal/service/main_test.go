@@ -0,0 +1,47 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/pkg/jwt"
+)
+
+// TestMain 为服务层测试准备独立的临时 SQLite 数据库
+// 数据库执行全部版本迁移并填充初始数据 (管理员 admin 与演示用户 xu)。
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "orange-service-test")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	config.AppConfig = &config.Config{
+		DBType:      "sqlite",
+		DBPath:      filepath.Join(dir, "orange.db"),
+		TokenExpiry: 1,
+	}
+	jwt.TokenExpiry = time.Minute
+
+	db := database.GetDB()
+	defer database.Close()
+	if err := database.Migrate(db); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := database.Seed(db); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return m.Run()
+}
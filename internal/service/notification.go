@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
 	"github.com/FruitsAI/Orange/internal/repository"
 )
 
@@ -13,21 +14,24 @@ import (
 //
 // 依赖:
 //   - NotificationRepository: 通知数据持久化接口
+//   - AuditService: 记录数据变更审计日志
 type NotificationService struct {
 	notificationRepo *repository.NotificationRepository
+	auditService     *AuditService
 }
 
 // NewNotificationService 创建通知服务实例
 func NewNotificationService() *NotificationService {
 	return &NotificationService{
 		notificationRepo: repository.NewNotificationRepository(),
+		auditService:     NewAuditService(),
 	}
 }
 
 // Create 发布新通知（通常由管理员操作）
 //
 // 参数:
-//   - senderID: 发送者ID
+//   - actor: 操作人 (即发送者)
 //   - title: 标题
 //   - content: 内容
 //   - notificationType: 通知类型 "system"(1), "activity"(2), "private"(3)
@@ -36,7 +40,7 @@ func NewNotificationService() *NotificationService {
 // 返回:
 //   - *models.Notification: 创建成功的通知实体
 //   - error: 校验失败或数据库错误
-func (s *NotificationService) Create(senderID int64, title, content, notificationType string, targetUserID int64) (*models.Notification, error) {
+func (s *NotificationService) Create(actor audit.Actor, title, content, notificationType string, targetUserID int64) (*models.Notification, error) {
 	// 1. 基础校验
 	if title == "" {
 		return nil, errors.New("标题不能为空")
@@ -70,7 +74,7 @@ func (s *NotificationService) Create(senderID int64, title, content, notificatio
 		Title:    title,
 		Content:  content,
 		Type:     typeInt,
-		SenderID: senderID,
+		SenderID: actor.UserID,
 		IsGlobal: isGlobal,
 	}
 
@@ -78,6 +82,7 @@ func (s *NotificationService) Create(senderID int64, title, content, notificatio
 	if err := s.notificationRepo.Create(notification, targetUserID); err != nil {
 		return nil, errors.New("创建通知失败")
 	}
+	s.auditService.Record(actor, audit.ActionCreate, audit.EntityNotification, notification.ID, nil, notification)
 
 	return notification, nil
 }
@@ -87,18 +92,20 @@ func (s *NotificationService) Create(senderID int64, title, content, notificatio
 // 如果涉及到接收关系的变更（如从全员改为私信），需要在Repo层有相应的处理逻辑。
 //
 // 参数:
+//   - actor: 操作人
 //   - id: 通知ID
 //   - title, content...: 更新字段
 //
 // 返回:
 //   - *models.Notification: 更新后的实体
 //   - error: 不存在或更新错误
-func (s *NotificationService) Update(id int64, title, content, notificationType string, targetUserID int64) (*models.Notification, error) {
+func (s *NotificationService) Update(actor audit.Actor, id int64, title, content, notificationType string, targetUserID int64) (*models.Notification, error) {
 	// 1. 检查是否存在
 	notification, err := s.notificationRepo.FindByID(id)
 	if err != nil {
 		return nil, errors.New("通知不存在")
 	}
+	before := *notification
 
 	if title == "" {
 		return nil, errors.New("标题不能为空")
@@ -139,6 +146,7 @@ func (s *NotificationService) Update(id int64, title, content, notificationType
 	if err := s.notificationRepo.Update(notification); err != nil {
 		return nil, errors.New("更新通知失败")
 	}
+	s.auditService.Record(actor, audit.ActionUpdate, audit.EntityNotification, notification.ID, &before, notification)
 
 	return notification, nil
 }
@@ -175,8 +183,16 @@ func (s *NotificationService) MarkAsRead(id int64, userID int64) error {
 }
 
 // Delete 删除通知 (软删除或物理删除，取决于Repo实现)
-func (s *NotificationService) Delete(id int64) error {
-	return s.notificationRepo.Delete(id)
+func (s *NotificationService) Delete(actor audit.Actor, id int64) error {
+	notification, err := s.notificationRepo.FindByID(id)
+	if err != nil {
+		return errors.New("通知不存在")
+	}
+	if err := s.notificationRepo.Delete(id); err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionDelete, audit.EntityNotification, id, notification, nil)
+	return nil
 }
 
 // GetUnreadCount 统计用户的未读通知数量
@@ -1,49 +1,101 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/FruitsAI/Orange/internal/database"
 	"github.com/FruitsAI/Orange/internal/dto"
 	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
 	"github.com/FruitsAI/Orange/internal/repository"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 // PaymentService 款项(回款)服务
-// 负责处理所有与款项相关的业务逻辑，包括生成收款计划、更新收款状态、
+// 负责处理所有与款项相关的业务逻辑，包括生成收款计划、登记收款记录、
 // 执行回款确认事务以及自动计算回款百分比。
+// 一期款项可分多笔到账，已收金额、状态 (pending / partially_paid / paid) 与实际收款日期均由收款记录推导；
+// 退还给客户的金额以退款记录登记，冲减款项与项目的实收净额。
 //
 // 依赖:
 //   - PaymentRepository: 款项数据操作
+//   - ReceiptRepository: 收款记录数据操作
+//   - RefundRepository: 退款记录数据操作
 //   - ProjectRepository: 项目数据操作 (用于更新项目总已收金额)
+//   - projectAccess: 项目访问控制 (款项的读写权限跟随所属项目)
+//   - AuditService: 记录数据变更审计日志
+//   - RevisionService: 保存款项历史版本
 type PaymentService struct {
-	paymentRepo *repository.PaymentRepository
-	projectRepo *repository.ProjectRepository
+	paymentRepo     *repository.PaymentRepository
+	receiptRepo     *repository.ReceiptRepository
+	refundRepo      *repository.RefundRepository
+	projectRepo     *repository.ProjectRepository
+	access          *projectAccess
+	auditService    *AuditService
+	revisionService *RevisionService
 }
 
+// 款项错误
+var (
+	ErrPaymentNotFound = errors.New("款项不存在") // 款项不存在或无权访问
+	ErrReceiptNotFound = errors.New("收款记录不存在")
+	ErrReceiptInvalid  = errors.New("收款金额无效")
+	ErrRefundNotFound  = errors.New("退款记录不存在")
+	ErrRefundInvalid   = errors.New("退款金额无效")
+)
+
 // NewPaymentService 创建并初始化收款服务
 //
 // 返回:
 //   - *PaymentService: 初始化的服务实例
 func NewPaymentService() *PaymentService {
 	return &PaymentService{
-		paymentRepo: repository.NewPaymentRepository(),
-		projectRepo: repository.NewProjectRepository(),
+		paymentRepo:     repository.NewPaymentRepository(),
+		receiptRepo:     repository.NewReceiptRepository(),
+		refundRepo:      repository.NewRefundRepository(),
+		projectRepo:     repository.NewProjectRepository(),
+		access:          newProjectAccess(),
+		auditService:    NewAuditService(),
+		revisionService: NewRevisionService(),
+	}
+}
+
+// authorizePayment 加载款项并校验用户对其所属项目的权限
+// 无访问权限时按款项不存在处理。
+func (s *PaymentService) authorizePayment(userID, id int64, required string) (*models.Payment, error) {
+	payment, err := s.paymentRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPaymentNotFound
+	}
+	if err != nil {
+		return nil, err
 	}
+	if _, err := s.access.authorize(userID, payment.ProjectID, required); err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return nil, ErrPaymentNotFound
+		}
+		return nil, err
+	}
+	return payment, nil
 }
 
 // ListByProject 根据项目ID获取该项目的所有收款计划
 // 用于在项目详情页展示款项列表。
 //
 // 参数:
+//   - userID: 当前用户ID (需为项目成员)
 //   - projectID: 项目ID
 //
 // 返回:
 //   - []models.Payment: 款项列表
-//   - error: 数据库查询错误
-func (s *PaymentService) ListByProject(projectID int64) ([]models.Payment, error) {
+//   - error: 无权访问或数据库查询错误
+func (s *PaymentService) ListByProject(userID, projectID int64) ([]models.Payment, error) {
+	if _, err := s.access.authorize(userID, projectID, ProjectRoleViewer); err != nil {
+		return nil, err
+	}
 	return s.paymentRepo.ListByProject(projectID)
 }
 
@@ -78,14 +130,21 @@ func (s *PaymentService) ListByDateRange(userID int64, startDate, endDate string
 }
 
 // Create 创建新的收款/回款计划
+// 状态传入 "paid" 时，按计划日期为全额登记一笔收款记录；其余状态由收款记录推导，传入值被忽略。
 //
 // 参数:
+//   - actor: 操作人 (需为项目所有者或编辑者)
 //   - input: 收款请求DTO
 //
 // 返回:
 //   - *models.Payment: 创建成功的款项实体
-//   - error: 业务规则校验失败或数据库错误
-func (s *PaymentService) Create(input dto.PaymentRequest) (*models.Payment, error) {
+//   - error: 无权操作、业务规则校验失败或数据库错误
+func (s *PaymentService) Create(actor audit.Actor, input dto.PaymentRequest) (*models.Payment, error) {
+	project, err := s.access.authorize(actor.UserID, input.ProjectID, ProjectRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
 	planDate, err := time.Parse("2006-01-02", input.PlanDate)
 	if err != nil {
 		return nil, err
@@ -96,60 +155,65 @@ func (s *PaymentService) Create(input dto.PaymentRequest) (*models.Payment, erro
 		Stage:     input.Stage,
 		Amount:    input.Amount,
 		PlanDate:  planDate,
-		Status:    input.Status,
+		Status:    models.PaymentStatusPending,
 		Method:    input.Method,
 		Remark:    input.Remark,
-		UserID:    input.UserID,
+		UserID:    project.UserID, // 归属项目负责人，共享成员代录的款项同样计入负责人的统计
 	}
 
-	// 默认状态为"待收款"
-	if payment.Status == "" {
-		payment.Status = "pending"
-	}
-
-	// 执行核心业务规则校验与处理（如计算百分比、自动填充实际日期逻辑等）
+	// 执行核心业务规则校验与处理（如计算百分比）
 	if err := s.processPaymentRules(payment); err != nil {
 		return nil, err
 	}
 
-	// 创建收款记录
-	if err := s.paymentRepo.Create(payment); err != nil {
-		return nil, err
-	}
-
-	// 级联更新: 重新计算并同步该项目对应的"已收款总额"字段
-	if err := s.syncProjectReceivedAmount(payment.ProjectID); err != nil {
+	// 创建款项，按需登记收款记录，并同步项目的"已收款总额"
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := s.paymentRepo.WithTx(tx).Create(payment); err != nil {
+			return err
+		}
+		return s.settle(tx, actor, payment, input.Status)
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.auditService.Record(actor, audit.ActionCreate, audit.EntityPayment, payment.ID, nil, payment)
+	s.revisionService.Record(actor, audit.EntityPayment, payment.ID, audit.ActionCreate, nil, paymentSnapshot(payment))
 
 	return payment, nil
 }
 
 // Update 更新收款计划详情
+// 本期金额不能小于已收金额；状态传入 "paid" 时为未收部分按计划日期补录一笔收款记录，
+// 其余状态由收款记录推导 (撤销收款需删除对应的收款记录)。
 //
 // 参数:
+//   - actor: 操作人 (需为项目所有者或编辑者)
 //   - id: 款项ID
 //   - input: 更新内容
 //
 // 返回:
 //   - *models.Payment: 更新后的实体
-//   - error: 更新失败
-func (s *PaymentService) Update(id int64, input dto.PaymentRequest) (*models.Payment, error) {
-	payment, err := s.paymentRepo.FindByID(id)
+//   - error: 无权操作或更新失败
+func (s *PaymentService) Update(actor audit.Actor, id int64, input dto.PaymentRequest) (*models.Payment, error) {
+	payment, err := s.authorizePayment(actor.UserID, id, ProjectRoleEditor)
 	if err != nil {
 		return nil, err
 	}
+	before := *payment
 
 	planDate, err := time.Parse("2006-01-02", input.PlanDate)
 	if err != nil {
 		return nil, err
 	}
 
+	if input.Amount < payment.ReceivedAmount {
+		return nil, fmt.Errorf("%w: 本期金额不能小于已收金额 %s", ErrReceiptInvalid, payment.ReceivedAmount)
+	}
+
 	// 更新字段
 	payment.Stage = input.Stage
 	payment.Amount = input.Amount
 	payment.PlanDate = planDate
-	payment.Status = input.Status
 	payment.Method = input.Method
 	payment.Remark = input.Remark
 
@@ -158,49 +222,41 @@ func (s *PaymentService) Update(id int64, input dto.PaymentRequest) (*models.Pay
 		return nil, err
 	}
 
-	// 更新数据库记录
-	if err := s.paymentRepo.Update(payment); err != nil {
-		return nil, err
-	}
-
-	// 级联更新: 数据变更后，必须重新同步项目的总收款状态
-	if err := s.syncProjectReceivedAmount(payment.ProjectID); err != nil {
+	// 更新数据库记录，并重新推导收款状态 (金额变化可能使已收齐的款项变为部分收款)
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := s.paymentRepo.WithTx(tx).Update(payment); err != nil {
+			return err
+		}
+		return s.settle(tx, actor, payment, input.Status)
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.auditService.Record(actor, audit.ActionUpdate, audit.EntityPayment, payment.ID, &before, payment)
+	s.revisionService.Record(actor, audit.EntityPayment, payment.ID, audit.ActionUpdate, paymentSnapshot(&before), paymentSnapshot(payment))
 
 	return payment, nil
 }
 
 // processPaymentRules 执行通用款项业务规则处理
-// 包含以下逻辑:
-//  1. 状态与日期的联动: 如果状态改为"paid"(已收款)，自动填充ActualDate(实际收款日)，反之置空。
-//  2. 百分比自动计算: 根据款项金额与项目合同总额，自动计算该笔款项的占比。
+// 百分比自动计算: 根据款项金额与项目合同总额，自动计算该笔款项的占比。
+// 状态与实际收款日期由收款记录推导，见 refreshReceived。
 func (s *PaymentService) processPaymentRules(payment *models.Payment) error {
-	// 1. 处理实际收款日期逻辑
-	if payment.Status == "paid" && payment.ActualDate == nil {
-		// 如果标记为已收款但用户未填实际日期，默认等于计划日期
-		payment.ActualDate = &payment.PlanDate
-	}
-	// 如果不是已收款状态，清除实际收款日期
-	if payment.Status != "paid" {
-		payment.ActualDate = nil
-	}
-
-	// 2. 自动计算百分比
 	project, err := s.projectRepo.FindByID(payment.ProjectID)
 	if err != nil {
 		return err
 	}
 
-	if project.TotalAmount > 0 {
-		payment.Percentage = (payment.Amount / project.TotalAmount) * 100
-	} else {
-		payment.Percentage = 0
-	}
+	applyPaymentRules(payment, project)
 
 	return nil
 }
 
+// applyPaymentRules 按所属项目计算款项的派生字段 (占有效合同金额的百分比)
+func applyPaymentRules(payment *models.Payment, project *models.Project) {
+	payment.Percentage = payment.Amount.Percent(project.ContractAmount())
+}
+
 // syncProjectReceivedAmount 重新计算并同步项目的"已收款总额"
 // 此方法应在任何款项金额或状态发生变化后被调用，以确保 Project 表数据的一致性。
 func (s *PaymentService) syncProjectReceivedAmount(projectID int64) error {
@@ -226,30 +282,54 @@ func (s *PaymentService) syncProjectReceivedAmount(projectID int64) error {
 	return nil
 }
 
-// Delete 删除收款
-func (s *PaymentService) Delete(id int64) error {
-	return s.paymentRepo.Delete(id)
+// Delete 删除收款 (移入回收站，需为项目所有者或编辑者)
+// 删除后重新计算项目的已收款总额。
+func (s *PaymentService) Delete(actor audit.Actor, id int64) error {
+	payment, err := s.authorizePayment(actor.UserID, id, ProjectRoleEditor)
+	if err != nil {
+		return err
+	}
+	if err := s.paymentRepo.Delete(id); err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionDelete, audit.EntityPayment, id, payment, nil)
+	return s.syncProjectReceivedAmount(payment.ProjectID)
 }
 
 // Confirm 确认收款（One-Click 操作）
-// 将款项标记为已收款，并自动更新实际收款日期和方式。通过数据库事务保证原子性。
+// 为款项登记一笔收款记录，金额为 0 时登记全部未收金额；未收齐时款项状态为部分收款 (partially_paid)。
+// 通过数据库事务保证原子性。
 //
 // 事务流程:
 //  1. 悲观锁锁定该款项记录 (Avoid Race Conditions)
-//  2. 检查幂等性 (如果已支付直接返回)
-//  3. 更新 Payment 记录状态
-//  4. 重新计算该项目下所有已支付总额 (Sum)
+//  2. 检查幂等性 (未指定金额且已收齐时直接返回最近一笔收款记录)
+//  3. 校验收款金额不超过未收金额，写入收款记录
+//  4. 重新推导款项的已收金额、状态与实际收款日期
 //  5. 更新 Project 记录的 received_amount
 //
 // 参数:
+//   - actor: 操作人 (需为项目所有者或编辑者)
 //   - id: 款项ID
-//   - actualDate: 实际收款日期字符串
-//   - method: 收款方式 (如 银行转账, 支付宝)
+//   - input: 到账日期、金额、收款方式 (如 银行转账, 支付宝) 与流水号
 //
 // 返回:
-//   - error: 事务执行失败
-func (s *PaymentService) Confirm(id int64, actualDate, method string) error {
-	return database.GetDB().Transaction(func(tx *gorm.DB) error {
+//   - *models.PaymentReceipt: 本次登记的收款记录
+//   - error: 无权操作、金额无效或事务执行失败
+func (s *PaymentService) Confirm(actor audit.Actor, id int64, input dto.ConfirmPaymentRequest) (*models.PaymentReceipt, error) {
+	before, err := s.authorizePayment(actor.UserID, id, ProjectRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	receivedDate, err := time.Parse("2006-01-02", input.ActualDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 到账日期格式应为 YYYY-MM-DD", ErrReceiptInvalid)
+	}
+	if input.Amount < 0 {
+		return nil, fmt.Errorf("%w: 收款金额不能为负数", ErrReceiptInvalid)
+	}
+
+	var receipt *models.PaymentReceipt
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
 		// 1. 锁定并获取当前收款记录 (防止并发修改)
 		var payment models.Payment
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, id).Error; err != nil {
@@ -257,36 +337,56 @@ func (s *PaymentService) Confirm(id int64, actualDate, method string) error {
 		}
 
 		// 2. 幂等性检查: 防止重复确认
-		if payment.Status == "paid" {
-			return nil
+		remaining := payment.Amount - payment.ReceivedAmount
+		if remaining <= 0 {
+			if input.Amount == 0 {
+				return nil
+			}
+			return fmt.Errorf("%w: 该款项已收齐", ErrReceiptInvalid)
 		}
 
-		// 3. 更新收款状态为"已收款"
-		if err := tx.Model(&payment).Updates(map[string]interface{}{
-			"status":      "paid",
-			"actual_date": actualDate,
-			"method":      method,
-		}).Error; err != nil {
-			return err
+		// 3. 写入收款记录
+		amount := input.Amount
+		if amount == 0 {
+			amount = remaining
 		}
-
-		// 4. 同步计算项目已收款总额
-		// 注意: 必须使用当前事务 tx 进行查询，否则读不到刚才更新的状态
-		var totalReceived float64
-		if err := tx.Model(&models.Payment{}).
-			Where("project_id = ? AND status = ?", payment.ProjectID, "paid").
-			Select("COALESCE(SUM(amount), 0)").
-			Scan(&totalReceived).Error; err != nil {
-			return err
+		if amount > remaining {
+			return fmt.Errorf("%w: 超出未收金额 %s", ErrReceiptInvalid, remaining)
 		}
-
-		// 5. 更新项目主表 sum 值
-		if err := tx.Model(&models.Project{}).
-			Where("id = ?", payment.ProjectID).
-			Update("received_amount", totalReceived).Error; err != nil {
+		receipt = &models.PaymentReceipt{
+			PaymentID:    payment.ID,
+			ProjectID:    payment.ProjectID,
+			Amount:       amount,
+			ReceivedDate: receivedDate,
+			Method:       input.Method,
+			Reference:    input.Reference,
+			UserID:       actor.UserID,
+		}
+		if err := s.receiptRepo.WithTx(tx).Create(receipt); err != nil {
 			return err
 		}
 
-		return nil
+		// 4-5. 重新推导款项收款状态并同步项目已收款总额
+		// 注意: 必须使用当前事务 tx 进行查询，否则读不到刚才写入的收款记录
+		return s.refreshReceived(tx, &payment)
 	})
+	if err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		latest, err := s.receiptRepo.Latest(id)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return latest, nil
+	}
+
+	after, err := s.paymentRepo.FindByID(id)
+	if err != nil {
+		return receipt, nil
+	}
+	s.auditService.Record(actor, audit.ActionCreate, audit.EntityPaymentReceipt, receipt.ID, nil, receipt)
+	s.auditService.Record(actor, audit.ActionConfirm, audit.EntityPayment, id, before, after)
+	s.revisionService.Record(actor, audit.EntityPayment, id, audit.ActionConfirm, paymentSnapshot(before), paymentSnapshot(after))
+	return receipt, nil
 }
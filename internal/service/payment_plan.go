@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
+	"github.com/FruitsAI/Orange/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+// ErrPlanRebalance 无法按合同总金额调整收款计划
+var ErrPlanRebalance = errors.New("无法调整收款计划")
+
+// paymentChange 收款计划调整前后的款项
+type paymentChange struct {
+	before models.Payment
+	after  models.Payment
+}
+
+// ValidatePlan 校验项目收款计划 (需为项目成员)
+// 比较各期款项合计与有效合同金额 (含已批准的变更单)，报告未分配或超额分配的金额。
+func (s *PaymentService) ValidatePlan(userID, projectID int64) (*dto.PaymentPlan, error) {
+	project, err := s.access.authorize(userID, projectID, ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.paymentRepo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return paymentPlan(project, payments), nil
+}
+
+// RebalancePlan 按合同总金额等比例调整待收款项 (需为项目所有者或编辑者)
+// 规则见 rebalance；调整后的款项各保存一个历史版本。
+func (s *PaymentService) RebalancePlan(actor audit.Actor, projectID int64) (*dto.PaymentPlan, error) {
+	project, err := s.access.authorize(actor.UserID, projectID, ProjectRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []paymentChange
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		changes, err = s.rebalance(tx, project)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.recordRebalanced(actor, changes)
+	return s.ValidatePlan(actor.UserID, projectID)
+}
+
+// rebalance 在事务中等比例调整待收款项，使各期款项合计等于合同总金额
+//   - 参与调整: 尚无收款记录、且不是由周期收款计划生成的款项
+//   - 保持不变: 已收款、部分收款及周期计划生成的款项，其合计为固定金额
+//   - 待收款项按原金额比例分摊 (合同总金额 - 固定金额)，四舍五入到分，最晚到期的一期取余额
+//
+// 款项合计已等于合同总金额时不做调整。
+func (s *PaymentService) rebalance(tx *gorm.DB, project *models.Project) ([]paymentChange, error) {
+	payments, err := s.paymentRepo.WithTx(tx).ListByProject(project.ID)
+	if err != nil {
+		return nil, err
+	}
+	plan := paymentPlan(project, payments)
+	if plan.Status == dto.PaymentPlanBalanced {
+		return nil, nil
+	}
+	if plan.AdjustableCount == 0 {
+		return nil, fmt.Errorf("%w: 没有可调整的待收款项", ErrPlanRebalance)
+	}
+	remaining := project.ContractAmount() - plan.FixedAmount
+	if remaining < money.Amount(plan.AdjustableCount) {
+		return nil, fmt.Errorf("%w: 已收及固定款项合计 %s，合同总金额 %s 已无可分摊余额", ErrPlanRebalance, plan.FixedAmount, project.ContractAmount())
+	}
+
+	repo := s.paymentRepo.WithTx(tx)
+	var changes []paymentChange
+	var allocated money.Amount
+	adjusted := 0
+	// 款项按计划日期倒序排列，这里按计划日期顺序分摊，最晚到期的一期取余额
+	for i := len(payments) - 1; i >= 0; i-- {
+		payment := &payments[i]
+		if !adjustablePayment(payment) {
+			continue
+		}
+		adjusted++
+
+		var amount money.Amount
+		switch {
+		case adjusted == plan.AdjustableCount:
+			amount = remaining - allocated
+		case plan.AdjustableAmount > 0:
+			amount = payment.Amount.Portion(remaining.Percent(plan.AdjustableAmount))
+		default:
+			amount = remaining / money.Amount(plan.AdjustableCount)
+		}
+		if amount <= 0 {
+			return nil, fmt.Errorf("%w: 调整后款项「%s」的金额必须大于 0", ErrPlanRebalance, payment.Stage)
+		}
+		allocated += amount
+		if amount == payment.Amount {
+			continue
+		}
+
+		before := *payment
+		payment.Amount = amount
+		applyPaymentRules(payment, project)
+		if err := repo.UpdateAmount(payment.ID, payment.Amount, payment.Percentage); err != nil {
+			return nil, err
+		}
+		changes = append(changes, paymentChange{before: before, after: *payment})
+	}
+	return changes, nil
+}
+
+// refreshPercentages 在事务中按有效合同金额重新计算项目全部款项 (含回收站中的款项) 的占比
+func (s *PaymentService) refreshPercentages(tx *gorm.DB, project *models.Project) error {
+	repo := s.paymentRepo.WithTx(tx)
+	payments, err := repo.ListAllByProject(project.ID)
+	if err != nil {
+		return err
+	}
+	for i := range payments {
+		payment := &payments[i]
+		percentage := payment.Percentage
+		applyPaymentRules(payment, project)
+		if payment.Percentage == percentage {
+			continue
+		}
+		if err := repo.UpdateAmount(payment.ID, payment.Amount, payment.Percentage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordRebalanced 记录收款计划调整的审计日志与款项历史版本
+func (s *PaymentService) recordRebalanced(actor audit.Actor, changes []paymentChange) {
+	for i := range changes {
+		change := &changes[i]
+		s.auditService.Record(actor, audit.ActionUpdate, audit.EntityPayment, change.after.ID, &change.before, &change.after)
+		s.revisionService.Record(actor, audit.EntityPayment, change.after.ID, audit.ActionUpdate, paymentSnapshot(&change.before), paymentSnapshot(&change.after))
+	}
+}
+
+// adjustablePayment 判断款项是否可在收款计划调整中改变金额
+func adjustablePayment(payment *models.Payment) bool {
+	return payment.ReceivedAmount == 0 && payment.Status != models.PaymentStatusPaid && payment.ScheduleID == nil
+}
+
+// paymentPlan 汇总项目的收款计划
+func paymentPlan(project *models.Project, payments []models.Payment) *dto.PaymentPlan {
+	plan := &dto.PaymentPlan{
+		ProjectID:   project.ID,
+		TotalAmount: project.ContractAmount(),
+	}
+	for i := range payments {
+		payment := &payments[i]
+		plan.PlannedAmount += payment.Amount
+		if adjustablePayment(payment) {
+			plan.AdjustableAmount += payment.Amount
+			plan.AdjustableCount++
+		} else {
+			plan.FixedAmount += payment.Amount
+		}
+	}
+	plan.PlannedPercentage = plan.PlannedAmount.Percent(project.ContractAmount())
+	plan.Difference = project.ContractAmount() - plan.PlannedAmount
+
+	switch {
+	case plan.Difference == 0:
+		plan.Status = dto.PaymentPlanBalanced
+	case plan.Difference > 0:
+		plan.Status = dto.PaymentPlanUnderAllocated
+	default:
+		plan.Status = dto.PaymentPlanOverAllocated
+	}
+	return plan
+}
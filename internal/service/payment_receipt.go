@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
+	"gorm.io/gorm"
+)
+
+// ListReceipts 获取款项的收款记录 (需为项目成员)，按到账日期倒序
+func (s *PaymentService) ListReceipts(userID, paymentID int64) ([]models.PaymentReceipt, error) {
+	if _, err := s.authorizePayment(userID, paymentID, ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.receiptRepo.ListByPayment(paymentID)
+}
+
+// DeleteReceipt 删除收款记录 (需为项目所有者或编辑者)
+// 用于撤销登记错误的收款；删除后重新推导款项的已收金额与状态，并同步项目已收款总额。
+// 删除后的已收金额不能少于已退金额。
+func (s *PaymentService) DeleteReceipt(actor audit.Actor, paymentID, receiptID int64) error {
+	before, err := s.authorizePayment(actor.UserID, paymentID, ProjectRoleEditor)
+	if err != nil {
+		return err
+	}
+	receipt, err := s.receiptRepo.FindByID(receiptID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && receipt.PaymentID != paymentID) {
+		return ErrReceiptNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	after := *before
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := s.receiptRepo.WithTx(tx).Delete(receiptID); err != nil {
+			return err
+		}
+		return s.refreshReceived(tx, &after)
+	})
+	if err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionDelete, audit.EntityPaymentReceipt, receiptID, receipt, nil)
+	s.revisionService.Record(actor, audit.EntityPayment, paymentID, audit.ActionUpdate, paymentSnapshot(before), paymentSnapshot(&after))
+	return nil
+}
+
+// settle 在事务中为款项登记收款并重新推导收款状态 (创建、更新款项时调用)
+// status 为 "paid" 时为未收部分按计划日期补录一笔收款记录。
+func (s *PaymentService) settle(tx *gorm.DB, actor audit.Actor, payment *models.Payment, status string) error {
+	if status == models.PaymentStatusPaid {
+		if remaining := payment.Amount - payment.ReceivedAmount; remaining > 0 {
+			receipt := &models.PaymentReceipt{
+				PaymentID:    payment.ID,
+				ProjectID:    payment.ProjectID,
+				Amount:       remaining,
+				ReceivedDate: payment.PlanDate,
+				Method:       payment.Method,
+				UserID:       actor.UserID,
+			}
+			if err := s.receiptRepo.WithTx(tx).Create(receipt); err != nil {
+				return err
+			}
+		}
+	}
+	return s.refreshReceived(tx, payment)
+}
+
+// refreshReceived 根据收款与退款记录重新推导款项的收款字段，并同步项目的实收净额
+//   - received_amount / received_percentage: 收款记录金额之和及其占本期金额的百分比
+//   - refunded_amount: 退款记录金额之和，不能超过已收金额
+//   - status: 无收款记录为 pending，未收齐为 partially_paid，收齐为 paid (退款不改变状态)
+//   - actual_date / method: 取最近一笔收款记录 (无收款记录时清空实际收款日期)
+//
+// payment 的对应字段同步更新为推导结果。
+func (s *PaymentService) refreshReceived(tx *gorm.DB, payment *models.Payment) error {
+	receipts := s.receiptRepo.WithTx(tx)
+	received, err := receipts.SumByPayment(payment.ID)
+	if err != nil {
+		return err
+	}
+	latest, err := receipts.Latest(payment.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	refunded, err := s.refundRepo.WithTx(tx).SumByPayment(payment.ID)
+	if err != nil {
+		return err
+	}
+	if refunded > received {
+		return fmt.Errorf("%w: 已收金额 %s 少于已退金额 %s，请先删除对应的退款记录", ErrReceiptInvalid, received, refunded)
+	}
+
+	payment.ReceivedAmount = received
+	payment.RefundedAmount = refunded
+	payment.ReceivedPercentage = received.Percent(payment.Amount)
+	switch {
+	case latest == nil:
+		payment.Status = models.PaymentStatusPending
+		payment.ActualDate = nil
+	case payment.ReceivedAmount >= payment.Amount:
+		payment.Status = models.PaymentStatusPaid
+	default:
+		payment.Status = models.PaymentStatusPartiallyPaid
+	}
+	if latest != nil {
+		actualDate := latest.ReceivedDate
+		payment.ActualDate = &actualDate
+		payment.Method = latest.Method
+	}
+
+	if err := tx.Model(&models.Payment{}).Where("id = ?", payment.ID).Updates(map[string]interface{}{
+		"received_amount":     payment.ReceivedAmount,
+		"received_percentage": payment.ReceivedPercentage,
+		"refunded_amount":     payment.RefundedAmount,
+		"status":              payment.Status,
+		"actual_date":         payment.ActualDate,
+		"method":              payment.Method,
+	}).Error; err != nil {
+		return err
+	}
+
+	totalReceived, err := s.paymentRepo.WithTx(tx).SumPaidByProject(payment.ProjectID)
+	if err != nil {
+		return err
+	}
+	return tx.Model(&models.Project{}).
+		Where("id = ?", payment.ProjectID).
+		Update("received_amount", totalReceived).Error
+}
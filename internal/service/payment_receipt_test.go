@@ -0,0 +1,163 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/money"
+)
+
+func TestPaymentServiceRefreshReceived(t *testing.T) {
+	s := NewPaymentService()
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.Local) }
+
+	type receipt struct {
+		amount money.Amount
+		date   time.Time
+		method string
+	}
+	tests := []struct {
+		name         string
+		receipts     []receipt
+		refunds      []money.Amount
+		wantErr      error
+		wantReceived money.Amount
+		wantRefunded money.Amount
+		wantPercent  float64
+		wantStatus   string
+		wantActual   *time.Time
+		wantMethod   string
+		wantProject  money.Amount // 项目实收净额 (另有一笔已收 100 元的款项)
+	}{
+		{
+			name:        "无收款记录",
+			wantStatus:  models.PaymentStatusPending,
+			wantProject: 10000,
+		},
+		{
+			name:         "部分收款",
+			receipts:     []receipt{{amount: 30000, date: day(1), method: "cash"}},
+			wantReceived: 30000,
+			wantPercent:  30,
+			wantStatus:   models.PaymentStatusPartiallyPaid,
+			wantActual:   ptrTime(day(1)),
+			wantMethod:   "cash",
+			wantProject:  40000,
+		},
+		{
+			name: "多笔收款收齐，取最近一笔的日期与方式",
+			receipts: []receipt{
+				{amount: 60000, date: day(5), method: "alipay"},
+				{amount: 40000, date: day(2), method: "cash"},
+			},
+			wantReceived: 100000,
+			wantPercent:  100,
+			wantStatus:   models.PaymentStatusPaid,
+			wantActual:   ptrTime(day(5)),
+			wantMethod:   "alipay",
+			wantProject:  110000,
+		},
+		{
+			name:         "退款不改变收款状态，冲减项目实收净额",
+			receipts:     []receipt{{amount: 100000, date: day(3), method: "bank_transfer"}},
+			refunds:      []money.Amount{20000, 5000},
+			wantReceived: 100000,
+			wantRefunded: 25000,
+			wantPercent:  100,
+			wantStatus:   models.PaymentStatusPaid,
+			wantActual:   ptrTime(day(3)),
+			wantMethod:   "bank_transfer",
+			wantProject:  85000,
+		},
+		{
+			name:     "退款超过已收金额",
+			receipts: []receipt{{amount: 10000, date: day(1), method: "cash"}},
+			refunds:  []money.Amount{10001},
+			wantErr:  ErrReceiptInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := database.GetDB().Begin()
+			defer tx.Rollback()
+
+			project := models.Project{
+				Name: "测试项目", Company: "测试客户", TotalAmount: 200000, Status: "active", Type: "web",
+				StartDate: day(1), EndDate: day(28), UserID: testUserID,
+			}
+			mustExec(t, tx.Create(&project).Error)
+			other := models.Payment{
+				ProjectID: project.ID, Stage: "deposit", Amount: 10000, PlanDate: day(1),
+				Status: models.PaymentStatusPaid, ReceivedAmount: 10000, UserID: testUserID,
+			}
+			mustExec(t, tx.Create(&other).Error)
+			payment := models.Payment{
+				ProjectID: project.ID, Stage: "final", Amount: 100000, PlanDate: day(10),
+				Status: models.PaymentStatusPending, UserID: testUserID,
+			}
+			mustExec(t, tx.Create(&payment).Error)
+			for _, r := range tt.receipts {
+				mustExec(t, tx.Create(&models.PaymentReceipt{
+					PaymentID: payment.ID, ProjectID: project.ID, Amount: r.amount,
+					ReceivedDate: r.date, Method: r.method, UserID: testUserID,
+				}).Error)
+			}
+			for _, amount := range tt.refunds {
+				mustExec(t, tx.Create(&models.PaymentRefund{
+					PaymentID: payment.ID, ProjectID: project.ID, Type: models.PaymentRefundTypeRefund,
+					Amount: amount, RefundDate: day(20), UserID: testUserID,
+				}).Error)
+			}
+
+			err := s.refreshReceived(tx, &payment)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("refreshReceived() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			var stored models.Payment
+			mustExec(t, tx.First(&stored, payment.ID).Error)
+			for _, got := range []models.Payment{payment, stored} {
+				if got.ReceivedAmount != tt.wantReceived || got.RefundedAmount != tt.wantRefunded {
+					t.Errorf("received/refunded = %d/%d, want %d/%d", got.ReceivedAmount, got.RefundedAmount, tt.wantReceived, tt.wantRefunded)
+				}
+				if math.Abs(got.ReceivedPercentage-tt.wantPercent) > 1e-9 {
+					t.Errorf("received percentage = %v, want %v", got.ReceivedPercentage, tt.wantPercent)
+				}
+				if got.Status != tt.wantStatus {
+					t.Errorf("status = %q, want %q", got.Status, tt.wantStatus)
+				}
+				if got.Method != tt.wantMethod {
+					t.Errorf("method = %q, want %q", got.Method, tt.wantMethod)
+				}
+				if !sameDate(got.ActualDate, tt.wantActual) {
+					t.Errorf("actual date = %v, want %v", got.ActualDate, tt.wantActual)
+				}
+			}
+
+			var storedProject models.Project
+			mustExec(t, tx.First(&storedProject, project.ID).Error)
+			if storedProject.ReceivedAmount != tt.wantProject {
+				t.Errorf("project received amount = %d, want %d", storedProject.ReceivedAmount, tt.wantProject)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
+// sameDate 比较两个可空日期是否为同一天
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
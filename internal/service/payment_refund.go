@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ListRefunds 获取款项的退款记录 (需为项目成员)，按退款日期倒序
+func (s *PaymentService) ListRefunds(userID, paymentID int64) ([]models.PaymentRefund, error) {
+	if _, err := s.authorizePayment(userID, paymentID, ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.refundRepo.ListByPayment(paymentID)
+}
+
+// Refund 为已收款项登记一笔退款或贷项通知 (需为项目所有者或编辑者)
+// 退款金额不能超过款项的实收净额 (已收金额 - 已退金额)；登记后冲减款项与项目的实收净额，
+// 款项的收款状态不变 (如需重新收取，应删除对应的收款记录)。
+//
+// 参数:
+//   - actor: 操作人
+//   - id: 款项ID
+//   - input: 类型、退款日期、金额、退款方式、流水号与原因
+//
+// 返回:
+//   - *models.PaymentRefund: 本次登记的退款记录
+//   - error: 无权操作、款项尚无收款或退款金额超出实收净额
+func (s *PaymentService) Refund(actor audit.Actor, id int64, input dto.RefundPaymentRequest) (*models.PaymentRefund, error) {
+	before, err := s.authorizePayment(actor.UserID, id, ProjectRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	refundDate, err := time.Parse("2006-01-02", input.RefundDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 退款日期格式应为 YYYY-MM-DD", ErrRefundInvalid)
+	}
+	if input.Amount <= 0 {
+		return nil, fmt.Errorf("%w: 退款金额必须大于 0", ErrRefundInvalid)
+	}
+	refundType := input.Type
+	if refundType == "" {
+		refundType = models.PaymentRefundTypeRefund
+	}
+
+	var refund *models.PaymentRefund
+	var after models.Payment
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		// 锁定款项，防止并发退款超出实收净额
+		var payment models.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, id).Error; err != nil {
+			return err
+		}
+
+		refundable := payment.ReceivedAmount - payment.RefundedAmount
+		if refundable <= 0 {
+			return fmt.Errorf("%w: 该款项没有可退的已收金额", ErrRefundInvalid)
+		}
+		if input.Amount > refundable {
+			return fmt.Errorf("%w: 超出可退金额 %s", ErrRefundInvalid, refundable)
+		}
+
+		refund = &models.PaymentRefund{
+			PaymentID:  payment.ID,
+			ProjectID:  payment.ProjectID,
+			Type:       refundType,
+			Amount:     input.Amount,
+			RefundDate: refundDate,
+			Method:     input.Method,
+			Reference:  input.Reference,
+			Reason:     input.Reason,
+			UserID:     actor.UserID,
+		}
+		if err := s.refundRepo.WithTx(tx).Create(refund); err != nil {
+			return err
+		}
+		if err := s.refreshReceived(tx, &payment); err != nil {
+			return err
+		}
+		after = payment
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 审计与历史版本以事务内刷新后的款项为准，不依赖提交后的再次查询
+	s.auditService.Record(actor, audit.ActionCreate, audit.EntityPaymentRefund, refund.ID, nil, refund)
+	s.auditService.Record(actor, audit.ActionRefund, audit.EntityPayment, id, before, &after)
+	s.revisionService.Record(actor, audit.EntityPayment, id, audit.ActionRefund, paymentSnapshot(before), paymentSnapshot(&after))
+	return refund, nil
+}
+
+// DeleteRefund 删除退款记录 (需为项目所有者或编辑者)
+// 用于撤销登记错误的退款；删除后重新计算款项的已退金额，并同步项目的实收净额。
+func (s *PaymentService) DeleteRefund(actor audit.Actor, paymentID, refundID int64) error {
+	before, err := s.authorizePayment(actor.UserID, paymentID, ProjectRoleEditor)
+	if err != nil {
+		return err
+	}
+	refund, err := s.refundRepo.FindByID(refundID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && refund.PaymentID != paymentID) {
+		return ErrRefundNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	after := *before
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := s.refundRepo.WithTx(tx).Delete(refundID); err != nil {
+			return err
+		}
+		return s.refreshReceived(tx, &after)
+	})
+	if err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionDelete, audit.EntityPaymentRefund, refundID, refund, nil)
+	s.revisionService.Record(actor, audit.EntityPayment, paymentID, audit.ActionUpdate, paymentSnapshot(before), paymentSnapshot(&after))
+	return nil
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"unicode/utf8"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+// 周期收款计划错误
+var (
+	ErrScheduleNotFound = errors.New("周期收款计划不存在")
+	ErrScheduleInvalid  = errors.New("周期收款计划无效")
+)
+
+// scheduleMonths 各周期对应的月数
+var scheduleMonths = map[string]int{
+	"monthly":   1,
+	"quarterly": 3,
+	"yearly":    12,
+}
+
+// scheduleStageMaxLen 阶段名称最大长度 (生成的款项名称需追加 " 第N期"，款项阶段字段上限为 50)
+const scheduleStageMaxLen = 40
+
+// ScheduleService 周期收款计划服务
+// 按月/季/年固定收费的项目可设置周期收款计划，由 ScheduleGenerator 在后台提前
+// PAYMENT_SCHEDULE_HORIZON_DAYS 天生成款项 (每期一条 Payment)。
+// 修改或停止计划时，仅移除尚未到期且没有收款记录的款项，已收款 (含部分收款) 与已逾期的款项保留不动。
+//
+// 依赖:
+//   - ScheduleRepository: 周期收款计划数据操作
+//   - PaymentRepository: 生成与移除款项
+//   - PaymentService: 计算款项占比
+//   - projectAccess: 项目访问控制 (计划的读写权限跟随所属项目)
+type ScheduleService struct {
+	scheduleRepo    *repository.ScheduleRepository
+	paymentRepo     *repository.PaymentRepository
+	paymentService  *PaymentService
+	access          *projectAccess
+	auditService    *AuditService
+	revisionService *RevisionService
+}
+
+// NewScheduleService 创建周期收款计划服务实例
+func NewScheduleService() *ScheduleService {
+	return &ScheduleService{
+		scheduleRepo:    repository.NewScheduleRepository(),
+		paymentRepo:     repository.NewPaymentRepository(),
+		paymentService:  NewPaymentService(),
+		access:          newProjectAccess(),
+		auditService:    NewAuditService(),
+		revisionService: NewRevisionService(),
+	}
+}
+
+// ListByProject 获取项目的周期收款计划 (需为项目成员)
+func (s *ScheduleService) ListByProject(userID, projectID int64) ([]models.PaymentSchedule, error) {
+	if _, err := s.access.authorize(userID, projectID, ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.scheduleRepo.ListByProject(projectID)
+}
+
+// Create 创建周期收款计划 (需为项目所有者或编辑者)，并立即生成生成窗口内的款项
+func (s *ScheduleService) Create(actor audit.Actor, input dto.PaymentScheduleRequest) (*models.PaymentSchedule, error) {
+	if _, err := s.access.authorize(actor.UserID, input.ProjectID, ProjectRoleEditor); err != nil {
+		return nil, err
+	}
+
+	schedule := &models.PaymentSchedule{
+		ProjectID: input.ProjectID,
+		Status:    models.PaymentScheduleActive,
+		UserID:    actor.UserID,
+	}
+	if err := applyScheduleInput(schedule, input); err != nil {
+		return nil, err
+	}
+	first := nextScheduleDate(schedule, schedule.StartDate)
+	schedule.NextDate = &first
+
+	if err := s.scheduleRepo.Create(schedule); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(actor, audit.ActionCreate, audit.EntityPaymentSchedule, schedule.ID, nil, schedule)
+
+	return s.generate(actor, schedule.ID, scheduleHorizon(time.Now()))
+}
+
+// Update 修改周期收款计划 (需为项目所有者或编辑者)
+// 移除尚未到期的待收款项后按新规则重新生成；已收款、部分收款与已逾期的款项保留，期数顺延。
+func (s *ScheduleService) Update(actor audit.Actor, id int64, input dto.PaymentScheduleRequest) (*models.PaymentSchedule, error) {
+	schedule, err := s.authorizeSchedule(actor.UserID, id, ProjectRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Status == models.PaymentScheduleStopped {
+		return nil, fmt.Errorf("%w: 已停止的计划不能修改", ErrScheduleInvalid)
+	}
+	before := *schedule
+	if err := applyScheduleInput(schedule, input); err != nil {
+		return nil, err
+	}
+
+	var purged []int64
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		payments := s.paymentRepo.WithTx(tx)
+		ids, err := payments.PurgePendingBySchedule(id, today(time.Now()))
+		if err != nil {
+			return err
+		}
+		purged = ids
+
+		// 从最近一期保留的款项之后继续生成
+		from := schedule.StartDate
+		schedule.GeneratedCount = 0
+		last, err := payments.LastBySchedule(id)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if last != nil {
+			schedule.GeneratedCount = last.ScheduleSeq
+			if after := last.PlanDate.AddDate(0, 0, 1); after.After(from) {
+				from = after
+			}
+		}
+		next := nextScheduleDate(schedule, from)
+		schedule.NextDate = &next
+		schedule.Status = models.PaymentScheduleActive
+		return s.scheduleRepo.WithTx(tx).Update(schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.recordPurged(actor, purged)
+	s.auditService.Record(actor, audit.ActionUpdate, audit.EntityPaymentSchedule, id, &before, schedule)
+
+	return s.generate(actor, id, scheduleHorizon(time.Now()))
+}
+
+// Stop 停止周期收款计划 (需为项目所有者或编辑者)
+// 不再生成新的款项，并移除尚未到期的待收款项；已生成的其他款项保留不动。
+func (s *ScheduleService) Stop(actor audit.Actor, id int64) (*models.PaymentSchedule, error) {
+	schedule, err := s.authorizeSchedule(actor.UserID, id, ProjectRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Status == models.PaymentScheduleStopped {
+		return schedule, nil
+	}
+	before := *schedule
+
+	var purged []int64
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		ids, err := s.paymentRepo.WithTx(tx).PurgePendingBySchedule(id, today(time.Now()))
+		if err != nil {
+			return err
+		}
+		purged = ids
+		schedule.Status = models.PaymentScheduleStopped
+		schedule.NextDate = nil
+		return s.scheduleRepo.WithTx(tx).Update(schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.recordPurged(actor, purged)
+	s.auditService.Record(actor, audit.ActionUpdate, audit.EntityPaymentSchedule, id, &before, schedule)
+	return schedule, nil
+}
+
+// GenerateDue 为所有到期的进行中计划生成款项 (后台任务调用)
+// 单个计划生成失败时记录日志并继续。
+//
+// 返回:
+//   - int: 生成的款项数量
+func (s *ScheduleService) GenerateDue(now time.Time) (int, error) {
+	until := scheduleHorizon(now)
+	ids, err := s.scheduleRepo.ListDueIDs(until)
+	if err != nil {
+		return 0, err
+	}
+
+	generated := 0
+	for _, id := range ids {
+		before, err := s.scheduleRepo.FindByID(id)
+		if err != nil {
+			slog.Error("周期款项生成失败", "schedule_id", id, "error", err)
+			continue
+		}
+		after, err := s.generate(systemActor, id, until)
+		if err != nil {
+			slog.Error("周期款项生成失败", "schedule_id", id, "error", err)
+			continue
+		}
+		generated += after.GeneratedCount - before.GeneratedCount
+	}
+	return generated, nil
+}
+
+// generate 生成计划日期不晚于 until 的款项 (事务，锁定计划防止重复生成)
+// 项目与款项占比均在同一事务中读取与更新；达到结束日期或总期数后计划标记为已完成。
+func (s *ScheduleService) generate(actor audit.Actor, id int64, until time.Time) (*models.PaymentSchedule, error) {
+	var schedule *models.PaymentSchedule
+	var created []models.Payment
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var err error
+		schedule, err = s.scheduleRepo.WithTx(tx).FindForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if schedule.Status != models.PaymentScheduleActive {
+			return nil
+		}
+		// 所属项目已移入回收站时跳过，项目恢复后再补生成期间到期的款项
+		project, err := s.access.projectRepo.WithTx(tx).FindByID(schedule.ProjectID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		payments := s.paymentRepo.WithTx(tx)
+		for schedule.NextDate != nil && !schedule.NextDate.After(until) && !scheduleFinished(schedule) {
+			seq := schedule.GeneratedCount + 1
+			payment := models.Payment{
+				ProjectID:   schedule.ProjectID,
+				Stage:       fmt.Sprintf("%s 第%d期", schedule.Stage, seq),
+				Amount:      schedule.Amount,
+				PlanDate:    *schedule.NextDate,
+				Status:      models.PaymentStatusPending,
+				Method:      schedule.Method,
+				Remark:      schedule.Remark,
+				UserID:      project.UserID, // 与手工录入一致，归属项目负责人
+				ScheduleID:  &schedule.ID,
+				ScheduleSeq: seq,
+			}
+			applyPaymentRules(&payment, project)
+			if err := payments.Create(&payment); err != nil {
+				return err
+			}
+			created = append(created, payment)
+
+			schedule.GeneratedCount = seq
+			next := nextScheduleDate(schedule, schedule.NextDate.AddDate(0, 0, 1))
+			schedule.NextDate = &next
+		}
+		if scheduleFinished(schedule) {
+			schedule.Status = models.PaymentScheduleCompleted
+			schedule.NextDate = nil
+		}
+		if err := s.scheduleRepo.WithTx(tx).Update(schedule); err != nil {
+			return err
+		}
+		if len(created) == 0 {
+			return nil
+		}
+		return s.paymentService.refreshPercentages(tx, project)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range created {
+		payment := &created[i]
+		s.auditService.Record(actor, audit.ActionCreate, audit.EntityPayment, payment.ID, nil, payment)
+		s.revisionService.Record(actor, audit.EntityPayment, payment.ID, audit.ActionCreate, nil, paymentSnapshot(payment))
+	}
+	return schedule, nil
+}
+
+// recordPurged 记录被移除的未到期款项
+func (s *ScheduleService) recordPurged(actor audit.Actor, ids []int64) {
+	for _, paymentID := range ids {
+		s.auditService.Record(actor, audit.ActionPurge, audit.EntityPayment, paymentID, nil, nil)
+	}
+}
+
+// authorizeSchedule 加载周期收款计划并校验用户对其所属项目的权限
+// 无访问权限时按计划不存在处理。
+func (s *ScheduleService) authorizeSchedule(userID, id int64, required string) (*models.PaymentSchedule, error) {
+	schedule, err := s.scheduleRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.access.authorize(userID, schedule.ProjectID, required); err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// applyScheduleInput 校验请求并写入计划规则字段
+func applyScheduleInput(schedule *models.PaymentSchedule, input dto.PaymentScheduleRequest) error {
+	if utf8.RuneCountInString(input.Stage) > scheduleStageMaxLen {
+		return fmt.Errorf("%w: 阶段名称不能超过 %d 个字符", ErrScheduleInvalid, scheduleStageMaxLen)
+	}
+	if input.Amount <= 0 {
+		return fmt.Errorf("%w: 每期金额必须大于 0", ErrScheduleInvalid)
+	}
+	if _, ok := scheduleMonths[input.Frequency]; !ok {
+		return fmt.Errorf("%w: 周期应为 monthly、quarterly 或 yearly", ErrScheduleInvalid)
+	}
+	if input.DayOfMonth < 1 || input.DayOfMonth > 31 {
+		return fmt.Errorf("%w: 收款日应为 1-31", ErrScheduleInvalid)
+	}
+	if input.Count < 0 {
+		return fmt.Errorf("%w: 总期数不能为负数", ErrScheduleInvalid)
+	}
+	startDate, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return fmt.Errorf("%w: 开始日期格式应为 YYYY-MM-DD", ErrScheduleInvalid)
+	}
+	var endDate *time.Time
+	if input.EndDate != "" {
+		t, err := time.Parse("2006-01-02", input.EndDate)
+		if err != nil {
+			return fmt.Errorf("%w: 结束日期格式应为 YYYY-MM-DD", ErrScheduleInvalid)
+		}
+		if t.Before(startDate) {
+			return fmt.Errorf("%w: 结束日期不能早于开始日期", ErrScheduleInvalid)
+		}
+		endDate = &t
+	}
+
+	schedule.Stage = input.Stage
+	schedule.Amount = input.Amount
+	schedule.Frequency = input.Frequency
+	schedule.DayOfMonth = input.DayOfMonth
+	schedule.StartDate = startDate
+	schedule.EndDate = endDate
+	schedule.Count = input.Count
+	schedule.Method = input.Method
+	schedule.Remark = input.Remark
+	return nil
+}
+
+// nextScheduleDate 返回按计划规则不早于 from 的第一个收款日
+// 周期以开始日期所在月份为起点 (如 1 月开始的季度计划在 1、4、7、10 月收款)，
+// 收款日超过当月天数时取月末。
+func nextScheduleDate(schedule *models.PaymentSchedule, from time.Time) time.Time {
+	months := scheduleMonths[schedule.Frequency]
+	start := schedule.StartDate
+	for i := 0; ; i += months {
+		month := time.Date(start.Year(), start.Month()+time.Month(i), 1, 0, 0, 0, 0, time.UTC)
+		day := schedule.DayOfMonth
+		if last := month.AddDate(0, 1, -1).Day(); day > last {
+			day = last
+		}
+		date := time.Date(month.Year(), month.Month(), day, 0, 0, 0, 0, time.UTC)
+		if !date.Before(start) && !date.Before(from) {
+			return date
+		}
+	}
+}
+
+// scheduleFinished 判断计划是否已达到总期数或结束日期
+func scheduleFinished(schedule *models.PaymentSchedule) bool {
+	if schedule.Count > 0 && schedule.GeneratedCount >= schedule.Count {
+		return true
+	}
+	return schedule.NextDate != nil && schedule.EndDate != nil && schedule.NextDate.After(*schedule.EndDate)
+}
+
+// scheduleHorizon 返回生成窗口的截止日期 (今天 + PAYMENT_SCHEDULE_HORIZON_DAYS)
+func scheduleHorizon(now time.Time) time.Time {
+	days := config.AppConfig.PaymentScheduleHorizonDays
+	if days < 0 {
+		days = 0
+	}
+	return today(now).AddDate(0, 0, days)
+}
+
+// today 返回 now 所在日期 (与款项计划日期一致，按 UTC 零点表示)
+func today(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -1,12 +1,14 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/FruitsAI/Orange/internal/database"
 	"github.com/FruitsAI/Orange/internal/dto"
 	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
 	"github.com/FruitsAI/Orange/internal/repository"
 	"gorm.io/gorm"
 )
@@ -18,9 +20,20 @@ import (
 // 依赖:
 //   - ProjectRepository: 项目数据持久化接口
 //   - PaymentRepository: 款项数据持久化接口
+//   - ProjectMemberRepository: 项目成员 (共享) 数据接口
+//   - AuditService: 记录数据变更审计日志
+//   - RevisionService: 保存项目历史版本
+//   - PaymentService: 恢复版本后重新同步已收款总额
 type ProjectService struct {
-	projectRepo *repository.ProjectRepository
-	paymentRepo *repository.PaymentRepository
+	projectRepo         *repository.ProjectRepository
+	paymentRepo         *repository.PaymentRepository
+	memberRepo          *repository.ProjectMemberRepository
+	userRepo            *repository.UserRepository
+	notificationService *NotificationService
+	auditService        *AuditService
+	revisionService     *RevisionService
+	paymentService      *PaymentService
+	access              *projectAccess
 }
 
 // NewProjectService 创建并初始化项目服务实例
@@ -29,13 +42,21 @@ type ProjectService struct {
 //   - *ProjectService: 包含已初始化 Repository 的服务实例
 func NewProjectService() *ProjectService {
 	return &ProjectService{
-		projectRepo: repository.NewProjectRepository(),
-		paymentRepo: repository.NewPaymentRepository(),
+		projectRepo:         repository.NewProjectRepository(),
+		paymentRepo:         repository.NewPaymentRepository(),
+		memberRepo:          repository.NewProjectMemberRepository(),
+		userRepo:            repository.NewUserRepository(),
+		notificationService: NewNotificationService(),
+		auditService:        NewAuditService(),
+		revisionService:     NewRevisionService(),
+		paymentService:      NewPaymentService(),
+		access:              newProjectAccess(),
 	}
 }
 
 // List 分页获取项目列表
-// 支持根据用户ID、项目状态和关键词进行过滤查询。
+// 支持根据用户ID、项目状态和关键词进行过滤查询。结果包含共享给该用户的项目，
+// 每个项目的 Role 字段为当前用户在其中的角色。
 //
 // 参数:
 //   - userID: 当前用户ID，强制数据隔离
@@ -56,11 +77,38 @@ func (s *ProjectService) List(userID int64, status, keyword string, page, pageSi
 		pageSize = 10
 	}
 
+	// 拥有 projects:read_all 权限时查询全部项目
+	includeAll, err := s.access.canReadAll(userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// 执行查询
-	projects, total, err := s.projectRepo.List(userID, status, keyword, page, pageSize)
+	projects, total, err := s.projectRepo.List(userID, includeAll, status, keyword, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// 标注当前用户角色 (负责的项目为所有者，其余为共享成员角色，非成员项目为查看者)
+	var sharedIDs []int64
+	for _, p := range projects {
+		if p.UserID != userID {
+			sharedIDs = append(sharedIDs, p.ID)
+		}
+	}
+	roles, err := s.memberRepo.RolesByUser(userID, sharedIDs)
 	if err != nil {
 		return nil, err
 	}
+	for i := range projects {
+		if projects[i].UserID == userID {
+			projects[i].Role = ProjectRoleOwner
+		} else if role, ok := roles[projects[i].ID]; ok {
+			projects[i].Role = role
+		} else {
+			projects[i].Role = ProjectRoleViewer
+		}
+	}
 
 	// 组装返回结果
 	return &dto.ProjectListResult{
@@ -75,26 +123,55 @@ func (s *ProjectService) List(userID int64, status, keyword string, page, pageSi
 // 根据项目ID获取单个项目的详细信息，并默认包含该项目关联的所有款项数据。
 //
 // 参数:
+//   - userID: 当前用户ID (需为项目成员)
 //   - id: 项目ID
 //
 // 返回:
 //   - *models.Project: 项目实体（包含 Preloaded Payments）
-//   - error: 记录不存在或数据库错误
-func (s *ProjectService) Get(id int64) (*models.Project, error) {
+//   - error: 记录不存在、无权访问或数据库错误
+func (s *ProjectService) Get(userID, id int64) (*models.Project, error) {
 	// 使用 FindByIDWithPayments 确保在详情页能展示关联的收款计划
-	return s.projectRepo.FindByIDWithPayments(id)
+	project, err := s.projectRepo.FindByIDWithPayments(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.check(userID, project, ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	return project, nil
 }
 
 // Create 创建新项目
 // 接收前端表单数据，进行日期解析和默认值处理后，将项目存入数据库。
 //
 // 参数:
+//   - actor: 操作人
 //   - input: 创建项目的请求DTO，包含前端传递的所有表单字段
 //
 // 返回:
 //   - *models.Project: 创建成功的项目实体
 //   - error: 日期解析失败或数据库写入错误
-func (s *ProjectService) Create(input dto.CreateProjectRequest) (*models.Project, error) {
+func (s *ProjectService) Create(actor audit.Actor, input dto.CreateProjectRequest) (*models.Project, error) {
+	project, err := newProject(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// 持久化到数据库
+	if err := s.projectRepo.Create(project); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(actor, audit.ActionCreate, audit.EntityProject, project.ID, nil, project)
+	s.revisionService.Record(actor, audit.EntityProject, project.ID, audit.ActionCreate, nil, projectSnapshot(project))
+
+	return project, nil
+}
+
+// newProject 根据请求构建项目实体 (解析日期字段并设置默认状态)
+func newProject(input dto.CreateProjectRequest) (*models.Project, error) {
 	// 1. 日期字段解析 (字符串 "YYYY-MM-DD" -> time.Time)
 	startDate, err := time.Parse("2006-01-02", input.StartDate)
 	if err != nil {
@@ -136,27 +213,24 @@ func (s *ProjectService) Create(input dto.CreateProjectRequest) (*models.Project
 		project.Status = "active"
 	}
 
-	// 4. 持久化到数据库
-	if err := s.projectRepo.Create(project); err != nil {
-		return nil, err
-	}
-
 	return project, nil
 }
 
 // Update 更新项目详情
-// 根据项目ID更新指定字段。
+// 根据项目ID更新指定字段。合同总金额变化后，全部款项的占比在同一事务中重新计算；
+// input.RebalancePayments 为 true 时还会等比例调整待收款项，使各期款项合计等于新的合同总金额。
 //
 // 参数:
+//   - actor: 操作人 (需为所有者或编辑者)
 //   - id: 项目ID
 //   - input: 更新请求DTO
 //
 // 返回:
 //   - *models.Project: 更新后的项目实体
-//   - error: 记录不存在或更新失败
-func (s *ProjectService) Update(id int64, input dto.CreateProjectRequest) (*models.Project, error) {
-	// 1. 检查是否存在
-	project, err := s.projectRepo.FindByID(id)
+//   - error: 记录不存在、无权操作、无法调整收款计划或更新失败
+func (s *ProjectService) Update(actor audit.Actor, id int64, input dto.CreateProjectRequest) (*models.Project, error) {
+	// 1. 检查是否存在及编辑权限
+	authorized, err := s.access.authorize(actor.UserID, id, ProjectRoleEditor)
 	if err != nil {
 		return nil, err
 	}
@@ -179,53 +253,90 @@ func (s *ProjectService) Update(id int64, input dto.CreateProjectRequest) (*mode
 		contractDate = &t
 	}
 
-	// 3. 更新实体字段
-	project.Name = input.Name
-	project.Company = input.Company
-	project.TotalAmount = input.TotalAmount
-	project.Status = input.Status
-	project.Type = input.Type
-	project.ContractNumber = input.ContractNumber
-	project.ContractDate = contractDate
-	project.PaymentMethod = input.PaymentMethod
-	project.StartDate = startDate
-	project.EndDate = endDate
-	project.Description = input.Description
-
-	// 4. 执行数据库更新
-	if err := s.projectRepo.Update(project); err != nil {
+	// 3. 在事务内重新读取并锁定项目后更新字段 (已收款与变更金额以最新值为准)，
+	//    并在同一事务中按新的合同总金额调整收款计划 (可选) 与重新计算款项占比
+	var project *models.Project
+	var before models.Project
+	var rebalanced []paymentChange
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		locked, err := s.projectRepo.WithTx(tx).FindForUpdate(id)
+		if err != nil {
+			return err
+		}
+		locked.Role = authorized.Role
+		before = *locked
+		project = locked
+
+		project.Name = input.Name
+		project.Company = input.Company
+		project.TotalAmount = input.TotalAmount
+		project.Status = input.Status
+		project.Type = input.Type
+		project.ContractNumber = input.ContractNumber
+		project.ContractDate = contractDate
+		project.PaymentMethod = input.PaymentMethod
+		project.StartDate = startDate
+		project.EndDate = endDate
+		project.Description = input.Description
+		if err := s.projectRepo.WithTx(tx).Update(project); err != nil {
+			return err
+		}
+		if input.RebalancePayments {
+			changes, err := s.paymentService.rebalance(tx, project)
+			if err != nil {
+				return err
+			}
+			rebalanced = changes
+		}
+		return s.paymentService.refreshPercentages(tx, project)
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.auditService.Record(actor, audit.ActionUpdate, audit.EntityProject, project.ID, &before, project)
+	s.revisionService.Record(actor, audit.EntityProject, project.ID, audit.ActionUpdate, projectSnapshot(&before), projectSnapshot(project))
+	s.paymentService.recordRebalanced(actor, rebalanced)
 
 	return project, nil
 }
 
-// Delete 删除项目及关联数据
-// 这是一个事务操作，会同时删除项目本身及其下属的所有款项记录。
+// Delete 删除项目 (移入回收站)
+// 项目及其下属的所有款项在同一事务中移入回收站，共享成员保留以便恢复。仅所有者可删除。
 //
 // 参数:
+//   - actor: 操作人 (需为项目所有者)
 //   - id: 待删除的项目ID
 //
 // 返回:
-//   - error: 事务执行错误
-func (s *ProjectService) Delete(id int64) error {
-	return database.GetDB().Transaction(func(tx *gorm.DB) error {
-		// 1. 级联删除: 先删除项目关联的所有款项 (Payments)
-		if err := tx.Where("project_id = ?", id).Delete(&models.Payment{}).Error; err != nil {
-			return err
-		}
-		// 2. 主体删除: 删除项目本身
-		if err := tx.Delete(&models.Project{}, id).Error; err != nil {
-			return err
-		}
-		return nil
-	})
+//   - error: 无权操作或事务执行错误
+func (s *ProjectService) Delete(actor audit.Actor, id int64) error {
+	project, err := s.access.authorize(actor.UserID, id, ProjectRoleOwner)
+	if err != nil {
+		return err
+	}
+
+	if err := s.projectRepo.Delete(id); err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionDelete, audit.EntityProject, id, project, nil)
+	return nil
 }
 
 // Archive 归档项目
-// 将项目状态更新为 "archived"，归档后的项目通常只读或不显示在主列表中。
-func (s *ProjectService) Archive(id int64) error {
-	return s.projectRepo.UpdateStatus(id, "archived")
+// 将项目状态更新为 "archived"，归档后的项目通常只读或不显示在主列表中。需要编辑权限。
+func (s *ProjectService) Archive(actor audit.Actor, id int64) error {
+	project, err := s.access.authorize(actor.UserID, id, ProjectRoleEditor)
+	if err != nil {
+		return err
+	}
+	if err := s.projectRepo.UpdateStatus(id, "archived"); err != nil {
+		return err
+	}
+	after := *project
+	after.Status = "archived"
+	s.auditService.Record(actor, audit.ActionArchive, audit.EntityProject, id, project, &after)
+	s.revisionService.Record(actor, audit.EntityProject, id, audit.ActionArchive, projectSnapshot(project), projectSnapshot(&after))
+	return nil
 }
 
 // CheckContractNumberExists 检查合同编号是否在库中已存在
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
+	"github.com/FruitsAI/Orange/internal/pkg/permission"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+// 项目成员角色
+const (
+	ProjectRoleOwner  = "owner"  // 所有者 (项目负责人): 全部权限，可管理成员、删除项目
+	ProjectRoleEditor = "editor" // 编辑者: 可查看、修改项目及款项
+	ProjectRoleViewer = "viewer" // 查看者: 只读
+)
+
+// 项目访问错误
+var (
+	ErrProjectNotFound  = errors.New("项目不存在")
+	ErrProjectForbidden = errors.New("无权执行该操作")
+)
+
+// projectRoleLevel 角色权限等级，数值越大权限越高
+var projectRoleLevel = map[string]int{
+	ProjectRoleViewer: 1,
+	ProjectRoleEditor: 2,
+	ProjectRoleOwner:  3,
+}
+
+// projectRoleLabels 角色显示名称
+var projectRoleLabels = map[string]string{
+	ProjectRoleOwner:  "所有者",
+	ProjectRoleEditor: "编辑者",
+	ProjectRoleViewer: "查看者",
+}
+
+// projectAccess 项目访问控制
+// 由 ProjectService 与 PaymentService 共用，所有项目与款项的读写都需经过校验。
+// 拥有 projects:read_all 权限的用户 (如审计员) 对非成员项目按查看者处理。
+type projectAccess struct {
+	projectRepo *repository.ProjectRepository
+	memberRepo  *repository.ProjectMemberRepository
+	roles       *RoleService
+}
+
+// newProjectAccess 创建项目访问控制实例
+func newProjectAccess() *projectAccess {
+	return &projectAccess{
+		projectRepo: repository.NewProjectRepository(),
+		memberRepo:  repository.NewProjectMemberRepository(),
+		roles:       NewRoleService(),
+	}
+}
+
+// canReadAll 判断用户是否可只读查看全部项目
+func (a *projectAccess) canReadAll(userID int64) (bool, error) {
+	role, err := a.roles.UserRole(userID)
+	if err != nil {
+		return false, nil // 用户不存在或已禁用，视为无此权限
+	}
+	return a.roles.HasPermission(role, permission.ProjectsReadAll)
+}
+
+// roleOf 获取用户在项目中的角色 (无访问权限时返回空字符串)
+func (a *projectAccess) roleOf(userID int64, project *models.Project) (string, error) {
+	if project.UserID == userID {
+		return ProjectRoleOwner, nil
+	}
+	member, err := a.memberRepo.Find(project.ID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return member.Role, nil
+}
+
+// check 校验用户对已加载项目的权限，通过后写入 project.Role
+// 无任何访问权限时按项目不存在处理，避免泄露他人项目是否存在。
+func (a *projectAccess) check(userID int64, project *models.Project, required string) error {
+	role, err := a.roleOf(userID, project)
+	if err != nil {
+		return err
+	}
+	if role == "" {
+		readAll, err := a.canReadAll(userID)
+		if err != nil {
+			return err
+		}
+		if !readAll {
+			return ErrProjectNotFound
+		}
+		role = ProjectRoleViewer
+	}
+	if projectRoleLevel[role] < projectRoleLevel[required] {
+		return ErrProjectForbidden
+	}
+	project.Role = role
+	return nil
+}
+
+// authorize 加载项目并校验用户权限
+//
+// 参数:
+//   - userID: 当前用户ID
+//   - projectID: 项目ID
+//   - required: 所需的最低角色
+//
+// 返回:
+//   - *models.Project: 项目实体 (Role 为当前用户角色)
+//   - error: ErrProjectNotFound / ErrProjectForbidden 或数据库错误
+func (a *projectAccess) authorize(userID, projectID int64, required string) (*models.Project, error) {
+	project, err := a.projectRepo.FindByID(projectID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := a.check(userID, project, required); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// ListMembers 获取项目成员列表
+// 列表首项为项目所有者 (负责人)，其后为共享成员。项目的任何成员均可查看。
+func (s *ProjectService) ListMembers(userID, projectID int64) ([]models.ProjectMember, error) {
+	project, err := s.access.authorize(userID, projectID, ProjectRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.memberRepo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.userRepo.FindByID(project.UserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	result := make([]models.ProjectMember, 0, len(members)+1)
+	result = append(result, models.ProjectMember{
+		ProjectID:  project.ID,
+		UserID:     project.UserID,
+		Role:       ProjectRoleOwner,
+		CreateTime: project.CreateTime,
+		UpdateTime: project.UpdateTime,
+		User:       owner,
+	})
+	return append(result, members...), nil
+}
+
+// AddMember 邀请用户加入项目 (仅所有者)
+// 被邀请用户会收到一条站内通知。
+//
+// 参数:
+//   - actor: 操作人 (需为项目所有者)
+//   - projectID: 项目ID
+//   - input: 被邀请用户 (用户名或邮箱) 与角色
+//
+// 返回:
+//   - *models.ProjectMember: 新增的成员记录
+//   - error: 权限不足、用户不存在或已是成员
+func (s *ProjectService) AddMember(actor audit.Actor, projectID int64, input dto.AddProjectMemberRequest) (*models.ProjectMember, error) {
+	project, err := s.access.authorize(actor.UserID, projectID, ProjectRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	invitee, err := s.userRepo.FindByCredential(input.Account)
+	if err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	if invitee.Status != 1 {
+		return nil, errors.New("该用户已被禁用")
+	}
+	if invitee.ID == project.UserID {
+		return nil, errors.New("不能邀请项目所有者")
+	}
+	if _, err := s.memberRepo.Find(projectID, invitee.ID); err == nil {
+		return nil, errors.New("该用户已是项目成员")
+	}
+
+	member := &models.ProjectMember{
+		ProjectID: projectID,
+		UserID:    invitee.ID,
+		Role:      input.Role,
+		InvitedBy: actor.UserID,
+	}
+	if err := s.memberRepo.Create(member); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(actor, audit.ActionCreate, audit.EntityProjectMember, member.ID, nil, member)
+	member.User = invitee
+
+	// 通知被邀请用户 (通知失败不影响共享结果)
+	content := fmt.Sprintf("项目「%s」已共享给你，你的角色为%s。", project.Name, projectRoleLabels[input.Role])
+	if _, err := s.notificationService.Create(actor, "项目共享", content, "system", invitee.ID); err != nil {
+		slog.Warn("发送项目共享通知失败", "project_id", projectID, "user_id", invitee.ID, "error", err)
+	}
+
+	return member, nil
+}
+
+// UpdateMember 修改项目成员角色 (仅所有者)
+func (s *ProjectService) UpdateMember(actor audit.Actor, projectID, memberUserID int64, role string) (*models.ProjectMember, error) {
+	if _, err := s.access.authorize(actor.UserID, projectID, ProjectRoleOwner); err != nil {
+		return nil, err
+	}
+
+	member, err := s.memberRepo.Find(projectID, memberUserID)
+	if err != nil {
+		return nil, errors.New("成员不存在")
+	}
+	before := *member
+	member.Role = role
+	if err := s.memberRepo.Update(member); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(actor, audit.ActionUpdate, audit.EntityProjectMember, member.ID, &before, member)
+	return member, nil
+}
+
+// RemoveMember 移除项目成员
+// 所有者可移除任意成员，成员可将自己移出项目 (退出共享)。
+func (s *ProjectService) RemoveMember(actor audit.Actor, projectID, memberUserID int64) error {
+	required := ProjectRoleOwner
+	if memberUserID == actor.UserID {
+		required = ProjectRoleViewer
+	}
+	project, err := s.access.authorize(actor.UserID, projectID, required)
+	if err != nil {
+		return err
+	}
+	if memberUserID == project.UserID {
+		return errors.New("不能移除项目所有者")
+	}
+
+	member, err := s.memberRepo.Find(projectID, memberUserID)
+	if err != nil {
+		return errors.New("成员不存在")
+	}
+	if err := s.memberRepo.Delete(projectID, memberUserID); err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionDelete, audit.EntityProjectMember, member.ID, member, nil)
+	return nil
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
+	"github.com/FruitsAI/Orange/internal/pkg/money"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+// 项目模板错误
+var (
+	ErrTemplateNotFound = errors.New("项目模板不存在")
+	ErrTemplateInvalid  = errors.New("项目模板无效")
+)
+
+// templatePercentEpsilon 收款阶段百分比合计的比较容差 (百分比为浮点数，如 33.33 + 33.33 + 33.34)
+const templatePercentEpsilon = 1e-6
+
+// TemplateService 项目模板服务
+// 按项目类型维护默认描述、工期与收款阶段，从模板创建项目时在同一事务中写入项目与各期款项。
+type TemplateService struct {
+	templateRepo    *repository.TemplateRepository
+	projectRepo     *repository.ProjectRepository
+	paymentRepo     *repository.PaymentRepository
+	auditService    *AuditService
+	revisionService *RevisionService
+}
+
+// NewTemplateService 创建项目模板服务实例
+func NewTemplateService() *TemplateService {
+	return &TemplateService{
+		templateRepo:    repository.NewTemplateRepository(),
+		projectRepo:     repository.NewProjectRepository(),
+		paymentRepo:     repository.NewPaymentRepository(),
+		auditService:    NewAuditService(),
+		revisionService: NewRevisionService(),
+	}
+}
+
+// List 获取全部项目模板 (含收款阶段)
+func (s *TemplateService) List() ([]models.ProjectTemplate, error) {
+	return s.templateRepo.List()
+}
+
+// Get 获取项目模板详情
+func (s *TemplateService) Get(id int64) (*models.ProjectTemplate, error) {
+	template, err := s.templateRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTemplateNotFound
+	}
+	return template, err
+}
+
+// Create 创建项目模板 (每种项目类型只能有一个模板)
+func (s *TemplateService) Create(actor audit.Actor, input dto.ProjectTemplateRequest) (*models.ProjectTemplate, error) {
+	template := &models.ProjectTemplate{UserID: actor.UserID}
+	if err := s.applyInput(template, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.Create(template); err != nil {
+		return nil, err
+	}
+	s.auditService.Record(actor, audit.ActionCreate, audit.EntityProjectTemplate, template.ID, nil, template)
+	return template, nil
+}
+
+// Update 更新项目模板，收款阶段按请求整体替换
+// 已从模板创建的项目与款项不受影响。
+func (s *TemplateService) Update(actor audit.Actor, id int64, input dto.ProjectTemplateRequest) (*models.ProjectTemplate, error) {
+	before, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	template := *before
+	if err := s.applyInput(&template, input); err != nil {
+		return nil, err
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		return s.templateRepo.WithTx(tx).Update(&template)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.Record(actor, audit.ActionUpdate, audit.EntityProjectTemplate, id, before, &template)
+	return &template, nil
+}
+
+// Delete 删除项目模板
+func (s *TemplateService) Delete(actor audit.Actor, id int64) error {
+	before, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		return s.templateRepo.WithTx(tx).Delete(id)
+	})
+	if err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionDelete, audit.EntityProjectTemplate, id, before, nil)
+	return nil
+}
+
+// CreateProject 从模板创建项目
+// 在同一事务中创建项目及模板定义的各期款项:
+//   - 金额: 合同总金额 × 阶段百分比 (四舍五入到分)；各阶段合计为 100% 时，末期取余额，保证款项合计等于合同总金额
+//   - 计划收款日期: 项目开始日期 + 阶段偏移天数
+//   - 占比: 与手工录入款项一致，按金额占合同总金额的百分比计算
+//
+// 参数:
+//   - actor: 操作人
+//   - input: 项目信息，template_id 为空时使用项目类型对应的模板
+//
+// 返回:
+//   - *models.Project: 创建的项目 (含生成的款项)
+func (s *TemplateService) CreateProject(actor audit.Actor, input dto.CreateProjectFromTemplateRequest) (*models.Project, error) {
+	template, err := s.resolve(input.TemplateID, input.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	// 模板默认值
+	if input.Type == "" {
+		input.Type = template.Type
+	}
+	if input.Description == "" {
+		input.Description = template.Description
+	}
+	if input.EndDate == "" {
+		if template.DurationDays <= 0 {
+			return nil, fmt.Errorf("%w: 模板未设置工期，请填写计划结束日期", ErrTemplateInvalid)
+		}
+		startDate, err := time.Parse("2006-01-02", input.StartDate)
+		if err != nil {
+			return nil, err
+		}
+		input.EndDate = startDate.AddDate(0, 0, template.DurationDays).Format("2006-01-02")
+	}
+
+	project, err := newProject(dto.CreateProjectRequest{
+		Name:           input.Name,
+		Company:        input.Company,
+		TotalAmount:    input.TotalAmount,
+		Status:         input.Status,
+		Type:           input.Type,
+		ContractNumber: input.ContractNumber,
+		ContractDate:   input.ContractDate,
+		PaymentMethod:  input.PaymentMethod,
+		StartDate:      input.StartDate,
+		EndDate:        input.EndDate,
+		Description:    input.Description,
+		UserID:         input.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := s.projectRepo.WithTx(tx).Create(project); err != nil {
+			return err
+		}
+		project.Payments = templatePayments(template, project)
+		payments := s.paymentRepo.WithTx(tx)
+		for i := range project.Payments {
+			if err := payments.Create(&project.Payments[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(actor, audit.ActionCreate, audit.EntityProject, project.ID, nil, projectSnapshot(project))
+	s.revisionService.Record(actor, audit.EntityProject, project.ID, audit.ActionCreate, nil, projectSnapshot(project))
+	for i := range project.Payments {
+		payment := &project.Payments[i]
+		s.auditService.Record(actor, audit.ActionCreate, audit.EntityPayment, payment.ID, nil, payment)
+		s.revisionService.Record(actor, audit.EntityPayment, payment.ID, audit.ActionCreate, nil, paymentSnapshot(payment))
+	}
+	return project, nil
+}
+
+// resolve 按模板ID或项目类型查找模板
+func (s *TemplateService) resolve(id int64, projectType string) (*models.ProjectTemplate, error) {
+	if id > 0 {
+		return s.Get(id)
+	}
+	if projectType == "" {
+		return nil, fmt.Errorf("%w: 请指定模板或项目类型", ErrTemplateInvalid)
+	}
+	template, err := s.templateRepo.FindByType(projectType)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTemplateNotFound
+	}
+	return template, err
+}
+
+// applyInput 校验请求并写入模板字段
+func (s *TemplateService) applyInput(template *models.ProjectTemplate, input dto.ProjectTemplateRequest) error {
+	if input.DurationDays < 0 {
+		return fmt.Errorf("%w: 工期不能为负数", ErrTemplateInvalid)
+	}
+	exists, err := s.templateRepo.ExistsByType(input.Type, template.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w: 该项目类型已有模板", ErrTemplateInvalid)
+	}
+
+	var total float64
+	stages := make([]models.ProjectTemplateStage, 0, len(input.Stages))
+	for i, stage := range input.Stages {
+		if stage.Percentage <= 0 {
+			return fmt.Errorf("%w: 收款阶段百分比必须大于 0", ErrTemplateInvalid)
+		}
+		if stage.OffsetDays < 0 {
+			return fmt.Errorf("%w: 收款阶段偏移天数不能为负数", ErrTemplateInvalid)
+		}
+		total += stage.Percentage
+		stages = append(stages, models.ProjectTemplateStage{
+			Stage:      stage.Stage,
+			Percentage: stage.Percentage,
+			OffsetDays: stage.OffsetDays,
+			Sort:       i + 1,
+		})
+	}
+	if total > 100+templatePercentEpsilon {
+		return fmt.Errorf("%w: 收款阶段百分比合计不能超过 100%%", ErrTemplateInvalid)
+	}
+
+	template.Name = input.Name
+	template.Type = input.Type
+	template.Description = input.Description
+	template.DurationDays = input.DurationDays
+	template.Stages = stages
+	return nil
+}
+
+// templatePayments 按模板收款阶段构建项目的各期款项
+func templatePayments(template *models.ProjectTemplate, project *models.Project) []models.Payment {
+	var percent float64
+	for _, stage := range template.Stages {
+		percent += stage.Percentage
+	}
+	full := percent > 100-templatePercentEpsilon
+
+	payments := make([]models.Payment, 0, len(template.Stages))
+	var allocated money.Amount // 已分配金额
+	for i, stage := range template.Stages {
+		amount := project.TotalAmount.Portion(stage.Percentage)
+		if full && i == len(template.Stages)-1 {
+			amount = project.TotalAmount - allocated
+		}
+		allocated += amount
+
+		payment := models.Payment{
+			ProjectID: project.ID,
+			Stage:     stage.Stage,
+			Amount:    amount,
+			PlanDate:  project.StartDate.AddDate(0, 0, stage.OffsetDays),
+			Status:    models.PaymentStatusPending,
+			Method:    project.PaymentMethod,
+			UserID:    project.UserID,
+		}
+		applyPaymentRules(&payment, project)
+		payments = append(payments, payment)
+	}
+	return payments
+}
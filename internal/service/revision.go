@@ -0,0 +1,325 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ErrRevisionNotFound 版本不存在
+var ErrRevisionNotFound = errors.New("版本不存在")
+
+// revisionActionBaseline 基线版本
+// 实体首次变更时若尚无版本记录 (如功能上线前创建的数据)，先保存变更前的数据作为基线，保证变更前的值可恢复。
+const revisionActionBaseline = "baseline"
+
+// revisionIgnoredFields 对比差异时忽略的字段 (随每次保存自动变化)
+var revisionIgnoredFields = map[string]bool{
+	"create_time": true,
+	"update_time": true,
+}
+
+// RevisionService 数据版本服务
+// 项目与款项每次变更后保存完整快照，支持查看历史版本与对比差异。
+// 访问控制与恢复逻辑由 ProjectService / PaymentService 负责。
+//
+// 依赖:
+//   - RevisionRepository: 数据版本操作
+type RevisionService struct {
+	revisionRepo *repository.RevisionRepository
+}
+
+// NewRevisionService 创建数据版本服务实例
+func NewRevisionService() *RevisionService {
+	return &RevisionService{
+		revisionRepo: repository.NewRevisionRepository(),
+	}
+}
+
+// Record 保存实体变更后的快照
+// 版本写入失败只记录系统日志，不影响已完成的业务操作。
+//
+// 参数:
+//   - actor: 操作人
+//   - entityType: 实体类型 (audit.EntityProject / audit.EntityPayment)
+//   - entityID: 实体ID
+//   - action: 产生版本的操作 (audit.Action*)
+//   - before: 变更前的数据 (创建时为 nil，仅在尚无版本记录时作为基线保存)
+//   - after: 变更后的数据
+func (s *RevisionService) Record(actor audit.Actor, entityType string, entityID int64, action string, before, after interface{}) {
+	if before != nil {
+		exists, err := s.revisionRepo.Exists(entityType, entityID)
+		if err != nil {
+			slog.Error("查询数据版本失败", "entity_type", entityType, "entity_id", entityID, "error", err)
+			return
+		}
+		if !exists {
+			s.create(audit.Actor{}, entityType, entityID, revisionActionBaseline, before)
+		}
+	}
+	s.create(actor, entityType, entityID, action, after)
+}
+
+// List 获取实体的全部版本 (按版本号倒序)
+func (s *RevisionService) List(entityType string, entityID int64) ([]models.Revision, error) {
+	return s.revisionRepo.List(entityType, entityID)
+}
+
+// Get 获取实体的指定版本
+func (s *RevisionService) Get(entityType string, entityID int64, version int) (*models.Revision, error) {
+	revision, err := s.revisionRepo.FindVersion(entityType, entityID, version)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRevisionNotFound
+	}
+	return revision, err
+}
+
+// Diff 对比实体的两个版本
+// 按快照的 JSON 字段逐一比较，返回值不同的字段 (忽略创建/更新时间)。
+func (s *RevisionService) Diff(entityType string, entityID int64, from, to int) (*dto.RevisionDiff, error) {
+	fromRev, err := s.Get(entityType, entityID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := s.Get(entityType, entityID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var fromData, toData map[string]interface{}
+	if err := json.Unmarshal([]byte(fromRev.Snapshot), &fromData); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(toRev.Snapshot), &toData); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]bool, len(fromData)+len(toData))
+	for k := range fromData {
+		fields[k] = true
+	}
+	for k := range toData {
+		fields[k] = true
+	}
+	names := make([]string, 0, len(fields))
+	for k := range fields {
+		if !revisionIgnoredFields[k] {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+
+	changes := make([]dto.FieldChange, 0)
+	for _, name := range names {
+		if !reflect.DeepEqual(fromData[name], toData[name]) {
+			changes = append(changes, dto.FieldChange{Field: name, From: fromData[name], To: toData[name]})
+		}
+	}
+
+	return &dto.RevisionDiff{
+		EntityType: entityType,
+		EntityID:   entityID,
+		From:       from,
+		To:         to,
+		Changes:    changes,
+	}, nil
+}
+
+// create 写入一个版本
+func (s *RevisionService) create(actor audit.Actor, entityType string, entityID int64, action string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.Error("数据版本序列化失败", "entity_type", entityType, "entity_id", entityID, "error", err)
+		return
+	}
+	if err := s.revisionRepo.Create(&models.Revision{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Snapshot:   string(data),
+		ActorID:    actor.UserID,
+		ActorName:  actor.Username,
+	}); err != nil {
+		slog.Error("写入数据版本失败", "entity_type", entityType, "entity_id", entityID, "error", err)
+	}
+}
+
+// projectSnapshot 生成项目快照 (去除关联数据与展示字段)
+func projectSnapshot(project *models.Project) *models.Project {
+	snapshot := *project
+	snapshot.User = nil
+	snapshot.Payments = nil
+	snapshot.Role = ""
+	return &snapshot
+}
+
+// paymentSnapshot 生成款项快照 (去除关联数据)
+func paymentSnapshot(payment *models.Payment) *models.Payment {
+	snapshot := *payment
+	snapshot.Project = nil
+	return &snapshot
+}
+
+// ListRevisions 获取项目的历史版本 (项目的任何成员均可查看)
+func (s *ProjectService) ListRevisions(userID, id int64) ([]models.Revision, error) {
+	if _, err := s.access.authorize(userID, id, ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.revisionService.List(audit.EntityProject, id)
+}
+
+// DiffRevisions 对比项目的两个历史版本
+func (s *ProjectService) DiffRevisions(userID, id int64, from, to int) (*dto.RevisionDiff, error) {
+	if _, err := s.access.authorize(userID, id, ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.revisionService.Diff(audit.EntityProject, id, from, to)
+}
+
+// RestoreRevision 将项目恢复到指定版本 (需为所有者或编辑者)
+// 仅恢复可编辑字段，负责人与已收款总额保持不变；恢复后重新计算款项占比并同步已收款总额，并保存为新版本。
+//
+// 参数:
+//   - actor: 操作人
+//   - id: 项目ID
+//   - version: 目标版本号
+//
+// 返回:
+//   - *models.Project: 恢复后的项目
+//   - error: 无权操作、版本不存在或更新失败
+func (s *ProjectService) RestoreRevision(actor audit.Actor, id int64, version int) (*models.Project, error) {
+	authorized, err := s.access.authorize(actor.UserID, id, ProjectRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := s.revisionService.Get(audit.EntityProject, id, version)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot models.Project
+	if err := json.Unmarshal([]byte(revision.Snapshot), &snapshot); err != nil {
+		return nil, err
+	}
+
+	// 在事务内重新读取并锁定项目，已收款与变更金额以最新值为准
+	var before models.Project
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		project, err := s.projectRepo.WithTx(tx).FindForUpdate(id)
+		if err != nil {
+			return err
+		}
+		project.Role = authorized.Role
+		before = *project
+
+		project.Name = snapshot.Name
+		project.Company = snapshot.Company
+		project.TotalAmount = snapshot.TotalAmount
+		project.Status = snapshot.Status
+		project.Type = snapshot.Type
+		project.ContractNumber = snapshot.ContractNumber
+		project.ContractDate = snapshot.ContractDate
+		project.PaymentMethod = snapshot.PaymentMethod
+		project.StartDate = snapshot.StartDate
+		project.EndDate = snapshot.EndDate
+		project.Description = snapshot.Description
+		if err := s.projectRepo.WithTx(tx).Update(project); err != nil {
+			return err
+		}
+		return s.paymentService.refreshPercentages(tx, project)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.paymentService.syncProjectReceivedAmount(id); err != nil {
+		return nil, err
+	}
+
+	restored, err := s.projectRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	restored.Role = before.Role
+	s.auditService.Record(actor, audit.ActionRestore, audit.EntityProject, id, &before, restored)
+	s.revisionService.Record(actor, audit.EntityProject, id, audit.ActionRestore, projectSnapshot(&before), projectSnapshot(restored))
+	return restored, nil
+}
+
+// ListRevisions 获取款项的历史版本 (需为所属项目成员)
+func (s *PaymentService) ListRevisions(userID, id int64) ([]models.Revision, error) {
+	if _, err := s.authorizePayment(userID, id, ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.revisionService.List(audit.EntityPayment, id)
+}
+
+// DiffRevisions 对比款项的两个历史版本
+func (s *PaymentService) DiffRevisions(userID, id int64, from, to int) (*dto.RevisionDiff, error) {
+	if _, err := s.authorizePayment(userID, id, ProjectRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.revisionService.Diff(audit.EntityPayment, id, from, to)
+}
+
+// RestoreRevision 将款项恢复到指定版本 (需为项目所有者或编辑者)
+// 仅恢复阶段、金额、计划日期、收款方式与备注；收款状态由现有收款记录重新推导，
+// 金额不能小于已收金额。恢复后重新计算占比并同步项目已收款总额，并保存为新版本。
+//
+// 参数:
+//   - actor: 操作人
+//   - id: 款项ID
+//   - version: 目标版本号
+//
+// 返回:
+//   - *models.Payment: 恢复后的款项
+//   - error: 无权操作、版本不存在或更新失败
+func (s *PaymentService) RestoreRevision(actor audit.Actor, id int64, version int) (*models.Payment, error) {
+	payment, err := s.authorizePayment(actor.UserID, id, ProjectRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := s.revisionService.Get(audit.EntityPayment, id, version)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot models.Payment
+	if err := json.Unmarshal([]byte(revision.Snapshot), &snapshot); err != nil {
+		return nil, err
+	}
+
+	// 收款状态由收款记录推导，不随历史版本回退
+	if snapshot.Amount < payment.ReceivedAmount {
+		return nil, fmt.Errorf("%w: 历史版本的金额小于已收金额 %s", ErrReceiptInvalid, payment.ReceivedAmount)
+	}
+	before := *payment
+	payment.Stage = snapshot.Stage
+	payment.Amount = snapshot.Amount
+	payment.PlanDate = snapshot.PlanDate
+	payment.Method = snapshot.Method
+	payment.Remark = snapshot.Remark
+	if err := s.processPaymentRules(payment); err != nil {
+		return nil, err
+	}
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := s.paymentRepo.WithTx(tx).Update(payment); err != nil {
+			return err
+		}
+		return s.refreshReceived(tx, payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(actor, audit.ActionRestore, audit.EntityPayment, id, &before, payment)
+	s.revisionService.Record(actor, audit.EntityPayment, id, audit.ActionRestore, paymentSnapshot(&before), paymentSnapshot(payment))
+	return payment, nil
+}
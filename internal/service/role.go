@@ -0,0 +1,301 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/permission"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+// roleCodePattern 角色编码格式: 小写字母开头，仅包含小写字母、数字与下划线
+var roleCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+// 角色权限缓存 (角色编码 -> 权限集合)
+// 权限校验在每个受保护请求上执行，缓存避免重复查询；角色变更后整体失效。
+var (
+	permissionCacheMu sync.RWMutex
+	permissionCache   = make(map[string]map[string]bool)
+)
+
+// invalidatePermissionCache 清空角色权限缓存
+func invalidatePermissionCache() {
+	permissionCacheMu.Lock()
+	permissionCache = make(map[string]map[string]bool)
+	permissionCacheMu.Unlock()
+}
+
+// RoleService 角色与权限服务
+// 负责角色的增删改查、用户角色分配以及请求级的权限判定。
+//
+// 依赖:
+//   - RoleRepository: 角色数据操作
+//   - UserRepository: 用户数据操作 (角色分配、使用人数统计)
+type RoleService struct {
+	roleRepo *repository.RoleRepository
+	userRepo *repository.UserRepository
+}
+
+// NewRoleService 创建角色服务实例
+func NewRoleService() *RoleService {
+	return &RoleService{
+		roleRepo: repository.NewRoleRepository(),
+		userRepo: repository.NewUserRepository(),
+	}
+}
+
+// ListPermissions 获取系统定义的全部权限
+func (s *RoleService) ListPermissions() []permission.Definition {
+	return permission.All
+}
+
+// List 获取角色列表 (包含权限编码与使用人数)
+func (s *RoleService) List() ([]models.Role, error) {
+	roles, err := s.roleRepo.List()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.userRepo.CountByRole()
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles {
+		fillRolePermissions(&roles[i])
+		roles[i].UserCount = counts[roles[i].Code]
+	}
+	return roles, nil
+}
+
+// Create 创建自定义角色
+//
+// 参数:
+//   - input: 角色编码、名称、说明与权限列表
+//
+// 返回:
+//   - *models.Role: 创建的角色
+//   - error: 编码格式错误、编码已存在或包含未知权限
+func (s *RoleService) Create(input dto.CreateRoleRequest) (*models.Role, error) {
+	if !roleCodePattern.MatchString(input.Code) {
+		return nil, errors.New("角色编码须以小写字母开头，仅包含小写字母、数字和下划线")
+	}
+	if s.roleRepo.ExistsByCode(input.Code) {
+		return nil, errors.New("角色编码已存在")
+	}
+	permissions, err := normalizePermissions(input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &models.Role{
+		Code:        input.Code,
+		Name:        input.Name,
+		Description: input.Description,
+	}
+	for _, p := range permissions {
+		role.Permissions = append(role.Permissions, models.RolePermission{Permission: p})
+	}
+	if err := s.roleRepo.Create(role); err != nil {
+		return nil, err
+	}
+	invalidatePermissionCache()
+
+	fillRolePermissions(role)
+	return role, nil
+}
+
+// Update 更新角色名称、说明与权限
+// 管理员角色始终拥有全部权限，不允许修改其权限项。
+func (s *RoleService) Update(id int64, input dto.UpdateRoleRequest) (*models.Role, error) {
+	role, err := s.roleRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("角色不存在")
+	}
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := normalizePermissions(input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	if role.Code == permission.RoleAdmin {
+		permissions = nil
+	}
+
+	role.Name = input.Name
+	role.Description = input.Description
+	if err := s.roleRepo.Update(role, permissions); err != nil {
+		return nil, err
+	}
+	invalidatePermissionCache()
+
+	updated, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	fillRolePermissions(updated)
+	return updated, nil
+}
+
+// Delete 删除自定义角色
+// 内置角色不可删除；仍有用户使用的角色需先调整这些用户的角色。
+func (s *RoleService) Delete(id int64) error {
+	role, err := s.roleRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("角色不存在")
+	}
+	if err != nil {
+		return err
+	}
+	if role.IsBuiltin == 1 {
+		return errors.New("内置角色不可删除")
+	}
+
+	counts, err := s.userRepo.CountByRole()
+	if err != nil {
+		return err
+	}
+	if n := counts[role.Code]; n > 0 {
+		return fmt.Errorf("仍有 %d 个用户使用该角色，无法删除", n)
+	}
+
+	if err := s.roleRepo.Delete(id); err != nil {
+		return err
+	}
+	invalidatePermissionCache()
+	return nil
+}
+
+// AssignRole 为用户分配角色
+//
+// 参数:
+//   - userID: 目标用户ID
+//   - role: 角色编码
+//
+// 返回:
+//   - error: 用户或角色不存在，或将导致系统中没有可用的管理员
+func (s *RoleService) AssignRole(userID int64, role string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("用户不存在")
+	}
+	if !s.roleRepo.ExistsByCode(role) {
+		return errors.New("角色不存在")
+	}
+	if err := s.ensureAdminRemains(user, role, user.Status); err != nil {
+		return err
+	}
+	return s.userRepo.UpdateFields(userID, map[string]interface{}{"role": role})
+}
+
+// ensureAdminRemains 校验对用户角色或状态的修改不会移除最后一个可用的管理员
+func (s *RoleService) ensureAdminRemains(user *models.User, newRole string, newStatus int) error {
+	if user.Role != permission.RoleAdmin || user.Status != 1 {
+		return nil
+	}
+	if newRole == permission.RoleAdmin && newStatus == 1 {
+		return nil
+	}
+	count, err := s.userRepo.CountActiveByRole(permission.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	if count <= 1 {
+		return errors.New("系统至少需要保留一个启用状态的管理员")
+	}
+	return nil
+}
+
+// UserRole 获取用户当前的角色编码
+// 以数据库中的角色为准 (而非 Token 中携带的角色)，角色调整或账户禁用后立即生效。
+func (s *RoleService) UserRole(userID int64) (string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", errors.New("用户不存在")
+	}
+	if user.Status != 1 {
+		return "", errors.New("账户已被禁用")
+	}
+	return user.Role, nil
+}
+
+// UserPermissions 获取用户拥有的权限编码列表
+func (s *RoleService) UserPermissions(userID int64) (string, []string, error) {
+	role, err := s.UserRole(userID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	result := make([]string, 0, len(permission.All))
+	for _, d := range permission.All {
+		ok, err := s.HasPermission(role, d.Code)
+		if err != nil {
+			return "", nil, err
+		}
+		if ok {
+			result = append(result, d.Code)
+		}
+	}
+	return role, result, nil
+}
+
+// HasPermission 判断角色是否拥有指定权限
+// 管理员角色隐式拥有全部权限。
+func (s *RoleService) HasPermission(role, perm string) (bool, error) {
+	if role == permission.RoleAdmin {
+		return true, nil
+	}
+
+	permissionCacheMu.RLock()
+	granted, ok := permissionCache[role]
+	permissionCacheMu.RUnlock()
+	if !ok {
+		codes, err := s.roleRepo.ListPermissions(role)
+		if err != nil {
+			return false, err
+		}
+		granted = make(map[string]bool, len(codes))
+		for _, code := range codes {
+			granted[code] = true
+		}
+		permissionCacheMu.Lock()
+		permissionCache[role] = granted
+		permissionCacheMu.Unlock()
+	}
+	return granted[perm], nil
+}
+
+// normalizePermissions 校验并去重权限编码
+func normalizePermissions(codes []string) ([]string, error) {
+	seen := make(map[string]bool, len(codes))
+	result := make([]string, 0, len(codes))
+	for _, code := range codes {
+		if !permission.IsValid(code) {
+			return nil, fmt.Errorf("未知权限: %s", code)
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		result = append(result, code)
+	}
+	return result, nil
+}
+
+// fillRolePermissions 填充角色的权限编码列表 (管理员为全部权限)
+func fillRolePermissions(role *models.Role) {
+	role.PermissionCodes = make([]string, 0, len(role.Permissions))
+	if role.Code == permission.RoleAdmin {
+		for _, d := range permission.All {
+			role.PermissionCodes = append(role.PermissionCodes, d.Code)
+		}
+		return
+	}
+	for _, p := range role.Permissions {
+		role.PermissionCodes = append(role.PermissionCodes, p.Permission)
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// scheduleGeneratorTick 周期款项生成的检查间隔
+const scheduleGeneratorTick = time.Hour
+
+// ScheduleGenerator 周期款项生成任务
+// 启动时及此后每小时为进行中的周期收款计划生成未来 PAYMENT_SCHEDULE_HORIZON_DAYS 天内到期的款项。
+type ScheduleGenerator struct {
+	scheduleService *ScheduleService
+	stop            chan struct{}
+	wg              sync.WaitGroup
+}
+
+// NewScheduleGenerator 创建周期款项生成任务
+func NewScheduleGenerator() *ScheduleGenerator {
+	return &ScheduleGenerator{
+		scheduleService: NewScheduleService(),
+		stop:            make(chan struct{}),
+	}
+}
+
+// Start 启动生成任务 (非阻塞)
+func (g *ScheduleGenerator) Start() {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		ticker := time.NewTicker(scheduleGeneratorTick)
+		defer ticker.Stop()
+
+		slog.Info("Payment schedule generator started")
+		g.generate(time.Now())
+		for {
+			select {
+			case <-g.stop:
+				return
+			case now := <-ticker.C:
+				g.generate(now)
+			}
+		}
+	}()
+}
+
+// Stop 停止生成任务，并等待正在进行的生成结束
+func (g *ScheduleGenerator) Stop() {
+	close(g.stop)
+	g.wg.Wait()
+}
+
+// generate 执行一次生成
+func (g *ScheduleGenerator) generate(now time.Time) {
+	generated, err := g.scheduleService.GenerateDue(now)
+	if err != nil {
+		slog.Error("周期款项生成失败", "error", err)
+		return
+	}
+	if generated > 0 {
+		slog.Info("周期款项生成完成", "generated", generated)
+	}
+}
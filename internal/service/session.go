@@ -0,0 +1,256 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/jwt"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+// 登录会话错误
+var (
+	ErrSessionInvalid      = errors.New("登录已失效，请重新登录")
+	ErrRefreshTokenInvalid = errors.New("刷新令牌无效或已过期，请重新登录")
+)
+
+const (
+	// refreshTokenPrefix 刷新令牌前缀 (便于与访问令牌、个人访问令牌区分)
+	refreshTokenPrefix = "rt_"
+
+	// refreshReuseGrace 刷新令牌轮换后的宽限期
+	// 客户端并发刷新时，落后的请求会携带刚被轮换的旧令牌，宽限期内不视为重放。
+	refreshReuseGrace = 30 * time.Second
+
+	// sessionRetention 已过期或已吊销会话的保留时长，超过后在用户下次登录时清理
+	sessionRetention = 30 * 24 * time.Hour
+
+	// maxUserAgentLength User-Agent 最大存储长度 (与字段长度一致)
+	maxUserAgentLength = 255
+)
+
+// SessionService 登录会话服务
+// 负责签发访问令牌与刷新令牌、刷新令牌轮换以及会话的查询与吊销。
+//
+// 依赖:
+//   - SessionRepository: 会话数据操作
+//   - UserRepository: 刷新时校验用户状态
+type SessionService struct {
+	sessionRepo *repository.SessionRepository
+	userRepo    *repository.UserRepository
+}
+
+// NewSessionService 创建登录会话服务实例
+func NewSessionService() *SessionService {
+	return &SessionService{
+		sessionRepo: repository.NewSessionRepository(),
+		userRepo:    repository.NewUserRepository(),
+	}
+}
+
+// Create 为用户创建登录会话并签发令牌
+//
+// 参数:
+//   - user: 已通过认证的用户
+//   - userAgent: 客户端 User-Agent
+//   - ip: 客户端 IP
+//
+// 返回:
+//   - *dto.LoginResult: 访问令牌、刷新令牌及用户信息
+//   - error: 数据库或签名错误
+func (s *SessionService) Create(user *models.User, userAgent, ip string) (*dto.LoginResult, error) {
+	now := time.Now()
+
+	// 顺带清理该用户早已失效的会话，避免会话表无限增长
+	if err := s.sessionRepo.DeleteStale(user.ID, now.Add(-sessionRetention)); err != nil {
+		slog.Warn("清理过期会话失败", "user_id", user.ID, "error", err)
+	}
+
+	refreshToken, refreshHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.UserSession{
+		UserID:           user.ID,
+		RefreshTokenHash: refreshHash,
+		UserAgent:        truncateUserAgent(userAgent),
+		IP:               ip,
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(sessionLifetime()),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	return s.issue(user, session.ID, refreshToken)
+}
+
+// Refresh 使用刷新令牌换取新的访问令牌
+// 刷新令牌为一次性令牌，每次刷新都会轮换；已轮换的旧令牌超过宽限期后再次出现，
+// 视为令牌泄露被重放，直接吊销整个会话。
+func (s *SessionService) Refresh(refreshToken, userAgent, ip string) (*dto.LoginResult, error) {
+	hash := hashRefreshToken(refreshToken)
+
+	session, err := s.sessionRepo.FindByRefreshHash(hash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if prev, err := s.sessionRepo.FindByPreviousHash(hash); err == nil && prev.RevokedAt == nil &&
+			time.Since(prev.LastUsedAt) > refreshReuseGrace {
+			slog.Warn("检测到刷新令牌重放，吊销会话", "session_id", prev.ID, "user_id", prev.UserID)
+			if err := s.sessionRepo.Revoke(prev.ID, 0); err != nil {
+				return nil, err
+			}
+		}
+		return nil, ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.RevokedAt != nil || session.ExpiresAt.Before(time.Now()) {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	user, err := s.userRepo.FindByID(session.UserID)
+	if err != nil || user.Status != 1 {
+		if err := s.sessionRepo.Revoke(session.ID, 0); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	newToken, newHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.sessionRepo.Rotate(session.ID, hash, newHash, truncateUserAgent(userAgent), ip, time.Now().Add(sessionLifetime()))
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	return s.issue(user, session.ID, newToken)
+}
+
+// Validate 校验访问令牌所属的会话是否仍然有效
+// 会话被吊销 (退出登录、修改密码等) 后，未过期的访问令牌也随即失效。
+func (s *SessionService) Validate(sessionID, userID int64) error {
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil {
+		return ErrSessionInvalid
+	}
+	if session.UserID != userID || session.RevokedAt != nil || session.ExpiresAt.Before(time.Now()) {
+		return ErrSessionInvalid
+	}
+	return nil
+}
+
+// Logout 退出登录，吊销访问令牌或刷新令牌对应的会话
+//
+// 参数:
+//   - sessionID: 访问令牌中的会话ID (访问令牌无效时为 0)
+//   - refreshToken: 客户端持有的刷新令牌 (可为空)
+func (s *SessionService) Logout(sessionID int64, refreshToken string) error {
+	if sessionID > 0 {
+		if err := s.sessionRepo.Revoke(sessionID, 0); err != nil {
+			return err
+		}
+	}
+	if refreshToken != "" {
+		session, err := s.sessionRepo.FindByRefreshHash(hashRefreshToken(refreshToken))
+		if err == nil {
+			return s.sessionRepo.Revoke(session.ID, 0)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// List 获取用户的有效会话列表
+//
+// 参数:
+//   - userID: 用户ID
+//   - currentID: 当前请求所在的会话ID (用于标记当前设备)
+func (s *SessionService) List(userID, currentID int64) ([]models.UserSession, error) {
+	sessions, err := s.sessionRepo.ListActive(userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+	return sessions, nil
+}
+
+// Revoke 吊销用户的指定会话 (注销某台设备)
+func (s *SessionService) Revoke(userID, sessionID int64) error {
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil || session.UserID != userID {
+		return errors.New("会话不存在")
+	}
+	return s.sessionRepo.Revoke(sessionID, userID)
+}
+
+// RevokeOthers 吊销用户除当前会话外的全部会话
+func (s *SessionService) RevokeOthers(userID, currentID int64) error {
+	return s.sessionRepo.RevokeAllByUser(userID, currentID)
+}
+
+// RevokeAll 吊销用户的全部会话 (修改密码、重置密码、禁用或删除用户时调用)
+func (s *SessionService) RevokeAll(userID int64) error {
+	return s.sessionRepo.RevokeAllByUser(userID, 0)
+}
+
+// issue 签发访问令牌并组装登录结果
+func (s *SessionService) issue(user *models.User, sessionID int64, refreshToken string) (*dto.LoginResult, error) {
+	token, err := jwt.GenerateToken(user.ID, user.Username, user.Role, sessionID)
+	if err != nil {
+		return nil, errors.New("生成Token失败")
+	}
+	return &dto.LoginResult{
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(jwt.TokenExpiry.Seconds()),
+		User:         user,
+	}, nil
+}
+
+// sessionLifetime 登录会话有效期
+func sessionLifetime() time.Duration {
+	return time.Duration(config.AppConfig.TokenExpiry) * time.Hour
+}
+
+// newRefreshToken 生成刷新令牌，返回原始令牌及其 Hash
+func newRefreshToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := refreshTokenPrefix + hex.EncodeToString(buf)
+	return token, hashRefreshToken(token), nil
+}
+
+// hashRefreshToken 计算刷新令牌 Hash (SHA256)
+func hashRefreshToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// truncateUserAgent 截断过长的 User-Agent
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) > maxUserAgentLength {
+		return userAgent[:maxUserAgentLength]
+	}
+	return userAgent
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/repository"
+)
+
+// testUserID 种子数据中的演示用户 (xu)
+const testUserID = 2
+
+func TestSessionServiceRefresh(t *testing.T) {
+	s := NewSessionService()
+	db := database.GetDB()
+
+	tests := []struct {
+		name string
+		// prepare 在刷新前调整会话状态，返回用于刷新的令牌
+		prepare     func(t *testing.T, session *models.UserSession, token string) string
+		wantErr     error
+		wantRevoked bool
+	}{
+		{
+			name:    "有效令牌",
+			prepare: func(t *testing.T, _ *models.UserSession, token string) string { return token },
+		},
+		{
+			name:    "未知令牌",
+			prepare: func(t *testing.T, _ *models.UserSession, _ string) string { return "rt_unknown" },
+			wantErr: ErrRefreshTokenInvalid,
+		},
+		{
+			name: "会话已吊销",
+			prepare: func(t *testing.T, session *models.UserSession, token string) string {
+				mustExec(t, db.Model(session).Update("revoked_at", time.Now()).Error)
+				return token
+			},
+			wantErr:     ErrRefreshTokenInvalid,
+			wantRevoked: true,
+		},
+		{
+			name: "会话已过期",
+			prepare: func(t *testing.T, session *models.UserSession, token string) string {
+				mustExec(t, db.Model(session).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+				return token
+			},
+			wantErr: ErrRefreshTokenInvalid,
+		},
+		{
+			name: "用户已禁用",
+			prepare: func(t *testing.T, _ *models.UserSession, token string) string {
+				mustExec(t, db.Model(&models.User{}).Where("id = ?", testUserID).Update("status", 0).Error)
+				t.Cleanup(func() {
+					db.Model(&models.User{}).Where("id = ?", testUserID).Update("status", 1)
+				})
+				return token
+			},
+			wantErr:     ErrRefreshTokenInvalid,
+			wantRevoked: true,
+		},
+		{
+			name: "宽限期内重复使用旧令牌",
+			prepare: func(t *testing.T, _ *models.UserSession, token string) string {
+				if _, err := s.Refresh(token, "test", "127.0.0.1"); err != nil {
+					t.Fatalf("first Refresh() unexpected error: %v", err)
+				}
+				return token
+			},
+			wantErr: ErrRefreshTokenInvalid,
+		},
+		{
+			name: "宽限期后重放旧令牌",
+			prepare: func(t *testing.T, session *models.UserSession, token string) string {
+				if _, err := s.Refresh(token, "test", "127.0.0.1"); err != nil {
+					t.Fatalf("first Refresh() unexpected error: %v", err)
+				}
+				mustExec(t, db.Model(session).Update("last_used_at", time.Now().Add(-2*refreshReuseGrace)).Error)
+				return token
+			},
+			wantErr:     ErrRefreshTokenInvalid,
+			wantRevoked: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, token := newTestSession(t, s)
+			refreshToken := tt.prepare(t, session, token)
+
+			result, err := s.Refresh(refreshToken, "test", "127.0.0.1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Refresh() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if !strings.HasPrefix(result.RefreshToken, refreshTokenPrefix) || result.RefreshToken == refreshToken {
+					t.Errorf("Refresh() did not rotate refresh token: %q", result.RefreshToken)
+				}
+				if result.Token == "" {
+					t.Error("Refresh() returned empty access token")
+				}
+				// 轮换后的新令牌可继续刷新
+				if _, err := s.Refresh(result.RefreshToken, "test", "127.0.0.1"); err != nil {
+					t.Errorf("Refresh() with rotated token unexpected error: %v", err)
+				}
+			}
+
+			current, err := s.sessionRepo.FindByID(session.ID)
+			if err != nil {
+				t.Fatalf("FindByID() unexpected error: %v", err)
+			}
+			if revoked := current.RevokedAt != nil; revoked != tt.wantRevoked {
+				t.Errorf("session revoked = %v, want %v", revoked, tt.wantRevoked)
+			}
+		})
+	}
+}
+
+// newTestSession 为演示用户创建登录会话，返回会话及其刷新令牌
+func newTestSession(t *testing.T, s *SessionService) (*models.UserSession, string) {
+	t.Helper()
+	user, err := repository.NewUserRepository().FindByID(testUserID)
+	if err != nil {
+		t.Fatalf("FindByID() unexpected error: %v", err)
+	}
+	result, err := s.Create(user, "test", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+	session, err := s.sessionRepo.FindByRefreshHash(hashRefreshToken(result.RefreshToken))
+	if err != nil {
+		t.Fatalf("FindByRefreshHash() unexpected error: %v", err)
+	}
+	return session, result.RefreshToken
+}
+
+// mustExec 数据准备失败时终止测试
+func mustExec(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+}
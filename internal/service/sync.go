@@ -2,10 +2,18 @@ package service
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/FruitsAI/Orange/internal/database"
 	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/repository"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"gorm.io/gorm"
@@ -13,36 +21,148 @@ import (
 
 // SyncConfig 云端数据库连接配置
 type SyncConfig struct {
-	DBType   string `json:"db_type"`  // postgres, mysql
+	DBType   string `json:"db_type"`  // postgres, mysql, sqlite (离线文件)
 	Host     string `json:"host"`     // 主机地址
 	Port     int    `json:"port"`     // 端口号
 	User     string `json:"user"`     // 用户名
 	Password string `json:"password"` // 密码
 	DBName   string `json:"db_name"`  // 数据库名
 	SSLMode  string `json:"ssl_mode"` // SSL 模式 (postgres: disable/require)
+	Path     string `json:"path"`     // 文件路径 (仅 sqlite 有效)
 }
 
 // TableCompareResult 表对比结果
+// 差异以推送方向 (本地 -> 云端) 描述: 执行推送同步时将在云端新增、更新、删除的记录。
 type TableCompareResult struct {
-	TableName   string `json:"table_name"`   // 表名
-	LocalCount  int64  `json:"local_count"`  // 本地记录数
-	RemoteCount int64  `json:"remote_count"` // 云端记录数
+	TableName      string    `json:"table_name"`              // 表名
+	LocalCount     int64     `json:"local_count"`             // 本地记录数
+	RemoteCount    int64     `json:"remote_count"`            // 云端记录数 (-1 表示表不存在或查询失败)
+	UnchangedCount int64     `json:"unchanged_count"`         // 内容一致的记录数
+	InsertedIDs    []int64   `json:"inserted_ids"`            // 仅本地存在的记录ID (将新增至云端)
+	UpdatedIDs     []int64   `json:"updated_ids"`             // 两侧内容不同的记录ID (将覆盖云端)
+	DeletedIDs     []int64   `json:"deleted_ids"`             // 仅云端存在的记录ID (全量推送时将从云端删除)
+	Diffs          []RowDiff `json:"diffs,omitempty"`         // 更新记录的字段级差异 (按需返回)
+	ErrorMessage   string    `json:"error_message,omitempty"` // 错误信息
+}
+
+// RowDiff 单条记录的字段级差异
+type RowDiff struct {
+	RecordID int64       `json:"record_id"` // 记录ID
+	Fields   []FieldDiff `json:"fields"`    // 不一致的字段
+}
+
+// FieldDiff 字段差异
+type FieldDiff struct {
+	Column string      `json:"column"` // 列名
+	Local  interface{} `json:"local"`  // 本地值
+	Remote interface{} `json:"remote"` // 云端值
+}
+
+// CompareOptions 数据对比选项
+type CompareOptions struct {
+	Tables    []string // 对比的表，为空表示全部同步表
+	FieldDiff bool     // 是否返回更新记录的字段级差异
+}
+
+// 同步方向
+const (
+	SyncDirectionPush  = "push"  // 本地 -> 云端
+	SyncDirectionPull  = "pull"  // 云端 -> 本地
+	SyncDirectionMerge = "merge" // 双向合并 (冲突检测)
+)
+
+// SyncOptions 同步选项
+type SyncOptions struct {
+	Direction string // 同步方向: push (默认), pull, merge
+	Full      bool   // 是否全量同步 (忽略检查点)
+	Atomic    bool   // 是否将所选表作为整体在同一事务中同步 (默认每张表一个事务)
 }
 
 // SyncResult 同步结果
 type SyncResult struct {
-	TableName    string `json:"table_name"`    // 表名
-	SyncedCount  int64  `json:"synced_count"`  // 同步记录数
-	Success      bool   `json:"success"`       // 是否成功
-	ErrorMessage string `json:"error_message"` // 错误信息
+	TableName    string         `json:"table_name"`          // 表名
+	Direction    string         `json:"direction"`           // 同步方向: push, pull, merge
+	Mode         string         `json:"mode"`                // 同步模式: full (全量), incremental (增量)
+	SyncedCount  int64          `json:"synced_count"`        // 推送至云端的记录数
+	PulledCount  int64          `json:"pulled_count"`        // 拉取至本地的记录数
+	DeletedCount int64          `json:"deleted_count"`       // 删除的记录数 (云端或本地)
+	Conflicts    []SyncConflict `json:"conflicts,omitempty"` // 冲突列表 (merge 模式)
+	Success      bool           `json:"success"`             // 是否成功
+	RolledBack   bool           `json:"rolled_back"`         // 是否已回滚 (事务内任一表失败时全部回滚)
+	ErrorMessage string         `json:"error_message"`       // 错误信息
+}
+
+// syncTableSpec 同步表定义
+type syncTableSpec struct {
+	Name       string      // 表名
+	Model      interface{} // 对应模型 (用于创建/迁移云端表结构)
+	Columns    []string    // 同步列 (首列必须为主键 id)
+	SoftDelete bool        // 是否为软删除表 (回收站中的记录不参与同步)
+}
+
+// localTable 本地表查询，软删除的表排除回收站中的记录
+// 记录移入回收站时已写入墓碑，对云端而言等同于删除；从回收站恢复后更新时间刷新，会被重新推送。
+func (spec syncTableSpec) localTable(db *gorm.DB) *gorm.DB {
+	query := db.Table(spec.Name)
+	if spec.SoftDelete {
+		query = query.Where("deleted_at IS NULL")
+	}
+	return query
+}
+
+// syncTableSpecs 各同步表的列定义
+var syncTableSpecs = map[string]syncTableSpec{
+	"users":                   {Name: "users", Model: &models.User{}, Columns: []string{"id", "username", "password", "name", "email", "phone", "avatar", "role", "department", "position", "status", "create_time", "update_time"}, SoftDelete: true},
+	"projects":                {Name: "projects", Model: &models.Project{}, Columns: []string{"id", "name", "company", "total_amount", "change_amount", "received_amount", "status", "type", "contract_number", "contract_date", "payment_method", "start_date", "end_date", "description", "user_id", "create_time", "update_time"}, SoftDelete: true},
+	"project_members":         {Name: "project_members", Model: &models.ProjectMember{}, Columns: []string{"id", "project_id", "user_id", "role", "invited_by", "create_time", "update_time"}},
+	"change_orders":           {Name: "change_orders", Model: &models.ChangeOrder{}, Columns: []string{"id", "project_id", "number", "change_date", "amount", "reason", "status", "approved_by", "approved_at", "user_id", "create_time", "update_time"}},
+	"payment_schedules":       {Name: "payment_schedules", Model: &models.PaymentSchedule{}, Columns: []string{"id", "project_id", "stage", "amount", "frequency", "day_of_month", "start_date", "end_date", "count", "method", "remark", "status", "generated_count", "next_date", "user_id", "create_time", "update_time"}},
+	"payments":                {Name: "payments", Model: &models.Payment{}, Columns: []string{"id", "project_id", "stage", "amount", "percentage", "plan_date", "status", "actual_date", "method", "received_amount", "received_percentage", "refunded_amount", "remark", "schedule_id", "schedule_seq", "user_id", "create_time", "update_time"}, SoftDelete: true},
+	"payment_receipts":        {Name: "payment_receipts", Model: &models.PaymentReceipt{}, Columns: []string{"id", "payment_id", "project_id", "amount", "received_date", "method", "reference_no", "remark", "user_id", "create_time", "update_time"}},
+	"payment_refunds":         {Name: "payment_refunds", Model: &models.PaymentRefund{}, Columns: []string{"id", "payment_id", "project_id", "type", "amount", "refund_date", "method", "reference_no", "reason", "user_id", "create_time", "update_time"}},
+	"dictionaries":            {Name: "dictionaries", Model: &models.Dictionary{}, Columns: []string{"id", "code", "name", "status", "remark", "create_time", "update_time"}},
+	"dictionary_item":         {Name: "dictionary_item", Model: &models.DictionaryItem{}, Columns: []string{"id", "dictionary_id", "label", "value", "sort", "status", "remark", "create_time", "update_time"}},
+	"project_templates":       {Name: "project_templates", Model: &models.ProjectTemplate{}, Columns: []string{"id", "name", "type", "description", "duration_days", "user_id", "create_time", "update_time"}},
+	"project_template_stages": {Name: "project_template_stages", Model: &models.ProjectTemplateStage{}, Columns: []string{"id", "template_id", "stage", "percentage", "offset_days", "sort", "create_time", "update_time"}},
+	"notifications":           {Name: "notifications", Model: &models.Notification{}, Columns: []string{"id", "title", "content", "type", "sender_id", "is_global", "create_time", "update_time"}},
+	"user_notifications":      {Name: "user_notifications", Model: &models.UserNotification{}, Columns: []string{"id", "user_id", "notification_id", "is_read", "read_time", "update_time"}},
+	"personal_access_tokens":  {Name: "personal_access_tokens", Model: &models.PersonalAccessToken{}, Columns: []string{"id", "user_id", "name", "token_hash", "scopes", "status", "last_used_at", "expires_at", "create_time", "update_time"}},
+}
+
+// syncBatchSize 批量写入/删除云端记录时每批的行数
+const syncBatchSize = 500
+
+// syncTombstoneRetention 删除墓碑的保留时长
+// 超过保留期的墓碑即使仍有目标未消费也会被清理，这些目标下次推送时改为全量同步。
+const syncTombstoneRetention = 90 * 24 * time.Hour
+
+// syncExecutor 云端 SQL 执行器 (*sql.DB 或 *sql.Tx)
+type syncExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// syncSession 一次同步事务的上下文
+// 本地与云端各开启一个事务，检查点与行基准版本通过绑定本地事务的仓库写入，
+// 保证同步失败回滚时元数据与业务数据保持一致。
+type syncSession struct {
+	local  *gorm.DB                   // 本地事务
+	remote syncExecutor               // 云端事务
+	repo   *repository.SyncRepository // 绑定本地事务的同步元数据仓库
+	dbType string                     // 云端数据库类型
+	target string                     // 同步目标标识
 }
 
 // SyncService 数据同步服务
-type SyncService struct{}
+type SyncService struct {
+	syncRepo *repository.SyncRepository
+}
 
 // NewSyncService 创建同步服务实例
 func NewSyncService() *SyncService {
-	return &SyncService{}
+	return &SyncService{
+		syncRepo: repository.NewSyncRepository(),
+	}
 }
 
 // buildDSN 根据配置构建数据库连接字符串
@@ -58,6 +178,8 @@ func (s *SyncService) buildDSN(cfg SyncConfig) string {
 		}
 		return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 			cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, sslMode)
+	case "sqlite":
+		return cfg.Path
 	default:
 		return ""
 	}
@@ -70,22 +192,56 @@ func (s *SyncService) getDriverName(dbType string) string {
 		return "mysql"
 	case "postgres":
 		return "pgx"
+	case "sqlite":
+		return "sqlite"
 	default:
 		return ""
 	}
 }
 
-// TestConnection 测试云端数据库连接
-func (s *SyncService) TestConnection(cfg SyncConfig) error {
-	driver := s.getDriverName(cfg.DBType)
-	if driver == "" {
+// validateConfig 校验同步目标配置
+// 网络数据库需要主机、端口、用户名与库名；SQLite 离线目标只需要文件路径。
+func (s *SyncService) validateConfig(cfg SyncConfig) error {
+	switch cfg.DBType {
+	case "mysql", "postgres":
+		if cfg.Host == "" || cfg.Port == 0 || cfg.User == "" || cfg.DBName == "" {
+			return errors.New("主机、端口、用户名与数据库名不能为空")
+		}
+	case "sqlite":
+		if cfg.Path == "" {
+			return errors.New("SQLite 文件路径不能为空")
+		}
+	default:
 		return fmt.Errorf("不支持的数据库类型: %s", cfg.DBType)
 	}
+	return nil
+}
+
+// openRemote 打开同步目标数据库
+// SQLite 目标文件不存在时自动创建 (含上级目录)。
+func (s *SyncService) openRemote(cfg SyncConfig) (*sql.DB, error) {
+	if err := s.validateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.DBType == "sqlite" {
+		if err := os.MkdirAll(filepath.Dir(cfg.Path), 0755); err != nil {
+			return nil, fmt.Errorf("创建目录失败: %w", err)
+		}
+	}
 
-	dsn := s.buildDSN(cfg)
-	db, err := sql.Open(driver, dsn)
+	db, err := sql.Open(s.getDriverName(cfg.DBType), s.buildDSN(cfg))
 	if err != nil {
-		return fmt.Errorf("连接失败: %w", err)
+		return nil, fmt.Errorf("连接云端数据库失败: %w", err)
+	}
+	return db, nil
+}
+
+// TestConnection 测试云端数据库连接
+func (s *SyncService) TestConnection(cfg SyncConfig) error {
+	db, err := s.openRemote(cfg)
+	if err != nil {
+		return err
 	}
 	defer db.Close()
 
@@ -97,18 +253,14 @@ func (s *SyncService) TestConnection(cfg SyncConfig) error {
 	return nil
 }
 
-// CompareData 对比本地与云端表的记录数
-func (s *SyncService) CompareData(cfg SyncConfig) ([]TableCompareResult, error) {
+// CompareData 对比本地与云端数据
+// 逐行计算两侧记录的内容校验和 (与双向同步使用相同的规范化哈希)，
+// 按表返回新增、更新、删除的记录ID，FieldDiff=true 时附带更新记录的字段级差异。
+func (s *SyncService) CompareData(cfg SyncConfig, opts CompareOptions) ([]TableCompareResult, error) {
 	// 连接云端数据库
-	driver := s.getDriverName(cfg.DBType)
-	if driver == "" {
-		return nil, fmt.Errorf("不支持的数据库类型: %s", cfg.DBType)
-	}
-
-	dsn := s.buildDSN(cfg)
-	remoteDB, err := sql.Open(driver, dsn)
+	remoteDB, err := s.openRemote(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("连接云端数据库失败: %w", err)
+		return nil, err
 	}
 	defer remoteDB.Close()
 
@@ -116,351 +268,498 @@ func (s *SyncService) CompareData(cfg SyncConfig) ([]TableCompareResult, error)
 	localDB := database.GetDB()
 
 	// 要对比的表
-	tables := []string{"users", "projects", "payments", "dictionaries", "dictionary_item", "notifications", "user_notifications", "personal_access_tokens"}
+	tables := database.SyncTables
+	if len(opts.Tables) > 0 {
+		tables = s.orderTables(opts.Tables)
+	}
 	results := make([]TableCompareResult, 0, len(tables))
 
 	for _, table := range tables {
-		result := TableCompareResult{TableName: table}
+		result := TableCompareResult{
+			TableName:   table,
+			InsertedIDs: []int64{},
+			UpdatedIDs:  []int64{},
+			DeletedIDs:  []int64{},
+		}
 
-		// 本地计数
-		var localCount int64
-		localDB.Table(table).Count(&localCount)
-		result.LocalCount = localCount
+		spec, ok := syncTableSpecs[table]
+		if !ok {
+			result.RemoteCount = -1
+			result.ErrorMessage = "未知表名"
+			results = append(results, result)
+			continue
+		}
+
+		localRows, err := s.readLocalRows(localDB, spec, nil)
+		if err != nil {
+			result.RemoteCount = -1
+			result.ErrorMessage = fmt.Sprintf("读取本地数据失败: %v", err)
+			results = append(results, result)
+			continue
+		}
+		result.LocalCount = int64(len(localRows))
 
-		// 云端计数 (表可能不存在)
-		var remoteCount int64
-		row := remoteDB.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", table))
-		if err := row.Scan(&remoteCount); err != nil {
+		// 云端表可能不存在
+		remoteRows, _, err := s.readRemoteRows(remoteDB, cfg.DBType, spec, nil)
+		if err != nil {
 			result.RemoteCount = -1 // -1 表示表不存在或查询失败
-		} else {
-			result.RemoteCount = remoteCount
+			result.ErrorMessage = fmt.Sprintf("读取云端数据失败: %v", err)
+			results = append(results, result)
+			continue
 		}
+		result.RemoteCount = int64(len(remoteRows))
 
+		s.diffRows(spec, localRows, remoteRows, opts.FieldDiff, &result)
 		results = append(results, result)
 	}
 
 	return results, nil
 }
 
+// diffRows 逐行比较两侧记录并填充对比结果
+func (s *SyncService) diffRows(spec syncTableSpec, localRows, remoteRows map[int64][]interface{}, fieldDiff bool, result *TableCompareResult) {
+	for id, localRow := range localRows {
+		remoteRow, ok := remoteRows[id]
+		if !ok {
+			result.InsertedIDs = append(result.InsertedIDs, id)
+			continue
+		}
+		if syncRowHash(spec, localRow) == syncRowHash(spec, remoteRow) {
+			result.UnchangedCount++
+			continue
+		}
+
+		result.UpdatedIDs = append(result.UpdatedIDs, id)
+		if fieldDiff {
+			diff := RowDiff{RecordID: id}
+			kinds := spec.columnKinds()
+			for i, col := range spec.Columns {
+				if canonicalSyncValue(kinds[i], localRow[i]) != canonicalSyncValue(kinds[i], remoteRow[i]) {
+					diff.Fields = append(diff.Fields, FieldDiff{
+						Column: col,
+						Local:  normalizeSyncValue(localRow[i]),
+						Remote: normalizeSyncValue(remoteRow[i]),
+					})
+				}
+			}
+			result.Diffs = append(result.Diffs, diff)
+		}
+	}
+	for id := range remoteRows {
+		if _, ok := localRows[id]; !ok {
+			result.DeletedIDs = append(result.DeletedIDs, id)
+		}
+	}
+
+	// 按ID排序，便于前端展示
+	sort.Slice(result.InsertedIDs, func(i, j int) bool { return result.InsertedIDs[i] < result.InsertedIDs[j] })
+	sort.Slice(result.UpdatedIDs, func(i, j int) bool { return result.UpdatedIDs[i] < result.UpdatedIDs[j] })
+	sort.Slice(result.DeletedIDs, func(i, j int) bool { return result.DeletedIDs[i] < result.DeletedIDs[j] })
+	sort.Slice(result.Diffs, func(i, j int) bool { return result.Diffs[i].RecordID < result.Diffs[j].RecordID })
+}
+
 // SyncTables 执行数据同步
-func (s *SyncService) SyncTables(cfg SyncConfig, tables []string) ([]SyncResult, error) {
-	// 连接云端数据库
-	driver := s.getDriverName(cfg.DBType)
-	if driver == "" {
-		return nil, fmt.Errorf("不支持的数据库类型: %s", cfg.DBType)
+// 支持三种方向:
+//   - push (默认): 本地推送至云端。增量模式下仅推送检查点之后 update_time 变化的记录，并依据墓碑日志删除云端记录；
+//     目标无检查点或 Full=true 时执行全量推送并清理云端多余数据。
+//   - pull: 云端拉取至本地，云端优先。
+//   - merge: 双向合并，仅一侧变化的记录自动同步，两侧均变化的记录作为冲突返回，需人工处理。
+//
+// 同步前会按模型定义自动创建或迁移云端表结构。
+// 表按依赖顺序同步 (users → projects → payments 等)。默认每张表在独立的云端/本地事务中同步，
+// Atomic=true 时所选表共用一个事务；事务内任一表失败则全部回滚，结果中标记 RolledBack。
+func (s *SyncService) SyncTables(cfg SyncConfig, tables []string, opts SyncOptions) ([]SyncResult, error) {
+	direction := opts.Direction
+	if direction == "" {
+		direction = SyncDirectionPush
+	}
+	if direction != SyncDirectionPush && direction != SyncDirectionPull && direction != SyncDirectionMerge {
+		return nil, fmt.Errorf("不支持的同步方向: %s", direction)
 	}
 
-	dsn := s.buildDSN(cfg)
-	remoteDB, err := sql.Open(driver, dsn)
+	// 连接云端数据库
+	remoteDB, err := s.openRemote(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("连接云端数据库失败: %w", err)
+		return nil, err
 	}
 	defer remoteDB.Close()
 
-	// 获取本地数据库
-	localDB := database.GetDB()
-	results := make([]SyncResult, 0, len(tables))
+	target := s.targetKey(cfg)
+	ordered := s.orderTables(tables)
 
-	for _, table := range tables {
-		result := SyncResult{TableName: table, Success: true}
-
-		switch table {
-		case "users":
-			result.SyncedCount, result.ErrorMessage = s.syncUsers(localDB, remoteDB, cfg.DBType)
-		case "projects":
-			result.SyncedCount, result.ErrorMessage = s.syncProjects(localDB, remoteDB, cfg.DBType)
-		case "payments":
-			result.SyncedCount, result.ErrorMessage = s.syncPayments(localDB, remoteDB, cfg.DBType)
-		case "dictionaries":
-			result.SyncedCount, result.ErrorMessage = s.syncDictionaries(localDB, remoteDB, cfg.DBType)
-		case "dictionary_item":
-			result.SyncedCount, result.ErrorMessage = s.syncDictionaryItems(localDB, remoteDB, cfg.DBType)
-		case "notifications":
-			result.SyncedCount, result.ErrorMessage = s.syncNotifications(localDB, remoteDB, cfg.DBType)
-		case "user_notifications":
-			result.SyncedCount, result.ErrorMessage = s.syncUserNotifications(localDB, remoteDB, cfg.DBType)
-		case "personal_access_tokens":
-			result.SyncedCount, result.ErrorMessage = s.syncPersonalAccessTokens(localDB, remoteDB, cfg.DBType)
-		default:
-			result.ErrorMessage = "未知表名"
-		}
+	// 同步前确保云端表结构存在且与本地模型一致
+	if _, err := s.provisionSchema(remoteDB, cfg.DBType, ordered, false); err != nil {
+		return nil, fmt.Errorf("初始化云端表结构失败: %w", err)
+	}
 
-		if result.ErrorMessage != "" {
-			result.Success = false
-		}
-		results = append(results, result)
+	if opts.Atomic {
+		return s.syncInTx(remoteDB, cfg.DBType, target, ordered, direction, opts.Full), nil
 	}
 
+	results := make([]SyncResult, 0, len(ordered))
+	for _, table := range ordered {
+		results = append(results, s.syncInTx(remoteDB, cfg.DBType, target, []string{table}, direction, opts.Full)...)
+	}
 	return results, nil
 }
 
-// deleteExtras 删除云端多余的数据
-func (s *SyncService) deleteExtras(remoteDB *sql.DB, table string, keepIDs []interface{}, dbType string) error {
-	if len(keepIDs) == 0 {
-		_, err := remoteDB.Exec(fmt.Sprintf("DELETE FROM %s", table))
-		return err
+// orderTables 按依赖顺序排列待同步的表 (父表在前)，去除重复项
+// 未知表名排在最后，由同步流程返回错误。
+func (s *SyncService) orderTables(tables []string) []string {
+	requested := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		requested[t] = true
 	}
 
-	// 简单的分批处理，防止 SQL 过长 (假设每批 500)
-	// 这里简化处理，直接拼接。对于大量数据建议分批或使用临时表。
-	// 注意：PostgreSQL $1, $2... 占位符处理比较麻烦，因为需要动态生成序号。
-	// 为简单起见，这里假设 ID 数量不会非常巨大，直接拼接值（注意防止注入，ID是数字相对安全，但最好还是参数化）。
-	// 鉴于 Wails 应用场景，我们使用参数化查询。
-
-	query := fmt.Sprintf("DELETE FROM %s WHERE id NOT IN (", table)
-	args := make([]interface{}, len(keepIDs))
-
-	for i, id := range keepIDs {
-		args[i] = id
-		if i > 0 {
-			query += ","
+	ordered := make([]string, 0, len(requested))
+	for _, t := range database.SyncTables {
+		if requested[t] {
+			ordered = append(ordered, t)
+			delete(requested, t)
 		}
-		if dbType == "postgres" {
-			query += fmt.Sprintf("$%d", i+1)
-		} else {
-			query += "?"
+	}
+	for _, t := range tables {
+		if requested[t] {
+			ordered = append(ordered, t)
+			delete(requested, t)
 		}
 	}
-	query += ")"
-
-	_, err := remoteDB.Exec(query, args...)
-	return err
+	return ordered
 }
 
-// syncUsers 同步用户表
-func (s *SyncService) syncUsers(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var users []models.User
-	if err := localDB.Find(&users).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
+// syncInTx 在同一组云端/本地事务中依次同步多张表
+// 任一表失败即回滚两侧事务，并将整组结果标记为失败。
+func (s *SyncService) syncInTx(remoteDB *sql.DB, dbType, target string, tables []string, direction string, full bool) []SyncResult {
+	remoteTx, err := remoteDB.Begin()
+	if err != nil {
+		return s.failedResults(tables, direction, fmt.Sprintf("开启云端事务失败: %v", err))
 	}
-
-	var ids []interface{}
-	for _, u := range users {
-		ids = append(ids, u.ID)
-		query := s.buildUpsertQuery("users", []string{"id", "username", "password", "name", "email", "phone", "avatar", "role", "department", "position", "status", "create_time", "update_time"}, dbType)
-		_, err := remoteDB.Exec(query, u.ID, u.Username, u.Password, u.Name, u.Email, u.Phone, u.Avatar, u.Role, u.Department, u.Position, u.Status, u.CreateTime, u.UpdateTime)
-		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
-		}
+	localTx := database.GetDB().Begin()
+	if localTx.Error != nil {
+		remoteTx.Rollback()
+		return s.failedResults(tables, direction, fmt.Sprintf("开启本地事务失败: %v", localTx.Error))
 	}
 
-	// 删除多余数据
-	if err := s.deleteExtras(remoteDB, "users", ids, dbType); err != nil {
-		// 忽略删除错误，可能是外键约束，下次同步解决
-		fmt.Printf("清理 users 多余数据失败: %v\n", err)
+	sess := &syncSession{
+		local:  localTx,
+		remote: remoteTx,
+		repo:   s.syncRepo.WithTx(localTx),
+		dbType: dbType,
+		target: target,
 	}
 
-	return int64(len(users)), ""
-}
-
-// syncProjects 同步项目表
-func (s *SyncService) syncProjects(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var projects []models.Project
-	if err := localDB.Find(&projects).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
+	results := make([]SyncResult, 0, len(tables))
+	for _, table := range tables {
+		result := s.syncTable(sess, table, direction, full)
+		results = append(results, result)
+		if !result.Success {
+			remoteTx.Rollback()
+			localTx.Rollback()
+			return s.rollbackResults(results, tables, direction)
+		}
 	}
 
-	var ids []interface{}
-	for _, p := range projects {
-		ids = append(ids, p.ID)
-		query := s.buildUpsertQuery("projects", []string{"id", "name", "company", "total_amount", "received_amount", "status", "type", "contract_number", "contract_date", "payment_method", "start_date", "end_date", "description", "user_id", "create_time", "update_time"}, dbType)
-		_, err := remoteDB.Exec(query, p.ID, p.Name, p.Company, p.TotalAmount, p.ReceivedAmount, p.Status, p.Type, p.ContractNumber, p.ContractDate, p.PaymentMethod, p.StartDate, p.EndDate, p.Description, p.UserID, p.CreateTime, p.UpdateTime)
-		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
-		}
+	// 先提交云端：云端写入均为幂等 UPSERT/DELETE，本地提交失败时检查点未前移，下次同步会重新推送
+	if err := remoteTx.Commit(); err != nil {
+		localTx.Rollback()
+		return s.failedResults(tables, direction, fmt.Sprintf("提交云端事务失败: %v", err))
+	}
+	if err := localTx.Commit().Error; err != nil {
+		return s.failedResults(tables, direction, fmt.Sprintf("提交本地事务失败: %v", err))
 	}
 
-	if err := s.deleteExtras(remoteDB, "projects", ids, dbType); err != nil {
-		fmt.Printf("清理 projects 多余数据失败: %v\n", err)
+	// 墓碑清理不影响同步结果，放在事务之外
+	cutoff := time.Now().Add(-syncTombstoneRetention)
+	for _, table := range tables {
+		if err := s.syncRepo.PurgeTombstones(table, cutoff); err != nil {
+			slog.Warn("清理同步墓碑失败", "table", table, "error", err)
+		}
 	}
 
-	return int64(len(projects)), ""
+	return results
 }
 
-// syncPayments 同步收款表
-func (s *SyncService) syncPayments(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var payments []models.Payment
-	if err := localDB.Find(&payments).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
-	}
-
-	var ids []interface{}
-	for _, p := range payments {
-		ids = append(ids, p.ID)
-		query := s.buildUpsertQuery("payments", []string{"id", "project_id", "stage", "amount", "percentage", "plan_date", "status", "actual_date", "method", "remark", "user_id", "create_time", "update_time"}, dbType)
-		_, err := remoteDB.Exec(query, p.ID, p.ProjectID, p.Stage, p.Amount, p.Percentage, p.PlanDate, p.Status, p.ActualDate, p.Method, p.Remark, p.UserID, p.CreateTime, p.UpdateTime)
-		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
+// rollbackResults 将已回滚事务内的结果标记为失败
+// 失败的表保留原错误信息，其余已执行的表提示被连带回滚，未执行的表提示已跳过。
+func (s *SyncService) rollbackResults(results []SyncResult, tables []string, direction string) []SyncResult {
+	for i := range results {
+		results[i].RolledBack = true
+		if results[i].Success {
+			results[i].Success = false
+			results[i].ErrorMessage = "同一事务内其他表同步失败，已回滚"
 		}
 	}
-
-	if err := s.deleteExtras(remoteDB, "payments", ids, dbType); err != nil {
-		fmt.Printf("清理 payments 多余数据失败: %v\n", err)
+	for _, table := range tables[len(results):] {
+		results = append(results, SyncResult{
+			TableName:    table,
+			Direction:    direction,
+			RolledBack:   true,
+			ErrorMessage: "同一事务内其他表同步失败，已跳过",
+		})
 	}
+	return results
+}
 
-	return int64(len(payments)), ""
+// failedResults 为整组表生成失败结果
+func (s *SyncService) failedResults(tables []string, direction, message string) []SyncResult {
+	results := make([]SyncResult, 0, len(tables))
+	for _, table := range tables {
+		results = append(results, SyncResult{
+			TableName:    table,
+			Direction:    direction,
+			RolledBack:   true,
+			ErrorMessage: message,
+		})
+	}
+	return results
 }
 
-// syncDictionaries 同步字典表
-func (s *SyncService) syncDictionaries(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var dicts []models.Dictionary
-	if err := localDB.Find(&dicts).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
+// syncTable 在事务上下文中同步单张表并更新检查点
+func (s *SyncService) syncTable(sess *syncSession, table, direction string, full bool) SyncResult {
+	result := SyncResult{TableName: table, Direction: direction, Success: true, Mode: "full"}
+
+	spec, ok := syncTableSpecs[table]
+	if !ok {
+		result.Success = false
+		result.ErrorMessage = "未知表名"
+		return result
 	}
 
-	var ids []interface{}
-	for _, d := range dicts {
-		ids = append(ids, d.ID)
-		query := s.buildUpsertQuery("dictionaries", []string{"id", "code", "name", "status", "remark", "create_time", "update_time"}, dbType)
-		_, err := remoteDB.Exec(query, d.ID, d.Code, d.Name, d.Status, d.Remark, d.CreateTime, d.UpdateTime)
-		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
+	// 1. 读取检查点，确定增量起点
+	// 高水位取本次同步的开始时间，同步期间产生的变更会在下次同步中再次推送 (UPSERT 幂等)
+	startedAt := time.Now()
+	checkpoint, err := sess.repo.FindCheckpoint(sess.target, table)
+	incremental := err == nil && !full
+	if err != nil {
+		checkpoint = &models.SyncCheckpoint{Target: sess.target, Table: table}
+	}
+	if incremental {
+		result.Mode = "incremental"
+	}
+
+	// 2. 按方向执行同步
+	switch direction {
+	case SyncDirectionPull:
+		result.ErrorMessage = s.pullTable(sess, spec, checkpoint, incremental, &result)
+	case SyncDirectionMerge:
+		result.ErrorMessage = s.mergeTable(sess, spec, checkpoint, incremental, &result)
+	default:
+		// 检查点早于墓碑保留期时，期间的删除记录可能已被清理，改为全量推送以清理云端多余数据
+		var since *time.Time
+		if incremental && !checkpoint.LastSyncTime.IsZero() && checkpoint.LastSyncTime.After(startedAt.Add(-syncTombstoneRetention)) {
+			since = &checkpoint.LastSyncTime
+		} else {
+			result.Mode = "full"
 		}
+		result.SyncedCount, result.DeletedCount, result.ErrorMessage = s.pushTable(sess, spec, since)
+	}
+	if result.ErrorMessage != "" {
+		result.Success = false
+		return result
 	}
 
-	if err := s.deleteExtras(remoteDB, "dictionaries", ids, dbType); err != nil {
-		fmt.Printf("清理 dictionaries 多余数据失败: %v\n", err)
+	// 3. 记录检查点 (仅拉取时本地高水位不前移)
+	if direction != SyncDirectionPull {
+		checkpoint.LastSyncTime = startedAt
+	}
+	checkpoint.SyncedCount = result.SyncedCount + result.PulledCount
+	if err := sess.repo.SaveCheckpoint(checkpoint); err != nil {
+		result.Success = false
+		result.ErrorMessage = fmt.Sprintf("保存同步检查点失败: %v", err)
 	}
 
-	return int64(len(dicts)), ""
+	return result
 }
 
-// syncDictionaryItems 同步字典项表
-func (s *SyncService) syncDictionaryItems(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var items []models.DictionaryItem
-	if err := localDB.Find(&items).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
+// targetKey 生成同步目标标识，用于区分不同云端库的检查点
+func (s *SyncService) targetKey(cfg SyncConfig) string {
+	if cfg.DBType == "sqlite" {
+		return "sqlite://" + cfg.Path
 	}
+	return fmt.Sprintf("%s://%s@%s:%d/%s", cfg.DBType, cfg.User, cfg.Host, cfg.Port, cfg.DBName)
+}
 
-	var ids []interface{}
-	for _, item := range items {
-		ids = append(ids, item.ID)
-		query := s.buildUpsertQuery("dictionary_item", []string{"id", "dictionary_id", "label", "value", "sort", "status", "remark", "create_time", "update_time"}, dbType)
-		_, err := remoteDB.Exec(query, item.ID, item.DictionaryID, item.Label, item.Value, item.Sort, item.Status, item.Remark, item.CreateTime, item.UpdateTime)
+// pushTable 将本地表的变更推送至云端
+// since 为空时推送全部记录并清理云端多余数据；否则仅推送 update_time 晚于 since 的记录，
+// 并删除墓碑日志中 since 之后被删除的记录。记录按 syncBatchSize 分批以多行 UPSERT 写入。
+//
+// 返回:
+//   - synced: 推送 (UPSERT) 的记录数
+//   - deleted: 云端删除的记录数
+//   - errMsg: 错误信息，为空表示成功
+func (s *SyncService) pushTable(sess *syncSession, spec syncTableSpec, since *time.Time) (synced, deleted int64, errMsg string) {
+	// 1. 增量模式下先传播删除，避免与后续 UPSERT 冲突
+	if since != nil {
+		ids, err := sess.repo.ListTombstoneIDs(spec.Name, *since)
 		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
+			return 0, 0, fmt.Sprintf("读取删除日志失败: %v", err)
+		}
+		if len(ids) > 0 {
+			n, err := s.deleteByIDs(sess.remote, spec.Name, ids, sess.dbType)
+			if err != nil {
+				return 0, 0, fmt.Sprintf("同步删除失败: %v", err)
+			}
+			deleted = n
 		}
 	}
 
-	if err := s.deleteExtras(remoteDB, "dictionary_item", ids, dbType); err != nil {
-		fmt.Printf("清理 dictionary_item 多余数据失败: %v\n", err)
+	// 2. 读取需要推送的本地记录
+	query := spec.localTable(sess.local).Select(spec.Columns).Order("id ASC")
+	if since != nil {
+		query = query.Where("update_time > ?", *since)
 	}
+	rows, err := query.Rows()
+	if err != nil {
+		return 0, deleted, fmt.Sprintf("读取本地数据失败: %v", err)
+	}
+	defer rows.Close()
+
+	// 3. 分批 UPSERT
+	keepIDs := make(map[int64]bool)
+	batch := make([][]interface{}, 0, syncBatchSize)
+	for rows.Next() {
+		values := make([]interface{}, len(spec.Columns))
+		pointers := make([]interface{}, len(spec.Columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return synced, deleted, fmt.Sprintf("读取本地数据失败: %v", err)
+		}
+		id, err := syncRowID(values[0])
+		if err != nil {
+			return synced, deleted, fmt.Sprintf("读取本地数据失败: %v", err)
+		}
+		keepIDs[id] = true
 
-	return int64(len(items)), ""
-}
-
-// syncNotifications 同步通知表
-func (s *SyncService) syncNotifications(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var notifications []models.Notification
-	if err := localDB.Find(&notifications).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
+		batch = append(batch, values)
+		if len(batch) == syncBatchSize {
+			if err := s.upsertRemote(sess.remote, spec, batch, sess.dbType); err != nil {
+				return synced, deleted, fmt.Sprintf("同步失败: %v", err)
+			}
+			synced += int64(len(batch))
+			batch = batch[:0]
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return synced, deleted, fmt.Sprintf("读取本地数据失败: %v", err)
 	}
+	if err := s.upsertRemote(sess.remote, spec, batch, sess.dbType); err != nil {
+		return synced, deleted, fmt.Sprintf("同步失败: %v", err)
+	}
+	synced += int64(len(batch))
 
-	var ids []interface{}
-	for _, n := range notifications {
-		ids = append(ids, n.ID)
-		query := s.buildUpsertQuery("notifications", []string{"id", "title", "content", "type", "sender_id", "is_global", "create_time", "update_time"}, dbType)
-		_, err := remoteDB.Exec(query, n.ID, n.Title, n.Content, n.Type, n.SenderID, n.IsGlobal, n.CreateTime, n.UpdateTime)
+	// 4. 全量模式: 删除云端多余数据
+	if since == nil {
+		n, err := s.deleteExtras(sess.remote, spec.Name, keepIDs, sess.dbType)
 		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
+			return synced, deleted, fmt.Sprintf("清理云端多余数据失败: %v", err)
 		}
+		deleted += n
 	}
 
-	if err := s.deleteExtras(remoteDB, "notifications", ids, dbType); err != nil {
-		fmt.Printf("清理 notifications 多余数据失败: %v\n", err)
-	}
-
-	return int64(len(notifications)), ""
+	return synced, deleted, ""
 }
 
-// syncUserNotifications 同步用户通知关联表
-func (s *SyncService) syncUserNotifications(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var userNotifications []models.UserNotification
-	if err := localDB.Find(&userNotifications).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
+// deleteExtras 删除云端存在而本地不存在的数据
+// 先读取云端全部主键再按差集分批删除，避免 NOT IN 参数过多。
+func (s *SyncService) deleteExtras(remote syncExecutor, table string, keepIDs map[int64]bool, dbType string) (int64, error) {
+	remoteIDs, err := s.readRemoteIDs(remote, table)
+	if err != nil {
+		return 0, err
 	}
 
-	var ids []interface{}
-	for _, un := range userNotifications {
-		ids = append(ids, un.ID)
-		query := s.buildUpsertQuery("user_notifications", []string{"id", "user_id", "notification_id", "is_read", "read_time"}, dbType)
-		_, err := remoteDB.Exec(query, un.ID, un.UserID, un.NotificationID, un.IsRead, un.ReadTime)
-		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
+	var extras []int64
+	for id := range remoteIDs {
+		if !keepIDs[id] {
+			extras = append(extras, id)
 		}
 	}
+	return s.deleteByIDs(remote, table, extras, dbType)
+}
 
-	if err := s.deleteExtras(remoteDB, "user_notifications", ids, dbType); err != nil {
-		fmt.Printf("清理 user_notifications 多余数据失败: %v\n", err)
-	}
+// deleteByIDs 按主键批量删除云端记录
+func (s *SyncService) deleteByIDs(remote syncExecutor, table string, ids []int64, dbType string) (int64, error) {
+	var affected int64
 
-	return int64(len(userNotifications)), ""
-}
+	for start := 0; start < len(ids); start += syncBatchSize {
+		end := start + syncBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batch := ids[start:end]
 
-// syncPersonalAccessTokens 同步个人访问令牌表
-func (s *SyncService) syncPersonalAccessTokens(localDB *gorm.DB, remoteDB *sql.DB, dbType string) (int64, string) {
-	var tokens []models.PersonalAccessToken
-	if err := localDB.Find(&tokens).Error; err != nil {
-		return 0, fmt.Sprintf("读取本地数据失败: %v", err)
-	}
+		placeholders := make([]string, len(batch))
+		args := make([]interface{}, len(batch))
+		for i, id := range batch {
+			placeholders[i] = s.placeholder(dbType, i+1)
+			args[i] = id
+		}
+		query := fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", table, strings.Join(placeholders, ","))
 
-	var ids []interface{}
-	for _, t := range tokens {
-		ids = append(ids, t.ID)
-		query := s.buildUpsertQuery("personal_access_tokens", []string{"id", "user_id", "name", "token_hash", "scopes", "status", "last_used_at", "expires_at", "create_time", "update_time"}, dbType)
-		_, err := remoteDB.Exec(query, t.ID, t.UserID, t.Name, t.TokenHash, t.Scopes, t.Status, t.LastUsedAt, t.ExpiresAt, t.CreateTime, t.UpdateTime)
+		res, err := remote.Exec(query, args...)
 		if err != nil {
-			return 0, fmt.Sprintf("同步失败: %v", err)
+			return affected, err
 		}
+		n, _ := res.RowsAffected()
+		affected += n
 	}
 
-	if err := s.deleteExtras(remoteDB, "personal_access_tokens", ids, dbType); err != nil {
-		fmt.Printf("清理 personal_access_tokens 多余数据失败: %v\n", err)
-	}
-
-	return int64(len(tokens)), ""
+	return affected, nil
 }
 
-// buildUpsertQuery 构建 UPSERT 语句 (支持 PostgreSQL 和 MySQL)
-func (s *SyncService) buildUpsertQuery(table string, columns []string, dbType string) string {
-	// 构建占位符
-	placeholders := ""
-	updateSet := ""
-	for i, col := range columns {
-		if i > 0 {
-			placeholders += ", "
-			if col != "id" {
-				if updateSet != "" {
-					updateSet += ", "
-				}
-			}
+// upsertRemote 以多行 UPSERT 批量写入云端记录
+func (s *SyncService) upsertRemote(remote syncExecutor, spec syncTableSpec, rows [][]interface{}, dbType string) error {
+	for start := 0; start < len(rows); start += syncBatchSize {
+		end := start + syncBatchSize
+		if end > len(rows) {
+			end = len(rows)
 		}
-		if dbType == "postgres" {
-			placeholders += fmt.Sprintf("$%d", i+1)
-			if col != "id" {
-				updateSet += fmt.Sprintf("%s = EXCLUDED.%s", col, col)
-			}
-		} else {
-			placeholders += "?"
-			if col != "id" {
-				updateSet += fmt.Sprintf("%s = VALUES(%s)", col, col)
-			}
+		batch := rows[start:end]
+
+		args := make([]interface{}, 0, len(batch)*len(spec.Columns))
+		for _, row := range batch {
+			args = append(args, row...)
+		}
+		if _, err := remote.Exec(s.buildUpsertQuery(spec.Name, spec.Columns, dbType, len(batch)), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildUpsertQuery 构建多行 UPSERT 语句 (支持 PostgreSQL、SQLite 和 MySQL)
+func (s *SyncService) buildUpsertQuery(table string, columns []string, dbType string, rowCount int) string {
+	// 构建每行的占位符
+	values := make([]string, rowCount)
+	n := 0
+	for r := 0; r < rowCount; r++ {
+		placeholders := make([]string, len(columns))
+		for i := range columns {
+			n++
+			placeholders[i] = s.placeholder(dbType, n)
 		}
+		values[r] = "(" + strings.Join(placeholders, ", ") + ")"
 	}
 
-	colNames := ""
-	for i, col := range columns {
-		if i > 0 {
-			colNames += ", "
+	updateSet := make([]string, 0, len(columns))
+	for _, col := range columns {
+		if col == "id" {
+			continue
+		}
+		if dbType == "postgres" || dbType == "sqlite" {
+			updateSet = append(updateSet, fmt.Sprintf("%s = EXCLUDED.%s", col, col))
+		} else {
+			updateSet = append(updateSet, fmt.Sprintf("%s = VALUES(%s)", col, col))
 		}
-		colNames += col
 	}
 
-	if dbType == "postgres" {
-		return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (id) DO UPDATE SET %s",
-			table, colNames, placeholders, updateSet)
+	colNames := strings.Join(columns, ", ")
+	if dbType == "postgres" || dbType == "sqlite" {
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON CONFLICT (id) DO UPDATE SET %s",
+			table, colNames, strings.Join(values, ", "), strings.Join(updateSet, ", "))
 	}
 	// MySQL
-	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s",
-		table, colNames, placeholders, updateSet)
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON DUPLICATE KEY UPDATE %s",
+		table, colNames, strings.Join(values, ", "), strings.Join(updateSet, ", "))
 }
@@ -0,0 +1,715 @@
+package service
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// remoteClockSkew 云端高水位的回看窗口
+// 多台设备写入同一云端库时各自时钟可能存在偏差，拉取时向前多查一段时间，
+// 重复读到的记录会因内容哈希与基准版本一致而被跳过。
+const remoteClockSkew = 5 * time.Minute
+
+// 冲突类型
+const (
+	ConflictBothUpdated   = "both_updated"   // 两侧均修改且内容不同
+	ConflictLocalDeleted  = "local_deleted"  // 本地已删除，云端已修改
+	ConflictRemoteDeleted = "remote_deleted" // 云端已删除，本地已修改
+)
+
+// SyncConflict 双向同步冲突
+type SyncConflict struct {
+	TableName string                 `json:"table_name"` // 表名
+	RecordID  int64                  `json:"record_id"`  // 记录ID
+	Type      string                 `json:"type"`       // 冲突类型: both_updated, local_deleted, remote_deleted
+	Local     map[string]interface{} `json:"local"`      // 本地记录 (已删除时为 null)
+	Remote    map[string]interface{} `json:"remote"`     // 云端记录 (已删除时为 null)
+}
+
+// pullTable 将云端变更拉取至本地 (云端优先)
+// 增量模式下仅读取云端高水位之后修改的记录；内容与基准版本一致的记录跳过。
+// 基准版本中存在但云端已不存在的记录视为云端删除，同步删除本地记录。
+func (s *SyncService) pullTable(sess *syncSession, spec syncTableSpec, checkpoint *models.SyncCheckpoint, incremental bool, result *SyncResult) string {
+	var remoteSince *time.Time
+	if incremental && checkpoint.RemoteSyncTime != nil {
+		t := checkpoint.RemoteSyncTime.Add(-remoteClockSkew)
+		remoteSince = &t
+	}
+
+	remoteRows, highWater, err := s.readRemoteRows(sess.remote, sess.dbType, spec, remoteSince)
+	if err != nil {
+		return fmt.Sprintf("读取云端数据失败: %v", err)
+	}
+	remoteIDs, err := s.readRemoteIDs(sess.remote, spec.Name)
+	if err != nil {
+		return fmt.Sprintf("读取云端数据失败: %v", err)
+	}
+	base, err := sess.repo.ListRowVersions(sess.target, spec.Name)
+	if err != nil {
+		return fmt.Sprintf("读取基准版本失败: %v", err)
+	}
+
+	// 1. 云端新增/修改 -> 本地
+	versions := make(map[int64]string)
+	for id, row := range remoteRows {
+		hash := syncRowHash(spec, row)
+		if base[id] == hash {
+			continue
+		}
+		if err := s.upsertLocal(sess.local, spec, row); err != nil {
+			return fmt.Sprintf("写入本地数据失败: %v", err)
+		}
+		versions[id] = hash
+		result.PulledCount++
+	}
+
+	// 2. 云端删除 -> 本地
+	var removed []int64
+	for id := range base {
+		if !remoteIDs[id] {
+			removed = append(removed, id)
+		}
+	}
+	if err := s.deleteLocalByIDs(sess.local, spec, removed); err != nil {
+		return fmt.Sprintf("删除本地数据失败: %v", err)
+	}
+	result.DeletedCount = int64(len(removed))
+
+	// 3. 更新基准版本与云端高水位
+	if err := sess.repo.SaveRowVersions(sess.target, spec.Name, versions); err != nil {
+		return fmt.Sprintf("保存基准版本失败: %v", err)
+	}
+	if err := sess.repo.DeleteRowVersions(sess.target, spec.Name, removed); err != nil {
+		return fmt.Sprintf("保存基准版本失败: %v", err)
+	}
+	if highWater != nil {
+		checkpoint.RemoteSyncTime = highWater
+	}
+
+	return ""
+}
+
+// mergeTable 双向合并本地与云端数据
+// 以上次同步时保存的行基准版本 (内容哈希) 判断每条记录在两侧是否发生变化:
+//   - 仅一侧变化: 自动同步到另一侧 (含删除)
+//   - 两侧均变化且内容不同 / 一侧删除另一侧修改: 记为冲突，两侧均不改动，等待人工处理
+func (s *SyncService) mergeTable(sess *syncSession, spec syncTableSpec, checkpoint *models.SyncCheckpoint, incremental bool, result *SyncResult) string {
+	// 1. 确定两侧的候选变更集
+	var localSince, remoteSince *time.Time
+	if incremental {
+		if !checkpoint.LastSyncTime.IsZero() {
+			localSince = &checkpoint.LastSyncTime
+		}
+		if checkpoint.RemoteSyncTime != nil {
+			t := checkpoint.RemoteSyncTime.Add(-remoteClockSkew)
+			remoteSince = &t
+		}
+	}
+
+	localRows, err := s.readLocalRows(sess.local, spec, localSince)
+	if err != nil {
+		return fmt.Sprintf("读取本地数据失败: %v", err)
+	}
+	remoteRows, highWater, err := s.readRemoteRows(sess.remote, sess.dbType, spec, remoteSince)
+	if err != nil {
+		return fmt.Sprintf("读取云端数据失败: %v", err)
+	}
+	localIDs, err := s.readLocalIDs(sess.local, spec)
+	if err != nil {
+		return fmt.Sprintf("读取本地数据失败: %v", err)
+	}
+	remoteIDs, err := s.readRemoteIDs(sess.remote, spec.Name)
+	if err != nil {
+		return fmt.Sprintf("读取云端数据失败: %v", err)
+	}
+	base, err := sess.repo.ListRowVersions(sess.target, spec.Name)
+	if err != nil {
+		return fmt.Sprintf("读取基准版本失败: %v", err)
+	}
+
+	// 2. 汇总所有需要判断的记录ID
+	candidates := make(map[int64]bool)
+	for id := range localRows {
+		candidates[id] = true
+	}
+	for id := range remoteRows {
+		candidates[id] = true
+	}
+	for id := range base {
+		if !localIDs[id] || !remoteIDs[id] {
+			candidates[id] = true
+		}
+	}
+
+	versions := make(map[int64]string)
+	var dropped, pushDeletes, pullDeletes []int64
+	var pushRows [][]interface{}
+
+	for id := range candidates {
+		baseHash, hasBase := base[id]
+		localRow, localCandidate := localRows[id]
+		remoteRow, remoteCandidate := remoteRows[id]
+
+		localHash, remoteHash := "", ""
+		if localCandidate {
+			localHash = syncRowHash(spec, localRow)
+		}
+		if remoteCandidate {
+			remoteHash = syncRowHash(spec, remoteRow)
+		}
+
+		localChanged := localCandidate && localHash != baseHash
+		remoteChanged := remoteCandidate && remoteHash != baseHash
+		localDeleted := hasBase && !localIDs[id]
+		remoteDeleted := hasBase && !remoteIDs[id]
+
+		switch {
+		case localDeleted && remoteDeleted:
+			dropped = append(dropped, id)
+		case localDeleted && remoteChanged:
+			result.Conflicts = append(result.Conflicts, s.newConflict(spec, id, ConflictLocalDeleted, nil, remoteRow))
+		case remoteDeleted && localChanged:
+			result.Conflicts = append(result.Conflicts, s.newConflict(spec, id, ConflictRemoteDeleted, localRow, nil))
+		case localDeleted:
+			pushDeletes = append(pushDeletes, id)
+		case remoteDeleted:
+			pullDeletes = append(pullDeletes, id)
+		case localChanged && remoteChanged:
+			if localHash == remoteHash {
+				versions[id] = localHash
+			} else {
+				result.Conflicts = append(result.Conflicts, s.newConflict(spec, id, ConflictBothUpdated, localRow, remoteRow))
+			}
+		case localChanged:
+			pushRows = append(pushRows, localRow)
+			versions[id] = localHash
+		case remoteChanged:
+			if err := s.upsertLocal(sess.local, spec, remoteRow); err != nil {
+				return fmt.Sprintf("写入本地数据失败: %v", err)
+			}
+			versions[id] = remoteHash
+			result.PulledCount++
+		}
+	}
+
+	// 3. 批量推送本地变更并传播删除
+	if err := s.upsertRemote(sess.remote, spec, pushRows, sess.dbType); err != nil {
+		return fmt.Sprintf("同步失败: %v", err)
+	}
+	result.SyncedCount = int64(len(pushRows))
+
+	if len(pushDeletes) > 0 {
+		if _, err := s.deleteByIDs(sess.remote, spec.Name, pushDeletes, sess.dbType); err != nil {
+			return fmt.Sprintf("同步删除失败: %v", err)
+		}
+	}
+	if err := s.deleteLocalByIDs(sess.local, spec, pullDeletes); err != nil {
+		return fmt.Sprintf("删除本地数据失败: %v", err)
+	}
+	result.DeletedCount = int64(len(pushDeletes) + len(pullDeletes))
+
+	// 4. 更新基准版本与云端高水位
+	if err := sess.repo.SaveRowVersions(sess.target, spec.Name, versions); err != nil {
+		return fmt.Sprintf("保存基准版本失败: %v", err)
+	}
+	removed := append(append(dropped, pushDeletes...), pullDeletes...)
+	if err := sess.repo.DeleteRowVersions(sess.target, spec.Name, removed); err != nil {
+		return fmt.Sprintf("保存基准版本失败: %v", err)
+	}
+	if highWater != nil {
+		checkpoint.RemoteSyncTime = highWater
+	}
+
+	return ""
+}
+
+// ResolveConflict 人工处理同步冲突
+// resolution 为 "local" 时以本地记录覆盖云端 (本地已删除则删除云端)，
+// 为 "remote" 时以云端记录覆盖本地 (云端已删除则删除本地)，并将结果记为新的基准版本。
+// 两侧写入与基准版本更新在同一组事务中完成。
+func (s *SyncService) ResolveConflict(cfg SyncConfig, table string, recordID int64, resolution string) error {
+	spec, ok := syncTableSpecs[table]
+	if !ok {
+		return fmt.Errorf("未知表名: %s", table)
+	}
+	if resolution != "local" && resolution != "remote" {
+		return fmt.Errorf("无效的处理方式: %s", resolution)
+	}
+
+	remoteDB, err := s.openRemote(cfg)
+	if err != nil {
+		return err
+	}
+	defer remoteDB.Close()
+
+	remoteTx, err := remoteDB.Begin()
+	if err != nil {
+		return fmt.Errorf("开启云端事务失败: %w", err)
+	}
+	defer remoteTx.Rollback()
+
+	return database.GetDB().Transaction(func(tx *gorm.DB) error {
+		sess := &syncSession{
+			local:  tx,
+			remote: remoteTx,
+			repo:   s.syncRepo.WithTx(tx),
+			dbType: cfg.DBType,
+			target: s.targetKey(cfg),
+		}
+		if err := s.resolveRecord(sess, spec, recordID, resolution); err != nil {
+			return err
+		}
+		if err := remoteTx.Commit(); err != nil {
+			return fmt.Errorf("提交云端事务失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// resolveRecord 按处理方式覆盖单条冲突记录并更新基准版本
+func (s *SyncService) resolveRecord(sess *syncSession, spec syncTableSpec, recordID int64, resolution string) error {
+	// 读取两侧当前记录
+	localRows, err := s.readLocalRowsByID(sess.local, spec, recordID)
+	if err != nil {
+		return fmt.Errorf("读取本地数据失败: %w", err)
+	}
+	remoteRows, err := s.readRemoteRowsByID(sess.remote, sess.dbType, spec, recordID)
+	if err != nil {
+		return fmt.Errorf("读取云端数据失败: %w", err)
+	}
+	localRow, localExists := localRows[recordID]
+	remoteRow, remoteExists := remoteRows[recordID]
+
+	switch {
+	case resolution == "local" && localExists:
+		if err := s.upsertRemote(sess.remote, spec, [][]interface{}{localRow}, sess.dbType); err != nil {
+			return fmt.Errorf("写入云端数据失败: %w", err)
+		}
+		return sess.repo.SaveRowVersions(sess.target, spec.Name, map[int64]string{recordID: syncRowHash(spec, localRow)})
+	case resolution == "local":
+		if _, err := s.deleteByIDs(sess.remote, spec.Name, []int64{recordID}, sess.dbType); err != nil {
+			return fmt.Errorf("删除云端数据失败: %w", err)
+		}
+	case remoteExists:
+		if err := s.upsertLocal(sess.local, spec, remoteRow); err != nil {
+			return fmt.Errorf("写入本地数据失败: %w", err)
+		}
+		return sess.repo.SaveRowVersions(sess.target, spec.Name, map[int64]string{recordID: syncRowHash(spec, remoteRow)})
+	default:
+		if err := s.deleteLocalByIDs(sess.local, spec, []int64{recordID}); err != nil {
+			return fmt.Errorf("删除本地数据失败: %w", err)
+		}
+	}
+
+	return sess.repo.DeleteRowVersions(sess.target, spec.Name, []int64{recordID})
+}
+
+// newConflict 构建冲突描述
+func (s *SyncService) newConflict(spec syncTableSpec, id int64, conflictType string, localRow, remoteRow []interface{}) SyncConflict {
+	return SyncConflict{
+		TableName: spec.Name,
+		RecordID:  id,
+		Type:      conflictType,
+		Local:     syncRowToMap(spec, localRow),
+		Remote:    syncRowToMap(spec, remoteRow),
+	}
+}
+
+// readLocalRows 读取本地记录 (since 非空时仅读取 update_time 晚于 since 的记录)
+func (s *SyncService) readLocalRows(localDB *gorm.DB, spec syncTableSpec, since *time.Time) (map[int64][]interface{}, error) {
+	query := spec.localTable(localDB).Select(spec.Columns)
+	if since != nil {
+		query = query.Where("update_time > ?", *since)
+	}
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result, _, err := scanSyncRows(rows, len(spec.Columns), -1)
+	return result, err
+}
+
+// readLocalRowsByID 按主键读取本地记录
+func (s *SyncService) readLocalRowsByID(localDB *gorm.DB, spec syncTableSpec, id int64) (map[int64][]interface{}, error) {
+	rows, err := spec.localTable(localDB).Select(spec.Columns).Where("id = ?", id).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result, _, err := scanSyncRows(rows, len(spec.Columns), -1)
+	return result, err
+}
+
+// readRemoteRows 读取云端记录 (since 非空时仅读取 update_time 晚于 since 的记录)
+// 同时返回所读记录中最大的 update_time，作为新的云端高水位。
+func (s *SyncService) readRemoteRows(remote syncExecutor, dbType string, spec syncTableSpec, since *time.Time) (map[int64][]interface{}, *time.Time, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(spec.Columns, ", "), spec.Name)
+	var args []interface{}
+	if since != nil {
+		query += " WHERE update_time > " + s.placeholder(dbType, 1)
+		args = append(args, *since)
+	}
+
+	rows, err := remote.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	return scanSyncRows(rows, len(spec.Columns), s.columnIndex(spec, "update_time"))
+}
+
+// readRemoteRowsByID 按主键读取云端记录
+func (s *SyncService) readRemoteRowsByID(remote syncExecutor, dbType string, spec syncTableSpec, id int64) (map[int64][]interface{}, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = %s", strings.Join(spec.Columns, ", "), spec.Name, s.placeholder(dbType, 1))
+	rows, err := remote.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result, _, err := scanSyncRows(rows, len(spec.Columns), -1)
+	return result, err
+}
+
+// readLocalIDs 读取本地表的全部主键 (回收站中的记录视为已删除)
+func (s *SyncService) readLocalIDs(localDB *gorm.DB, spec syncTableSpec) (map[int64]bool, error) {
+	var ids []int64
+	if err := spec.localTable(localDB).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+// readRemoteIDs 读取云端表的全部主键
+func (s *SyncService) readRemoteIDs(remote syncExecutor, table string) (map[int64]bool, error) {
+	rows, err := remote.Query(fmt.Sprintf("SELECT id FROM %s", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[int64]bool)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		result[id] = true
+	}
+	return result, rows.Err()
+}
+
+// upsertLocal 将一行云端记录写入本地 (存在则更新)
+// 保留云端的 update_time，不触发本地删除墓碑。软删除表中位于回收站的记录会被一并恢复 (云端记录仍然存在)。
+func (s *SyncService) upsertLocal(localDB *gorm.DB, spec syncTableSpec, row []interface{}) error {
+	values := make(map[string]interface{}, len(spec.Columns)+1)
+	for i, col := range spec.Columns {
+		values[col] = normalizeSyncValue(row[i])
+	}
+	updates := spec.Columns[1:]
+	if spec.SoftDelete {
+		values["deleted_at"] = nil
+		updates = append(append([]string{}, updates...), "deleted_at")
+	}
+	return localDB.Table(spec.Name).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns(updates),
+	}).Create(values).Error
+}
+
+// deleteLocalByIDs 删除本地记录
+// 使用原生 SQL 执行，不写入墓碑日志 (删除源自云端，无需再推送回去)。
+// 软删除表仅写入删除时间，记录移入回收站，可在保留期内恢复。
+func (s *SyncService) deleteLocalByIDs(localDB *gorm.DB, spec syncTableSpec, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if spec.SoftDelete {
+		return localDB.Exec(fmt.Sprintf("UPDATE %s SET deleted_at = ? WHERE id IN ? AND deleted_at IS NULL", spec.Name), time.Now(), ids).Error
+	}
+	return localDB.Exec(fmt.Sprintf("DELETE FROM %s WHERE id IN ?", spec.Name), ids).Error
+}
+
+// placeholder 返回第 n 个参数占位符
+func (s *SyncService) placeholder(dbType string, n int) string {
+	if dbType == "postgres" {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// columnIndex 返回列在同步列中的位置，不存在返回 -1
+func (s *SyncService) columnIndex(spec syncTableSpec, column string) int {
+	for i, col := range spec.Columns {
+		if col == column {
+			return i
+		}
+	}
+	return -1
+}
+
+// scanSyncRows 将查询结果扫描为 "主键 -> 行值" 的映射
+// timeIndex 指定需要统计最大值的时间列位置 (-1 表示不统计)。
+func scanSyncRows(rows *sql.Rows, columns, timeIndex int) (map[int64][]interface{}, *time.Time, error) {
+	result := make(map[int64][]interface{})
+	var maxTime *time.Time
+
+	for rows.Next() {
+		values := make([]interface{}, columns)
+		pointers := make([]interface{}, columns)
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, nil, err
+		}
+
+		id, err := syncRowID(values[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		result[id] = values
+
+		if timeIndex >= 0 {
+			if t, ok := values[timeIndex].(time.Time); ok && (maxTime == nil || t.After(*maxTime)) {
+				maxTime = &t
+			}
+		}
+	}
+
+	return result, maxTime, rows.Err()
+}
+
+// syncRowID 将主键列的值转换为 int64
+func syncRowID(v interface{}) (int64, error) {
+	switch val := v.(type) {
+	case int64:
+		return val, nil
+	case int32:
+		return int64(val), nil
+	case int:
+		return int64(val), nil
+	case []byte:
+		return strconv.ParseInt(string(val), 10, 64)
+	case string:
+		return strconv.ParseInt(val, 10, 64)
+	default:
+		return 0, fmt.Errorf("无法识别的主键类型: %T", v)
+	}
+}
+
+// syncRowToMap 将行值转换为 "列名 -> 值" 的映射 (用于冲突展示)
+func syncRowToMap(spec syncTableSpec, row []interface{}) map[string]interface{} {
+	if row == nil {
+		return nil
+	}
+	result := make(map[string]interface{}, len(spec.Columns))
+	for i, col := range spec.Columns {
+		result[col] = normalizeSyncValue(row[i])
+	}
+	return result
+}
+
+// normalizeSyncValue 统一驱动返回值的类型 ([]byte -> string)
+func normalizeSyncValue(v interface{}) interface{} {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return v
+}
+
+// syncColumnKind 同步列的值类型，决定哈希前的规范化方式
+type syncColumnKind int
+
+const (
+	syncKindText  syncColumnKind = iota // 文本: 原样比较
+	syncKindInt                         // 整数 (含金额分、布尔): 按精确整数比较
+	syncKindFloat                       // 浮点 (百分比): 按两位小数比较
+	syncKindTime                        // 日期/时间
+)
+
+// syncColumnKinds 缓存各同步表的列类型 (表名 -> 列类型，顺序同 Columns)
+var syncColumnKinds sync.Map
+
+// columnKinds 根据模型定义获取同步列的值类型
+// 模型中找不到的列按文本处理。
+func (spec syncTableSpec) columnKinds() []syncColumnKind {
+	if cached, ok := syncColumnKinds.Load(spec.Name); ok {
+		return cached.([]syncColumnKind)
+	}
+
+	kinds := make([]syncColumnKind, len(spec.Columns))
+	if spec.Model != nil {
+		if sch, err := schema.Parse(spec.Model, &sync.Map{}, schema.NamingStrategy{}); err == nil {
+			for i, col := range spec.Columns {
+				field := sch.LookUpField(col)
+				if field == nil {
+					continue
+				}
+				// 按 Go 字段类型判断 (列的 gorm type 标签如 real/date 会覆盖 DataType)
+				fieldType := field.IndirectFieldType
+				switch {
+				case fieldType == reflect.TypeOf(time.Time{}):
+					kinds[i] = syncKindTime
+				case fieldType.Kind() >= reflect.Int && fieldType.Kind() <= reflect.Uint64, fieldType.Kind() == reflect.Bool:
+					kinds[i] = syncKindInt
+				case fieldType.Kind() == reflect.Float32 || fieldType.Kind() == reflect.Float64:
+					kinds[i] = syncKindFloat
+				}
+			}
+		}
+	}
+	syncColumnKinds.Store(spec.Name, kinds)
+	return kinds
+}
+
+// syncRowHash 计算行内容哈希
+// 不同数据库驱动返回的类型与精度不同 (如 MySQL 文本协议返回 []byte、DATETIME 精度为秒)，
+// 先按列类型将每个值规范化为统一的字符串形式再计算 SHA256，保证同一内容在两侧得到相同的哈希。
+func syncRowHash(spec syncTableSpec, row []interface{}) string {
+	kinds := spec.columnKinds()
+	h := sha256.New()
+	for i, v := range row {
+		kind := syncKindText
+		if i < len(kinds) {
+			kind = kinds[i]
+		}
+		h.Write([]byte(canonicalSyncValue(kind, v)))
+		h.Write([]byte{0x1f})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalSyncValue 按列类型将单个值规范化为字符串
+// 无法按列类型解析的值 (如云端列类型与模型不一致) 退回原样格式化，宁可判为不同也不误判为相同。
+func canonicalSyncValue(kind syncColumnKind, v interface{}) string {
+	if v == nil {
+		return "\x00"
+	}
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+
+	switch kind {
+	case syncKindInt:
+		if n, ok := canonicalSyncInt(v); ok {
+			return n
+		}
+	case syncKindFloat:
+		if f, ok := canonicalSyncFloat(v); ok {
+			return f
+		}
+	case syncKindTime:
+		if t, ok := canonicalSyncTime(v); ok {
+			return t
+		}
+	}
+
+	switch val := v.(type) {
+	case string:
+		return val
+	case time.Time:
+		return val.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// canonicalSyncInt 将整数列的值规范化为十进制整数
+// 字符串按十进制整数解析 (前导零视为同一数值)；浮点值仅在为整数时接受，
+// 超出 float64 精确表示范围 (2^53) 的整数只接受整数类型或整数字符串。
+func canonicalSyncInt(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case int64:
+		return strconv.FormatInt(val, 10), true
+	case int32:
+		return strconv.FormatInt(int64(val), 10), true
+	case int:
+		return strconv.FormatInt(int64(val), 10), true
+	case uint64:
+		return strconv.FormatUint(val, 10), true
+	case bool:
+		if val {
+			return "1", true
+		}
+		return "0", true
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1<<53 {
+			return strconv.FormatInt(int64(val), 10), true
+		}
+	case float32:
+		return canonicalSyncInt(float64(val))
+	case string:
+		if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return strconv.FormatInt(n, 10), true
+		}
+		// DECIMAL 列以 "123.00" 形式返回，小数部分全为零时视为整数
+		if whole, frac, ok := strings.Cut(val, "."); ok && strings.Trim(frac, "0") == "" {
+			if n, err := strconv.ParseInt(whole, 10, 64); err == nil {
+				return strconv.FormatInt(n, 10), true
+			}
+		}
+	}
+	return "", false
+}
+
+// canonicalSyncFloat 将浮点列的值规范化为两位小数
+// 百分比以两位小数保存，云端 REAL (单精度) 列的舍入误差在此精度下被消除。
+func canonicalSyncFloat(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case float64:
+		return strconv.FormatFloat(val, 'f', 2, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', 2, 64), true
+	case int64:
+		return strconv.FormatFloat(float64(val), 'f', 2, 64), true
+	case int:
+		return strconv.FormatFloat(float64(val), 'f', 2, 64), true
+	case string:
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return strconv.FormatFloat(f, 'f', 2, 64), true
+		}
+	}
+	return "", false
+}
+
+// canonicalSyncTime 将时间列的值规范化
+// 仅有日期部分的值 (DATE 列) 按所在时区的日期比较，其余统一为 UTC 秒级精度。
+func canonicalSyncTime(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case time.Time:
+		if val.Hour() == 0 && val.Minute() == 0 && val.Second() == 0 && val.Nanosecond() == 0 {
+			return val.Format("2006-01-02"), true
+		}
+		return val.UTC().Truncate(time.Second).Format(time.RFC3339), true
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05", "2006-01-02"} {
+			if t, err := time.Parse(layout, val); err == nil {
+				return canonicalSyncTime(t)
+			}
+		}
+	}
+	return "", false
+}
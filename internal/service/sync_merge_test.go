@@ -0,0 +1,290 @@
+package service
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestCanonicalSyncValue(t *testing.T) {
+	cst := time.FixedZone("CST", 8*3600)
+	tests := []struct {
+		name      string
+		kind      syncColumnKind
+		a, b      interface{}
+		wantEqual bool
+	}{
+		{name: "整数与整数字符串", kind: syncKindInt, a: int64(123), b: "123", wantEqual: true},
+		{name: "整数前导零", kind: syncKindInt, a: "0123", b: int64(123), wantEqual: true},
+		{name: "DECIMAL 整数", kind: syncKindInt, a: []byte("123.00"), b: int64(123), wantEqual: true},
+		{name: "整数值的浮点数", kind: syncKindInt, a: float64(123), b: int32(123), wantEqual: true},
+		{name: "布尔与整数", kind: syncKindInt, a: true, b: int64(1), wantEqual: true},
+		{name: "大整数精确比较", kind: syncKindInt, a: int64(1<<53 + 1), b: "9007199254740993", wantEqual: true},
+		{name: "大整数不经浮点比较", kind: syncKindInt, a: int64(1<<53 + 1), b: float64(1 << 53), wantEqual: false},
+		{name: "带小数的整数列", kind: syncKindInt, a: "123.50", b: int64(123), wantEqual: false},
+		{name: "不同整数", kind: syncKindInt, a: int64(123), b: int64(124), wantEqual: false},
+		{name: "文本保留前导零", kind: syncKindText, a: "0123", b: "123", wantEqual: false},
+		{name: "文本保留空白", kind: syncKindText, a: "a ", b: "a", wantEqual: false},
+		{name: "文本与字节", kind: syncKindText, a: []byte("备注"), b: "备注", wantEqual: true},
+		{name: "浮点两位小数", kind: syncKindFloat, a: 33.333333, b: float32(33.33), wantEqual: true},
+		{name: "浮点字符串", kind: syncKindFloat, a: "12.5", b: 12.5, wantEqual: true},
+		{name: "浮点整数", kind: syncKindFloat, a: int64(50), b: 50.0, wantEqual: true},
+		{name: "不同浮点", kind: syncKindFloat, a: 12.5, b: 12.51, wantEqual: false},
+		{name: "日期与日期字符串", kind: syncKindTime, a: time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local), b: "2026-03-01", wantEqual: true},
+		{name: "时间截断到秒", kind: syncKindTime, a: time.Date(2026, 3, 1, 8, 30, 15, 123456789, time.UTC), b: "2026-03-01 08:30:15", wantEqual: true},
+		{name: "时间按 UTC 比较", kind: syncKindTime, a: time.Date(2026, 3, 1, 16, 30, 15, 0, cst), b: "2026-03-01T08:30:15Z", wantEqual: true},
+		{name: "不同时间", kind: syncKindTime, a: "2026-03-01 08:30:15", b: "2026-03-01 08:30:16", wantEqual: false},
+		{name: "空值与空字符串", kind: syncKindText, a: nil, b: "", wantEqual: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := canonicalSyncValue(tt.kind, tt.a), canonicalSyncValue(tt.kind, tt.b)
+			if (a == b) != tt.wantEqual {
+				t.Errorf("canonicalSyncValue(%v) = %q, canonicalSyncValue(%v) = %q, want equal %v", tt.a, a, tt.b, b, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func TestSyncRowHash(t *testing.T) {
+	spec := syncTableSpecs["payments"]
+	// 列顺序: id, project_id, stage, amount, percentage, plan_date, status, actual_date, method,
+	// received_amount, received_percentage, refunded_amount, remark, schedule_id, schedule_seq, user_id, create_time, update_time
+	local := []interface{}{
+		int64(1), int64(2), "首付款", int64(123456), 30.0, time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local), "paid", nil, "cash",
+		int64(123456), 100.0, int64(0), "", nil, int64(0), int64(1), time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 8, 0, 0, 500, time.UTC),
+	}
+	// MySQL 文本协议返回的同一行: 数值与时间均为 []byte，DECIMAL 带小数位
+	remote := []interface{}{
+		[]byte("1"), []byte("2"), []byte("首付款"), []byte("123456.00"), []byte("30"), []byte("2026-03-01"), []byte("paid"), nil, []byte("cash"),
+		[]byte("123456"), float32(100), []byte("0"), []byte(""), nil, []byte("0"), []byte("1"), []byte("2026-03-01 08:00:00"), []byte("2026-03-02 08:00:00"),
+	}
+	if syncRowHash(spec, local) != syncRowHash(spec, remote) {
+		t.Error("syncRowHash() differs for equivalent rows from different drivers")
+	}
+
+	remote[3] = []byte("123457.00")
+	if syncRowHash(spec, local) == syncRowHash(spec, remote) {
+		t.Error("syncRowHash() equal for rows with different amounts")
+	}
+}
+
+func TestSyncServiceMergeTable(t *testing.T) {
+	s := NewSyncService()
+	spec := syncTableSpecs["dictionaries"]
+
+	tests := []struct {
+		name         string
+		local        []string // 首次同步后在本地执行的语句
+		remote       []string // 首次同步后在云端执行的语句
+		wantLocal    string   // 合并后本地记录的名称 (空表示已删除)
+		wantRemote   string   // 合并后云端记录的名称 (空表示已删除)
+		wantConflict string
+		wantPulled   int64
+		wantSynced   int64
+		wantDeleted  int64
+	}{
+		{
+			name:      "两侧均未修改",
+			wantLocal: "原始", wantRemote: "原始",
+		},
+		{
+			name:      "仅本地修改",
+			local:     []string{"UPDATE dictionaries SET name = '本地' WHERE id = 1"},
+			wantLocal: "本地", wantRemote: "本地", wantSynced: 1,
+		},
+		{
+			name:      "仅云端修改",
+			remote:    []string{"UPDATE dictionaries SET name = '云端' WHERE id = 1"},
+			wantLocal: "云端", wantRemote: "云端", wantPulled: 1,
+		},
+		{
+			name:      "两侧修改为相同内容",
+			local:     []string{"UPDATE dictionaries SET name = '相同' WHERE id = 1"},
+			remote:    []string{"UPDATE dictionaries SET name = '相同' WHERE id = 1"},
+			wantLocal: "相同", wantRemote: "相同",
+		},
+		{
+			name:      "两侧修改为不同内容",
+			local:     []string{"UPDATE dictionaries SET name = '本地' WHERE id = 1"},
+			remote:    []string{"UPDATE dictionaries SET name = '云端' WHERE id = 1"},
+			wantLocal: "本地", wantRemote: "云端", wantConflict: ConflictBothUpdated,
+		},
+		{
+			name:        "本地删除",
+			local:       []string{"DELETE FROM dictionaries WHERE id = 1"},
+			wantDeleted: 1,
+		},
+		{
+			name:        "云端删除",
+			remote:      []string{"DELETE FROM dictionaries WHERE id = 1"},
+			wantDeleted: 1,
+		},
+		{
+			name:       "本地删除且云端修改",
+			local:      []string{"DELETE FROM dictionaries WHERE id = 1"},
+			remote:     []string{"UPDATE dictionaries SET name = '云端' WHERE id = 1"},
+			wantRemote: "云端", wantConflict: ConflictLocalDeleted,
+		},
+		{
+			name:      "云端删除且本地修改",
+			local:     []string{"UPDATE dictionaries SET name = '本地' WHERE id = 1"},
+			remote:    []string{"DELETE FROM dictionaries WHERE id = 1"},
+			wantLocal: "本地", wantConflict: ConflictRemoteDeleted,
+		},
+		{
+			name:   "两侧均删除",
+			local:  []string{"DELETE FROM dictionaries WHERE id = 1"},
+			remote: []string{"DELETE FROM dictionaries WHERE id = 1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess := newTestSyncSession(t)
+			now := time.Now().Truncate(time.Second)
+			insert := "INSERT INTO dictionaries (id, code, name, status, remark, create_time, update_time) VALUES (1, 'merge_test', '原始', 1, '', ?, ?)"
+			mustExec(t, sess.local.Exec("DELETE FROM dictionaries").Error)
+			mustExec(t, sess.local.Exec(insert, now, now).Error)
+			if _, err := sess.remote.Exec(insert, now, now); err != nil {
+				t.Fatalf("prepare remote: %v", err)
+			}
+
+			// 首次同步: 两侧内容一致，仅记录基准版本
+			if result := runMerge(t, s, sess, spec); result.PulledCount+result.SyncedCount+result.DeletedCount != 0 {
+				t.Fatalf("initial merge changed rows: %+v", result)
+			}
+
+			for _, stmt := range tt.local {
+				mustExec(t, sess.local.Exec(stmt).Error)
+			}
+			for _, stmt := range tt.remote {
+				if _, err := sess.remote.Exec(stmt); err != nil {
+					t.Fatalf("prepare remote: %v", err)
+				}
+			}
+
+			result := runMerge(t, s, sess, spec)
+			if result.PulledCount != tt.wantPulled || result.SyncedCount != tt.wantSynced || result.DeletedCount != tt.wantDeleted {
+				t.Errorf("pulled/synced/deleted = %d/%d/%d, want %d/%d/%d",
+					result.PulledCount, result.SyncedCount, result.DeletedCount, tt.wantPulled, tt.wantSynced, tt.wantDeleted)
+			}
+			switch {
+			case tt.wantConflict == "" && len(result.Conflicts) > 0:
+				t.Errorf("unexpected conflicts: %+v", result.Conflicts)
+			case tt.wantConflict != "" && (len(result.Conflicts) != 1 || result.Conflicts[0].Type != tt.wantConflict):
+				t.Errorf("conflicts = %+v, want one %s", result.Conflicts, tt.wantConflict)
+			}
+
+			var localName string
+			sess.local.Raw("SELECT name FROM dictionaries WHERE id = 1").Scan(&localName)
+			var remoteName string
+			if err := sess.remote.(*sql.DB).QueryRow("SELECT name FROM dictionaries WHERE id = 1").Scan(&remoteName); err != nil && err != sql.ErrNoRows {
+				t.Fatalf("read remote: %v", err)
+			}
+			if localName != tt.wantLocal || remoteName != tt.wantRemote {
+				t.Errorf("local/remote name = %q/%q, want %q/%q", localName, remoteName, tt.wantLocal, tt.wantRemote)
+			}
+
+			// 再次合并不应产生任何变更
+			if again := runMerge(t, s, sess, spec); again.PulledCount+again.SyncedCount+again.DeletedCount != 0 {
+				t.Errorf("second merge changed rows: %+v", again)
+			}
+		})
+	}
+}
+
+func TestSyncServiceDeleteLocalByIDs(t *testing.T) {
+	s := NewSyncService()
+	tests := []struct {
+		name        string
+		table       string
+		insert      string
+		wantRows    int64 // 删除后表中剩余的行数 (含回收站)
+		wantDeleted int64 // 删除后位于回收站的行数
+	}{
+		{
+			name:        "软删除表移入回收站",
+			table:       "projects",
+			insert:      "INSERT INTO projects (id, name, company, total_amount, status, type, start_date, end_date, user_id) VALUES (1, 'P', 'C', 0, 'active', 'web', '2026-01-01', '2026-12-31', 1)",
+			wantRows:    1,
+			wantDeleted: 1,
+		},
+		{
+			name:     "普通表直接删除",
+			table:    "dictionaries",
+			insert:   "INSERT INTO dictionaries (id, code, name, status) VALUES (1, 'delete_test', 'D', 1)",
+			wantRows: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := database.GetDB().Begin()
+			defer tx.Rollback()
+			mustExec(t, tx.Exec("DELETE FROM "+tt.table).Error)
+			mustExec(t, tx.Exec(tt.insert).Error)
+
+			if err := s.deleteLocalByIDs(tx, syncTableSpecs[tt.table], []int64{1}); err != nil {
+				t.Fatalf("deleteLocalByIDs() unexpected error: %v", err)
+			}
+
+			var rows int64
+			mustExec(t, tx.Table(tt.table).Count(&rows).Error)
+			if rows != tt.wantRows {
+				t.Errorf("rows = %d, want %d", rows, tt.wantRows)
+			}
+			if tt.wantDeleted > 0 {
+				var deleted int64
+				mustExec(t, tx.Table(tt.table).Where("deleted_at IS NOT NULL").Count(&deleted).Error)
+				if deleted != tt.wantDeleted {
+					t.Errorf("deleted rows = %d, want %d", deleted, tt.wantDeleted)
+				}
+			}
+		})
+	}
+}
+
+// newTestSyncSession 创建以临时 SQLite 文件为云端的同步会话
+// 本地写入位于测试结束后回滚的事务中。
+func newTestSyncSession(t *testing.T) *syncSession {
+	t.Helper()
+	remoteGorm, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "remote.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open remote: %v", err)
+	}
+	if err := remoteGorm.AutoMigrate(&models.Dictionary{}); err != nil {
+		t.Fatalf("migrate remote: %v", err)
+	}
+	remote, err := remoteGorm.DB()
+	if err != nil {
+		t.Fatalf("open remote: %v", err)
+	}
+	t.Cleanup(func() { remote.Close() })
+
+	local := database.GetDB().Begin()
+	t.Cleanup(func() { local.Rollback() })
+
+	return &syncSession{
+		local:  local,
+		remote: remote,
+		repo:   repository.NewSyncRepository().WithTx(local),
+		dbType: "sqlite",
+		target: "sqlite://merge-test",
+	}
+}
+
+// runMerge 执行一次全量双向合并
+func runMerge(t *testing.T, s *SyncService, sess *syncSession, spec syncTableSpec) SyncResult {
+	t.Helper()
+	result := SyncResult{TableName: spec.Name}
+	if msg := s.mergeTable(sess, spec, &models.SyncCheckpoint{}, false, &result); msg != "" {
+		t.Fatalf("mergeTable() failed: %s", msg)
+	}
+	return result
+}
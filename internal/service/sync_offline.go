@@ -0,0 +1,507 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// 离线导出格式
+const (
+	OfflineFormatSQLite = "sqlite" // 独立 SQLite 文件
+	OfflineFormatBundle = "bundle" // gzip 压缩的 JSON 数据包
+)
+
+// 离线数据包标识
+const (
+	bundleFormat  = "orange-sync-bundle"
+	bundleVersion = 2
+)
+
+// sqliteFileHeader SQLite 数据库文件头
+var sqliteFileHeader = []byte("SQLite format 3\x00")
+
+// syncBundle JSON 数据包结构
+type syncBundle struct {
+	Format        string        `json:"format"`         // 固定为 orange-sync-bundle
+	Version       int           `json:"version"`        // 数据包版本
+	SchemaVersion int64         `json:"schema_version"` // 导出时的数据库迁移版本
+	CreatedAt     time.Time     `json:"created_at"`     // 导出时间
+	Tables        []bundleTable `json:"tables"`         // 各表数据
+}
+
+// bundleTable 数据包中的单表数据
+type bundleTable struct {
+	Name    string          `json:"name"`    // 表名
+	Columns []string        `json:"columns"` // 列名 (首列为 id)
+	Rows    [][]interface{} `json:"rows"`    // 行数据 (按 columns 顺序)
+}
+
+// Export 将所选表导出为离线文件 (供无网络环境使用)
+// sqlite 格式复用同步流程全量推送至独立 SQLite 文件 (已存在时覆盖同名表数据)；
+// bundle 格式写入 gzip 压缩的 JSON 数据包。文件统一写入离线同步目录。
+//
+// 参数:
+//   - format: 导出格式 (sqlite, bundle)
+//   - name: 目标文件名 (不含目录)
+//   - tables: 导出的表，为空表示全部同步表
+func (s *SyncService) Export(format, name string, tables []string) ([]SyncResult, error) {
+	path, err := resolveOfflineFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		tables = database.SyncTables
+	}
+	if format != OfflineFormatSQLite && format != OfflineFormatBundle {
+		return nil, fmt.Errorf("不支持的导出格式: %s", format)
+	}
+	if err := os.MkdirAll(config.AppConfig.OfflineSyncDir, 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	if format == OfflineFormatSQLite {
+		return s.SyncTables(SyncConfig{DBType: "sqlite", Path: path}, tables, SyncOptions{Full: true, Atomic: true})
+	}
+	return s.exportBundle(path, s.orderTables(tables))
+}
+
+// resolveOfflineFile 校验离线文件名并返回离线同步目录下的完整路径
+// 只接受不含目录的文件名，防止路径穿越读写任意文件。
+func resolveOfflineFile(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || filepath.Base(name) != name {
+		return "", errors.New("无效的离线文件名")
+	}
+	return filepath.Join(config.AppConfig.OfflineSyncDir, name), nil
+}
+
+// exportBundle 导出 JSON 数据包
+// 先写入临时文件，成功后再重命名，避免中途失败留下不完整的数据包。
+func (s *SyncService) exportBundle(path string, tables []string) ([]SyncResult, error) {
+	localDB := database.GetDB()
+	version, err := database.SchemaVersion(localDB)
+	if err != nil {
+		return nil, fmt.Errorf("读取数据库版本失败: %w", err)
+	}
+	bundle := syncBundle{Format: bundleFormat, Version: bundleVersion, SchemaVersion: version, CreatedAt: time.Now()}
+	results := make([]SyncResult, 0, len(tables))
+
+	for _, table := range tables {
+		spec, ok := syncTableSpecs[table]
+		if !ok {
+			return nil, fmt.Errorf("未知表名: %s", table)
+		}
+
+		rows, err := spec.localTable(localDB).Select(spec.Columns).Order("id ASC").Rows()
+		if err != nil {
+			return nil, fmt.Errorf("读取本地数据失败: %w", err)
+		}
+		data, err := scanOrderedRows(rows, len(spec.Columns))
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("读取本地数据失败: %w", err)
+		}
+
+		bundle.Tables = append(bundle.Tables, bundleTable{Name: spec.Name, Columns: spec.Columns, Rows: data})
+		results = append(results, SyncResult{
+			TableName:   table,
+			Direction:   "export",
+			Mode:        "full",
+			SyncedCount: int64(len(data)),
+			Success:     true,
+		})
+	}
+
+	tmpPath := path + ".tmp"
+	if err := writeGzipJSON(tmpPath, &bundle); err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("写入数据包失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return nil, fmt.Errorf("写入数据包失败: %w", err)
+	}
+
+	return results, nil
+}
+
+// writeGzipJSON 将数据以 gzip 压缩的 JSON 写入文件
+func writeGzipJSON(path string, v interface{}) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	if err := json.NewEncoder(gz).Encode(v); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// Import 将离线文件 (SQLite 文件或 JSON 数据包) 合并至本地
+// 按文件头自动识别格式。合并语义与同步一致：按主键 UPSERT，不删除本地已有记录；
+// 导入的记录 update_time 更新为导入时间，视为本地变更，后续增量推送会将其同步至云端。
+// 全部表在同一本地事务中导入，任一表失败则整体回滚。文件须位于离线同步目录。
+//
+// 参数:
+//   - name: 离线文件名 (不含目录)
+func (s *SyncService) Import(name string) ([]SyncResult, error) {
+	path, err := resolveOfflineFile(name)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	header, _ := reader.Peek(len(sqliteFileHeader))
+
+	var tables []bundleTable
+	switch {
+	case bytes.Equal(header, sqliteFileHeader):
+		tables, err = s.readSQLiteFile(path)
+	case len(header) >= 2 && header[0] == 0x1f && header[1] == 0x8b:
+		tables, err = readBundle(reader)
+	default:
+		return nil, errors.New("无法识别的文件格式")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.importTables(tables)
+}
+
+// readBundle 读取并校验 JSON 数据包
+func readBundle(r io.Reader) ([]bundleTable, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("解压数据包失败: %w", err)
+	}
+	defer gz.Close()
+
+	var bundle syncBundle
+	decoder := json.NewDecoder(gz)
+	decoder.UseNumber()
+	if err := decoder.Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("解析数据包失败: %w", err)
+	}
+	if bundle.Format != bundleFormat {
+		return nil, errors.New("无法识别的数据包格式")
+	}
+	if bundle.Version > bundleVersion {
+		return nil, fmt.Errorf("数据包版本 %d 过高，请升级应用后再导入", bundle.Version)
+	}
+	// 版本 1 的数据包未记录数据库版本，无法区分金额以元还是以分存储
+	if bundle.SchemaVersion < database.MoneyMinorUnitsVersion {
+		return nil, errors.New("数据包由旧版本导出 (金额以元为单位)，请升级导出端应用后重新导出")
+	}
+	return bundle.Tables, nil
+}
+
+// readSQLiteFile 读取 SQLite 离线文件中的同步表
+// 仅读取文件中存在的表与列，兼容由旧版本导出的文件；旧版本文件中以元存储的金额列 (浮点类型) 换算为分。
+func (s *SyncService) readSQLiteFile(path string) ([]bundleTable, error) {
+	src, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("打开 SQLite 文件失败: %w", err)
+	}
+	defer src.Close()
+
+	var tables []bundleTable
+	for _, table := range database.SyncTables {
+		columns, err := sqliteColumns(src, table)
+		if err != nil {
+			return nil, fmt.Errorf("读取表 %s 结构失败: %w", table, err)
+		}
+		if len(columns) == 0 {
+			continue // 文件中不存在该表
+		}
+
+		// 只读取与本地同步列的交集
+		var selected []string
+		for _, col := range syncTableSpecs[table].Columns {
+			if _, ok := columns[col]; ok {
+				selected = append(selected, col)
+			}
+		}
+
+		rows, err := src.Query(fmt.Sprintf("SELECT %s FROM %s ORDER BY id", strings.Join(selected, ", "), table))
+		if err != nil {
+			return nil, fmt.Errorf("读取表 %s 失败: %w", table, err)
+		}
+		data, err := scanOrderedRows(rows, len(selected))
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("读取表 %s 失败: %w", table, err)
+		}
+		if err := convertYuanColumns(table, selected, columns, data); err != nil {
+			return nil, fmt.Errorf("读取表 %s 失败: %w", table, err)
+		}
+
+		tables = append(tables, bundleTable{Name: table, Columns: selected, Rows: data})
+	}
+	return tables, nil
+}
+
+// convertYuanColumns 将以元存储的金额列 (列类型为浮点/定点小数) 换算为分
+func convertYuanColumns(table string, selected []string, types map[string]string, data [][]interface{}) error {
+	for _, col := range syncMoneyColumns[table] {
+		if !isDecimalColumnType(strings.ToLower(types[col])) {
+			continue
+		}
+		for i, name := range selected {
+			if name != col {
+				continue
+			}
+			for _, row := range data {
+				if row[i] == nil {
+					continue
+				}
+				yuan, err := strconv.ParseFloat(fmt.Sprint(normalizeSyncValue(row[i])), 64)
+				if err != nil {
+					return fmt.Errorf("无法识别的金额 %v", row[i])
+				}
+				row[i] = int64(math.Round(yuan * 100))
+			}
+		}
+	}
+	return nil
+}
+
+// sqliteColumns 获取 SQLite 表的列名及声明类型 (表不存在时返回空)
+func sqliteColumns(db *sql.DB, table string) (map[string]string, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]string)
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue interface{}
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return nil, err
+		}
+		columns[name] = colType
+	}
+	return columns, rows.Err()
+}
+
+// importTables 在本地事务中按依赖顺序 UPSERT 各表数据
+func (s *SyncService) importTables(tables []bundleTable) ([]SyncResult, error) {
+	byName := make(map[string]bundleTable, len(tables))
+	names := make([]string, 0, len(tables))
+	for _, t := range tables {
+		byName[t.Name] = t
+		names = append(names, t.Name)
+	}
+
+	now := time.Now()
+	results := make([]SyncResult, 0, len(tables))
+
+	err := database.GetDB().Transaction(func(tx *gorm.DB) error {
+		for _, name := range s.orderTables(names) {
+			spec, ok := syncTableSpecs[name]
+			if !ok {
+				// 未知表 (如更高版本新增的表) 跳过，不影响其他表
+				results = append(results, SyncResult{TableName: name, Direction: "import", Mode: "full", ErrorMessage: "未知表名，已跳过"})
+				continue
+			}
+
+			count, err := s.importTable(tx, spec, byName[name], now)
+			if err != nil {
+				return fmt.Errorf("导入表 %s 失败: %w", name, err)
+			}
+			results = append(results, SyncResult{
+				TableName:   name,
+				Direction:   "import",
+				Mode:        "full",
+				PulledCount: count,
+				Success:     true,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// importTable 导入单表数据，返回写入的记录数
+func (s *SyncService) importTable(tx *gorm.DB, spec syncTableSpec, table bundleTable, now time.Time) (int64, error) {
+	// 1. 取数据包列与本地同步列的交集
+	known := make(map[string]bool, len(spec.Columns))
+	for _, col := range spec.Columns {
+		known[col] = true
+	}
+	indexes := make(map[string]int)
+	var columns []string
+	for i, col := range table.Columns {
+		if known[col] {
+			indexes[col] = i
+			columns = append(columns, col)
+		}
+	}
+	if _, ok := indexes["id"]; !ok {
+		return 0, errors.New("缺少 id 列")
+	}
+
+	// 2. 识别时间列，JSON 中的时间字符串需要解析后再写入
+	timeColumns, err := s.timeColumns(tx, spec)
+	if err != nil {
+		return 0, err
+	}
+
+	// update_time 统一改写为导入时间 (数据包中缺少该列时补充)
+	if _, ok := indexes["update_time"]; known["update_time"] && !ok {
+		columns = append(columns, "update_time")
+	}
+	updates := make([]string, 0, len(columns))
+	for _, col := range columns {
+		if col != "id" {
+			updates = append(updates, col)
+		}
+	}
+
+	// 3. 分批 UPSERT
+	var count int64
+	batch := make([]map[string]interface{}, 0, syncBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := tx.Table(spec.Name).Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns(updates),
+		}).Create(&batch).Error
+		count += int64(len(batch))
+		batch = batch[:0]
+		return err
+	}
+
+	for _, row := range table.Rows {
+		if len(row) != len(table.Columns) {
+			return count, errors.New("行数据与列定义不匹配")
+		}
+		values := make(map[string]interface{}, len(columns))
+		for _, col := range columns {
+			if col == "update_time" {
+				values[col] = now
+				continue
+			}
+			v, err := importValue(row[indexes[col]], timeColumns[col])
+			if err != nil {
+				return count, fmt.Errorf("列 %s: %w", col, err)
+			}
+			values[col] = v
+		}
+
+		batch = append(batch, values)
+		if len(batch) == syncBatchSize {
+			if err := flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// timeColumns 根据模型定义获取表中的时间列
+func (s *SyncService) timeColumns(db *gorm.DB, spec syncTableSpec) (map[string]bool, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(spec.Model); err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]bool)
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName != "" && field.DataType == schema.Time {
+			columns[field.DBName] = true
+		}
+	}
+	return columns, nil
+}
+
+// importValue 将离线文件中的值转换为可写入本地库的类型
+func importValue(v interface{}, isTime bool) (interface{}, error) {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i, nil
+		}
+		return val.Float64()
+	case []byte:
+		return importValue(string(val), isTime)
+	case string:
+		if !isTime || val == "" {
+			return val, nil
+		}
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05", "2006-01-02"} {
+			if t, err := time.ParseInLocation(layout, val, time.Local); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("无法解析时间: %s", val)
+	default:
+		return val, nil
+	}
+}
+
+// scanOrderedRows 按查询顺序扫描全部行
+func scanOrderedRows(rows *sql.Rows, columns int) ([][]interface{}, error) {
+	result := make([][]interface{}, 0)
+	for rows.Next() {
+		values := make([]interface{}, columns)
+		pointers := make([]interface{}, columns)
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		for i := range values {
+			values[i] = normalizeSyncValue(values[i])
+		}
+		result = append(result, values)
+	}
+	return result, rows.Err()
+}
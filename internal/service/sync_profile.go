@@ -0,0 +1,398 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/crypto"
+	"github.com/FruitsAI/Orange/internal/repository"
+)
+
+// 同步触发方式
+const (
+	SyncTriggerManual   = "manual"   // 手动执行
+	SyncTriggerSchedule = "schedule" // 定时执行
+)
+
+// 同步历史状态
+const (
+	SyncStatusRunning = "running" // 执行中
+	SyncStatusSuccess = "success" // 全部成功
+	SyncStatusPartial = "partial" // 部分表失败
+	SyncStatusFailed  = "failed"  // 全部失败
+)
+
+// syncRunMu 同一时间仅允许一个同步任务执行 (手动与定时共用)，避免本地事务互相阻塞
+var syncRunMu sync.Mutex
+
+// SyncProfileService 同步配置服务
+// 负责同步配置的管理、按配置执行同步以及同步历史的记录。
+type SyncProfileService struct {
+	profileRepo *repository.SyncProfileRepository
+	syncService *SyncService
+}
+
+// NewSyncProfileService 创建同步配置服务实例
+func NewSyncProfileService() *SyncProfileService {
+	return &SyncProfileService{
+		profileRepo: repository.NewSyncProfileRepository(),
+		syncService: NewSyncService(),
+	}
+}
+
+// List 获取全部同步配置
+func (s *SyncProfileService) List() ([]models.SyncProfile, error) {
+	return s.profileRepo.List()
+}
+
+// Get 获取同步配置详情
+func (s *SyncProfileService) Get(id int64) (*models.SyncProfile, error) {
+	profile, err := s.profileRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("同步配置不存在")
+	}
+	return profile, nil
+}
+
+// Create 创建同步配置
+// 密码加密后存储；启用定时同步时从当前时间起计算下次执行时间。
+func (s *SyncProfileService) Create(req *dto.SyncProfileRequest, userID int64) (*models.SyncProfile, error) {
+	if req.Password == "" && req.DBType != "sqlite" {
+		return nil, errors.New("密码不能为空")
+	}
+
+	profile := &models.SyncProfile{UserID: userID}
+	if err := s.applyRequest(profile, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.profileRepo.Create(profile); err != nil {
+		return nil, errors.New("创建同步配置失败")
+	}
+	return profile, nil
+}
+
+// Update 更新同步配置
+// 请求中密码为空时保留原密码。
+func (s *SyncProfileService) Update(id int64, req *dto.SyncProfileRequest) (*models.SyncProfile, error) {
+	profile, err := s.profileRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("同步配置不存在")
+	}
+
+	if err := s.applyRequest(profile, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.profileRepo.Update(profile); err != nil {
+		return nil, errors.New("更新同步配置失败")
+	}
+	return profile, nil
+}
+
+// Delete 删除同步配置 (历史记录保留)
+// 没有其他配置指向同一云端库时，一并删除该目标的检查点与行基准版本，避免其阻止墓碑清理。
+func (s *SyncProfileService) Delete(id int64) error {
+	profile, err := s.profileRepo.FindByID(id)
+	if err != nil {
+		return errors.New("同步配置不存在")
+	}
+	if err := s.profileRepo.Delete(id); err != nil {
+		return err
+	}
+
+	target := s.profileTarget(profile)
+	profiles, err := s.profileRepo.List()
+	if err != nil {
+		slog.Warn("清理同步检查点失败", "target", target, "error", err)
+		return nil
+	}
+	for i := range profiles {
+		if s.profileTarget(&profiles[i]) == target {
+			return nil
+		}
+	}
+	if err := s.syncService.syncRepo.DeleteTarget(target); err != nil {
+		slog.Warn("清理同步检查点失败", "target", target, "error", err)
+	}
+	return nil
+}
+
+// profileTarget 获取同步配置对应的同步目标标识
+func (s *SyncProfileService) profileTarget(profile *models.SyncProfile) string {
+	return s.syncService.targetKey(SyncConfig{
+		DBType: profile.DBType,
+		Host:   profile.Host,
+		Port:   profile.Port,
+		User:   profile.User,
+		DBName: profile.DBName,
+		Path:   profile.Path,
+	})
+}
+
+// applyRequest 校验请求并写入配置模型
+func (s *SyncProfileService) applyRequest(profile *models.SyncProfile, req *dto.SyncProfileRequest) error {
+	if err := s.syncService.validateConfig(SyncConfig{
+		DBType: req.DBType,
+		Host:   req.Host,
+		Port:   req.Port,
+		User:   req.User,
+		DBName: req.DBName,
+		Path:   req.Path,
+	}); err != nil {
+		return err
+	}
+
+	direction := req.Direction
+	if direction == "" {
+		direction = SyncDirectionPush
+	}
+	if direction != SyncDirectionPush && direction != SyncDirectionPull && direction != SyncDirectionMerge {
+		return fmt.Errorf("不支持的同步方向: %s", direction)
+	}
+
+	for _, table := range req.Tables {
+		if _, ok := syncTableSpecs[table]; !ok {
+			return fmt.Errorf("未知表名: %s", table)
+		}
+	}
+
+	if req.IntervalMinutes < 0 {
+		return errors.New("同步间隔不能为负数")
+	}
+
+	exists, err := s.profileRepo.ExistsByName(req.Name, profile.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("配置名称已存在")
+	}
+
+	if req.Password != "" {
+		encrypted, err := crypto.Encrypt(req.Password)
+		if err != nil {
+			return errors.New("密码加密失败")
+		}
+		profile.Password = encrypted
+	}
+
+	profile.Name = req.Name
+	profile.DBType = req.DBType
+	profile.Host = req.Host
+	profile.Port = req.Port
+	profile.User = req.User
+	profile.DBName = req.DBName
+	profile.SSLMode = req.SSLMode
+	profile.Path = req.Path
+	profile.Tables = strings.Join(req.Tables, ",")
+	profile.Direction = direction
+	profile.Atomic = boolToInt(req.Atomic)
+	profile.IntervalMinutes = req.IntervalMinutes
+	profile.Enabled = boolToInt(req.Enabled)
+
+	// 重新计算下次执行时间
+	profile.NextRunTime = nil
+	if profile.Enabled == 1 && profile.IntervalMinutes > 0 {
+		next := time.Now().Add(time.Duration(profile.IntervalMinutes) * time.Minute)
+		profile.NextRunTime = &next
+	}
+	return nil
+}
+
+// Run 按配置执行一次同步并记录历史
+//
+// 参数:
+//   - id: 同步配置ID
+//   - trigger: 触发方式 (manual, schedule)
+//   - userID: 触发用户ID (定时同步为 0)
+//
+// 返回:
+//   - *models.SyncHistory: 本次同步的历史记录
+//   - error: 配置不存在或密码解密失败 (同步本身的失败记录在历史中)
+func (s *SyncProfileService) Run(id int64, trigger string, userID int64) (*models.SyncHistory, error) {
+	profile, err := s.profileRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("同步配置不存在")
+	}
+
+	password, err := crypto.Decrypt(profile.Password)
+	if err != nil {
+		// 密钥变更等原因导致无法解密，同样记录为失败并顺延下次执行时间，避免调度器反复重试
+		err = errors.New("密码解密失败，请重新保存同步配置")
+		now := time.Now()
+		history := &models.SyncHistory{
+			ProfileID:    profile.ID,
+			ProfileName:  profile.Name,
+			Trigger:      trigger,
+			Direction:    profile.Direction,
+			Status:       SyncStatusFailed,
+			StartTime:    now,
+			EndTime:      &now,
+			ErrorMessage: err.Error(),
+			UserID:       userID,
+		}
+		if createErr := s.profileRepo.CreateHistory(history); createErr != nil {
+			slog.Warn("写入同步历史失败", "error", createErr)
+		}
+		s.updateRunState(profile, history)
+		return history, err
+	}
+
+	cfg := SyncConfig{
+		DBType:   profile.DBType,
+		Host:     profile.Host,
+		Port:     profile.Port,
+		User:     profile.User,
+		Password: password,
+		DBName:   profile.DBName,
+		SSLMode:  profile.SSLMode,
+		Path:     profile.Path,
+	}
+	tables := database.SyncTables
+	if profile.Tables != "" {
+		tables = strings.Split(profile.Tables, ",")
+	}
+	opts := SyncOptions{Direction: profile.Direction, Atomic: profile.Atomic == 1}
+
+	history, _, _ := s.execute(profile, trigger, userID, cfg, tables, opts)
+	s.updateRunState(profile, history)
+
+	return history, nil
+}
+
+// updateRunState 更新配置的执行状态，定时同步从本次开始时间起顺延
+func (s *SyncProfileService) updateRunState(profile *models.SyncProfile, history *models.SyncHistory) {
+	var next *time.Time
+	if profile.Enabled == 1 && profile.IntervalMinutes > 0 {
+		t := history.StartTime.Add(time.Duration(profile.IntervalMinutes) * time.Minute)
+		next = &t
+	}
+	if err := s.profileRepo.UpdateRunState(profile.ID, history.StartTime, history.Status, next); err != nil {
+		slog.Warn("更新同步配置执行状态失败", "profile_id", profile.ID, "error", err)
+	}
+}
+
+// Execute 执行未保存为配置的手动同步并记录历史
+func (s *SyncProfileService) Execute(cfg SyncConfig, tables []string, opts SyncOptions, userID int64) ([]SyncResult, error) {
+	_, results, err := s.execute(nil, SyncTriggerManual, userID, cfg, tables, opts)
+	return results, err
+}
+
+// execute 执行同步并写入历史记录
+// 开始时写入 running 状态的记录，结束后回填统计、各表结果与最终状态。
+func (s *SyncProfileService) execute(profile *models.SyncProfile, trigger string, userID int64, cfg SyncConfig, tables []string, opts SyncOptions) (*models.SyncHistory, []SyncResult, error) {
+	syncRunMu.Lock()
+	defer syncRunMu.Unlock()
+
+	direction := opts.Direction
+	if direction == "" {
+		direction = SyncDirectionPush
+	}
+	history := &models.SyncHistory{
+		Target:    s.syncService.targetKey(cfg),
+		Trigger:   trigger,
+		Direction: direction,
+		Status:    SyncStatusRunning,
+		StartTime: time.Now(),
+		UserID:    userID,
+	}
+	if profile != nil {
+		history.ProfileID = profile.ID
+		history.ProfileName = profile.Name
+	}
+	if err := s.profileRepo.CreateHistory(history); err != nil {
+		slog.Warn("写入同步历史失败", "error", err)
+	}
+
+	results, err := s.syncService.SyncTables(cfg, tables, opts)
+
+	// 汇总结果
+	endTime := time.Now()
+	history.EndTime = &endTime
+	failed := 0
+	var messages []string
+	for _, r := range results {
+		history.SyncedCount += r.SyncedCount
+		history.PulledCount += r.PulledCount
+		history.DeletedCount += r.DeletedCount
+		history.ConflictCount += int64(len(r.Conflicts))
+		if !r.Success {
+			failed++
+			messages = append(messages, fmt.Sprintf("%s: %s", r.TableName, r.ErrorMessage))
+		}
+	}
+
+	switch {
+	case err != nil:
+		history.Status = SyncStatusFailed
+		history.ErrorMessage = err.Error()
+	case failed == 0:
+		history.Status = SyncStatusSuccess
+	case failed < len(results):
+		history.Status = SyncStatusPartial
+		history.ErrorMessage = strings.Join(messages, "; ")
+	default:
+		history.Status = SyncStatusFailed
+		history.ErrorMessage = strings.Join(messages, "; ")
+	}
+	if data, jsonErr := json.Marshal(results); jsonErr == nil && results != nil {
+		history.Results = string(data)
+	}
+
+	if saveErr := s.profileRepo.UpdateHistory(history); saveErr != nil {
+		slog.Warn("更新同步历史失败", "history_id", history.ID, "error", saveErr)
+	}
+
+	return history, results, err
+}
+
+// DueProfileIDs 获取已到执行时间的定时同步配置ID
+func (s *SyncProfileService) DueProfileIDs(now time.Time) ([]int64, error) {
+	profiles, err := s.profileRepo.ListDue(now)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(profiles))
+	for _, p := range profiles {
+		ids = append(ids, p.ID)
+	}
+	return ids, nil
+}
+
+// ListHistory 分页获取同步历史
+func (s *SyncProfileService) ListHistory(profileID int64, page, pageSize int) ([]models.SyncHistory, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+	return s.profileRepo.ListHistory(profileID, offset, pageSize)
+}
+
+// GetHistory 获取同步历史详情
+func (s *SyncProfileService) GetHistory(id int64) (*models.SyncHistory, error) {
+	history, err := s.profileRepo.FindHistoryByID(id)
+	if err != nil {
+		return nil, errors.New("同步历史不存在")
+	}
+	return history, nil
+}
+
+// boolToInt 将布尔值转换为 1/0
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// syncSchedulerTick 调度器检查到期配置的间隔
+const syncSchedulerTick = time.Minute
+
+// SyncScheduler 后台定时同步调度器
+// 在应用进程内定期检查启用了定时同步且已到执行时间的配置，并依次执行同步。
+type SyncScheduler struct {
+	profileService *SyncProfileService
+	stop           chan struct{}
+	wg             sync.WaitGroup
+}
+
+// NewSyncScheduler 创建定时同步调度器
+func NewSyncScheduler() *SyncScheduler {
+	return &SyncScheduler{
+		profileService: NewSyncProfileService(),
+		stop:           make(chan struct{}),
+	}
+}
+
+// Start 启动调度器 (非阻塞)
+func (s *SyncScheduler) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(syncSchedulerTick)
+		defer ticker.Stop()
+
+		slog.Info("Sync scheduler started")
+		for {
+			select {
+			case <-s.stop:
+				return
+			case now := <-ticker.C:
+				s.runDue(now)
+			}
+		}
+	}()
+}
+
+// Stop 停止调度器，并等待正在执行的同步结束
+func (s *SyncScheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+// runDue 依次执行所有到期的同步配置
+func (s *SyncScheduler) runDue(now time.Time) {
+	ids, err := s.profileService.DueProfileIDs(now)
+	if err != nil {
+		slog.Error("查询定时同步配置失败", "error", err)
+		return
+	}
+
+	for _, id := range ids {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+
+		history, err := s.profileService.Run(id, SyncTriggerSchedule, 0)
+		if err != nil {
+			slog.Warn("定时同步失败", "profile_id", id, "error", err)
+			continue
+		}
+		slog.Info("定时同步完成", "profile_id", id, "status", history.Status, "history_id", history.ID)
+	}
+}
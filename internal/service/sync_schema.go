@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/FruitsAI/Orange/internal/database"
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+// 表结构变更类型
+const (
+	SchemaActionCreate = "create" // 新建表
+	SchemaActionAlter  = "alter"  // 修改已有表 (新增列/索引等)
+	SchemaActionNone   = "none"   // 无需变更
+)
+
+// syncMoneyColumns 迁移 000013 之前以元为单位的浮点数存储、此后以分为单位的整数存储的金额列
+// 之后新增的金额列 (变更金额、已退金额、周期计划与变更单金额等) 自创建起即以分存储。
+var syncMoneyColumns = map[string][]string{
+	"projects":         {"total_amount", "received_amount"},
+	"payments":         {"amount", "received_amount"},
+	"payment_receipts": {"amount"},
+}
+
+// SchemaChange 云端表结构变更
+type SchemaChange struct {
+	TableName  string   `json:"table_name"` // 表名
+	Action     string   `json:"action"`     // 变更类型: create, alter, none
+	Statements []string `json:"statements"` // DDL 语句
+	Applied    bool     `json:"applied"`    // 是否已执行 (预览模式为 false)
+}
+
+// ddlConn 云端连接 (*sql.DB 或 *sql.Tx)
+type ddlConn interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// ddlRecorder 记录 GORM 迁移产生的 DDL 语句
+// 查询 (表/列信息) 直接透传至云端库；写操作先记录，预览模式下不执行。
+// 支持事务 (SQLite 修改列时需在事务中重建表)，事务内的语句记录到开启事务的记录器上。
+type ddlRecorder struct {
+	db         ddlConn
+	tx         *sql.Tx
+	parent     *ddlRecorder
+	dryRun     bool
+	explain    func(sql string, vars ...interface{}) string
+	statements []string
+}
+
+// PrepareContext 实现 gorm.ConnPool
+func (r *ddlRecorder) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return r.db.PrepareContext(ctx, query)
+}
+
+// ExecContext 记录 DDL 语句，非预览模式下执行
+func (r *ddlRecorder) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	root := r
+	for root.parent != nil {
+		root = root.parent
+	}
+	root.statements = append(root.statements, r.explain(query, args...))
+	if r.dryRun {
+		return driver.RowsAffected(0), nil
+	}
+	return r.db.ExecContext(ctx, query, args...)
+}
+
+// QueryContext 实现 gorm.ConnPool
+func (r *ddlRecorder) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.db.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext 实现 gorm.ConnPool
+func (r *ddlRecorder) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return r.db.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx 实现 gorm.ConnPoolBeginner
+// 预览模式下不开启云端事务 (写操作均不执行)。
+func (r *ddlRecorder) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	child := &ddlRecorder{db: r.db, parent: r, dryRun: r.dryRun, explain: r.explain}
+	if r.dryRun {
+		return child, nil
+	}
+	db, ok := r.db.(*sql.DB)
+	if !ok {
+		return nil, gorm.ErrInvalidTransaction
+	}
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	child.db, child.tx = tx, tx
+	return child, nil
+}
+
+// Commit 实现 gorm.TxCommitter
+func (r *ddlRecorder) Commit() error {
+	if r.tx == nil {
+		return nil
+	}
+	return r.tx.Commit()
+}
+
+// Rollback 实现 gorm.TxCommitter
+func (r *ddlRecorder) Rollback() error {
+	if r.tx == nil {
+		return nil
+	}
+	return r.tx.Rollback()
+}
+
+// ProvisionSchema 按模型定义创建或迁移云端表结构
+// dryRun=true 时仅返回将要执行的 DDL 语句，不修改云端库。
+//
+// 参数:
+//   - cfg: 云端数据库连接配置
+//   - tables: 需要处理的表，为空表示全部同步表
+//   - dryRun: 是否仅预览
+func (s *SyncService) ProvisionSchema(cfg SyncConfig, tables []string, dryRun bool) ([]SchemaChange, error) {
+	remoteDB, err := s.openRemote(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer remoteDB.Close()
+
+	if len(tables) == 0 {
+		tables = database.SyncTables
+	}
+	return s.provisionSchema(remoteDB, cfg.DBType, s.orderTables(tables), dryRun)
+}
+
+// provisionSchema 在云端库上逐表执行 GORM AutoMigrate，并按表汇总产生的 DDL
+// 云端不创建外键约束 (IgnoreRelationshipsWhenMigrating)，各表可独立同步，也避免迁移单表时连带创建关联表。
+// 已有表先将仍以元存储的金额列换算为分 (见 migrateRemoteMoney)，再执行 AutoMigrate。
+func (s *SyncService) provisionSchema(remoteDB *sql.DB, dbType string, tables []string, dryRun bool) ([]SchemaChange, error) {
+	recorder := &ddlRecorder{db: remoteDB, dryRun: dryRun}
+
+	var dialector gorm.Dialector
+	switch dbType {
+	case "postgres":
+		dialector = postgres.New(postgres.Config{Conn: recorder})
+	case "mysql":
+		dialector = mysql.New(mysql.Config{Conn: recorder})
+	case "sqlite":
+		dialector = &sqlite.Dialector{Conn: recorder}
+	default:
+		return nil, fmt.Errorf("不支持的数据库类型: %s", dbType)
+	}
+	recorder.explain = dialector.Explain
+
+	gdb, err := gorm.Open(dialector, &gorm.Config{
+		Logger:                           logger.Default.LogMode(logger.Silent),
+		IgnoreRelationshipsWhenMigrating: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("初始化云端连接失败: %w", err)
+	}
+	migrator := gdb.Migrator()
+
+	changes := make([]SchemaChange, 0, len(tables))
+	for _, table := range tables {
+		spec, ok := syncTableSpecs[table]
+		if !ok {
+			continue
+		}
+
+		change := SchemaChange{TableName: table, Action: SchemaActionAlter}
+		if !migrator.HasTable(spec.Model) {
+			change.Action = SchemaActionCreate
+		}
+
+		start := len(recorder.statements)
+		if change.Action == SchemaActionAlter {
+			if err := s.migrateRemoteMoney(gdb, dbType, spec); err != nil {
+				return changes, fmt.Errorf("转换表 %s 的金额单位失败: %w", table, err)
+			}
+		}
+		if err := migrator.AutoMigrate(spec.Model); err != nil {
+			return changes, fmt.Errorf("迁移表 %s 失败: %w", table, err)
+		}
+		change.Statements = append([]string{}, recorder.statements[start:]...)
+		if len(change.Statements) == 0 {
+			change.Action = SchemaActionNone
+		}
+		change.Applied = !dryRun && len(change.Statements) > 0
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// migrateRemoteMoney 将云端以元为单位的浮点金额列转换为以分为单位的整数列
+// AutoMigrate 仅修改列类型而不换算数值，需在其之前完成换算。列类型即转换标记: 仍为浮点/定点类型的列视为以元存储。
+// 每列依次执行 新增临时列 -> 换算写入 -> 删除原列 -> 临时列改名；任一步中断后重新执行会从中断处继续。
+func (s *SyncService) migrateRemoteMoney(gdb *gorm.DB, dbType string, spec syncTableSpec) error {
+	columns, ok := syncMoneyColumns[spec.Name]
+	if !ok {
+		return nil
+	}
+
+	migrator := gdb.Migrator()
+	columnTypes, err := migrator.ColumnTypes(spec.Model)
+	if err != nil {
+		return err
+	}
+	types := make(map[string]string, len(columnTypes))
+	for _, ct := range columnTypes {
+		types[strings.ToLower(ct.Name())] = strings.ToLower(ct.DatabaseTypeName())
+	}
+
+	intType, roundExpr := "bigint", "ROUND(? * 100)"
+	if dbType == "sqlite" {
+		intType, roundExpr = "integer", "CAST(ROUND(? * 100) AS INTEGER)"
+	}
+
+	table := clause.Table{Name: spec.Name}
+	for _, col := range columns {
+		tmp := col + "_minor"
+		colType, hasCol := types[col]
+		_, hasTmp := types[tmp]
+
+		switch {
+		case hasCol && isDecimalColumnType(colType):
+			if !hasTmp {
+				if err := gdb.Exec("ALTER TABLE ? ADD ? "+intType+" NOT NULL DEFAULT 0", table, clause.Column{Name: tmp}).Error; err != nil {
+					return err
+				}
+			}
+			if err := gdb.Exec("UPDATE ? SET ? = "+roundExpr, table, clause.Column{Name: tmp}, clause.Column{Name: col}).Error; err != nil {
+				return err
+			}
+			// SQLite 的 Migrator.DropColumn 需在事务中重建表，此处直接使用 DROP COLUMN (SQLite 3.35+)
+			if err := gdb.Exec("ALTER TABLE ? DROP COLUMN ?", table, clause.Column{Name: col}).Error; err != nil {
+				return err
+			}
+		case !hasCol && hasTmp:
+			// 上次转换在删除原列后中断，仅需改名
+		default:
+			continue
+		}
+		if err := migrator.RenameColumn(spec.Model, tmp, col); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isDecimalColumnType 判断列类型是否为浮点或定点小数类型
+func isDecimalColumnType(typ string) bool {
+	for _, t := range []string{"real", "double", "float", "numeric", "decimal"} {
+		if strings.Contains(typ, t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/config"
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/audit"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+// 回收站错误
+var (
+	ErrTrashItemNotFound = errors.New("回收站中不存在该记录")
+	ErrTrashConflict     = errors.New("无法恢复")
+)
+
+// TrashService 回收站服务
+// 项目、款项与用户删除后进入回收站 (软删除)，可在保留期内恢复或彻底删除；
+// 超出 TRASH_RETENTION_DAYS 天的记录由 TrashPurger 自动彻底删除。
+//
+// 权限规则:
+//   - 项目: 所有者可查看、恢复与彻底删除；拥有 projects:read_all 权限的用户可查看全部
+//   - 款项: 所属项目的所有者或编辑者可恢复与彻底删除；随项目一并删除的款项跟随项目处理
+//   - 用户: 由路由限定为拥有 users:manage 权限的管理员
+type TrashService struct {
+	projectRepo    *repository.ProjectRepository
+	paymentRepo    *repository.PaymentRepository
+	userRepo       *repository.UserRepository
+	paymentService *PaymentService
+	auditService   *AuditService
+	access         *projectAccess
+}
+
+// NewTrashService 创建回收站服务实例
+func NewTrashService() *TrashService {
+	return &TrashService{
+		projectRepo:    repository.NewProjectRepository(),
+		paymentRepo:    repository.NewPaymentRepository(),
+		userRepo:       repository.NewUserRepository(),
+		paymentService: NewPaymentService(),
+		auditService:   NewAuditService(),
+		access:         newProjectAccess(),
+	}
+}
+
+// ListProjects 获取回收站中的项目
+func (s *TrashService) ListProjects(userID int64) ([]dto.TrashItem, error) {
+	includeAll, err := s.access.canReadAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := s.projectRepo.ListDeleted(userID, includeAll)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.TrashItem, 0, len(projects))
+	for i := range projects {
+		p := &projects[i]
+		items = append(items, newTrashItem(audit.EntityProject, p.ID, p.Name, p.Company, p.DeletedAt, p))
+	}
+	return items, nil
+}
+
+// RestoreProject 从回收站恢复项目 (仅所有者)
+// 随项目一并删除的款项同时恢复；合同编号已被其他项目使用时拒绝恢复。
+func (s *TrashService) RestoreProject(actor audit.Actor, id int64) (*models.Project, error) {
+	project, err := s.deletedProject(actor.UserID, id)
+	if err != nil {
+		return nil, err
+	}
+	if project.ContractNumber != "" {
+		exists, err := s.projectRepo.ExistsByContractNumber(project.UserID, project.ContractNumber, project.ID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("%w: 合同编号 %s 已被其他项目使用", ErrTrashConflict, project.ContractNumber)
+		}
+	}
+
+	if err := s.projectRepo.Restore(id); err != nil {
+		return nil, err
+	}
+	restored, err := s.projectRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.Record(actor, audit.ActionRestore, audit.EntityProject, id, nil, restored)
+	return restored, nil
+}
+
+// PurgeProject 彻底删除回收站中的项目 (仅所有者，不可恢复)
+func (s *TrashService) PurgeProject(actor audit.Actor, id int64) error {
+	project, err := s.deletedProject(actor.UserID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.projectRepo.Purge(id); err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionPurge, audit.EntityProject, id, project, nil)
+	return nil
+}
+
+// ListPayments 获取回收站中单独删除的款项 (所属项目的成员可见)
+func (s *TrashService) ListPayments(userID int64) ([]dto.TrashItem, error) {
+	includeAll, err := s.access.canReadAll(userID)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.paymentRepo.ListDeleted(userID, includeAll)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.TrashItem, 0, len(payments))
+	for i := range payments {
+		p := &payments[i]
+		detail := ""
+		if p.Project != nil {
+			detail = p.Project.Name
+		}
+		items = append(items, newTrashItem(audit.EntityPayment, p.ID, p.Stage, detail, p.DeletedAt, p))
+	}
+	return items, nil
+}
+
+// RestorePayment 从回收站恢复款项 (需为所属项目的所有者或编辑者)
+// 恢复后重新计算项目的已收款总额。
+func (s *TrashService) RestorePayment(actor audit.Actor, id int64) (*models.Payment, error) {
+	payment, err := s.deletedPayment(actor.UserID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.paymentRepo.Restore(id); err != nil {
+		return nil, err
+	}
+	if err := s.paymentService.syncProjectReceivedAmount(payment.ProjectID); err != nil {
+		return nil, err
+	}
+	restored, err := s.paymentRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.Record(actor, audit.ActionRestore, audit.EntityPayment, id, nil, restored)
+	return restored, nil
+}
+
+// PurgePayment 彻底删除回收站中的款项 (需为所属项目的所有者或编辑者，不可恢复)
+func (s *TrashService) PurgePayment(actor audit.Actor, id int64) error {
+	payment, err := s.deletedPayment(actor.UserID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.paymentRepo.Purge(id); err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionPurge, audit.EntityPayment, id, payment, nil)
+	return nil
+}
+
+// ListUsers 获取回收站中的用户 (管理员)
+func (s *TrashService) ListUsers() ([]dto.TrashItem, error) {
+	users, err := s.userRepo.ListDeleted()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.TrashItem, 0, len(users))
+	for i := range users {
+		u := &users[i]
+		items = append(items, newTrashItem(audit.EntityUser, u.ID, u.Username, u.Name, u.DeletedAt, u))
+	}
+	return items, nil
+}
+
+// RestoreUser 从回收站恢复用户 (管理员)
+// 邮箱已被其他用户使用时拒绝恢复。
+func (s *TrashService) RestoreUser(actor audit.Actor, id int64) (*models.User, error) {
+	user, err := s.deletedUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Email != "" && s.userRepo.ExistsByEmail(user.Email) {
+		return nil, fmt.Errorf("%w: 邮箱 %s 已被其他用户使用", ErrTrashConflict, user.Email)
+	}
+
+	if err := s.userRepo.Restore(id); err != nil {
+		return nil, err
+	}
+	restored, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	s.auditService.Record(actor, audit.ActionRestore, audit.EntityUser, id, nil, restored)
+	return restored, nil
+}
+
+// PurgeUser 彻底删除回收站中的用户 (管理员，不可恢复)
+func (s *TrashService) PurgeUser(actor audit.Actor, id int64) error {
+	user, err := s.deletedUser(id)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.Purge(id); err != nil {
+		return err
+	}
+	s.auditService.Record(actor, audit.ActionPurge, audit.EntityUser, id, user, nil)
+	return nil
+}
+
+// PurgeExpired 彻底删除超出保留期的回收站记录
+// 保留天数为 0 时不清理。单条记录清理失败时记录日志并继续。
+//
+// 返回:
+//   - int: 彻底删除的记录数
+func (s *TrashService) PurgeExpired(now time.Time) (int, error) {
+	days := config.AppConfig.TrashRetentionDays
+	if days <= 0 {
+		return 0, nil
+	}
+	cutoff := now.AddDate(0, 0, -days)
+
+	purged := 0
+	targets := []struct {
+		entityType string
+		list       func(time.Time) ([]int64, error)
+		purge      func(int64) error
+	}{
+		{audit.EntityPayment, s.paymentRepo.ListDeletedBefore, s.paymentRepo.Purge},
+		{audit.EntityProject, s.projectRepo.ListDeletedBefore, s.projectRepo.Purge},
+		{audit.EntityUser, s.userRepo.ListDeletedBefore, s.userRepo.Purge},
+	}
+	for _, target := range targets {
+		ids, err := target.list(cutoff)
+		if err != nil {
+			return purged, err
+		}
+		for _, id := range ids {
+			if err := target.purge(id); err != nil {
+				slog.Error("回收站自动清理失败", "entity_type", target.entityType, "entity_id", id, "error", err)
+				continue
+			}
+			s.auditService.Record(systemActor, audit.ActionPurge, target.entityType, id, nil, nil)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// deletedProject 加载回收站中的项目并校验所有者权限
+func (s *TrashService) deletedProject(userID, id int64) (*models.Project, error) {
+	project, err := s.projectRepo.FindDeleted(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTrashItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.access.check(userID, project, ProjectRoleOwner); err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return nil, ErrTrashItemNotFound
+		}
+		return nil, err
+	}
+	return project, nil
+}
+
+// deletedPayment 加载回收站中的款项并校验所属项目的编辑权限
+// 所属项目也已删除时，款项需随项目一并恢复。
+func (s *TrashService) deletedPayment(userID, id int64) (*models.Payment, error) {
+	payment, err := s.paymentRepo.FindDeleted(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTrashItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.access.authorize(userID, payment.ProjectID, ProjectRoleEditor); err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return nil, ErrTrashItemNotFound
+		}
+		return nil, err
+	}
+	return payment, nil
+}
+
+// deletedUser 加载回收站中的用户
+func (s *TrashService) deletedUser(id int64) (*models.User, error) {
+	user, err := s.userRepo.FindDeleted(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTrashItemNotFound
+	}
+	return user, err
+}
+
+// newTrashItem 构造回收站条目，启用自动清理时计算预计彻底删除时间
+func newTrashItem(entityType string, id int64, name, detail string, deletedAt gorm.DeletedAt, data interface{}) dto.TrashItem {
+	item := dto.TrashItem{
+		ID:        id,
+		Type:      entityType,
+		Name:      name,
+		Detail:    detail,
+		DeletedAt: deletedAt.Time,
+		Data:      data,
+	}
+	if days := config.AppConfig.TrashRetentionDays; days > 0 {
+		purgeAt := deletedAt.Time.AddDate(0, 0, days)
+		item.PurgeAt = &purgeAt
+	}
+	return item
+}
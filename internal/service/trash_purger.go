@@ -0,0 +1,66 @@
+package service
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// trashPurgerTick 回收站自动清理的检查间隔
+const trashPurgerTick = time.Hour
+
+// TrashPurger 回收站自动清理任务
+// 启动时及此后每小时彻底删除一次超出保留期 (TRASH_RETENTION_DAYS) 的回收站记录。
+type TrashPurger struct {
+	trashService *TrashService
+	stop         chan struct{}
+	wg           sync.WaitGroup
+}
+
+// NewTrashPurger 创建回收站自动清理任务
+func NewTrashPurger() *TrashPurger {
+	return &TrashPurger{
+		trashService: NewTrashService(),
+		stop:         make(chan struct{}),
+	}
+}
+
+// Start 启动清理任务 (非阻塞)
+func (p *TrashPurger) Start() {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(trashPurgerTick)
+		defer ticker.Stop()
+
+		slog.Info("Trash purger started")
+		p.purge(time.Now())
+		for {
+			select {
+			case <-p.stop:
+				return
+			case now := <-ticker.C:
+				p.purge(now)
+			}
+		}
+	}()
+}
+
+// Stop 停止清理任务，并等待正在进行的清理结束
+func (p *TrashPurger) Stop() {
+	close(p.stop)
+	p.wg.Wait()
+}
+
+// purge 执行一次清理
+func (p *TrashPurger) purge(now time.Time) {
+	purged, err := p.trashService.PurgeExpired(now)
+	if err != nil {
+		slog.Error("回收站自动清理失败", "error", err)
+		return
+	}
+	if purged > 0 {
+		slog.Info("回收站自动清理完成", "purged", purged)
+	}
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FruitsAI/Orange/internal/dto"
+	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/crypto"
+	"github.com/FruitsAI/Orange/internal/pkg/jwt"
+	"github.com/FruitsAI/Orange/internal/pkg/password"
+	"github.com/FruitsAI/Orange/internal/pkg/totp"
+	"github.com/FruitsAI/Orange/internal/repository"
+	"gorm.io/gorm"
+)
+
+// 两步验证错误
+var (
+	ErrChallengeInvalid   = errors.New("验证已超时，请重新登录")
+	ErrTwoFactorCodeWrong = errors.New("验证码错误")
+	ErrChallengeExhausted = errors.New("验证码错误次数过多，请重新登录")
+)
+
+const (
+	// recoveryCodeCount 每次生成的恢复码数量
+	recoveryCodeCount = 10
+
+	// maxChallengeAttempts 每个质询令牌允许的验证码错误次数，超过后需重新输入密码
+	maxChallengeAttempts = 5
+)
+
+// 质询令牌使用状态 (jti -> 状态)
+// 质询令牌本身无状态，服务端记录错误次数与是否已使用，防止暴力猜测验证码及令牌重复使用。
+var (
+	challengeMu     sync.Mutex
+	challengeStates = make(map[string]*challengeState)
+)
+
+// challengeState 质询令牌使用状态
+type challengeState struct {
+	failures  int
+	used      bool
+	expiresAt time.Time
+}
+
+// TwoFactorService 两步验证服务
+// 负责 TOTP 密钥的生成与启用、登录时的验证码校验以及恢复码管理。
+//
+// 依赖:
+//   - TwoFactorRepository: 两步验证数据操作
+//   - UserRepository: 校验用户密码
+type TwoFactorService struct {
+	tfRepo   *repository.TwoFactorRepository
+	userRepo *repository.UserRepository
+}
+
+// NewTwoFactorService 创建两步验证服务实例
+func NewTwoFactorService() *TwoFactorService {
+	return &TwoFactorService{
+		tfRepo:   repository.NewTwoFactorRepository(),
+		userRepo: repository.NewUserRepository(),
+	}
+}
+
+// Status 获取用户的两步验证状态
+func (s *TwoFactorService) Status(userID int64) (*dto.TwoFactorStatus, error) {
+	status := &dto.TwoFactorStatus{}
+	tf, err := s.tfRepo.FindByUser(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tf.Enabled != 1 {
+		return status, nil
+	}
+
+	remaining, err := s.tfRepo.CountUnusedRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	status.Enabled = true
+	status.EnabledAt = tf.EnabledAt
+	status.RecoveryCodesRemaining = remaining
+	return status, nil
+}
+
+// IsEnabled 判断用户是否已启用两步验证
+func (s *TwoFactorService) IsEnabled(userID int64) (bool, error) {
+	return s.tfRepo.IsEnabled(userID)
+}
+
+// Setup 生成新的 TOTP 密钥 (待验证状态)
+// 用户使用验证器扫码后，需调用 Enable 提交验证码才会正式启用。
+//
+// 返回:
+//   - *dto.TwoFactorSetupResult: 密钥及 otpauth 链接
+//   - error: 已启用两步验证或生成失败
+func (s *TwoFactorService) Setup(userID int64) (*dto.TwoFactorSetupResult, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("用户不存在")
+	}
+	enabled, err := s.tfRepo.IsEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, errors.New("两步验证已启用，如需更换验证器请先关闭")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := crypto.Encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tfRepo.SavePending(userID, encrypted); err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorSetupResult{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(user.Username, secret),
+	}, nil
+}
+
+// Enable 校验验证码并启用两步验证
+//
+// 返回:
+//   - []string: 恢复码明文 (仅此一次返回，请提示用户妥善保存)
+//   - error: 未生成密钥、已启用或验证码错误
+func (s *TwoFactorService) Enable(userID int64, code string) ([]string, error) {
+	tf, err := s.tfRepo.FindByUser(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("请先生成两步验证密钥")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tf.Enabled == 1 {
+		return nil, errors.New("两步验证已启用")
+	}
+
+	secret, err := crypto.Decrypt(tf.Secret)
+	if err != nil {
+		return nil, errors.New("两步验证密钥无法解密，请重新生成")
+	}
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok {
+		return nil, ErrTwoFactorCodeWrong
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tfRepo.Enable(userID, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable 关闭两步验证 (需验证密码及验证码/恢复码)
+func (s *TwoFactorService) Disable(userID int64, pwd, code string) error {
+	if err := s.checkPassword(userID, pwd); err != nil {
+		return err
+	}
+	ok, err := s.VerifyCode(userID, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTwoFactorCodeWrong
+	}
+	return s.tfRepo.Delete(userID)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码 (需验证密码)，旧恢复码全部作废
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID int64, pwd string) ([]string, error) {
+	if err := s.checkPassword(userID, pwd); err != nil {
+		return nil, err
+	}
+	enabled, err := s.tfRepo.IsEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, errors.New("尚未启用两步验证")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tfRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Reset 重置用户的两步验证 (管理员，用户丢失验证器且恢复码用尽时使用)
+func (s *TwoFactorService) Reset(userID int64) error {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return errors.New("用户不存在")
+	}
+	return s.tfRepo.Delete(userID)
+}
+
+// VerifyCode 校验验证码或恢复码
+// 6 位数字按 TOTP 校验 (同一时间步的验证码只能使用一次)，其余输入按恢复码校验。
+//
+// 返回:
+//   - bool: 是否通过校验
+//   - error: 数据库或解密错误
+func (s *TwoFactorService) VerifyCode(userID int64, code string) (bool, error) {
+	tf, err := s.tfRepo.FindByUser(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if tf.Enabled != 1 {
+		return false, nil
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		secret, err := crypto.Decrypt(tf.Secret)
+		if err != nil {
+			return false, err
+		}
+		step, ok := totp.Validate(secret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+		return s.tfRepo.UseStep(userID, step)
+	}
+
+	return s.tfRepo.UseRecoveryCode(userID, hashRecoveryCode(code))
+}
+
+// Challenge 为已通过密码校验的用户签发两步验证质询令牌
+func (s *TwoFactorService) Challenge(user *models.User) (*dto.LoginResult, error) {
+	token, err := jwt.GenerateChallengeToken(user.ID, user.Username)
+	if err != nil {
+		return nil, errors.New("生成Token失败")
+	}
+	return &dto.LoginResult{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+	}, nil
+}
+
+// VerifyChallenge 校验质询令牌与验证码
+// 每个质询令牌只能成功使用一次，错误次数超过上限后作废。
+//
+// 返回:
+//   - int64: 质询令牌所属的用户ID (验证码错误时同样返回，便于记录登录失败)
+//   - error: 质询令牌无效或验证码错误
+func (s *TwoFactorService) VerifyChallenge(challengeToken, code string) (int64, error) {
+	claims, err := jwt.ParseChallengeToken(challengeToken)
+	if err != nil {
+		return 0, ErrChallengeInvalid
+	}
+
+	challengeMu.Lock()
+	defer challengeMu.Unlock()
+
+	now := time.Now()
+	for id, st := range challengeStates {
+		if st.expiresAt.Before(now) {
+			delete(challengeStates, id)
+		}
+	}
+	st, ok := challengeStates[claims.ID]
+	if !ok {
+		st = &challengeState{expiresAt: claims.ExpiresAt.Time}
+		challengeStates[claims.ID] = st
+	}
+	if st.used || st.failures >= maxChallengeAttempts {
+		return 0, ErrChallengeInvalid
+	}
+
+	valid, err := s.VerifyCode(claims.UserID, code)
+	if err != nil {
+		return 0, err
+	}
+	if !valid {
+		st.failures++
+		if st.failures >= maxChallengeAttempts {
+			return claims.UserID, ErrChallengeExhausted
+		}
+		return claims.UserID, ErrTwoFactorCodeWrong
+	}
+
+	st.used = true
+	return claims.UserID, nil
+}
+
+// checkPassword 校验用户当前密码
+func (s *TwoFactorService) checkPassword(userID int64, pwd string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("用户不存在")
+	}
+	if !password.CheckPassword(pwd, user.Password) {
+		return errors.New("密码错误")
+	}
+	return nil
+}
+
+// newRecoveryCodes 生成恢复码，返回明文列表及对应 Hash
+// 格式为 xxxxx-xxxxx (十六进制小写)，便于抄写。
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := hex.EncodeToString(buf)
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode 计算恢复码 Hash (忽略大小写、空格与连字符)
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
@@ -10,16 +10,19 @@ import (
 	"net/http"
 	"os"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/FruitsAI/Orange/internal/config"
 	"github.com/FruitsAI/Orange/internal/database"
-	"github.com/FruitsAI/Orange/internal/models"
+	"github.com/FruitsAI/Orange/internal/pkg/crypto"
 	"github.com/FruitsAI/Orange/internal/pkg/jwt"
 	"github.com/FruitsAI/Orange/internal/pkg/logger"
 	"github.com/FruitsAI/Orange/internal/router"
+	"github.com/FruitsAI/Orange/internal/service"
 	"github.com/wailsapp/wails/v3/pkg/application"
+	"gorm.io/gorm"
 )
 
 // Wails 使用 Go 的 `embed` 包将前端构建产物嵌入到二进制文件中。
@@ -59,6 +62,45 @@ func createAssetHandler(ginRouter http.Handler) http.Handler {
 	})
 }
 
+// runMigrateCommand 执行命令行迁移子命令
+// up: 执行全部未执行的迁移; down [n]: 回滚最近 n 个迁移 (默认 1); status: 查看迁移状态
+func runMigrateCommand(db *gorm.DB, args []string) error {
+	action := "status"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "up":
+		return database.Migrate(db)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid steps: %s", args[1])
+			}
+			steps = n
+		}
+		return database.MigrateDown(db, steps)
+	case "status":
+		statuses, err := database.MigrationStatuses(db)
+		if err != nil {
+			return err
+		}
+		for _, st := range statuses {
+			state := "pending"
+			if st.Applied {
+				state = "applied " + st.AppliedAt.Format(time.DateTime)
+			}
+			fmt.Printf("%06d  %-20s %s\n", st.Version, st.Name, state)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate action: %s (expected up, down or status)", action)
+	}
+}
+
 // main 是应用程序的入口点。
 // 它负责初始化应用配置、日志、数据库，创建 Wails 应用实例及窗口，并启动主事件循环。
 func main() {
@@ -82,23 +124,28 @@ func main() {
 
 	// 4. 初始化 JWT 密钥配置
 	jwt.SecretKey = []byte(config.AppConfig.JWTSecret)
-	jwt.TokenExpiry = time.Duration(config.AppConfig.TokenExpiry) * time.Hour
+	jwt.TokenExpiry = time.Duration(config.AppConfig.AccessTokenExpiry) * time.Minute
+
+	// 初始化同步配置加密密钥
+	crypto.SecretKey = []byte(config.AppConfig.SyncSecretKey)
 
 	// 5. 初始化数据库连接
 	slog.Info("Initializing database...")
 	db := database.GetDB()
 
-	// 执行数据库自动迁移 (同步表结构)
-	db.AutoMigrate(
-		&models.User{},
-		&models.Project{},
-		&models.Payment{},
-		&models.Dictionary{},
-		&models.DictionaryItem{},
-		&models.Notification{},
-		&models.UserNotification{},
-		&models.PersonalAccessToken{},
-	)
+	// 命令行迁移管理: orange migrate [up|down [n]|status]
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(db, os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	// 执行数据库版本迁移 (数据库版本高于程序时拒绝启动)
+	if err := database.Migrate(db); err != nil {
+		slog.Error("Failed to migrate database", "error", err)
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
 
 	// 播种初始化数据 (如默认用户、字典等)
 	if err := database.Seed(db); err != nil {
@@ -109,6 +156,25 @@ func main() {
 
 	defer database.Close()
 
+	// 启动后台定时同步调度器
+	if config.AppConfig.SyncSchedulerEnabled {
+		syncScheduler := service.NewSyncScheduler()
+		syncScheduler.Start()
+		defer syncScheduler.Stop()
+	}
+
+	// 启动回收站自动清理任务 (超出保留期的记录彻底删除)
+	if config.AppConfig.TrashRetentionDays > 0 {
+		trashPurger := service.NewTrashPurger()
+		trashPurger.Start()
+		defer trashPurger.Stop()
+	}
+
+	// 启动周期款项生成任务 (按周期收款计划提前生成款项)
+	scheduleGenerator := service.NewScheduleGenerator()
+	scheduleGenerator.Start()
+	defer scheduleGenerator.Stop()
+
 	// 6. 初始化 Gin 路由器 (API 处理器)
 	ginRouter := router.NewRouter()
 